@@ -45,6 +45,13 @@ func main() {
 	mux.HandleFunc("/auth/login", transport.Login) // TODO: temporary
 	mux.HandleFunc("/auth/logout", transport.Logout)
 	mux.HandleFunc("/auth/delete", transport.Delete)
+	mux.HandleFunc("PUT /auth/keys", transport.UploadKeys)
+	mux.HandleFunc("GET /auth/keys", transport.GetKeys)
+	mux.HandleFunc("DELETE /auth/keys/{device_id}", transport.DeleteKeys)
+	mux.HandleFunc("POST /auth/bots", transport.CreateBot)
+	mux.HandleFunc("GET /auth/bots", transport.ListBots)
+	mux.HandleFunc("POST /auth/bots/{bot_id}/token", transport.RotateBotToken)
+	mux.HandleFunc("DELETE /auth/bots/{bot_id}", transport.DeleteBot)
 
 	address := fmt.Sprintf("0.0.0.0:%d", cfg.Port)
 	logger.Info(ctx, fmt.Sprintf("REST: Addr:%s", address))
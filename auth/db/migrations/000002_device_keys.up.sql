@@ -0,0 +1,13 @@
+-- Каталог публичных ключей для сквозного шифрования: ключ идентичности и подписанный prekey
+-- каждого устройства пользователя. Приватные ключи никогда не покидают устройство
+CREATE TABLE IF NOT EXISTS auth_schema.device_keys
+(
+    user_id INTEGER NOT NULL REFERENCES auth_schema.users(id) ON DELETE CASCADE,
+    device_id VARCHAR(64) NOT NULL,
+    identity_key TEXT NOT NULL,
+    signed_prekey_id INTEGER NOT NULL,
+    signed_prekey TEXT NOT NULL,
+    signed_prekey_signature TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, device_id)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE auth_schema.users
+    DROP COLUMN suspend_reason,
+    DROP COLUMN suspended_until,
+    DROP COLUMN suspended_at,
+    DROP COLUMN role;
@@ -0,0 +1,7 @@
+-- Роль пользователя попадает в claims токена. Блокировка проверяется при каждом Validate:
+-- suspended_at задан - пользователь заблокирован до suspended_until, NULL в suspended_until - бессрочно
+ALTER TABLE auth_schema.users
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    ADD COLUMN suspended_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN suspended_until TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN suspend_reason TEXT;
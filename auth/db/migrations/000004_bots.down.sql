@@ -0,0 +1,9 @@
+DELETE FROM auth_schema.users WHERE role = 'bot';
+
+DROP INDEX auth_schema.users_owner_id_idx;
+
+ALTER TABLE auth_schema.users
+    DROP CONSTRAINT users_bot_owner_check,
+    DROP CONSTRAINT users_role_check,
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin')),
+    DROP COLUMN owner_id;
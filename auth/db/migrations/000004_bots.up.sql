@@ -0,0 +1,9 @@
+-- Бот - учетная запись с ролью bot, принадлежащая владельцу owner_id. Входа по паролю у бота нет,
+-- в token хранится бессрочный API-токен, который владелец может перевыпустить
+ALTER TABLE auth_schema.users
+    ADD COLUMN owner_id INTEGER REFERENCES auth_schema.users(id) ON DELETE CASCADE,
+    DROP CONSTRAINT users_role_check,
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin', 'bot')),
+    ADD CONSTRAINT users_bot_owner_check CHECK ((role = 'bot') = (owner_id IS NOT NULL));
+
+CREATE INDEX users_owner_id_idx ON auth_schema.users (owner_id) WHERE owner_id IS NOT NULL;
@@ -0,0 +1,122 @@
+package transport
+
+import (
+	pbapi "auth_service/pkg/api"
+	"auth_service/pkg/postgres"
+	"context"
+	"errors"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultUsersLimit = 50
+	maxUsersLimit     = 200
+)
+
+func (x *Service) ListUsers(ctx context.Context, in *pbapi.ListUsersRequest) (*pbapi.ListUsersResponse, error) {
+	if _, err := requireAdmin(in.GetToken()); err != nil {
+		return nil, err
+	}
+
+	limit := int(in.GetLimit())
+	if limit <= 0 {
+		limit = defaultUsersLimit
+	}
+	limit = min(limit, maxUsersLimit)
+	offset := max(int(in.GetOffset()), 0)
+
+	users, err := postgres.ListUsers(in.GetQuery(), limit, offset)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+
+	ret := &pbapi.ListUsersResponse{Users: make([]*pbapi.User, 0, len(users))}
+	for _, u := range users {
+		ret.Users = append(ret.Users, userToProto(u))
+	}
+	return ret, nil
+}
+
+// SuspendUser блокирует пользователя: его токен перестает проходить Validate до конца блокировки
+func (x *Service) SuspendUser(ctx context.Context, in *pbapi.SuspendUserRequest) (*pbapi.User, error) {
+	adminID, err := requireAdmin(in.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	if int(in.GetUserId()) == adminID {
+		return nil, status.Error(codes.InvalidArgument, "cannot suspend yourself")
+	}
+
+	var until *time.Time
+	if in.GetUntil() != 0 {
+		t := time.Unix(in.GetUntil(), 0)
+		if !t.After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "suspension end must be in the future")
+		}
+		until = &t
+	}
+
+	u, err := postgres.SuspendUser(int(in.GetUserId()), until, in.GetReason())
+	return userResult(u, err)
+}
+
+func (x *Service) UnsuspendUser(ctx context.Context, in *pbapi.UnsuspendUserRequest) (*pbapi.User, error) {
+	if _, err := requireAdmin(in.GetToken()); err != nil {
+		return nil, err
+	}
+
+	u, err := postgres.UnsuspendUser(int(in.GetUserId()))
+	return userResult(u, err)
+}
+
+// requireAdmin пропускает токен с ролью admin в claims, если его владелец и сейчас администратор
+// и не заблокирован: снятие роли действует сразу, не дожидаясь истечения токена
+func requireAdmin(token string) (int, error) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, &claims, keyFunc); err != nil {
+		return 0, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if role, _ := claims["role"].(string); role != postgres.RoleAdmin {
+		return 0, status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	access, err := postgres.GetAccessByToken(token)
+	if err != nil {
+		return 0, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if access.Role != postgres.RoleAdmin || access.Suspended {
+		return 0, status.Error(codes.PermissionDenied, "admin role required")
+	}
+	return access.ID, nil
+}
+
+func userResult(u postgres.User, err error) (*pbapi.User, error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to update user")
+	}
+	return userToProto(u), nil
+}
+
+func userToProto(u postgres.User) *pbapi.User {
+	ret := &pbapi.User{
+		Id:            int32(u.ID),
+		Login:         u.Login,
+		Email:         u.Email,
+		Name:          u.Name,
+		Role:          u.Role,
+		Suspended:     u.Suspended,
+		SuspendReason: u.SuspendReason,
+	}
+	if u.SuspendedUntil != nil {
+		ret.SuspendedUntil = u.SuspendedUntil.Unix()
+	}
+	return ret
+}
@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"auth_service/internal/config"
+	pbapi "auth_service/pkg/api"
+	"auth_service/pkg/postgres"
+	"context"
+	"testing"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewTokenRole(t *testing.T) {
+	JWTKey = "testkey"
+
+	token, err := newToken("testlogin", postgres.RoleAdmin)
+	if err != nil {
+		t.Fatal("newToken() error:", err)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, &claims, keyFunc); err != nil {
+		t.Fatal("ParseWithClaims() error:", err)
+	}
+	if claims["role"] != postgres.RoleAdmin || claims["sub"] != "testlogin" {
+		t.Error("Unexpected claims:", claims)
+	}
+}
+
+// Проверки, которые отсекают запрос до обращения к базе
+func TestRequireAdminRejects(t *testing.T) {
+	JWTKey = "testkey"
+	userToken, _ := newToken("testlogin", postgres.RoleUser)
+
+	tests := []struct {
+		name	string
+		token	string
+		code	codes.Code
+	}{
+		{"garbage", "not a token", codes.Unauthenticated},
+		{"user role", userToken, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := requireAdmin(tt.token)
+			if status.Code(err) != tt.code {
+				t.Error("requireAdmin() error:", err, "want code:", tt.code)
+			}
+		})
+	}
+}
+
+// Снятая роль перестает действовать сразу: Validate отдает роль из базы, а не из claims старого токена
+func TestValidateDemotedAdmin(t *testing.T) {
+	cfg, err := config.New("../../config/config_test.env")
+	if err != nil {
+		t.Error("Error when loading config: ", err)
+		return
+	}
+
+	pool, err := postgres.New(context.Background(), (*cfg).Postgres, "../../db/migrations")
+	if err != nil {
+		t.Error("Error when executing postgres.New(): ", err)
+		return
+	}
+	defer pool.Close()
+
+	JWTKey = "testkey"
+	token, _ := newToken("testlogin", postgres.RoleAdmin)
+
+	id := 0
+	err = pool.QueryRow(context.Background(), "INSERT INTO auth_schema.users (token, login, email, pass, name, role) VALUES ($1, 'testlogin', 'testemail', '179ad45c6ce2cb97cf1029e212046e81', 'testname', 'admin') RETURNING id;", token).Scan(&id)
+	if err != nil {
+		t.Error("Error when inserting user: ", err)
+		return
+	}
+	defer pool.Exec(context.Background(), "DELETE FROM auth_schema.users WHERE id=$1;", id)
+
+	res, err := New().Validate(context.Background(), &pbapi.Token{Token: token})
+	if err != nil || res.GetRole() != postgres.RoleAdmin {
+		t.Error("Validate() before demotion:", res, err)
+		return
+	}
+
+	if _, err := pool.Exec(context.Background(), "UPDATE auth_schema.users SET role='user' WHERE id=$1;", id); err != nil {
+		t.Error("Error when demoting user: ", err)
+		return
+	}
+
+	res, err = New().Validate(context.Background(), &pbapi.Token{Token: token})
+	if err != nil || res.GetRole() != postgres.RoleUser || res.GetUserId() != int32(id) {
+		t.Error("Validate() after demotion:", res, err, "want role:", postgres.RoleUser)
+	}
+}
@@ -0,0 +1,211 @@
+package transport
+
+import (
+	"auth_service/pkg/logger"
+	"auth_service/pkg/postgres"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxBots - сколько ботов может создать один пользователь
+	MaxBots = 20
+
+	// BotTokenPrefix отличает API-токен бота от JWT пользователя
+	BotTokenPrefix = "bot_"
+
+	botTokenLen		= 32
+	maxBotNameLen	= 100
+)
+
+// Логин бота заканчивается на bot, чтобы в чатах его нельзя было принять за человека
+var botLoginPattern = regexp.MustCompile(`^[A-Za-z0-9_]{2,27}[Bb][Oo][Tt]$`)
+
+// CreateBot создает бота текущего пользователя и единственный раз отдает его токен
+func CreateBot(w http.ResponseWriter, r *http.Request) {
+	ownerID, ok := authorizeOwner(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(r.Context(), "Error in JSON decoder", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if err := validateBot(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	token, err := newBotToken()
+	if err != nil {
+		logger.Warn(r.Context(), "Failed to create bot token", zap.Error(err))
+		http.Error(w, "Failed to create bot token", http.StatusInternalServerError)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = req.Login
+	}
+
+	bot, err := postgres.InsertBot(ownerID, token, req.Login, name, MaxBots)
+	if errors.Is(err, postgres.ErrTooManyBots) || errors.Is(err, postgres.ErrLoginTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Warn(r.Context(), "Failed to create bot", zap.Error(err))
+		http.Error(w, "Failed to create bot", http.StatusInternalServerError)
+		return
+	}
+
+	writeBot(w, r, http.StatusCreated, bot)
+}
+
+// ListBots отдает ботов текущего пользователя без токенов
+func ListBots(w http.ResponseWriter, r *http.Request) {
+	ownerID, ok := authorizeOwner(w, r)
+	if !ok {
+		return
+	}
+
+	bots, err := postgres.ListBots(ownerID)
+	if err != nil {
+		logger.Warn(r.Context(), "Failed to list bots", zap.Error(err))
+		http.Error(w, "Failed to list bots", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]BotResponse, 0, len(bots))
+	for _, bot := range bots {
+		resp = append(resp, BotResponse{ID: bot.ID, Login: bot.Login, Name: bot.Name})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Warn(r.Context(), "Error in JSON encoder", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RotateBotToken выпускает боту новый токен, например, если старый утек
+func RotateBotToken(w http.ResponseWriter, r *http.Request) {
+	ownerID, ok := authorizeOwner(w, r)
+	if !ok {
+		return
+	}
+	botID, err := strconv.Atoi(r.PathValue("bot_id"))
+	if err != nil || botID <= 0 {
+		http.Error(w, "Invalid bot_id", http.StatusBadRequest)
+		return
+	}
+
+	token, err := newBotToken()
+	if err != nil {
+		logger.Warn(r.Context(), "Failed to create bot token", zap.Error(err))
+		http.Error(w, "Failed to create bot token", http.StatusInternalServerError)
+		return
+	}
+
+	bot, err := postgres.UpdateBotToken(ownerID, botID, token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Bot not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Warn(r.Context(), "Failed to update bot token", zap.Error(err))
+		http.Error(w, "Failed to update bot token", http.StatusInternalServerError)
+		return
+	}
+
+	writeBot(w, r, http.StatusOK, bot)
+}
+
+func DeleteBot(w http.ResponseWriter, r *http.Request) {
+	ownerID, ok := authorizeOwner(w, r)
+	if !ok {
+		return
+	}
+	botID, err := strconv.Atoi(r.PathValue("bot_id"))
+	if err != nil || botID <= 0 {
+		http.Error(w, "Invalid bot_id", http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := postgres.DeleteBot(ownerID, botID)
+	if err != nil {
+		logger.Warn(r.Context(), "Failed to delete bot", zap.Error(err))
+		http.Error(w, "Failed to delete bot", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Bot not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeOwner - authorize для управления ботами: бот не может заводить ботов,
+// а заблокированный пользователь - управлять ими. При ошибке ответ уже записан
+func authorizeOwner(w http.ResponseWriter, r *http.Request) (int, bool) {
+	tokenCookie, err := r.Cookie(JWTCookieName)
+	if err != nil {
+		http.Error(w, "Not logged in", http.StatusUnauthorized)
+		return 0, false
+	}
+	access, err := postgres.GetAccessByToken(tokenCookie.Value)
+	if err != nil {
+		logger.Info(r.Context(), "Failed to get user by token", zap.Error(err))
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return 0, false
+	}
+	if access.Role == postgres.RoleBot || access.Suspended {
+		http.Error(w, "Not allowed to manage bots", http.StatusForbidden)
+		return 0, false
+	}
+	return access.ID, true
+}
+
+func writeBot(w http.ResponseWriter, r *http.Request, code int, bot postgres.Bot) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(BotResponse{ID: bot.ID, Login: bot.Login, Name: bot.Name, Token: bot.Token}); err != nil {
+		logger.Warn(r.Context(), "Error in JSON encoder", zap.Error(err))
+	}
+}
+
+func validateBot(req CreateBotRequest) error {
+	if !botLoginPattern.MatchString(req.Login) {
+		return errors.New("login must be 5-30 latin letters, digits or '_' and end with 'bot'")
+	}
+	if len([]rune(req.Name)) > maxBotNameLen {
+		return errors.New("name is too long")
+	}
+	return nil
+}
+
+// newBotToken - бессрочный токен бота: префикс и 32 случайных байта
+func newBotToken() (string, error) {
+	secret := make([]byte, botTokenLen)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return BotTokenPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func isBotToken(token string) bool {
+	return strings.HasPrefix(token, BotTokenPrefix)
+}
@@ -0,0 +1,56 @@
+package transport
+
+import (
+	pbapi "auth_service/pkg/api"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateBot(t *testing.T) {
+	tests := []struct {
+		name	string
+		req		CreateBotRequest
+		wantErr	bool
+	}{
+		{"valid", CreateBotRequest{Login: "ci_alerts_bot", Name: "CI alerts"}, false},
+		{"upper case suffix", CreateBotRequest{Login: "HelpdeskBot"}, false},
+		{"no bot suffix", CreateBotRequest{Login: "helpdesk"}, true},
+		{"only suffix", CreateBotRequest{Login: "bot"}, true},
+		{"long login", CreateBotRequest{Login: strings.Repeat("a", 28) + "bot"}, true},
+		{"login with spaces", CreateBotRequest{Login: "my bot"}, true},
+		{"long name", CreateBotRequest{Login: "ci_bot", Name: strings.Repeat("a", 101)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateBot(tt.req); (err != nil) != tt.wantErr {
+				t.Error("validateBot() error:", err, "wantErr:", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewBotToken(t *testing.T) {
+	first, err := newBotToken()
+	if err != nil {
+		t.Fatal("newBotToken() error:", err)
+	}
+	second, _ := newBotToken()
+
+	if !isBotToken(first) || first == second {
+		t.Error("Unexpected bot tokens:", first, second)
+	}
+}
+
+// Токен, который не разбирается как JWT, отклоняется до обращения к базе
+func TestValidateGarbageToken(t *testing.T) {
+	JWTKey = "testkey"
+
+	resp, err := New().Validate(context.Background(), &pbapi.Token{Token: "not a token"})
+	if status.Code(err) != codes.Unauthenticated || resp.GetUserId() != -1 {
+		t.Error("Validate() response:", resp, "error:", err)
+	}
+}
@@ -1,5 +1,7 @@
 package transport
 
+import "time"
+
 var (
 	JWTKey string
 	JWTCookieName string = "user_jwt"
@@ -23,4 +25,37 @@ type LoginRequest struct {
 
 type LoginResponse struct {
 	Token		string	`json:"token"` // TODO: что надо отправлять после входа? То же, что и после регистрации?
-}
\ No newline at end of file
+}
+
+// DeviceKeys - публичные ключи устройства для сквозного шифрования, все ключи в base64
+type DeviceKeys struct {
+	DeviceID		string			`json:"device_id"`
+	IdentityKey		string			`json:"identity_key"`
+	SignedPrekey	SignedPrekey	`json:"signed_prekey"`
+	UpdatedAt		*time.Time		`json:"updated_at,omitempty"`
+}
+
+// SignedPrekey - prekey устройства, подписанный его ключом идентичности. Подпись проверяет клиент
+type SignedPrekey struct {
+	KeyID		int		`json:"key_id"`
+	PublicKey	string	`json:"public_key"`
+	Signature	string	`json:"signature"`
+}
+
+type DeviceKeysResponse struct {
+	UserID		int				`json:"user_id"`
+	Devices		[]DeviceKeys	`json:"devices"`
+}
+
+type CreateBotRequest struct {
+	Login	string	`json:"login"`
+	Name	string	`json:"name"`
+}
+
+// BotResponse - бот владельца. Token заполнен только в ответах на создание и перевыпуск токена
+type BotResponse struct {
+	ID		int		`json:"id"`
+	Login	string	`json:"login"`
+	Name	string	`json:"name"`
+	Token	string	`json:"token,omitempty"`
+}
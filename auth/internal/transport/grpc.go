@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"time"
 )
 
@@ -22,16 +24,41 @@ func New() *Service {
 }
 
 func (x *Service) Validate(ctx context.Context, in *pbapi.Token) (*pbapi.ValidateResponse, error) {
-	fmt.Println("token: ", in.GetToken())
-	token, err := jwt.ParseWithClaims(in.GetToken(), &jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+	if isBotToken(in.GetToken()) {
+		return validateBotToken(in.GetToken())
+	}
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(in.GetToken(), &claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(JWTKey), nil
 	})
+	if token == nil {
+		return &pbapi.ValidateResponse{UserId: -1}, status.Error(codes.Unauthenticated, "invalid token")
+	}
 	exp, _ := token.Claims.GetExpirationTime()
 	if exp.Before(time.Now()) {
 		return &pbapi.ValidateResponse{UserId: -1}, fmt.Errorf("Token has expired")
 	}
-	id, err := postgres.GetIdByToken(in.GetToken())
-	return &pbapi.ValidateResponse{UserId: int32(id)}, err
+	access, err := postgres.GetAccessByToken(in.GetToken())
+	if err != nil {
+		return &pbapi.ValidateResponse{UserId: -1}, err
+	}
+	if access.Suspended {
+		return &pbapi.ValidateResponse{UserId: -1}, status.Error(codes.PermissionDenied, "user is suspended")
+	}
+	// Роль берется из базы, а не из claims: снятая роль перестает действовать сразу
+	return &pbapi.ValidateResponse{UserId: int32(access.ID), Role: access.Role}, nil
+}
+
+// validateBotToken проверяет API-токен бота: он бессрочный, поэтому достаточно найти его в базе
+func validateBotToken(token string) (*pbapi.ValidateResponse, error) {
+	access, err := postgres.GetAccessByToken(token)
+	if err != nil {
+		return &pbapi.ValidateResponse{UserId: -1}, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if access.Suspended {
+		return &pbapi.ValidateResponse{UserId: -1}, status.Error(codes.PermissionDenied, "user is suspended")
+	}
+	return &pbapi.ValidateResponse{UserId: int32(access.ID), Role: access.Role}, nil
 }
 
 func (x *Service) GetUser(ctx context.Context, in *pbapi.Token) (*pbapi.GetUserResponse, error) {
@@ -39,6 +66,40 @@ func (x *Service) GetUser(ctx context.Context, in *pbapi.Token) (*pbapi.GetUserR
 	return &pbapi.GetUserResponse{Login: login, Email: email, Name: name}, err
 }
 
+// GetSessions возвращает сессию, выданную по токену, и устройства пользователя с опубликованными ключами
+func (x *Service) GetSessions(ctx context.Context, in *pbapi.Token) (*pbapi.GetSessionsResponse, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(in.GetToken(), &claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(JWTKey), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, err := postgres.GetIdByToken(in.GetToken())
+	if err != nil {
+		return nil, err
+	}
+
+	session := &pbapi.Session{}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		session.IssuedAt = iat.Unix()
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		session.ExpiresAt = exp.Unix()
+	}
+
+	keys, err := postgres.GetDeviceKeys(id)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]*pbapi.Device, 0, len(keys))
+	for _, k := range keys {
+		devices = append(devices, &pbapi.Device{DeviceId: k.DeviceID, UpdatedAt: k.UpdatedAt.Unix()})
+	}
+
+	return &pbapi.GetSessionsResponse{Sessions: []*pbapi.Session{session}, Devices: devices}, nil
+}
+
 func UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	guid := uuid.New().String()
 	ctx, _ = logger.New(ctx)
@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"auth_service/pkg/logger"
+	"auth_service/pkg/postgres"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxDevices - сколько устройств пользователя может быть в каталоге ключей
+	MaxDevices = 10
+
+	// Размеры ключей Curve25519/Ed25519: 32 байта или 33 с префиксом типа ключа, как в libsignal
+	keyLen			= 32
+	typedKeyLen		= 33
+	signatureLen	= 64
+)
+
+var deviceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// UploadKeys публикует ключи устройства текущего пользователя. Повторная публикация заменяет ключи устройства
+func UploadKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req DeviceKeys
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(r.Context(), "Error in JSON decoder", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if err := validateDeviceKeys(req); err != nil {
+		logger.Info(r.Context(), "Invalid device keys", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := postgres.UpsertDeviceKeys(userID, postgres.DeviceKeys{
+		DeviceID:				req.DeviceID,
+		IdentityKey:			req.IdentityKey,
+		SignedPrekeyID:			req.SignedPrekey.KeyID,
+		SignedPrekey:			req.SignedPrekey.PublicKey,
+		SignedPrekeySignature:	req.SignedPrekey.Signature,
+	}, MaxDevices)
+	if errors.Is(err, postgres.ErrTooManyDevices) {
+		http.Error(w, "Too many devices", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Warn(r.Context(), "Failed to save device keys", zap.Error(err))
+		http.Error(w, "Failed to save device keys", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetKeys отдает ключи всех устройств пользователя user_id, чтобы зашифровать для них сообщение
+func GetKeys(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authorize(w, r); !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil || userID <= 0 {
+		http.Error(w, "Invalid user_id", http.StatusBadRequest)
+		return
+	}
+
+	keys, err := postgres.GetDeviceKeys(userID)
+	if err != nil {
+		logger.Warn(r.Context(), "Failed to get device keys", zap.Error(err))
+		http.Error(w, "Failed to get device keys", http.StatusInternalServerError)
+		return
+	}
+
+	resp := DeviceKeysResponse{UserID: userID, Devices: make([]DeviceKeys, 0, len(keys))}
+	for _, k := range keys {
+		resp.Devices = append(resp.Devices, DeviceKeys{
+			DeviceID:		k.DeviceID,
+			IdentityKey:	k.IdentityKey,
+			SignedPrekey:	SignedPrekey{KeyID: k.SignedPrekeyID, PublicKey: k.SignedPrekey, Signature: k.SignedPrekeySignature},
+			UpdatedAt:		&k.UpdatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Warn(r.Context(), "Error in JSON encoder", zap.Error(err))
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteKeys убирает устройство текущего пользователя из каталога, например при выходе на устройстве
+func DeleteKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authorize(w, r)
+	if !ok {
+		return
+	}
+
+	deviceID := r.PathValue("device_id")
+	if !deviceIDPattern.MatchString(deviceID) {
+		http.Error(w, "Invalid device_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := postgres.DeleteDeviceKeys(userID, deviceID); err != nil {
+		logger.Warn(r.Context(), "Failed to delete device keys", zap.Error(err))
+		http.Error(w, "Failed to delete device keys", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorize достает id пользователя по токену из куки. При ошибке ответ уже записан
+func authorize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	tokenCookie, err := r.Cookie(JWTCookieName)
+	if err != nil {
+		http.Error(w, "Not logged in", http.StatusUnauthorized)
+		return 0, false
+	}
+	userID, err := postgres.GetIdByToken(tokenCookie.Value)
+	if err != nil {
+		logger.Info(r.Context(), "Failed to get user by token", zap.Error(err))
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return 0, false
+	}
+	return userID, true
+}
+
+// validateDeviceKeys проверяет формат и размеры ключей. Криптографическую подпись prekey сервер не проверяет
+func validateDeviceKeys(keys DeviceKeys) error {
+	if !deviceIDPattern.MatchString(keys.DeviceID) {
+		return errors.New("device_id must be 1-64 latin letters, digits, '-' or '_'")
+	}
+	if keys.SignedPrekey.KeyID < 0 {
+		return errors.New("invalid signed_prekey.key_id")
+	}
+	if !validKey(keys.IdentityKey) {
+		return errors.New("invalid identity_key")
+	}
+	if !validKey(keys.SignedPrekey.PublicKey) {
+		return errors.New("invalid signed_prekey.public_key")
+	}
+	signature, err := base64.StdEncoding.DecodeString(keys.SignedPrekey.Signature)
+	if err != nil || len(signature) != signatureLen {
+		return errors.New("invalid signed_prekey.signature")
+	}
+	return nil
+}
+
+func validKey(s string) bool {
+	key, err := base64.StdEncoding.DecodeString(s)
+	return err == nil && (len(key) == keyLen || len(key) == typedKeyLen)
+}
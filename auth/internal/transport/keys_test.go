@@ -0,0 +1,44 @@
+package transport
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestValidateDeviceKeys(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	typedKey := base64.StdEncoding.EncodeToString(append([]byte{0x05}, make([]byte, 32)...))
+	signature := base64.StdEncoding.EncodeToString(make([]byte, 64))
+
+	valid := DeviceKeys{
+		DeviceID:		"phone-1",
+		IdentityKey:	typedKey,
+		SignedPrekey:	SignedPrekey{KeyID: 1, PublicKey: key, Signature: signature},
+	}
+
+	tests := []struct {
+		name	string
+		modify	func(k *DeviceKeys)
+		wantErr	bool
+	}{
+		{"valid", func(k *DeviceKeys) {}, false},
+		{"empty device id", func(k *DeviceKeys) { k.DeviceID = "" }, true},
+		{"long device id", func(k *DeviceKeys) { k.DeviceID = strings.Repeat("a", 65) }, true},
+		{"device id with spaces", func(k *DeviceKeys) { k.DeviceID = "my phone" }, true},
+		{"short identity key", func(k *DeviceKeys) { k.IdentityKey = base64.StdEncoding.EncodeToString(make([]byte, 16)) }, true},
+		{"identity key is not base64", func(k *DeviceKeys) { k.IdentityKey = "not base64!" }, true},
+		{"missing prekey", func(k *DeviceKeys) { k.SignedPrekey.PublicKey = "" }, true},
+		{"short signature", func(k *DeviceKeys) { k.SignedPrekey.Signature = key }, true},
+		{"negative key id", func(k *DeviceKeys) { k.SignedPrekey.KeyID = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := valid
+			tt.modify(&keys)
+			if err := validateDeviceKeys(keys); (err != nil) != tt.wantErr {
+				t.Error("validateDeviceKeys() error:", err, "wantErr:", tt.wantErr)
+			}
+		})
+	}
+}
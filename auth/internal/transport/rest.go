@@ -12,6 +12,7 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 )
 
 func Register(w http.ResponseWriter, r *http.Request) {
@@ -30,12 +31,7 @@ func Register(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-			"sub": req.Login,
-			"exp": time.Now().Add(7*24*time.Hour).Unix(), // Неделя - "7 раз по 24 часа"
-			"iat": time.Now().Unix(),
-		})
-		stringKey, err := claims.SignedString([]byte(JWTKey))
+		stringKey, err := newToken(req.Login, postgres.RoleUser)
 		if err != nil {
 			logger.Warn(r.Context(), "Failed to create JWT token", zap.Error(err))
 			http.Error(w, "Failed to create JWT token", http.StatusInternalServerError)
@@ -89,6 +85,12 @@ func Login(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Failed to get user record", http.StatusInternalServerError)
 			return
 		}
+		stringKey, err = refreshRole(stringKey)
+		if err != nil {
+			logger.Warn(r.Context(), "Failed to refresh JWT token", zap.Error(err))
+			http.Error(w, "Failed to create JWT token", http.StatusInternalServerError)
+			return
+		}
 
 		http.SetCookie(w, &http.Cookie{
 			Name:    JWTCookieName,
@@ -153,6 +155,46 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 	Logout(w, r)
 }
 
+func newToken(login, role string) (string, error) {
+	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": login,
+		"role": role,
+		"exp": time.Now().Add(7*24*time.Hour).Unix(), // Неделя - "7 раз по 24 часа"
+		"iat": time.Now().Unix(),
+	})
+	return claims.SignedString([]byte(JWTKey))
+}
+
+// refreshRole перевыпускает токен, если роль в его claims разошлась с ролью в базе:
+// назначенный администратор получает права при следующем входе
+func refreshRole(token string) (string, error) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, &claims, keyFunc); err != nil && !errors.Is(err, jwt.ErrTokenExpired) {
+		return "", err
+	}
+	access, err := postgres.GetAccessByToken(token)
+	if err != nil {
+		return "", err
+	}
+	if role, _ := claims["role"].(string); role == access.Role {
+		return token, nil
+	}
+
+	login, _ := claims.GetSubject()
+	newKey, err := newToken(login, access.Role)
+	if err != nil {
+		return "", err
+	}
+	if err := postgres.UpdateToken(token, newKey); err != nil {
+		return "", err
+	}
+	return newKey, nil
+}
+
+func keyFunc(token *jwt.Token) (interface{}, error) {
+	return []byte(JWTKey), nil
+}
+
 func MiddlewareHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func (w http.ResponseWriter, r *http.Request) {
 		guid := uuid.New().String()
@@ -8,11 +8,21 @@ import (
 	migrate "github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
 )
 
+// uniqueViolation - код ошибки Postgres при нарушении уникальности
+const uniqueViolation = "23505"
+
 var (
 	PGXPool *pgxpool.Pool
+
+	ErrTooManyDevices = errors.New("too many devices")
+	ErrTooManyBots    = errors.New("too many bots")
+	ErrLoginTaken     = errors.New("login is already taken")
 )
 
 func New(ctx context.Context, cfg config.PGConfig, path string) (*pgxpool.Pool, error) {
@@ -87,3 +97,222 @@ func GetRecordByToken(token string) (string, string, string, error) {
 	err := PGXPool.QueryRow(context.Background(), "SELECT login, email, name FROM auth_schema.users WHERE token=$1;", token).Scan(&ret.Login, &ret.Email, &ret.Name)
 	return ret.Login, ret.Email, ret.Name, err
 }
+
+// DeviceKeys - публичные ключи устройства пользователя, ключи и подпись в base64
+type DeviceKeys struct {
+	DeviceID              string
+	IdentityKey           string
+	SignedPrekeyID        int
+	SignedPrekey          string
+	SignedPrekeySignature string
+	UpdatedAt             time.Time
+}
+
+// UpsertDeviceKeys сохраняет ключи устройства. Новое устройство не добавляется, если у пользователя
+// уже maxDevices устройств: тогда возвращается ErrTooManyDevices
+func UpsertDeviceKeys(userID int, keys DeviceKeys, maxDevices int) error {
+	tag, err := PGXPool.Exec(context.Background(), `
+		INSERT INTO auth_schema.device_keys (user_id, device_id, identity_key, signed_prekey_id, signed_prekey, signed_prekey_signature)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE EXISTS (SELECT 1 FROM auth_schema.device_keys WHERE user_id=$1 AND device_id=$2)
+		   OR (SELECT COUNT(*) FROM auth_schema.device_keys WHERE user_id=$1) < $7
+		ON CONFLICT (user_id, device_id) DO UPDATE
+		SET identity_key=EXCLUDED.identity_key,
+		    signed_prekey_id=EXCLUDED.signed_prekey_id,
+		    signed_prekey=EXCLUDED.signed_prekey,
+		    signed_prekey_signature=EXCLUDED.signed_prekey_signature,
+		    updated_at=NOW();`,
+		userID, keys.DeviceID, keys.IdentityKey, keys.SignedPrekeyID, keys.SignedPrekey, keys.SignedPrekeySignature, maxDevices)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTooManyDevices
+	}
+	return nil
+}
+
+func GetDeviceKeys(userID int) ([]DeviceKeys, error) {
+	rows, err := PGXPool.Query(context.Background(), "SELECT device_id, identity_key, signed_prekey_id, signed_prekey, signed_prekey_signature, updated_at FROM auth_schema.device_keys WHERE user_id=$1 ORDER BY device_id;", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := []DeviceKeys{}
+	for rows.Next() {
+		var keys DeviceKeys
+		if err := rows.Scan(&keys.DeviceID, &keys.IdentityKey, &keys.SignedPrekeyID, &keys.SignedPrekey, &keys.SignedPrekeySignature, &keys.UpdatedAt); err != nil {
+			return nil, err
+		}
+		ret = append(ret, keys)
+	}
+	return ret, rows.Err()
+}
+
+func DeleteDeviceKeys(userID int, deviceID string) error {
+	_, err := PGXPool.Exec(context.Background(), "DELETE FROM auth_schema.device_keys WHERE user_id=$1 AND device_id=$2;", userID, deviceID)
+	return err
+}
+
+// Роли пользователей
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+	RoleBot   = "bot"
+)
+
+// User - учетная запись для администрирования. SuspendedUntil пустой при бессрочной блокировке
+type User struct {
+	ID             int
+	Login          string
+	Email          string
+	Name           string
+	Role           string
+	Suspended      bool
+	SuspendedUntil *time.Time
+	SuspendReason  string
+}
+
+// Access - то, что нужно для проверки токена: пользователь, его роль и действует ли блокировка
+type Access struct {
+	ID        int
+	Role      string
+	Suspended bool
+}
+
+const userColumns = `id, login, COALESCE(email, ''), COALESCE(name, ''), role,
+	suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > NOW()),
+	suspended_until, COALESCE(suspend_reason, '')`
+
+func scanUser(row pgx.Row) (User, error) {
+	var u User
+	err := row.Scan(&u.ID, &u.Login, &u.Email, &u.Name, &u.Role, &u.Suspended, &u.SuspendedUntil, &u.SuspendReason)
+	if !u.Suspended {
+		u.SuspendedUntil, u.SuspendReason = nil, ""
+	}
+	return u, err
+}
+
+func GetAccessByToken(token string) (Access, error) {
+	var a Access
+	err := PGXPool.QueryRow(context.Background(), `
+		SELECT id, role, suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > NOW())
+		FROM auth_schema.users WHERE token=$1;`, token).Scan(&a.ID, &a.Role, &a.Suspended)
+	return a, err
+}
+
+// UpdateToken заменяет токен пользователя, например, после смены роли
+func UpdateToken(oldToken, newToken string) error {
+	_, err := PGXPool.Exec(context.Background(), "UPDATE auth_schema.users SET token=$2 WHERE token=$1;", oldToken, newToken)
+	return err
+}
+
+// ListUsers ищет пользователей по подстроке логина, email или имени либо по id
+func ListUsers(query string, limit, offset int) ([]User, error) {
+	rows, err := PGXPool.Query(context.Background(), `
+		SELECT `+userColumns+`
+		FROM auth_schema.users
+		WHERE $1::text = ''
+		   OR login ILIKE '%' || $1::text || '%' OR email ILIKE '%' || $1::text || '%' OR name ILIKE '%' || $1::text || '%'
+		   OR id::text = $1::text
+		ORDER BY id
+		LIMIT $2 OFFSET $3;`, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := []User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, u)
+	}
+	return ret, rows.Err()
+}
+
+// SuspendUser блокирует пользователя до until, nil - бессрочно. Повторная блокировка заменяет предыдущую
+func SuspendUser(id int, until *time.Time, reason string) (User, error) {
+	return scanUser(PGXPool.QueryRow(context.Background(), `
+		UPDATE auth_schema.users
+		SET suspended_at=NOW(), suspended_until=$2, suspend_reason=$3
+		WHERE id=$1
+		RETURNING `+userColumns+`;`, id, until, reason))
+}
+
+func UnsuspendUser(id int) (User, error) {
+	return scanUser(PGXPool.QueryRow(context.Background(), `
+		UPDATE auth_schema.users
+		SET suspended_at=NULL, suspended_until=NULL, suspend_reason=NULL
+		WHERE id=$1
+		RETURNING `+userColumns+`;`, id))
+}
+
+// Bot - учетная запись бота. Токен отдается только при создании и перевыпуске
+type Bot struct {
+	ID      int
+	Login   string
+	Name    string
+	OwnerID int
+	Token   string
+}
+
+// InsertBot создает бота владельца ownerID. Бот не создается, если у владельца уже maxBots ботов:
+// тогда возвращается ErrTooManyBots. Пароль пустой, поэтому войти ботом по логину нельзя
+func InsertBot(ownerID int, token, login, name string, maxBots int) (Bot, error) {
+	bot := Bot{Login: login, Name: name, OwnerID: ownerID, Token: token}
+	err := PGXPool.QueryRow(context.Background(), `
+		INSERT INTO auth_schema.users (token, login, pass, name, role, owner_id)
+		SELECT $2, $3, '', $4, 'bot', $1
+		WHERE (SELECT COUNT(*) FROM auth_schema.users WHERE owner_id=$1) < $5
+		RETURNING id;`, ownerID, token, login, name, maxBots).Scan(&bot.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Bot{}, ErrTooManyBots
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return Bot{}, ErrLoginTaken
+	}
+	return bot, err
+}
+
+func ListBots(ownerID int) ([]Bot, error) {
+	rows, err := PGXPool.Query(context.Background(), "SELECT id, login, COALESCE(name, ''), owner_id FROM auth_schema.users WHERE owner_id=$1 ORDER BY id;", ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := []Bot{}
+	for rows.Next() {
+		var bot Bot
+		if err := rows.Scan(&bot.ID, &bot.Login, &bot.Name, &bot.OwnerID); err != nil {
+			return nil, err
+		}
+		ret = append(ret, bot)
+	}
+	return ret, rows.Err()
+}
+
+// UpdateBotToken перевыпускает токен бота, старый сразу перестает проходить Validate.
+// Чужой или несуществующий бот - pgx.ErrNoRows
+func UpdateBotToken(ownerID, botID int, token string) (Bot, error) {
+	bot := Bot{Token: token}
+	err := PGXPool.QueryRow(context.Background(), `
+		UPDATE auth_schema.users SET token=$3
+		WHERE id=$2 AND owner_id=$1
+		RETURNING id, login, COALESCE(name, ''), owner_id;`, ownerID, botID, token).Scan(&bot.ID, &bot.Login, &bot.Name, &bot.OwnerID)
+	return bot, err
+}
+
+// DeleteBot удаляет бота владельца, false - такого бота у владельца нет
+func DeleteBot(ownerID, botID int) (bool, error) {
+	tag, err := PGXPool.Exec(context.Background(), "DELETE FROM auth_schema.users WHERE id=$2 AND owner_id=$1;", ownerID, botID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
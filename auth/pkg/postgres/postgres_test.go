@@ -202,4 +202,69 @@ func TestGetRecordByToken(t *testing.T) {
 		t.Error("Expected: dblogin,dbemail dbname, got", dblogin, dbemail, dbname)
 		return
 	}
-}
\ No newline at end of file
+}
+
+func TestDeviceKeys(t *testing.T) {
+	cfg, err := config.New("../../config/config_test.env")
+	if err != nil {
+		t.Error("Error when loading config: ", err)
+		return
+	}
+
+	_, err = New(context.Background(), (*cfg).Postgres, "../../db/migrations")
+	if err != nil {
+		t.Error("Error when executing New(): ", err)
+		return
+	}
+	defer PGXPool.Close()
+
+	id := 0
+	err = PGXPool.QueryRow(context.Background(), "INSERT INTO auth_schema.users (token, login, email, pass, name) VALUES ('testtoken', 'testlogin', 'testemail', 'testpass', 'testname') RETURNING id;").Scan(&id)
+	if err != nil {
+		t.Error("Error when inserting user: ", err)
+		return
+	}
+	defer PGXPool.Exec(context.Background(), "DELETE FROM auth_schema.users WHERE id=$1;", id)
+
+	phone := DeviceKeys{DeviceID: "phone", IdentityKey: "ik1", SignedPrekeyID: 1, SignedPrekey: "spk1", SignedPrekeySignature: "sig1"}
+	laptop := DeviceKeys{DeviceID: "laptop", IdentityKey: "ik2", SignedPrekeyID: 1, SignedPrekey: "spk2", SignedPrekeySignature: "sig2"}
+	if err := UpsertDeviceKeys(id, phone, 2); err != nil {
+		t.Error("Error when executing UpsertDeviceKeys(): ", err)
+		return
+	}
+	if err := UpsertDeviceKeys(id, laptop, 2); err != nil {
+		t.Error("Error when executing UpsertDeviceKeys(): ", err)
+		return
+	}
+
+	// Третье устройство не помещается в лимит, а обновление существующего проходит
+	if err := UpsertDeviceKeys(id, DeviceKeys{DeviceID: "tablet", IdentityKey: "ik3", SignedPrekey: "spk3", SignedPrekeySignature: "sig3"}, 2); err != ErrTooManyDevices {
+		t.Error("Expected ErrTooManyDevices, got", err)
+		return
+	}
+	phone.SignedPrekeyID, phone.SignedPrekey = 2, "spk1-rotated"
+	if err := UpsertDeviceKeys(id, phone, 2); err != nil {
+		t.Error("Error when rotating prekey: ", err)
+		return
+	}
+
+	keys, err := GetDeviceKeys(id)
+	if err != nil {
+		t.Error("Error when executing GetDeviceKeys(): ", err)
+		return
+	}
+	if len(keys) != 2 || keys[0].DeviceID != "laptop" || keys[1].SignedPrekey != "spk1-rotated" || keys[1].SignedPrekeyID != 2 {
+		t.Error("Unexpected device keys:", keys)
+		return
+	}
+
+	if err := DeleteDeviceKeys(id, "laptop"); err != nil {
+		t.Error("Error when executing DeleteDeviceKeys(): ", err)
+		return
+	}
+	keys, err = GetDeviceKeys(id)
+	if err != nil || len(keys) != 1 {
+		t.Error("Expected one device after delete, got", keys, err)
+		return
+	}
+}
@@ -2,14 +2,29 @@ package main
 
 import (
 	"chat/internal/config"
+	"chat/internal/contentfilter"
+	"chat/internal/events"
+	"chat/internal/export"
+	"chat/internal/linkpreview"
+	"chat/internal/notifications"
+	"chat/internal/retention"
+	"chat/internal/scheduler"
 	"chat/internal/service"
+	"chat/internal/storage/filesystem"
+	"chat/internal/storage/memory"
 	"chat/internal/storage/postgresql"
+	"chat/internal/storage/s3"
 	"chat/internal/transport/grpc/auth"
+	"chat/internal/transport/grpc/server"
 	"chat/internal/transport/http"
+	"chat/internal/transport/realtime"
+	"chat/internal/transport/webpush"
+	"chat/internal/webhooks"
 	"chat/pkg/logger"
 	"chat/pkg/migrator"
 	"chat/pkg/pg"
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -43,14 +58,61 @@ func main() {
 		os.Exit(1)
 	}
 
+	blobStore, err := newBlobStore(ctx, cfg)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to create blob store: %v", err)
+		os.Exit(1)
+	}
+
 	chatStorage := postgresql.New(pgConn)
-	chatService := service.New(chatStorage)
+	// События уходят и во внешний realtime-сервис, и подписчикам gRPC-стримов этой реплики
+	hub := events.NewHub()
+	realtimeClient := realtime.New(cfg.Realtime)
+	notifier := events.Fanout{realtimeClient, hub}
+
+	bgCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+	if cfg.WebPush.Enabled() {
+		pushClient, err := webpush.New(cfg.WebPush)
+		if err != nil {
+			l.ErrorContext(ctx, "failed to create web push client: %v", err)
+			os.Exit(1)
+		}
+		pusher := notifications.New(cfg.Notifications, chatStorage, pushClient, realtimeClient)
+		go pusher.Run(bgCtx)
+		notifier = append(notifier, pusher)
+	}
+
+	// Карточки ссылок рассылаются напрямую, мимо push-уведомлений
+	previewer := linkpreview.New(cfg.LinkPreview, chatStorage, linkpreview.NewFetcher(cfg.LinkPreview), events.Fanout{realtimeClient, hub})
+	go previewer.Run(bgCtx)
+	notifier = append(notifier, previewer)
+
+	// События о сообщениях в чатах ботов уходят и на их вебхуки
+	dispatcher := webhooks.New(cfg.Webhooks, chatStorage)
+	go dispatcher.Run(bgCtx)
+	notifier = append(notifier, dispatcher)
 
 	authClient := auth.New(cfg.Auth)
 
+	filter, err := newContentFilter(cfg)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to load content filter: %v", err)
+		os.Exit(1)
+	}
+
+	chatService := service.New(cfg.Service, chatStorage, notifier, blobStore, memory.NewRateLimiter(), hub, authClient, authClient, filter)
+
+	go retention.New(cfg.Retention, chatService).Run(bgCtx)
+	go export.NewWorker(cfg.Export, chatService).Run(bgCtx)
+	go scheduler.New(cfg.Scheduler, chatService).Run(bgCtx)
+
 	server := httpserver.New(ctx, cfg.HTTPServer, chatService, authClient)
 	go server.MustRun()
 
+	grpcServer := grpcserver.New(ctx, cfg.GRPCServer, chatService, authClient)
+	go grpcServer.MustRun()
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
@@ -58,7 +120,30 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if err := grpcServer.Shutdown(ctx); err != nil {
+		l.ErrorContext(ctx, "failed to shutdown grpc server: %v", err)
+	}
 	if err := server.Shutdown(ctx); err != nil {
 		return
 	}
 }
+
+func newBlobStore(ctx context.Context, cfg *config.Config) (service.BlobStore, error) {
+	switch cfg.BlobStore {
+	case "local":
+		return filesystem.New(cfg.Filesystem)
+	case "s3":
+		return s3.New(ctx, cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.BlobStore)
+	}
+}
+
+// newContentFilter возвращает nil-интерфейс, если список слов не задан: сервис тогда не проверяет текст
+func newContentFilter(cfg *config.Config) (service.ContentFilter, error) {
+	wordList, err := contentfilter.New(cfg.ContentFilter)
+	if err != nil || wordList == nil {
+		return nil, err
+	}
+	return wordList, nil
+}
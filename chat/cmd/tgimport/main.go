@@ -0,0 +1,104 @@
+// tgimport переносит историю личных чатов из экспорта Telegram Desktop (result.json).
+// Пользователи Telegram сопоставляются с пользователями мессенджера явным файлом -mapping,
+// отчет о перенесенных и пропущенных чатах и сообщениях выводится в JSON
+package main
+
+import (
+	"chat/internal/config"
+	"chat/internal/storage/postgresql"
+	"chat/internal/telegram"
+	"chat/pkg/migrator"
+	"chat/pkg/pg"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	exportPath := flag.String("export", "result.json", "путь к result.json из экспорта Telegram Desktop")
+	mappingPath := flag.String("mapping", "mapping.json", `сопоставление пользователей: {"user123456": 1, ...}`)
+	dryRun := flag.Bool("dry-run", false, "только проверить экспорт и вывести отчет, ничего не записывая")
+	reportPath := flag.String("report", "", "куда записать отчет; по умолчанию stdout")
+	flag.Parse()
+
+	if err := run(*exportPath, *mappingPath, *reportPath, *dryRun); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(exportPath, mappingPath, reportPath string, dryRun bool) error {
+	cfg, err := config.MustLoad()
+	if err != nil {
+		return err
+	}
+
+	export, err := readFile(exportPath, telegram.Parse)
+	if err != nil {
+		return err
+	}
+	mapping, err := readFile(mappingPath, telegram.LoadMapping)
+	if err != nil {
+		return err
+	}
+
+	opts := telegram.Options{DryRun: dryRun, MaxMessageLength: cfg.Service.MaxMessageLength}
+	var store telegram.Store
+	if !dryRun {
+		pgConn, err := pg.New(cfg.Postgres)
+		if err != nil {
+			return err
+		}
+		defer pgConn.Close()
+
+		m, err := migrator.New("migrations", cfg.Postgres)
+		if err != nil {
+			return fmt.Errorf("failed to create migrator: %w", err)
+		}
+		if err := m.Up(); err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+
+		store = postgresql.New(pgConn)
+	}
+
+	report, importErr := telegram.NewImporter(store, mapping, opts).Import(context.Background(), export)
+	if err := writeReport(reportPath, report); err != nil {
+		return err
+	}
+	return importErr
+}
+
+func readFile[T any](path string, parse func(io.Reader) (T, error)) (T, error) {
+	var zero T
+	f, err := os.Open(path)
+	if err != nil {
+		return zero, err
+	}
+	defer f.Close()
+
+	v, err := parse(f)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
+
+func writeReport(path string, report telegram.Report) error {
+	out := os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,21 @@
+// vapidkeys генерирует пару VAPID-ключей для push-уведомлений в формате example.env
+package main
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+func main() {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to generate key:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("VAPID_PUBLIC_KEY: %s\n", base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()))
+	fmt.Printf("VAPID_PRIVATE_KEY: %s\n", base64.RawURLEncoding.EncodeToString(key.Bytes()))
+}
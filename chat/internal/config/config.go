@@ -1,8 +1,21 @@
 package config
 
 import (
+	"chat/internal/contentfilter"
+	"chat/internal/export"
+	"chat/internal/linkpreview"
+	"chat/internal/notifications"
+	"chat/internal/retention"
+	"chat/internal/scheduler"
+	"chat/internal/service"
+	"chat/internal/storage/filesystem"
+	"chat/internal/storage/s3"
 	"chat/internal/transport/grpc/auth"
+	"chat/internal/transport/grpc/server"
 	"chat/internal/transport/http"
+	"chat/internal/transport/realtime"
+	"chat/internal/transport/webpush"
+	"chat/internal/webhooks"
 	"chat/pkg/pg"
 
 	"github.com/caarlos0/env/v11"
@@ -11,8 +24,27 @@ import (
 
 type Config struct {
 	HTTPServer httpserver.Config
+	GRPCServer grpcserver.Config
 	Postgres   pg.Config
 	Auth       auth.Config
+	Realtime   realtime.Config
+	Service    service.Config
+
+	ContentFilter contentfilter.Config
+	LinkPreview   linkpreview.Config
+	Webhooks      webhooks.Config
+
+	WebPush       webpush.Config
+	Notifications notifications.Config
+
+	Retention retention.Config
+	Export    export.Config
+	Scheduler scheduler.Config
+
+	// BlobStore - хранилище вложений: local или s3
+	BlobStore  string `env:"BLOB_STORE" envDefault:"local"`
+	Filesystem filesystem.Config
+	S3         s3.Config
 }
 
 func MustLoad() (*Config, error) {
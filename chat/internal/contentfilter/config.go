@@ -0,0 +1,6 @@
+package contentfilter
+
+type Config struct {
+	// WordsFile - файл со списком запрещенных слов и фраз, по одному на строку; пустой - фильтр выключен
+	WordsFile string `env:"CONTENT_FILTER_WORDS_FILE"`
+}
@@ -0,0 +1,114 @@
+package contentfilter
+
+import (
+	"bufio"
+	"chat/internal/domain"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// WordList отклоняет сообщения, в которых встречается слово или фраза из списка. Сравнение идет по целым словам
+// без учета регистра, ё не отличается от е. Слово со звездочкой на конце ("спам*") совпадает с любым словом,
+// которое с него начинается, - так одной строкой покрываются все падежные формы
+type WordList struct {
+	entries [][]pattern
+}
+
+type pattern struct {
+	word   string
+	prefix bool
+}
+
+// NewWordList собирает фильтр из слов и фраз. Пустые строки пропускаются
+func NewWordList(words []string) *WordList {
+	wl := &WordList{}
+	for _, w := range words {
+		var entry []pattern
+		for _, token := range tokenize(strings.TrimSuffix(strings.TrimSpace(w), "*")) {
+			entry = append(entry, pattern{word: token})
+		}
+		if len(entry) == 0 {
+			continue
+		}
+		entry[len(entry)-1].prefix = strings.HasSuffix(strings.TrimSpace(w), "*")
+		wl.entries = append(wl.entries, entry)
+	}
+	return wl
+}
+
+// LoadWordList читает список по одной записи на строку, строки с # - комментарии
+func LoadWordList(r io.Reader) (*WordList, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+	return NewWordList(words), nil
+}
+
+// New загружает фильтр из cfg.WordsFile. Без файла возвращает nil: фильтр не нужен
+func New(cfg Config) (*WordList, error) {
+	if cfg.WordsFile == "" {
+		return nil, nil
+	}
+	f, err := os.Open(cfg.WordsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadWordList(f)
+}
+
+func (wl *WordList) Len() int {
+	return len(wl.entries)
+}
+
+// Check возвращает ErrContentRejected, если в тексте есть запись из списка. Какая именно, не сообщается,
+// чтобы список нельзя было подобрать перебором
+func (wl *WordList) Check(_ context.Context, text string) error {
+	tokens := tokenize(text)
+	for i := range tokens {
+		for _, entry := range wl.entries {
+			if matchAt(tokens[i:], entry) {
+				return domain.ErrContentRejected
+			}
+		}
+	}
+	return nil
+}
+
+func matchAt(tokens []string, entry []pattern) bool {
+	if len(tokens) < len(entry) {
+		return false
+	}
+	for i, p := range entry {
+		if p.prefix {
+			if !strings.HasPrefix(tokens[i], p.word) {
+				return false
+			}
+		} else if tokens[i] != p.word {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize разбивает текст на слова из букв и цифр в нижнем регистре
+func tokenize(text string) []string {
+	text = strings.NewReplacer("ё", "е", "Ё", "е").Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
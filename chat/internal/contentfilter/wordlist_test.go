@@ -0,0 +1,60 @@
+package contentfilter
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWordList_Check(t *testing.T) {
+	wl, err := LoadWordList(strings.NewReader(`
+# комментарий
+казино
+спам*
+buy now
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wl.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", wl.Len())
+	}
+
+	tests := []struct {
+		text     string
+		rejected bool
+	}{
+		{"Лучшее КАЗИНО онлайн", true},
+		{"казиноман", false},
+		{"хватит спамить!", true},
+		{"Buy   NOW, limited offer", true},
+		{"buy it now", false},
+		{"обычное сообщение", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			err := wl.Check(context.Background(), tt.text)
+			if got := errors.Is(err, domain.ErrContentRejected); got != tt.rejected {
+				t.Errorf("Check(%q) = %v, rejected %v", tt.text, err, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestWordList_YoIsE(t *testing.T) {
+	wl := NewWordList([]string{"ёлка"})
+
+	if err := wl.Check(context.Background(), "Елка"); !errors.Is(err, domain.ErrContentRejected) {
+		t.Errorf("Check() = %v, want %v", err, domain.ErrContentRejected)
+	}
+}
+
+func TestNew_NoFile(t *testing.T) {
+	wl, err := New(Config{})
+	if err != nil || wl != nil {
+		t.Errorf("New() = %v, %v, want nil filter", wl, err)
+	}
+}
@@ -1,10 +1,629 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
 
 type Message struct {
-	SenderId string   `json:"sender_id"`
-	Text      string    `json:"text"`
+	Id        int        `json:"id"`
+	SenderId  string     `json:"sender_id"`
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"created_at"`
+	IsRead    bool       `json:"is_read"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// HiddenAt - сообщение скрыто до решения модератора: текст и вложения видит только отправитель
+	HiddenAt *time.Time `json:"hidden_at,omitempty"`
+
+	ReplyTo       *MessagePreview `json:"reply_to,omitempty"`
+	ForwardedFrom *ForwardInfo    `json:"forwarded_from,omitempty"`
+	Reactions     []ReactionCount `json:"reactions,omitempty"`
+	Attachments   []Attachment    `json:"attachments,omitempty"`
+	Envelope      *Envelope       `json:"envelope,omitempty"`
+	LinkPreviews  []LinkPreview   `json:"link_previews,omitempty"`
+	Entities      []MessageEntity `json:"entities,omitempty"`
+	// Mentioned - в сообщении упомянут пользователь, который его читает
+	Mentioned bool `json:"mentioned,omitempty"`
+	// Webhook - сообщение прислано входящим вебхуком, SenderId - создатель вебхука
+	Webhook *WebhookSender `json:"webhook,omitempty"`
+}
+
+// NewMessage - параметры создаваемого сообщения
+type NewMessage struct {
+	ChatId    int
+	SenderId  int
+	Text      string
+	ReplyToId int
+
+	// Format - разметка текста: пустой - текст как есть с разметкой Entities от клиента,
+	// FormatMarkdown - сервер сам выделяет Entities из Markdown
+	Format   string
+	Entities []MessageEntity
+	// MentionIds - упомянутые участники чата, кроме отправителя. Заполняет сервис
+	MentionIds []int
+
+	AttachmentIds []int
+
+	// Envelope - шифротекст сквозного шифрования, у таких сообщений Text пустой
+	Envelope *Envelope
+
+	// ClientMessageId - UUID, сгенерированный клиентом для безопасного повтора отправки
+	ClientMessageId string
+
+	ForwardedFromSenderId  int
+	ForwardedFromMessageId int
+
+	// IncomingWebhookId и WebhookName - входящий вебхук, которым отправлено сообщение, и имя, под которым оно показывается
+	IncomingWebhookId int
+	WebhookName       string
+}
+
+// Envelope - зашифрованное на клиенте сообщение. Сервер хранит и пересылает его, не заглядывая внутрь:
+// Ciphertext - тело сообщения, Headers - ключ сообщения, зашифрованный отдельно для каждого устройства
+// получателей и других устройств отправителя. Ciphertext и Header - в base64
+type Envelope struct {
+	Algorithm      string      `json:"algorithm"`
+	SenderDeviceId string      `json:"sender_device_id"`
+	Ciphertext     string      `json:"ciphertext"`
+	Headers        []KeyHeader `json:"headers"`
+}
+
+// KeyHeader - ключ сообщения для одного устройства пользователя
+type KeyHeader struct {
+	UserId   int    `json:"user_id"`
+	DeviceId string `json:"device_id"`
+	Header   string `json:"header"`
+}
+
+// MessagePreview - краткое содержимое сообщения, на которое отвечают
+type MessagePreview struct {
+	Id       int    `json:"id"`
+	SenderId string `json:"sender_id"`
+	Text     string `json:"text"`
+	Deleted  bool   `json:"deleted,omitempty"`
+}
+
+// ForwardInfo - первоисточник пересланного сообщения. MessageId пустой, если оригинал удален из базы
+type ForwardInfo struct {
+	SenderId  string `json:"sender_id"`
+	MessageId *int   `json:"message_id,omitempty"`
+}
+
+// FormatMarkdown - текст сообщения в подмножестве Markdown
+const FormatMarkdown = "markdown"
+
+const (
+	EntityBold    = "bold"
+	EntityItalic  = "italic"
+	EntityCode    = "code"
+	EntityLink    = "link"
+	EntityMention = "mention"
+)
+
+// MessageEntity - разметка участка текста. Offset и Length считаются в кодовых единицах UTF-16, как длина
+// строки в JavaScript. URL заполнен у ссылок, UserId - у упоминаний
+type MessageEntity struct {
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	URL    string `json:"url,omitempty"`
+	UserId int    `json:"user_id,omitempty"`
+}
+
+// TextEdit - новый текст сообщения, разметка задается так же, как в NewMessage
+type TextEdit struct {
+	Text       string
+	Format     string
+	Entities   []MessageEntity
+	MentionIds []int
+}
+
+// MessageEdit - предыдущая версия текста отредактированного сообщения
+type MessageEdit struct {
+	Text     string          `json:"text"`
+	Entities []MessageEntity `json:"entities,omitempty"`
+	EditedAt time.Time       `json:"edited_at"`
+}
+
+// UnreadCount - непрочитанные пользователем сообщения чата и сколько из них его упоминают
+type UnreadCount struct {
+	ChatId   int `json:"chat_id"`
+	Unread   int `json:"unread"`
+	Mentions int `json:"mentions"`
+}
+
+// ReadReceipt - указатель на последнее прочитанное участником сообщение чата
+type ReadReceipt struct {
+	ChatId            int       `json:"chat_id"`
+	UserId            int       `json:"user_id"`
+	LastReadMessageId int       `json:"last_read_message_id"`
+	ReadAt            time.Time `json:"read_at"`
+}
+
+// Reaction - реакция пользователя на сообщение
+type Reaction struct {
+	MessageId int    `json:"message_id"`
+	UserId    int    `json:"user_id"`
+	Emoji     string `json:"emoji"`
+}
+
+// ReactionCount - число реакций одним эмодзи на сообщение с точки зрения конкретного пользователя
+type ReactionCount struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+// Attachment - загруженный в чат файл. MessageId пустой, пока вложение не отправлено в сообщении
+type Attachment struct {
+	Id           int       `json:"id"`
+	ChatId       int       `json:"chat_id"`
+	UploaderId   int       `json:"uploader_id"`
+	MessageId    *int      `json:"message_id,omitempty"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+
+	StorageKey   string `json:"-"`
+	ThumbnailKey string `json:"-"`
+}
+
+// LinkPreview - карточка ссылки из текста сообщения по данным OpenGraph страницы.
+// FetchedAt - когда страница загружалась, по нему устаревает кэш
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+
+	FetchedAt time.Time `json:"-"`
+}
+
+// MessageLinkPreviews - карточки ссылок, готовые для уже отправленного сообщения
+type MessageLinkPreviews struct {
+	ChatId       int           `json:"chat_id"`
+	MessageId    int           `json:"message_id"`
+	LinkPreviews []LinkPreview `json:"link_previews"`
+}
+
+// AttachmentContent - содержимое вложения или его миниатюры для отдачи клиенту. Size -1, если размер неизвестен
+type AttachmentContent struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
+
+// SearchQuery - параметры полнотекстового поиска по сообщениям пользователя. ChatId 0 - поиск по всем чатам
+type SearchQuery struct {
+	UserId int
+	ChatId int
+	Text   string
+	Limit  int
+	After  *SearchCursor
+}
+
+// SearchCursor - позиция последнего найденного сообщения, результаты идут от новых к старым
+type SearchCursor struct {
+	CreatedAt time.Time
+	MessageId int
+}
+
+// SearchResult - найденное сообщение с фрагментом текста, совпадения выделены тегом <mark>
+type SearchResult struct {
+	ChatId    int       `json:"chat_id"`
+	MessageId int       `json:"message_id"`
+	SenderId  string    `json:"sender_id"`
+	Snippet   string    `json:"snippet"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SearchPage - страница результатов поиска. NextCursor пустой на последней странице
+type SearchPage struct {
+	Results    []SearchResult `json:"results"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// Change - запись журнала изменений пользователя. Type совпадает с типом события websocket
+type Change struct {
+	Seq       int64           `json:"seq"`
+	ChatId    int             `json:"chat_id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// SyncBatch - изменения после курсора клиента. При ResyncRequired клиент должен заново загрузить чаты
+// и продолжить синхронизацию с Cursor
+type SyncBatch struct {
+	Changes        []Change `json:"changes"`
+	Cursor         int64    `json:"cursor"`
+	HasMore        bool     `json:"has_more"`
+	ResyncRequired bool     `json:"resync_required,omitempty"`
+}
+
+// ChatMembers - участники созданного чата, содержимое изменения chat_created
+type ChatMembers struct {
+	ChatId  int   `json:"chat_id"`
+	UserIds []int `json:"user_ids"`
+}
+
+// ChatSettings - личные настройки чата пользователя. MutedUntil пустой, если уведомления отключены без срока
+type ChatSettings struct {
+	ChatId     int        `json:"chat_id"`
+	Muted      bool       `json:"muted"`
+	MutedUntil *time.Time `json:"muted_until,omitempty"`
+	Archived   bool       `json:"archived"`
+	Pinned     bool       `json:"pinned"`
+}
+
+// ChatSettingsUpdate - изменение настроек чата, nil-поля не меняются.
+// MutedUntil учитывается только вместе с Muted = true
+type ChatSettingsUpdate struct {
+	Muted      *bool
+	MutedUntil *time.Time
+	Archived   *bool
+	Pinned     *bool
+}
+
+// ChatRetention - срок жизни новых сообщений чата, общий для обоих участников. TTL в секундах, 0 - сообщения не исчезают.
+// Новый срок действует только на сообщения, отправленные после изменения
+type ChatRetention struct {
+	ChatId    int        `json:"chat_id"`
+	TTL       int        `json:"ttl"`
+	UpdatedBy int        `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ExpiredMessages - сообщения чата, удаленные по истечении срока хранения, содержимое события messages_expired
+type ExpiredMessages struct {
+	ChatId     int   `json:"chat_id"`
+	MessageIds []int `json:"message_ids"`
+}
+
+// RateLimit - корзина токенов: Burst действий подряд, затем одно действие в Interval. Burst 0 - без ограничения
+type RateLimit struct {
+	Burst    int
+	Interval time.Duration
+}
+
+// PushSubscription - Web Push подписка устройства из PushManager.subscribe() в браузере.
+// P256dh и Auth - ключи шифрования уведомлений в base64url
+type PushSubscription struct {
+	UserId    int
+	Endpoint  string
+	P256dh    string
+	Auth      string
+	UserAgent string
+	ExpiresAt *time.Time
+}
+
+// Account - данные учетной записи пользователя из auth-сервиса
+type Account struct {
+	UserId   int       `json:"user_id"`
+	Login    string    `json:"login"`
+	Email    string    `json:"email,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Sessions []Session `json:"sessions"`
+	Devices  []Device  `json:"devices"`
+}
+
+// Session - выданный пользователю токен входа
+type Session struct {
+	IssuedAt  *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Device - устройство пользователя в каталоге ключей сквозного шифрования
+type Device struct {
+	DeviceId  string    `json:"device_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Статусы выгрузки данных пользователя
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+// Export - задача выгрузки данных пользователя в ZIP-архив. Account снимается из auth-сервиса
+// при создании задачи, архив доступен для скачивания до ExpiresAt
+type Export struct {
+	Id          string     `json:"id"`
+	Status      string     `json:"status"`
+	Size        int64      `json:"size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+
+	UserId     int     `json:"-"`
+	Account    Account `json:"-"`
+	StorageKey string  `json:"-"`
+}
+
+// Статусы отложенного сообщения. Отправленные сообщения из списка отложенных удаляются
+const (
+	ScheduledPending = "pending"
+	ScheduledSending = "sending"
+	ScheduledFailed  = "failed"
+)
+
+// ScheduledMessage - сообщение, которое отправится в чат в SendAt. Текст хранится уже разобранным,
+// членство в чате, блокировки и упоминания проверяются заново в момент отправки.
+// ClientMessageId не меняется при повторных попытках, поэтому сообщение не может уйти дважды
+type ScheduledMessage struct {
+	Id              int             `json:"id"`
+	ChatId          int             `json:"chat_id"`
+	SenderId        int             `json:"sender_id"`
+	Text            string          `json:"text"`
+	Entities        []MessageEntity `json:"entities,omitempty"`
+	ReplyToId       int             `json:"reply_to_message_id,omitempty"`
+	AttachmentIds   []int           `json:"attachment_ids,omitempty"`
+	ClientMessageId string          `json:"client_message_id"`
+	SendAt          time.Time       `json:"send_at"`
+	Status          string          `json:"status"`
+	Error           string          `json:"error,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	// MessageId - отправленное сообщение, заполнен только в событии scheduled_message_sent
+	MessageId int `json:"message_id,omitempty"`
+}
+
+// ScheduledEdit - изменение отложенного сообщения, nil-поля остаются прежними
+type ScheduledEdit struct {
+	Text   *TextEdit
+	SendAt *time.Time
+}
+
+// PinnedMessage - сообщение, закрепленное в чате одним из участников. Список закрепленных общий для чата.
+// Message заполнен только в списке закрепленных
+type PinnedMessage struct {
+	ChatId    int       `json:"chat_id"`
+	MessageId int       `json:"message_id"`
+	PinnedBy  int       `json:"pinned_by"`
+	PinnedAt  time.Time `json:"pinned_at"`
+	Message   *Message  `json:"message,omitempty"`
+}
+
+// Draft - неотправленный текст пользователя в чате, общий для всех его устройств. Пустой черновик
+// (без текста и ответа) означает, что черновик удален. UpdatedAt - время правки на устройстве:
+// из конкурирующих правок остается самая поздняя
+type Draft struct {
+	ChatId    int             `json:"chat_id"`
+	Text      string          `json:"text"`
+	Entities  []MessageEntity `json:"entities,omitempty"`
+	ReplyToId int             `json:"reply_to_message_id,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ImportedChat - история личного чата из другого мессенджера, подготовленная к импорту.
+// ImportId сообщения уникален в чате, поэтому повторный импорт того же файла не создает дубликатов
+type ImportedChat struct {
+	User1Id  int
+	User2Id  int
+	Messages []ImportedMessage
+}
+
+type ImportedMessage struct {
+	ImportId        string
+	SenderId        int
+	Text            string
+	CreatedAt       time.Time
+	EditedAt        *time.Time
+	ReplyToImportId string
+}
+
+// ImportResult - итог импорта чата: Imported новых сообщений, Duplicates уже импортированных ранее
+type ImportResult struct {
+	ChatId     int  `json:"chat_id"`
+	Created    bool `json:"created"`
+	Imported   int  `json:"imported"`
+	Duplicates int  `json:"duplicates"`
+}
+
+// Роли пользователей, выдаются auth-сервисом в claims токена
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+	RoleBot   = "bot"
+)
+
+// Identity - аутентифицированный пользователь запроса. Token нужен для вызовов auth-сервиса от его имени
+type Identity struct {
+	UserId int
+	Role   string
+	Token  string
+}
+
+func (i Identity) IsAdmin() bool {
+	return i.Role == RoleAdmin
+}
+
+func (i Identity) IsBot() bool {
+	return i.Role == RoleBot
+}
+
+// User - учетная запись из auth-сервиса для администрирования. SuspendedUntil пустой при бессрочной блокировке
+type User struct {
+	Id             int        `json:"id"`
+	Login          string     `json:"login"`
+	Email          string     `json:"email"`
+	Name           string     `json:"name"`
+	Role           string     `json:"role"`
+	Suspended      bool       `json:"suspended"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	SuspendReason  string     `json:"suspend_reason,omitempty"`
+}
+
+// UserQuery - поиск пользователей по подстроке логина, email или имени либо по id
+type UserQuery struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// Suspension - блокировка пользователя до Until, пустой Until - бессрочно
+type Suspension struct {
+	UserId int        `json:"user_id"`
+	Until  *time.Time `json:"until,omitempty"`
+	Reason string     `json:"reason"`
+}
+
+// ChatQuery - список чатов для администратора, UserId 0 - все чаты
+type ChatQuery struct {
+	UserId int
+	Limit  int
+	Offset int
+}
+
+// ChatInfo - чат в списке администратора
+type ChatInfo struct {
+	Id        int       `json:"id"`
+	UserIds   []int     `json:"user_ids"`
+	Messages  int       `json:"messages"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Причины жалоб на сообщения
+const (
+	ReportSpam       = "spam"
+	ReportAbuse      = "abuse"
+	ReportHarassment = "harassment"
+	ReportIllegal    = "illegal"
+	ReportOther      = "other"
+)
+
+var ReportReasons = []string{ReportSpam, ReportAbuse, ReportHarassment, ReportIllegal, ReportOther}
+
+// Report - жалоба участника чата на сообщение собеседника
+type Report struct {
+	ChatId     int
+	MessageId  int
+	ReporterId int
+	Reason     string
+	Comment    string
+}
+
+// Статусы сообщения в очереди модерации. pending и hidden ждут решения модератора
+const (
+	ModerationPending   = "pending"
+	ModerationHidden    = "hidden"
+	ModerationDismissed = "dismissed"
+	ModerationRemoved   = "removed"
+)
+
+// Решения модератора по жалобам: оставить сообщение или удалить его
+const (
+	ResolveDismiss = "dismiss"
+	ResolveRemove  = "remove"
+)
+
+// ReportedMessage - сообщение в очереди модерации. Текст зашифрованных сообщений недоступен
+type ReportedMessage struct {
+	ChatId         int        `json:"chat_id"`
+	MessageId      int        `json:"message_id"`
+	SenderId       int        `json:"sender_id"`
+	Text           string     `json:"text"`
+	Encrypted      bool       `json:"encrypted,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	Status         string     `json:"status"`
+	Reports        int        `json:"reports"`
+	Reasons        []string   `json:"reasons"`
+	LastReportedAt time.Time  `json:"last_reported_at"`
+	HiddenAt       *time.Time `json:"hidden_at,omitempty"`
+	ResolvedBy     int        `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// MessageVisibility - содержимое событий message_hidden и message_restored
+type MessageVisibility struct {
+	ChatId    int        `json:"chat_id"`
+	MessageId int        `json:"message_id"`
+	HiddenAt  *time.Time `json:"hidden_at,omitempty"`
+}
+
+// Действия администраторов в журнале модерации
+const (
+	AuditSuspendUser   = "suspend_user"
+	AuditUnsuspendUser = "unsuspend_user"
+	AuditDeleteMessage = "delete_message"
+	AuditDeleteChat    = "delete_chat"
+	AuditDismissReport = "dismiss_report"
+)
+
+// AuditEntry - запись журнала модерации. Журнал только дополняется, изменить или удалить запись нельзя
+type AuditEntry struct {
+	Id        int64          `json:"id"`
+	AdminId   int            `json:"admin_id"`
+	Action    string         `json:"action"`
+	UserId    int            `json:"user_id,omitempty"`
+	ChatId    int            `json:"chat_id,omitempty"`
+	MessageId int            `json:"message_id,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Webhook - адрес, на который отправляются события чатов бота. Secret - ключ подписи запросов,
+// отдается только при установке вебхука. LastError - последняя неудачная доставка
+type Webhook struct {
+	BotId        int        `json:"bot_id"`
+	URL          string     `json:"url"`
+	Secret       string     `json:"secret,omitempty"`
+	PendingCount int        `json:"pending_count"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// WebhookDelivery - событие в очереди на отправку вебхуку бота. URL и Secret берутся из вебхука
+// в момент отправки, Attempts - сколько раз доставка уже бралась в работу
+type WebhookDelivery struct {
+	Id        int64
+	BotId     int
+	ChatId    int
+	Event     string
+	Payload   json.RawMessage
+	Attempts  int
+	CreatedAt time.Time
+	URL       string
+	Secret    string
+}
+
+// IncomingWebhook - адрес, по которому внешняя система пишет в чат без токена пользователя.
+// Сообщения сохраняются от имени CreatorId и показываются под именем вебхука. Token - секрет адреса,
+// отдается только при создании
+type IncomingWebhook struct {
+	Id        int       `json:"id"`
+	ChatId    int       `json:"chat_id"`
+	CreatorId int       `json:"creator_id"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
-	IsRead    bool      `json:"is_read"`
+}
+
+// IncomingWebhookPost - сообщение, присланное на адрес входящего вебхука. Username заменяет
+// имя вебхука в этом сообщении
+type IncomingWebhookPost struct {
+	Text     string
+	Username string
+	Format   string
+}
+
+// WebhookSender - входящий вебхук, которым отправлено сообщение. Id пустой, если вебхук уже отозван
+type WebhookSender struct {
+	Id   *int   `json:"id,omitempty"`
+	Name string `json:"name"`
 }
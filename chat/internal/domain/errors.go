@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotChatMember     = errors.New("user is not a member of the chat")
+	ErrMessageNotFound   = errors.New("message not found in the chat")
+	ErrNotMessageSender  = errors.New("only the sender can change the message")
+	ErrEditWindowExpired = errors.New("time to change the message has expired")
+	ErrMessageDeleted    = errors.New("message is deleted")
+	ErrInvalidEmoji      = errors.New("invalid emoji")
+	ErrInvalidClientId   = errors.New("client_message_id must be a UUID")
+
+	ErrAttachmentNotFound = errors.New("attachment not found in the chat")
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrAttachmentType     = errors.New("attachment type is not allowed")
+	ErrTooManyAttachments = errors.New("too many attachments in the message")
+
+	ErrEmptySearchQuery = errors.New("search query is empty")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+
+	ErrUserBlocked        = errors.New("user is blocked")
+	ErrInvalidBlock       = errors.New("cannot block this user")
+	ErrInvalidMuteTimeout = errors.New("mute expiration must be in the future")
+
+	ErrMessageTooLong = errors.New("message is too long")
+	ErrRateLimited    = errors.New("too many messages")
+
+	ErrInvalidPushSubscription = errors.New("invalid push subscription")
+	ErrPushDisabled            = errors.New("push notifications are disabled")
+
+	ErrInvalidEnvelope  = errors.New("invalid encrypted envelope")
+	ErrMessageEncrypted = errors.New("message is end-to-end encrypted")
+
+	ErrInvalidRetention = errors.New("invalid message retention")
+
+	ErrExportNotFound   = errors.New("export not found")
+	ErrExportInProgress = errors.New("export is already in progress")
+	ErrExportNotReady   = errors.New("export is not ready")
+	ErrExportExpired    = errors.New("export download link has expired")
+
+	ErrAdminRequired     = errors.New("admin role required")
+	ErrUserSuspended     = errors.New("user is suspended")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrChatNotFound      = errors.New("chat not found")
+	ErrInvalidSuspension = errors.New("invalid suspension")
+
+	ErrInvalidReport   = errors.New("invalid report")
+	ErrAlreadyReported = errors.New("message is already reported")
+	ErrReportNotFound  = errors.New("report not found")
+	ErrReportResolved  = errors.New("report is already resolved")
+	ErrMessageHidden   = errors.New("message is hidden by moderation")
+	ErrContentRejected = errors.New("message rejected by content filter")
+
+	ErrInvalidEntities = errors.New("invalid message entities")
+
+	ErrScheduledNotFound = errors.New("scheduled message not found")
+	ErrScheduledSending  = errors.New("scheduled message is already being sent")
+	ErrInvalidSendAt     = errors.New("send_at must be in the future and within the scheduling horizon")
+	ErrTooManyScheduled  = errors.New("too many scheduled messages")
+
+	ErrTooManyPinned = errors.New("too many pinned messages")
+
+	ErrBotRequired       = errors.New("bot token required")
+	ErrWebhookNotFound   = errors.New("webhook not found")
+	ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http or https url")
+
+	ErrIncomingWebhookNotFound = errors.New("incoming webhook not found")
+	ErrInvalidIncomingWebhook  = errors.New("invalid incoming webhook")
+	ErrTooManyIncomingWebhooks = errors.New("too many incoming webhooks in the chat")
+)
+
+// RateLimitError - превышен лимит отправки сообщений, повторить можно через RetryAfter
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return ErrRateLimited.Error()
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
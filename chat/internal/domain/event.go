@@ -0,0 +1,43 @@
+package domain
+
+const (
+	EventChatCreated     = "chat_created"
+	EventChatDeleted     = "chat_deleted"
+	EventMessageCreated  = "message_created"
+	EventRead            = "read"
+	EventMessageEdited   = "message_edited"
+	EventMessageDeleted  = "message_deleted"
+	EventReactionAdded   = "reaction_added"
+	EventReactionRemoved = "reaction_removed"
+
+	EventChatRetentionChanged = "chat_retention_changed"
+	EventMessagesExpired      = "messages_expired"
+
+	EventMessageHidden   = "message_hidden"
+	EventMessageRestored = "message_restored"
+
+	EventLinkPreviews = "message_link_previews"
+
+	EventMessagePinned   = "message_pinned"
+	EventMessageUnpinned = "message_unpinned"
+
+	// EventDraftUpdated адресовано только владельцу черновика, чтобы обновить его на других устройствах
+	EventDraftUpdated = "draft_updated"
+
+	// EventExportReady адресовано только владельцу выгрузки, ChatId пустой
+	EventExportReady = "export_ready"
+
+	// События отложенного сообщения адресованы только его отправителю
+	EventScheduledSent   = "scheduled_message_sent"
+	EventScheduledFailed = "scheduled_message_failed"
+)
+
+// Event - событие для участников чата, доставляемое через websocket-сервис.
+// MutedUserIds - получатели, отключившие уведомления чата: событие доставляется им без оповещения
+type Event struct {
+	Type         string `json:"event"`
+	ChatId       int    `json:"chat_id"`
+	UserIds      []int  `json:"user_ids"`
+	MutedUserIds []int  `json:"muted_user_ids,omitempty"`
+	Payload      any    `json:"payload"`
+}
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, event domain.Event) error
+}
+
+// Fanout рассылает событие всем получателям по очереди. Ошибка одного получателя
+// не мешает остальным, ошибки объединяются
+type Fanout []Notifier
+
+func (f Fanout) Notify(ctx context.Context, event domain.Event) error {
+	var errs []error
+	for _, n := range f {
+		if err := n.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"chat/internal/domain"
+	"context"
+	"sync"
+)
+
+// subscriptionBuffer - сколько событий подписчик может не прочитать, прежде чем его отключат
+const subscriptionBuffer = 64
+
+// Hub раздает события подписчикам внутри процесса. Подписчик получает только события,
+// разосланные этой репликой chat-сервиса
+type Hub struct {
+	mu   sync.Mutex
+	subs map[int]map[chan domain.Event]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[int]map[chan domain.Event]struct{})}
+}
+
+// Notify передает событие подписчикам из event.UserIds. Подписка, которая не успевает читать события,
+// закрывается: клиент переподключается и догоняет пропущенное через журнал изменений
+func (h *Hub) Notify(_ context.Context, event domain.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, userID := range event.UserIds {
+		for ch := range h.subs[userID] {
+			select {
+			case ch <- event:
+			default:
+				h.remove(userID, ch)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Subscribe подписывает на события пользователя userID. Функция отмены закрывает канал, ее можно вызывать повторно
+func (h *Hub) Subscribe(userID int) (<-chan domain.Event, func()) {
+	ch := make(chan domain.Event, subscriptionBuffer)
+
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[chan domain.Event]struct{})
+	}
+	h.subs[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(userID, ch)
+	}
+}
+
+func (h *Hub) remove(userID int, ch chan domain.Event) {
+	if _, ok := h.subs[userID][ch]; !ok {
+		return
+	}
+	delete(h.subs[userID], ch)
+	if len(h.subs[userID]) == 0 {
+		delete(h.subs, userID)
+	}
+	close(ch)
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"chat/internal/domain"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub(t *testing.T) {
+	ctx := context.Background()
+	h := NewHub()
+
+	events1, cancel1 := h.Subscribe(1)
+	events2, cancel2 := h.Subscribe(2)
+	defer cancel2()
+
+	event := domain.Event{Type: domain.EventRead, ChatId: 1, UserIds: []int{1}}
+	require.NoError(t, h.Notify(ctx, event))
+
+	t.Run("only recipients get event", func(t *testing.T) {
+		assert.Equal(t, event, <-events1)
+		assert.Empty(t, events2)
+	})
+
+	t.Run("cancel closes subscription", func(t *testing.T) {
+		cancel1()
+		cancel1()
+
+		_, ok := <-events1
+		assert.False(t, ok)
+		require.NoError(t, h.Notify(ctx, event))
+	})
+
+	t.Run("slow subscriber is dropped", func(t *testing.T) {
+		slow, cancel := h.Subscribe(3)
+		defer cancel()
+
+		for i := 0; i <= subscriptionBuffer; i++ {
+			require.NoError(t, h.Notify(ctx, domain.Event{Type: domain.EventRead, ChatId: 1, UserIds: []int{3}}))
+		}
+
+		received := 0
+		for range slow {
+			received++
+		}
+		assert.Equal(t, subscriptionBuffer, received)
+	})
+}
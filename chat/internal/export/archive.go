@@ -0,0 +1,134 @@
+package export
+
+import (
+	"archive/zip"
+	"chat/internal/domain"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Format("02.01.2006 15:04") },
+	"size": formatSize,
+}).ParseFS(templatesFS, "templates/*.html"))
+
+// Profile - содержимое account.json: учетная запись и личные настройки, не привязанные к чатам
+type Profile struct {
+	ExportedAt   time.Time      `json:"exported_at"`
+	Account      domain.Account `json:"account"`
+	BlockedUsers []int          `json:"blocked_users"`
+}
+
+// Chat - содержимое chats/chat_<id>.json: чат глазами пользователя, сообщения от старых к новым
+type Chat struct {
+	Id        int                  `json:"id"`
+	Members   []int                `json:"members"`
+	Settings  domain.ChatSettings  `json:"settings"`
+	Retention domain.ChatRetention `json:"retention"`
+	Messages  []domain.Message     `json:"messages"`
+}
+
+// chatSummary - строка оглавления index.html
+type chatSummary struct {
+	Id       int
+	Members  []int
+	Messages int
+}
+
+// Archive пишет выгрузку в ZIP: каждую часть в JSON для программ и в HTML для чтения в браузере.
+// Чаты добавляются по одному, поэтому в памяти держится только текущий
+type Archive struct {
+	zw      *zip.Writer
+	profile Profile
+	chats   []chatSummary
+}
+
+func NewArchive(w io.Writer, profile Profile) *Archive {
+	return &Archive{zw: zip.NewWriter(w), profile: profile}
+}
+
+func (a *Archive) AddChat(chat Chat) error {
+	for i := range chat.Messages {
+		for j := range chat.Messages[i].Attachments {
+			att := &chat.Messages[i].Attachments[j]
+			att.URL = fmt.Sprintf("/chat/%d/attachments/%d", att.ChatId, att.Id)
+		}
+	}
+
+	name := fmt.Sprintf("chats/chat_%d", chat.Id)
+	if err := a.writeJSON(name+".json", chat); err != nil {
+		return err
+	}
+	if err := a.writeHTML(name+".html", "chat.html", map[string]any{"Chat": chat, "Me": strconv.Itoa(a.profile.Account.UserId)}); err != nil {
+		return err
+	}
+
+	a.chats = append(a.chats, chatSummary{Id: chat.Id, Members: chat.Members, Messages: len(chat.Messages)})
+	return nil
+}
+
+// Close дописывает профиль и оглавление и завершает архив
+func (a *Archive) Close() error {
+	if err := a.writeJSON("account.json", a.profile); err != nil {
+		return err
+	}
+	if err := a.writeHTML("index.html", "index.html", map[string]any{"Profile": a.profile, "Chats": a.chats}); err != nil {
+		return err
+	}
+	return a.zw.Close()
+}
+
+func (a *Archive) writeJSON(name string, v any) error {
+	w, err := a.create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (a *Archive) writeHTML(name, tmpl string, data any) error {
+	w, err := a.create(name)
+	if err != nil {
+		return err
+	}
+	if err := templates.ExecuteTemplate(w, tmpl, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return nil
+}
+
+func (a *Archive) create(name string) (io.Writer, error) {
+	w, err := a.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: a.profile.ExportedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	return w, nil
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f МБ", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f КБ", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d Б", size)
+	}
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"chat/internal/domain"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	var buf bytes.Buffer
+	exportedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	a := NewArchive(&buf, Profile{
+		ExportedAt:   exportedAt,
+		Account:      domain.Account{UserId: 1, Login: "alice", Name: "Alice"},
+		BlockedUsers: []int{7},
+	})
+	err := a.AddChat(Chat{
+		Id:      3,
+		Members: []int{1, 2},
+		Messages: []domain.Message{
+			{Id: 10, SenderId: "1", Text: "<b>hi</b>", CreatedAt: exportedAt},
+			{Id: 11, SenderId: "2", Text: "file", CreatedAt: exportedAt,
+				Attachments: []domain.Attachment{{Id: 4, ChatId: 3, FileName: "a.txt", Size: 2048}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Archive.AddChat() error = %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Archive.Close() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+
+	for _, name := range []string{"account.json", "index.html", "chats/chat_3.json", "chats/chat_3.html"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("archive has no %s", name)
+		}
+	}
+
+	var profile Profile
+	if err := json.Unmarshal([]byte(files["account.json"]), &profile); err != nil {
+		t.Fatalf("account.json: %v", err)
+	}
+	if profile.Account.Login != "alice" || len(profile.BlockedUsers) != 1 {
+		t.Errorf("account.json = %+v", profile)
+	}
+
+	var chat Chat
+	if err := json.Unmarshal([]byte(files["chats/chat_3.json"]), &chat); err != nil {
+		t.Fatalf("chat_3.json: %v", err)
+	}
+	if len(chat.Messages) != 2 || chat.Messages[1].Attachments[0].URL != "/chat/3/attachments/4" {
+		t.Errorf("chat_3.json = %+v", chat)
+	}
+
+	page := files["chats/chat_3.html"]
+	if strings.Contains(page, "<b>hi</b>") || !strings.Contains(page, "&lt;b&gt;hi&lt;/b&gt;") {
+		t.Error("chat_3.html does not escape message text")
+	}
+	if !strings.Contains(page, "2.0 КБ") {
+		t.Error("chat_3.html has no attachment size")
+	}
+	if !strings.Contains(files["index.html"], "chats/chat_3.html") {
+		t.Error("index.html has no link to the chat")
+	}
+}
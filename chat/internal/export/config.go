@@ -0,0 +1,8 @@
+package export
+
+import "time"
+
+type Config struct {
+	// PollInterval - как часто воркер проверяет очередь выгрузок и удаляет истекшие архивы
+	PollInterval time.Duration `env:"EXPORT_POLL_INTERVAL" envDefault:"5s"`
+}
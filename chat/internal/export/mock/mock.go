@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: worker.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProcessor is a mock of Processor interface.
+type MockProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockProcessorMockRecorder
+}
+
+// MockProcessorMockRecorder is the mock recorder for MockProcessor.
+type MockProcessorMockRecorder struct {
+	mock *MockProcessor
+}
+
+// NewMockProcessor creates a new mock instance.
+func NewMockProcessor(ctrl *gomock.Controller) *MockProcessor {
+	mock := &MockProcessor{ctrl: ctrl}
+	mock.recorder = &MockProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProcessor) EXPECT() *MockProcessorMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredExports mocks base method.
+func (m *MockProcessor) DeleteExpiredExports(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredExports", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredExports indicates an expected call of DeleteExpiredExports.
+func (mr *MockProcessorMockRecorder) DeleteExpiredExports(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredExports", reflect.TypeOf((*MockProcessor)(nil).DeleteExpiredExports), ctx)
+}
+
+// ProcessExport mocks base method.
+func (m *MockProcessor) ProcessExport(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessExport", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessExport indicates an expected call of ProcessExport.
+func (mr *MockProcessorMockRecorder) ProcessExport(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessExport", reflect.TypeOf((*MockProcessor)(nil).ProcessExport), ctx)
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"chat/pkg/logger"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=./mock/mock.go -package=mock -source=worker.go
+
+// Processor выполняет выгрузки: ProcessExport собирает одну ожидающую выгрузку и возвращает false,
+// если очередь пуста, DeleteExpiredExports удаляет архивы с истекшей ссылкой
+type Processor interface {
+	ProcessExport(ctx context.Context) (bool, error)
+	DeleteExpiredExports(ctx context.Context) (int, error)
+}
+
+// Worker в фоне собирает архивы выгрузок. Выгрузки берутся из базы с блокировкой,
+// поэтому воркеры могут работать на нескольких репликах
+type Worker struct {
+	cfg       Config
+	processor Processor
+}
+
+func NewWorker(cfg Config, processor Processor) *Worker {
+	return &Worker{cfg: cfg, processor: processor}
+}
+
+// Run обрабатывает очередь выгрузок раз в PollInterval, пока не отменен ctx
+func (w *Worker) Run(ctx context.Context) {
+	if w.cfg.PollInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.drain(ctx)
+			w.cleanup(ctx)
+		}
+	}
+}
+
+// drain выполняет выгрузки, пока очередь не опустеет
+func (w *Worker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.processor.ProcessExport(ctx)
+		if err != nil {
+			logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to process export", err)
+			return
+		}
+		if !processed {
+			return
+		}
+	}
+}
+
+func (w *Worker) cleanup(ctx context.Context) {
+	deleted, err := w.processor.DeleteExpiredExports(ctx)
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to delete expired exports", err)
+		return
+	}
+	if deleted > 0 {
+		logger.GetFromCtx(ctx).InfoContext(ctx, "expired exports deleted", "count", deleted)
+	}
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"chat/internal/export/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestWorker_Drain(t *testing.T) {
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("processes until the queue is empty", func(t *testing.T) {
+		p := mock.NewMockProcessor(gomock.NewController(t))
+		w := NewWorker(Config{}, p)
+
+		gomock.InOrder(
+			p.EXPECT().ProcessExport(gomock.Any()).Return(true, nil),
+			p.EXPECT().ProcessExport(gomock.Any()).Return(true, nil),
+			p.EXPECT().ProcessExport(gomock.Any()).Return(false, nil),
+		)
+
+		w.drain(ctx)
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		p := mock.NewMockProcessor(gomock.NewController(t))
+		w := NewWorker(Config{}, p)
+
+		p.EXPECT().ProcessExport(gomock.Any()).Return(true, errors.New("db is down"))
+
+		w.drain(ctx)
+	})
+}
+
+func TestWorker_Run(t *testing.T) {
+	p := mock.NewMockProcessor(gomock.NewController(t))
+	w := NewWorker(Config{PollInterval: 10 * time.Millisecond}, p)
+
+	ctx, cancel := context.WithCancel(logger.InitFromCtx(context.Background(), logger.New()))
+	p.EXPECT().ProcessExport(gomock.Any()).Return(false, nil)
+	p.EXPECT().DeleteExpiredExports(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+		cancel()
+		return 1, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Worker.Run() did not stop after cancel")
+	}
+}
@@ -0,0 +1,22 @@
+package linkpreview
+
+import "time"
+
+type Config struct {
+	// Workers - сколько сообщений обрабатывается параллельно, QueueSize - сколько ждет обработки.
+	// При переполненной очереди сообщение остается без карточек
+	Workers   int `env:"LINK_PREVIEW_WORKERS" envDefault:"4"`
+	QueueSize int `env:"LINK_PREVIEW_QUEUE_SIZE" envDefault:"1024"`
+	// MaxLinks - сколько первых ссылок сообщения получают карточки
+	MaxLinks int `env:"LINK_PREVIEW_MAX_LINKS" envDefault:"3"`
+
+	// Timeout ограничивает загрузку одной страницы вместе с редиректами, MaxBodySize - сколько байт страницы читается
+	Timeout      time.Duration `env:"LINK_PREVIEW_TIMEOUT" envDefault:"5s"`
+	MaxBodySize  int64         `env:"LINK_PREVIEW_MAX_BODY_SIZE" envDefault:"1048576"`
+	MaxRedirects int           `env:"LINK_PREVIEW_MAX_REDIRECTS" envDefault:"3"`
+
+	// CacheTTL - сколько карточка хранится до повторной загрузки страницы,
+	// FailureTTL - сколько не запрашивается страница, с которой не удалось получить карточку
+	CacheTTL   time.Duration `env:"LINK_PREVIEW_CACHE_TTL" envDefault:"24h"`
+	FailureTTL time.Duration `env:"LINK_PREVIEW_FAILURE_TTL" envDefault:"1h"`
+}
@@ -0,0 +1,107 @@
+package linkpreview
+
+import (
+	"chat/internal/domain"
+	"chat/internal/netguard"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+const userAgent = "ChatLinkPreview/1.0"
+
+// ErrNotHTML - по ссылке не html-страница, карточку собрать не из чего
+var ErrNotHTML = errors.New("not an html page")
+
+// Fetcher загружает страницы по ссылкам из сообщений. Адрес проверяется в момент соединения, уже после
+// разрешения имени, поэтому ни редирект, ни DNS-запись не уведут запрос во внутреннюю сеть
+type Fetcher struct {
+	client      *http.Client
+	maxBodySize int64
+}
+
+func NewFetcher(cfg Config) *Fetcher {
+	return newFetcher(cfg, netguard.PublicAddress)
+}
+
+func newFetcher(cfg Config, allow func(netip.AddrPort) bool) *Fetcher {
+	dialer := netguard.Dialer(cfg.Timeout, allow)
+
+	transport := &http.Transport{
+		// Прокси из окружения соединялся бы с целью сам, в обход проверки адреса
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   cfg.Timeout,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          16,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > cfg.MaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", cfg.MaxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect to %s", netguard.ErrForbiddenAddress, req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+
+	return &Fetcher{client: client, maxBodySize: cfg.MaxBodySize}
+}
+
+// Fetch загружает страницу и собирает карточку из ее OpenGraph-разметки. Читается не больше
+// maxBodySize байт: метаданные в head, до тела страницы обычно дело не доходит
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (domain.LinkPreview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return domain.LinkPreview{}, fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return domain.LinkPreview{}, fmt.Errorf("%w: scheme %s", netguard.ErrForbiddenAddress, u.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return domain.LinkPreview{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return domain.LinkPreview{}, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.LinkPreview{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return domain.LinkPreview{}, fmt.Errorf("%w: %s", ErrNotHTML, mediaType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBodySize), contentType)
+	if err != nil {
+		return domain.LinkPreview{}, fmt.Errorf("failed to decode page: %w", err)
+	}
+
+	p := parseOpenGraph(body, resp.Request.URL)
+	p.URL = rawURL
+
+	return p, nil
+}
@@ -0,0 +1,137 @@
+package linkpreview
+
+import (
+	"chat/internal/netguard"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = Config{Timeout: time.Second, MaxBodySize: 64 << 10, MaxRedirects: 2}
+
+// allowAll пускает в локальную сеть, где работает httptest
+func allowAll(netip.AddrPort) bool { return true }
+
+func TestFetcher_Fetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<!doctype html><html><head>
+			<title>Запасной заголовок</title>
+			<meta property="og:title" content="Статья &amp; новости">
+			<meta property="og:description" content="  Краткое
+				описание  ">
+			<meta property="og:image" content="/img/cover.png">
+			<meta property="og:site_name" content="Пример">
+			</head><body><meta property="og:title" content="из тела"></body></html>`))
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+		// "Привет" в windows-1251
+		w.Write([]byte("<title>\xcf\xf0\xe8\xe2\xe5\xf2</title><meta name=\"description\" content=\"desc\">"))
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(3 * time.Second):
+		}
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<head>" + strings.Repeat("<meta name=x content=y>", 10000) + `<meta property="og:title" content="late"></head>`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newFetcher(testConfig, allowAll)
+	ctx := context.Background()
+
+	t.Run("opengraph", func(t *testing.T) {
+		p, err := f.Fetch(ctx, srv.URL+"/article")
+		require.NoError(t, err)
+		assert.Equal(t, srv.URL+"/article", p.URL)
+		assert.Equal(t, "Статья & новости", p.Title)
+		assert.Equal(t, "Краткое описание", p.Description)
+		assert.Equal(t, srv.URL+"/img/cover.png", p.ImageURL)
+		assert.Equal(t, "Пример", p.SiteName)
+	})
+
+	t.Run("title fallback and charset", func(t *testing.T) {
+		p, err := f.Fetch(ctx, srv.URL+"/plain")
+		require.NoError(t, err)
+		assert.Equal(t, "Привет", p.Title)
+		assert.Equal(t, "desc", p.Description)
+	})
+
+	t.Run("not html", func(t *testing.T) {
+		_, err := f.Fetch(ctx, srv.URL+"/image.png")
+		assert.ErrorIs(t, err, ErrNotHTML)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		start := time.Now()
+		_, err := f.Fetch(ctx, srv.URL+"/slow")
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("redirect loop", func(t *testing.T) {
+		_, err := f.Fetch(ctx, srv.URL+"/loop")
+		assert.ErrorContains(t, err, "redirects")
+	})
+
+	t.Run("body size limit", func(t *testing.T) {
+		p, err := f.Fetch(ctx, srv.URL+"/huge")
+		require.NoError(t, err)
+		assert.Empty(t, p.Title)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := f.Fetch(ctx, srv.URL+"/missing")
+		assert.ErrorContains(t, err, "404")
+	})
+}
+
+func TestFetcher_PrivateAddresses(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<title>secret</title>"))
+	}))
+	defer internal.Close()
+
+	t.Run("loopback is blocked", func(t *testing.T) {
+		_, err := NewFetcher(testConfig).Fetch(context.Background(), internal.URL)
+		assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+	})
+
+	t.Run("redirect to blocked address", func(t *testing.T) {
+		public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, internal.URL, http.StatusFound)
+		}))
+		defer public.Close()
+		publicAddr := netip.MustParseAddrPort(strings.TrimPrefix(public.URL, "http://"))
+
+		f := newFetcher(testConfig, func(addr netip.AddrPort) bool { return addr == publicAddr })
+		_, err := f.Fetch(context.Background(), public.URL)
+		assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+	})
+
+	t.Run("scheme", func(t *testing.T) {
+		_, err := NewFetcher(testConfig).Fetch(context.Background(), "file:///etc/passwd")
+		assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+	})
+}
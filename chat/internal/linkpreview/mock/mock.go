@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: previewer.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "chat/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepo is a mock of Repo interface.
+type MockRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoMockRecorder
+}
+
+// MockRepoMockRecorder is the mock recorder for MockRepo.
+type MockRepoMockRecorder struct {
+	mock *MockRepo
+}
+
+// NewMockRepo creates a new mock instance.
+func NewMockRepo(ctrl *gomock.Controller) *MockRepo {
+	mock := &MockRepo{ctrl: ctrl}
+	mock.recorder = &MockRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepo) EXPECT() *MockRepoMockRecorder {
+	return m.recorder
+}
+
+// AttachLinkPreviews mocks base method.
+func (m *MockRepo) AttachLinkPreviews(ctx context.Context, chatID, messageID int, text string, urls []string) ([]domain.LinkPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachLinkPreviews", ctx, chatID, messageID, text, urls)
+	ret0, _ := ret[0].([]domain.LinkPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachLinkPreviews indicates an expected call of AttachLinkPreviews.
+func (mr *MockRepoMockRecorder) AttachLinkPreviews(ctx, chatID, messageID, text, urls interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLinkPreviews", reflect.TypeOf((*MockRepo)(nil).AttachLinkPreviews), ctx, chatID, messageID, text, urls)
+}
+
+// GetChatMembers mocks base method.
+func (m *MockRepo) GetChatMembers(ctx context.Context, chatID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatMembers", ctx, chatID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatMembers indicates an expected call of GetChatMembers.
+func (mr *MockRepoMockRecorder) GetChatMembers(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatMembers", reflect.TypeOf((*MockRepo)(nil).GetChatMembers), ctx, chatID)
+}
+
+// GetLinkPreview mocks base method.
+func (m *MockRepo) GetLinkPreview(ctx context.Context, url string) (domain.LinkPreview, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinkPreview", ctx, url)
+	ret0, _ := ret[0].(domain.LinkPreview)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLinkPreview indicates an expected call of GetLinkPreview.
+func (mr *MockRepoMockRecorder) GetLinkPreview(ctx, url interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkPreview", reflect.TypeOf((*MockRepo)(nil).GetLinkPreview), ctx, url)
+}
+
+// SaveLinkPreview mocks base method.
+func (m *MockRepo) SaveLinkPreview(ctx context.Context, p domain.LinkPreview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLinkPreview", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLinkPreview indicates an expected call of SaveLinkPreview.
+func (mr *MockRepoMockRecorder) SaveLinkPreview(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLinkPreview", reflect.TypeOf((*MockRepo)(nil).SaveLinkPreview), ctx, p)
+}
+
+// MockPageFetcher is a mock of PageFetcher interface.
+type MockPageFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPageFetcherMockRecorder
+}
+
+// MockPageFetcherMockRecorder is the mock recorder for MockPageFetcher.
+type MockPageFetcherMockRecorder struct {
+	mock *MockPageFetcher
+}
+
+// NewMockPageFetcher creates a new mock instance.
+func NewMockPageFetcher(ctrl *gomock.Controller) *MockPageFetcher {
+	mock := &MockPageFetcher{ctrl: ctrl}
+	mock.recorder = &MockPageFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPageFetcher) EXPECT() *MockPageFetcherMockRecorder {
+	return m.recorder
+}
+
+// Fetch mocks base method.
+func (m *MockPageFetcher) Fetch(ctx context.Context, url string) (domain.LinkPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, url)
+	ret0, _ := ret[0].(domain.LinkPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockPageFetcherMockRecorder) Fetch(ctx, url interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockPageFetcher)(nil).Fetch), ctx, url)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, event domain.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, event)
+}
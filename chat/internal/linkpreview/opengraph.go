@@ -0,0 +1,117 @@
+package linkpreview
+
+import (
+	"chat/internal/domain"
+	"io"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	maxTitleLength       = 300
+	maxDescriptionLength = 500
+)
+
+// parseOpenGraph собирает карточку из og:-тегов head. Без них заголовок берется из <title>,
+// описание - из <meta name="description">. base - адрес страницы после редиректов для относительных картинок
+func parseOpenGraph(r io.Reader, base *url.URL) domain.LinkPreview {
+	var p domain.LinkPreview
+	var title, description string
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "body":
+				break loop
+			case "title":
+				if title == "" && z.Next() == html.TextToken {
+					title = string(z.Text())
+				}
+			case "meta":
+				if !hasAttr {
+					continue
+				}
+				key, content := metaAttrs(z)
+				switch key {
+				case "og:title":
+					p.Title = content
+				case "og:description":
+					p.Description = content
+				case "og:image", "og:image:url", "og:image:secure_url":
+					if p.ImageURL == "" {
+						p.ImageURL = content
+					}
+				case "og:site_name":
+					p.SiteName = content
+				case "description":
+					description = content
+				}
+			}
+		}
+	}
+
+	if p.Title == "" {
+		p.Title = title
+	}
+	if p.Description == "" {
+		p.Description = description
+	}
+	p.Title = clean(p.Title, maxTitleLength)
+	p.Description = clean(p.Description, maxDescriptionLength)
+	p.SiteName = clean(p.SiteName, maxTitleLength)
+	p.ImageURL = resolveImage(base, p.ImageURL)
+
+	return p
+}
+
+// metaAttrs возвращает property или name тега meta в нижнем регистре и его content
+func metaAttrs(z *html.Tokenizer) (string, string) {
+	var key, content string
+	for {
+		name, value, more := z.TagAttr()
+		switch string(name) {
+		case "property":
+			key = strings.ToLower(string(value))
+		case "name":
+			if key == "" {
+				key = strings.ToLower(string(value))
+			}
+		case "content":
+			content = string(value)
+		}
+		if !more {
+			return key, content
+		}
+	}
+}
+
+// clean схлопывает пробелы и обрезает текст до limit символов
+func clean(s string, limit int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:limit]) + "…"
+}
+
+// resolveImage делает адрес картинки абсолютным. Картинки не по http(s) отбрасываются
+func resolveImage(base *url.URL, image string) string {
+	if image == "" {
+		return ""
+	}
+	u, err := base.Parse(strings.TrimSpace(image))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,165 @@
+package linkpreview
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -destination=./mock/mock.go -package=mock -source=previewer.go
+
+// Repo - кэш карточек по url и их привязка к сообщениям
+type Repo interface {
+	GetLinkPreview(ctx context.Context, url string) (domain.LinkPreview, bool, error)
+	SaveLinkPreview(ctx context.Context, p domain.LinkPreview) error
+	AttachLinkPreviews(ctx context.Context, chatID, messageID int, text string, urls []string) ([]domain.LinkPreview, error)
+	GetChatMembers(ctx context.Context, chatID int) ([]int, error)
+}
+
+type PageFetcher interface {
+	Fetch(ctx context.Context, url string) (domain.LinkPreview, error)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, event domain.Event) error
+}
+
+// ErrQueueFull - сообщение осталось без карточек, потому что загрузка страниц не успевает за новыми сообщениями
+var ErrQueueFull = errors.New("link preview queue is full")
+
+type job struct {
+	chatID    int
+	messageID int
+	text      string
+	urls      []string
+}
+
+// Previewer собирает карточки ссылок для новых и отредактированных сообщений. Страницы загружаются в фоне,
+// готовые карточки сохраняются в сообщении и рассылаются участникам чата событием message_link_previews
+type Previewer struct {
+	cfg      Config
+	repo     Repo
+	fetcher  PageFetcher
+	notifier Notifier
+	queue    chan job
+}
+
+func New(cfg Config, repo Repo, fetcher PageFetcher, notifier Notifier) *Previewer {
+	return &Previewer{
+		cfg:      cfg,
+		repo:     repo,
+		fetcher:  fetcher,
+		notifier: notifier,
+		queue:    make(chan job, cfg.QueueSize),
+	}
+}
+
+// Notify ставит в очередь сообщение из события message_created или message_edited, если в его тексте есть ссылки.
+// Текст зашифрованных сообщений сервер не видит, их ссылки остаются без карточек
+func (p *Previewer) Notify(_ context.Context, event domain.Event) error {
+	if event.Type != domain.EventMessageCreated && event.Type != domain.EventMessageEdited {
+		return nil
+	}
+	msg, ok := event.Payload.(domain.Message)
+	if !ok || msg.Envelope != nil || msg.DeletedAt != nil {
+		return nil
+	}
+
+	urls := MessageURLs(msg.Text, msg.Entities, p.cfg.MaxLinks)
+	if len(urls) == 0 {
+		return nil
+	}
+
+	select {
+	case p.queue <- job{chatID: event.ChatId, messageID: msg.Id, text: msg.Text, urls: urls}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run обрабатывает очередь, пока не отменен ctx
+func (p *Previewer) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range max(p.cfg.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-p.queue:
+					p.process(ctx, j)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Previewer) process(ctx context.Context, j job) {
+	l := logger.GetFromCtx(ctx)
+
+	for _, u := range j.urls {
+		if err := p.refresh(ctx, u); err != nil {
+			l.ErrorContext(ctx, "failed to refresh link preview", err)
+		}
+	}
+
+	previews, err := p.repo.AttachLinkPreviews(ctx, j.chatID, j.messageID, j.text, j.urls)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to attach link previews", err)
+		return
+	}
+	if len(previews) == 0 {
+		return
+	}
+
+	members, err := p.repo.GetChatMembers(ctx, j.chatID)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to get chat members for event", err)
+		return
+	}
+
+	err = p.notifier.Notify(ctx, domain.Event{
+		Type:    domain.EventLinkPreviews,
+		ChatId:  j.chatID,
+		UserIds: members,
+		Payload: domain.MessageLinkPreviews{ChatId: j.chatID, MessageId: j.messageID, LinkPreviews: previews},
+	})
+	if err != nil {
+		l.ErrorContext(ctx, "failed to notify", err)
+	}
+}
+
+// refresh загружает страницу, если карточки url нет в кэше или она устарела. Неудачная загрузка
+// кэшируется пустой карточкой на FailureTTL, чтобы недоступную страницу не запрашивало каждое сообщение
+func (p *Previewer) refresh(ctx context.Context, url string) error {
+	cached, found, err := p.repo.GetLinkPreview(ctx, url)
+	if err != nil {
+		return err
+	}
+	if found && p.fresh(cached) {
+		return nil
+	}
+
+	preview, err := p.fetcher.Fetch(ctx, url)
+	if err != nil {
+		logger.GetFromCtx(ctx).InfoContext(ctx, "failed to fetch link preview", "url", url, "error", err.Error())
+		preview = domain.LinkPreview{URL: url}
+	}
+
+	return p.repo.SaveLinkPreview(ctx, preview)
+}
+
+func (p *Previewer) fresh(preview domain.LinkPreview) bool {
+	ttl := p.cfg.CacheTTL
+	if preview.Title == "" {
+		ttl = p.cfg.FailureTTL
+	}
+	return time.Since(preview.FetchedAt) < ttl
+}
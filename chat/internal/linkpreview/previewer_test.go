@@ -0,0 +1,111 @@
+package linkpreview
+
+import (
+	"chat/internal/domain"
+	"chat/internal/linkpreview/mock"
+	"chat/internal/netguard"
+	"chat/pkg/logger"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockRepo(ctrl)
+	fetcher := mock.NewMockPageFetcher(ctrl)
+	notifier := mock.NewMockNotifier(ctrl)
+
+	ctx, cancel := context.WithCancel(logger.InitFromCtx(context.Background(), logger.New()))
+	defer cancel()
+
+	cfg := Config{Workers: 1, QueueSize: 10, MaxLinks: 3, CacheTTL: time.Hour, FailureTTL: time.Minute}
+	p := New(cfg, repo, fetcher, notifier)
+	go p.Run(ctx)
+
+	text := "смотри https://example.com/a и https://example.com/b"
+	urls := []string{"https://example.com/a", "https://example.com/b"}
+	fetched := domain.LinkPreview{URL: urls[1], Title: "B"}
+
+	t.Run("fetches missing and reuses cached", func(t *testing.T) {
+		done := make(chan struct{})
+		repo.EXPECT().GetLinkPreview(gomock.Any(), urls[0]).
+			Return(domain.LinkPreview{URL: urls[0], Title: "A", FetchedAt: time.Now()}, true, nil)
+		repo.EXPECT().GetLinkPreview(gomock.Any(), urls[1]).Return(domain.LinkPreview{}, false, nil)
+		fetcher.EXPECT().Fetch(gomock.Any(), urls[1]).Return(fetched, nil)
+		repo.EXPECT().SaveLinkPreview(gomock.Any(), fetched).Return(nil)
+		attached := []domain.LinkPreview{{URL: urls[0], Title: "A"}, fetched}
+		repo.EXPECT().AttachLinkPreviews(gomock.Any(), 1, 7, text, urls).Return(attached, nil)
+		repo.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{1, 2}, nil)
+		notifier.EXPECT().Notify(gomock.Any(), domain.Event{
+			Type:    domain.EventLinkPreviews,
+			ChatId:  1,
+			UserIds: []int{1, 2},
+			Payload: domain.MessageLinkPreviews{ChatId: 1, MessageId: 7, LinkPreviews: attached},
+		}).DoAndReturn(func(context.Context, domain.Event) error {
+			close(done)
+			return nil
+		})
+
+		require.NoError(t, p.Notify(ctx, domain.Event{
+			Type:    domain.EventMessageCreated,
+			ChatId:  1,
+			UserIds: []int{2},
+			Payload: domain.Message{Id: 7, SenderId: "1", Text: text},
+		}))
+		waitDone(t, done)
+	})
+
+	t.Run("failed fetch is cached and not announced", func(t *testing.T) {
+		done := make(chan struct{})
+		stale := domain.LinkPreview{URL: urls[0], FetchedAt: time.Now().Add(-2 * time.Minute)}
+		repo.EXPECT().GetLinkPreview(gomock.Any(), urls[0]).Return(stale, true, nil)
+		fetcher.EXPECT().Fetch(gomock.Any(), urls[0]).Return(domain.LinkPreview{}, netguard.ErrForbiddenAddress)
+		repo.EXPECT().SaveLinkPreview(gomock.Any(), domain.LinkPreview{URL: urls[0]}).Return(nil)
+		repo.EXPECT().AttachLinkPreviews(gomock.Any(), 1, 8, urls[0], urls[:1]).DoAndReturn(
+			func(context.Context, int, int, string, []string) ([]domain.LinkPreview, error) {
+				close(done)
+				return nil, nil
+			})
+
+		require.NoError(t, p.Notify(ctx, domain.Event{
+			Type:    domain.EventMessageEdited,
+			ChatId:  1,
+			Payload: domain.Message{Id: 8, SenderId: "1", Text: urls[0]},
+		}))
+		waitDone(t, done)
+	})
+
+	t.Run("ignored events", func(t *testing.T) {
+		events := []domain.Event{
+			{Type: domain.EventRead, ChatId: 1, Payload: domain.ReadReceipt{}},
+			{Type: domain.EventMessageCreated, ChatId: 1, Payload: domain.Message{Id: 9, Text: "без ссылок"}},
+			{Type: domain.EventMessageCreated, ChatId: 1, Payload: domain.Message{Id: 10, Envelope: &domain.Envelope{}}},
+		}
+		for _, e := range events {
+			assert.NoError(t, p.Notify(ctx, e))
+		}
+		assert.Empty(t, p.queue)
+	})
+}
+
+func TestPreviewer_QueueFull(t *testing.T) {
+	p := New(Config{QueueSize: 1, MaxLinks: 1}, nil, nil, nil)
+	event := domain.Event{Type: domain.EventMessageCreated, Payload: domain.Message{Id: 1, Text: "https://example.com"}}
+
+	assert.NoError(t, p.Notify(context.Background(), event))
+	assert.ErrorIs(t, p.Notify(context.Background(), event), ErrQueueFull)
+}
+
+func waitDone(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("link previews were not processed")
+	}
+}
@@ -0,0 +1,69 @@
+package linkpreview
+
+import (
+	"chat/internal/domain"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"'«»]+`)
+
+// ExtractURLs возвращает первые limit различных http(s)-ссылок текста в порядке появления.
+// Знаки препинания в конце ссылки считаются частью предложения, закрывающая скобка - частью ссылки,
+// только если в ссылке есть открывающая
+func ExtractURLs(text string, limit int) []string {
+	var urls []string
+	for _, raw := range urlPattern.FindAllString(text, -1) {
+		if len(urls) >= limit {
+			break
+		}
+		raw = trimPunctuation(raw)
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		raw = u.String()
+		if !slices.Contains(urls, raw) {
+			urls = append(urls, raw)
+		}
+	}
+	return urls
+}
+
+// MessageURLs дополняет ссылки из текста адресами http(s)-ссылок разметки, которые в тексте не видны
+func MessageURLs(text string, entities []domain.MessageEntity, limit int) []string {
+	urls := ExtractURLs(text, limit)
+	for _, e := range entities {
+		if len(urls) >= limit {
+			break
+		}
+		if e.Type != domain.EntityLink {
+			continue
+		}
+		u, err := url.Parse(e.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			continue
+		}
+		if raw := u.String(); !slices.Contains(urls, raw) {
+			urls = append(urls, raw)
+		}
+	}
+	return urls
+}
+
+func trimPunctuation(s string) string {
+	for s != "" {
+		last := s[len(s)-1]
+		switch {
+		case strings.IndexByte(".,;:!?", last) >= 0:
+			s = s[:len(s)-1]
+		case last == ')' && strings.Count(s, "(") < strings.Count(s, ")"):
+			s = s[:len(s)-1]
+		default:
+			return s
+		}
+	}
+	return s
+}
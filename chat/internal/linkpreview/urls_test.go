@@ -0,0 +1,42 @@
+package linkpreview
+
+import (
+	"chat/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractURLs(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{"no links", "привет, как дела?", 3, nil},
+		{"punctuation", "смотри https://example.com/a, и http://example.org.", 3, []string{"https://example.com/a", "http://example.org"}},
+		{"parentheses", "(см. https://en.wikipedia.org/wiki/Go_(language))", 3, []string{"https://en.wikipedia.org/wiki/Go_(language)"}},
+		{"duplicates", "https://example.com https://example.com", 3, []string{"https://example.com"}},
+		{"limit", "https://a.com https://b.com https://c.com", 2, []string{"https://a.com", "https://b.com"}},
+		{"other schemes", "ftp://example.com javascript:alert(1)", 3, nil},
+		{"quotes", "«https://example.com/путь»", 3, []string{"https://example.com/%D0%BF%D1%83%D1%82%D1%8C"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractURLs(tt.text, tt.limit))
+		})
+	}
+}
+
+func TestMessageURLs(t *testing.T) {
+	entities := []domain.MessageEntity{
+		{Type: domain.EntityLink, Offset: 0, Length: 4, URL: "https://b.com"},
+		{Type: domain.EntityLink, Offset: 5, Length: 4, URL: "https://a.com"},
+		{Type: domain.EntityLink, Offset: 10, Length: 4, URL: "mailto:a@example.com"},
+		{Type: domain.EntityLink, Offset: 15, Length: 4, URL: "https://c.com"},
+	}
+
+	assert.Equal(t, []string{"https://a.com", "https://b.com"}, MessageURLs("сайт https://a.com", entities, 2))
+	assert.Equal(t, []string{"https://b.com", "https://a.com", "https://c.com"}, MessageURLs("без ссылок", entities, 3))
+}
@@ -0,0 +1,64 @@
+// Package netguard не дает исходящим запросам по адресам от пользователей уйти во внутреннюю сеть
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress - адрес или редирект ведут во внутреннюю сеть
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// blockedPrefixes - диапазоны, не покрытые проверками netip.Addr: общий NAT, служебные, тестовые и
+// зарезервированные сети, а также NAT64 и 6to4, через которые IPv6-адрес может вести на внутренний IPv4
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// PublicAddress разрешает соединения только с публичными адресами
+func PublicAddress(addr netip.AddrPort) bool {
+	ip := addr.Addr().Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Dialer соединяется только с адресами, которые разрешает allow. Адрес проверяется в момент соединения,
+// уже после разрешения имени, поэтому ни редирект, ни DNS-запись не уведут запрос во внутреннюю сеть.
+// Транспорт с таким Dialer должен работать без прокси: прокси соединялся бы с целью сам, в обход проверки
+func Dialer(timeout time.Duration, allow func(netip.AddrPort) bool) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+			}
+			if !allow(addr) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr.Addr())
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"10.1.2.3:80", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"100.64.0.1:80", false},
+		{"0.0.0.0:80", false},
+		{"[::1]:80", false},
+		{"[fd00::1]:80", false},
+		{"[fe80::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"[64:ff9b::a00:1]:80", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, PublicAddress(netip.MustParseAddrPort(tt.addr)))
+		})
+	}
+}
+
+func TestDialer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	_, err = Dialer(time.Second, PublicAddress).DialContext(context.Background(), "tcp", ln.Addr().String())
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+
+	conn, err := Dialer(time.Second, func(netip.AddrPort) bool { return true }).DialContext(context.Background(), "tcp", ln.Addr().String())
+	require.NoError(t, err)
+	conn.Close()
+}
@@ -0,0 +1,12 @@
+package notifications
+
+import "time"
+
+type Config struct {
+	// Workers - сколько уведомлений отправляется параллельно, QueueSize - сколько ждет отправки.
+	// При переполненной очереди новые уведомления отбрасываются
+	Workers   int `env:"PUSH_WORKERS" envDefault:"4"`
+	QueueSize int `env:"PUSH_QUEUE_SIZE" envDefault:"1024"`
+	// PruneInterval - период удаления подписок с истекшим сроком
+	PruneInterval time.Duration `env:"PUSH_PRUNE_INTERVAL" envDefault:"1h"`
+}
@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pusher.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "chat/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSubscriptionRepo is a mock of SubscriptionRepo interface.
+type MockSubscriptionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionRepoMockRecorder
+}
+
+// MockSubscriptionRepoMockRecorder is the mock recorder for MockSubscriptionRepo.
+type MockSubscriptionRepoMockRecorder struct {
+	mock *MockSubscriptionRepo
+}
+
+// NewMockSubscriptionRepo creates a new mock instance.
+func NewMockSubscriptionRepo(ctrl *gomock.Controller) *MockSubscriptionRepo {
+	mock := &MockSubscriptionRepo{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionRepo) EXPECT() *MockSubscriptionRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredPushSubscriptions mocks base method.
+func (m *MockSubscriptionRepo) DeleteExpiredPushSubscriptions(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredPushSubscriptions", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredPushSubscriptions indicates an expected call of DeleteExpiredPushSubscriptions.
+func (mr *MockSubscriptionRepoMockRecorder) DeleteExpiredPushSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredPushSubscriptions", reflect.TypeOf((*MockSubscriptionRepo)(nil).DeleteExpiredPushSubscriptions), ctx)
+}
+
+// GetPushSubscriptions mocks base method.
+func (m *MockSubscriptionRepo) GetPushSubscriptions(ctx context.Context, userIDs []int) ([]domain.PushSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPushSubscriptions", ctx, userIDs)
+	ret0, _ := ret[0].([]domain.PushSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPushSubscriptions indicates an expected call of GetPushSubscriptions.
+func (mr *MockSubscriptionRepoMockRecorder) GetPushSubscriptions(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPushSubscriptions", reflect.TypeOf((*MockSubscriptionRepo)(nil).GetPushSubscriptions), ctx, userIDs)
+}
+
+// RemovePushSubscriptions mocks base method.
+func (m *MockSubscriptionRepo) RemovePushSubscriptions(ctx context.Context, endpoints []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePushSubscriptions", ctx, endpoints)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePushSubscriptions indicates an expected call of RemovePushSubscriptions.
+func (mr *MockSubscriptionRepoMockRecorder) RemovePushSubscriptions(ctx, endpoints interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePushSubscriptions", reflect.TypeOf((*MockSubscriptionRepo)(nil).RemovePushSubscriptions), ctx, endpoints)
+}
+
+// MockSender is a mock of Sender interface.
+type MockSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSenderMockRecorder
+}
+
+// MockSenderMockRecorder is the mock recorder for MockSender.
+type MockSenderMockRecorder struct {
+	mock *MockSender
+}
+
+// NewMockSender creates a new mock instance.
+func NewMockSender(ctrl *gomock.Controller) *MockSender {
+	mock := &MockSender{ctrl: ctrl}
+	mock.recorder = &MockSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSender) EXPECT() *MockSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockSender) Send(ctx context.Context, sub domain.PushSubscription, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, sub, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSenderMockRecorder) Send(ctx, sub, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSender)(nil).Send), ctx, sub, payload)
+}
+
+// MockPresence is a mock of Presence interface.
+type MockPresence struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresenceMockRecorder
+}
+
+// MockPresenceMockRecorder is the mock recorder for MockPresence.
+type MockPresenceMockRecorder struct {
+	mock *MockPresence
+}
+
+// NewMockPresence creates a new mock instance.
+func NewMockPresence(ctrl *gomock.Controller) *MockPresence {
+	mock := &MockPresence{ctrl: ctrl}
+	mock.recorder = &MockPresenceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPresence) EXPECT() *MockPresenceMockRecorder {
+	return m.recorder
+}
+
+// OnlineUsers mocks base method.
+func (m *MockPresence) OnlineUsers(ctx context.Context, userIDs []int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnlineUsers", ctx, userIDs)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OnlineUsers indicates an expected call of OnlineUsers.
+func (mr *MockPresenceMockRecorder) OnlineUsers(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnlineUsers", reflect.TypeOf((*MockPresence)(nil).OnlineUsers), ctx, userIDs)
+}
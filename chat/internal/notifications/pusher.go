@@ -0,0 +1,225 @@
+package notifications
+
+import (
+	"chat/internal/domain"
+	"chat/internal/transport/webpush"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+//go:generate mockgen -destination=./mock/mock.go -package=mock -source=pusher.go
+
+type SubscriptionRepo interface {
+	GetPushSubscriptions(ctx context.Context, userIDs []int) ([]domain.PushSubscription, error)
+	RemovePushSubscriptions(ctx context.Context, endpoints []string) error
+	DeleteExpiredPushSubscriptions(ctx context.Context) (int64, error)
+}
+
+type Sender interface {
+	Send(ctx context.Context, sub domain.PushSubscription, payload []byte) error
+}
+
+// Presence сообщает, у кого из пользователей есть открытое websocket-соединение
+type Presence interface {
+	OnlineUsers(ctx context.Context, userIDs []int) ([]int, error)
+}
+
+// previewLength - сколько символов текста сообщения попадает в уведомление
+const previewLength = 200
+
+// ErrQueueFull - уведомление отброшено, потому что отправка не успевает за новыми сообщениями
+var ErrQueueFull = errors.New("push queue is full")
+
+// Payload - содержимое push-уведомления, его показывает service worker сайта
+type Payload struct {
+	Type      string `json:"type"`
+	ChatId    int    `json:"chat_id"`
+	MessageId int    `json:"message_id"`
+	SenderId  int    `json:"sender_id"`
+	Text      string `json:"text"`
+}
+
+type job struct {
+	userIDs []int
+	payload Payload
+}
+
+// Pusher отправляет Web Push уведомления о новых сообщениях получателям без открытого websocket-соединения.
+// Получатели, отключившие уведомления чата, пропускаются. Отправка идет в фоне, Notify не ждет push-сервисов
+type Pusher struct {
+	cfg      Config
+	repo     SubscriptionRepo
+	sender   Sender
+	presence Presence
+	queue    chan job
+}
+
+func New(cfg Config, repo SubscriptionRepo, sender Sender, presence Presence) *Pusher {
+	return &Pusher{
+		cfg:      cfg,
+		repo:     repo,
+		sender:   sender,
+		presence: presence,
+		queue:    make(chan job, cfg.QueueSize),
+	}
+}
+
+// Notify ставит в очередь уведомление о событии message_created, остальные события игнорируются
+func (p *Pusher) Notify(_ context.Context, event domain.Event) error {
+	if event.Type != domain.EventMessageCreated {
+		return nil
+	}
+	msg, ok := event.Payload.(domain.Message)
+	if !ok {
+		return nil
+	}
+
+	recipients := make([]int, 0, len(event.UserIds))
+	for _, id := range event.UserIds {
+		if !slices.Contains(event.MutedUserIds, id) {
+			recipients = append(recipients, id)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	senderID, _ := strconv.Atoi(msg.SenderId)
+	j := job{
+		userIDs: recipients,
+		payload: Payload{
+			Type:      event.Type,
+			ChatId:    event.ChatId,
+			MessageId: msg.Id,
+			SenderId:  senderID,
+			Text:      preview(msg),
+		},
+	}
+
+	select {
+	case p.queue <- j:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run отправляет уведомления из очереди и периодически удаляет истекшие подписки, пока не отменен ctx
+func (p *Pusher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range max(p.cfg.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-p.queue:
+					p.push(ctx, j)
+				}
+			}
+		}()
+	}
+
+	if p.cfg.PruneInterval > 0 {
+		ticker := time.NewTicker(p.cfg.PruneInterval)
+		defer ticker.Stop()
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case <-ticker.C:
+				p.prune(ctx)
+			}
+		}
+	}
+
+	wg.Wait()
+}
+
+func (p *Pusher) push(ctx context.Context, j job) {
+	l := logger.GetFromCtx(ctx)
+
+	offline := j.userIDs
+	online, err := p.presence.OnlineUsers(ctx, j.userIDs)
+	if err != nil {
+		// Лишнее уведомление лучше пропущенного: без данных о соединениях отправляем всем
+		l.ErrorContext(ctx, "failed to get online users", err)
+	} else {
+		offline = slices.DeleteFunc(slices.Clone(j.userIDs), func(id int) bool {
+			return slices.Contains(online, id)
+		})
+	}
+	if len(offline) == 0 {
+		return
+	}
+
+	subs, err := p.repo.GetPushSubscriptions(ctx, offline)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to get push subscriptions", err)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(j.payload)
+	if err != nil {
+		l.ErrorContext(ctx, "failed to marshal push payload", err)
+		return
+	}
+
+	var gone []string
+	for _, sub := range subs {
+		err := p.sender.Send(ctx, sub, payload)
+		switch {
+		case errors.Is(err, webpush.ErrSubscriptionGone):
+			gone = append(gone, sub.Endpoint)
+		case err != nil:
+			l.ErrorContext(ctx, "failed to send push", err)
+		}
+	}
+
+	if len(gone) > 0 {
+		if err := p.repo.RemovePushSubscriptions(ctx, gone); err != nil {
+			l.ErrorContext(ctx, "failed to remove gone push subscriptions", err)
+		}
+	}
+}
+
+func (p *Pusher) prune(ctx context.Context) {
+	deleted, err := p.repo.DeleteExpiredPushSubscriptions(ctx)
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to delete expired push subscriptions", err)
+		return
+	}
+	if deleted > 0 {
+		logger.GetFromCtx(ctx).InfoContext(ctx, "expired push subscriptions deleted", "count", deleted)
+	}
+}
+
+// preview - начало текста сообщения, без текста - отметка о вложении.
+// Зашифрованное сообщение сервер прочитать не может, поэтому в уведомлении только отметка о шифровании
+func preview(msg domain.Message) string {
+	text := msg.Text
+	if msg.Envelope != nil {
+		return "🔒 Зашифрованное сообщение"
+	}
+	if text == "" && len(msg.Attachments) > 0 {
+		return "📎 " + msg.Attachments[0].FileName
+	}
+	if utf8.RuneCountInString(text) <= previewLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:previewLength]) + "…"
+}
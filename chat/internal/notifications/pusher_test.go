@@ -0,0 +1,122 @@
+package notifications
+
+import (
+	"chat/internal/domain"
+	"chat/internal/notifications/mock"
+	"chat/internal/transport/webpush"
+	"chat/internal/transport/webpush/webpushtest"
+	"chat/pkg/logger"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowAll пускает в локальную сеть, где работает httptest
+func allowAll(netip.AddrPort) bool { return true }
+
+func newSender(t *testing.T) *webpush.Client {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sender, err := webpush.NewWithAddressCheck(webpush.Config{
+		PrivateKey: base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		Subject:    "mailto:admin@example.com",
+		TTL:        time.Hour,
+		Timeout:    time.Second,
+	}, allowAll)
+	require.NoError(t, err)
+	return sender
+}
+
+func TestPusher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockSubscriptionRepo(ctrl)
+	presence := mock.NewMockPresence(ctrl)
+	pushService := webpushtest.NewServer()
+	defer pushService.Close()
+
+	ctx, cancel := context.WithCancel(logger.InitFromCtx(context.Background(), logger.New()))
+	defer cancel()
+
+	p := New(Config{Workers: 1, QueueSize: 10}, repo, newSender(t), presence)
+	go p.Run(ctx)
+
+	msg := domain.Message{Id: 7, SenderId: "1", Text: "Привет"}
+
+	t.Run("offline and not muted recipients", func(t *testing.T) {
+		phone := pushService.NewSubscription(2)
+		laptop := pushService.NewSubscription(2)
+		pushService.Expire(laptop.Endpoint)
+
+		done := make(chan struct{})
+		presence.EXPECT().OnlineUsers(gomock.Any(), []int{2, 4}).Return([]int{4}, nil)
+		repo.EXPECT().GetPushSubscriptions(gomock.Any(), []int{2}).Return([]domain.PushSubscription{phone, laptop}, nil)
+		repo.EXPECT().RemovePushSubscriptions(gomock.Any(), []string{laptop.Endpoint}).DoAndReturn(
+			func(context.Context, []string) error {
+				close(done)
+				return nil
+			})
+
+		require.NoError(t, p.Notify(ctx, domain.Event{
+			Type:         domain.EventMessageCreated,
+			ChatId:       1,
+			UserIds:      []int{2, 3, 4},
+			MutedUserIds: []int{3},
+			Payload:      msg,
+		}))
+		<-done
+
+		pushes := pushService.Pushes()
+		require.Len(t, pushes, 1)
+		assert.Equal(t, phone.Endpoint, pushes[0].Endpoint)
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(pushes[0].Payload, &payload))
+		assert.Equal(t, Payload{Type: domain.EventMessageCreated, ChatId: 1, MessageId: 7, SenderId: 1, Text: "Привет"}, payload)
+	})
+
+	t.Run("presence unavailable", func(t *testing.T) {
+		sub := pushService.NewSubscription(5)
+		sent := len(pushService.Pushes())
+
+		presence.EXPECT().OnlineUsers(gomock.Any(), []int{5}).Return(nil, errors.New("websocket is down"))
+		repo.EXPECT().GetPushSubscriptions(gomock.Any(), []int{5}).Return([]domain.PushSubscription{sub}, nil)
+
+		require.NoError(t, p.Notify(ctx, domain.Event{Type: domain.EventMessageCreated, ChatId: 2, UserIds: []int{5}, Payload: msg}))
+
+		assert.Eventually(t, func() bool { return len(pushService.Pushes()) == sent+1 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("other events and muted only", func(t *testing.T) {
+		assert.NoError(t, p.Notify(ctx, domain.Event{Type: domain.EventRead, ChatId: 1, UserIds: []int{2}}))
+		assert.NoError(t, p.Notify(ctx, domain.Event{Type: domain.EventMessageCreated, ChatId: 1, UserIds: []int{3}, MutedUserIds: []int{3}, Payload: msg}))
+		assert.Empty(t, p.queue)
+	})
+}
+
+func TestPusher_QueueFull(t *testing.T) {
+	p := New(Config{QueueSize: 1}, nil, nil, nil)
+	event := domain.Event{Type: domain.EventMessageCreated, ChatId: 1, UserIds: []int{2}, Payload: domain.Message{Id: 1}}
+
+	require.NoError(t, p.Notify(context.Background(), event))
+	assert.ErrorIs(t, p.Notify(context.Background(), event), ErrQueueFull)
+}
+
+func TestPreview(t *testing.T) {
+	long := strings.Repeat("я", previewLength+10)
+
+	assert.Equal(t, "привет", preview(domain.Message{Text: "привет"}))
+	assert.Equal(t, strings.Repeat("я", previewLength)+"…", preview(domain.Message{Text: long}))
+	assert.Equal(t, "📎 photo.png", preview(domain.Message{Attachments: []domain.Attachment{{FileName: "photo.png"}}}))
+	assert.Equal(t, "🔒 Зашифрованное сообщение", preview(domain.Message{Envelope: &domain.Envelope{Ciphertext: "AQID"}}))
+}
@@ -0,0 +1,11 @@
+package retention
+
+import "time"
+
+type Config struct {
+	// Interval - период запуска очистки. За один запуск удаляются все истекшие сообщения
+	// порциями по BatchSize с паузой BatchPause, чтобы не держать долгие блокировки и не нагружать базу
+	Interval   time.Duration `env:"RETENTION_INTERVAL" envDefault:"1m"`
+	BatchSize  int           `env:"RETENTION_BATCH_SIZE" envDefault:"500"`
+	BatchPause time.Duration `env:"RETENTION_BATCH_PAUSE" envDefault:"100ms"`
+}
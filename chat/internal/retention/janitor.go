@@ -0,0 +1,71 @@
+package retention
+
+import (
+	"chat/pkg/logger"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=./mock/mock.go -package=mock -source=janitor.go
+
+// Expirer удаляет одну порцию истекших сообщений и возвращает число удаленных
+type Expirer interface {
+	ExpireMessages(ctx context.Context, limit int) (int, error)
+}
+
+// Janitor в фоне удаляет сообщения, у которых истек срок хранения
+type Janitor struct {
+	cfg     Config
+	expirer Expirer
+}
+
+func New(cfg Config, expirer Expirer) *Janitor {
+	return &Janitor{cfg: cfg, expirer: expirer}
+}
+
+// Run запускает очистку раз в Interval, пока не отменен ctx
+func (j *Janitor) Run(ctx context.Context) {
+	if j.cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.sweep(ctx)
+		}
+	}
+}
+
+// sweep удаляет истекшие сообщения порциями, пока порция заполняется целиком
+func (j *Janitor) sweep(ctx context.Context) {
+	batchSize := max(j.cfg.BatchSize, 1)
+	total := 0
+	defer func() {
+		if total > 0 {
+			logger.GetFromCtx(ctx).InfoContext(ctx, "expired messages deleted", "count", total)
+		}
+	}()
+
+	for {
+		deleted, err := j.expirer.ExpireMessages(ctx, batchSize)
+		if err != nil {
+			logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to delete expired messages", err)
+			return
+		}
+		total += deleted
+		if deleted < batchSize {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(j.cfg.BatchPause):
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package retention
+
+import (
+	"chat/internal/retention/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestJanitor_Sweep(t *testing.T) {
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("deletes batches until a partial one", func(t *testing.T) {
+		expirer := mock.NewMockExpirer(gomock.NewController(t))
+		j := New(Config{BatchSize: 2}, expirer)
+
+		gomock.InOrder(
+			expirer.EXPECT().ExpireMessages(gomock.Any(), 2).Return(2, nil),
+			expirer.EXPECT().ExpireMessages(gomock.Any(), 2).Return(2, nil),
+			expirer.EXPECT().ExpireMessages(gomock.Any(), 2).Return(1, nil),
+		)
+
+		j.sweep(ctx)
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		expirer := mock.NewMockExpirer(gomock.NewController(t))
+		j := New(Config{BatchSize: 2}, expirer)
+
+		expirer.EXPECT().ExpireMessages(gomock.Any(), 2).Return(0, errors.New("db is down"))
+
+		j.sweep(ctx)
+	})
+
+	t.Run("stops when cancelled between batches", func(t *testing.T) {
+		expirer := mock.NewMockExpirer(gomock.NewController(t))
+		j := New(Config{BatchSize: 2, BatchPause: time.Hour}, expirer)
+
+		ctx, cancel := context.WithCancel(ctx)
+		expirer.EXPECT().ExpireMessages(gomock.Any(), 2).DoAndReturn(func(context.Context, int) (int, error) {
+			cancel()
+			return 2, nil
+		})
+
+		j.sweep(ctx)
+	})
+}
+
+func TestJanitor_Run(t *testing.T) {
+	expirer := mock.NewMockExpirer(gomock.NewController(t))
+	j := New(Config{Interval: 10 * time.Millisecond, BatchSize: 10}, expirer)
+
+	ctx, cancel := context.WithCancel(logger.InitFromCtx(context.Background(), logger.New()))
+	expirer.EXPECT().ExpireMessages(gomock.Any(), 10).DoAndReturn(func(context.Context, int) (int, error) {
+		cancel()
+		return 0, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		j.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Janitor.Run() did not stop after cancel")
+	}
+}
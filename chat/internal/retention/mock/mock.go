@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: janitor.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockExpirer is a mock of Expirer interface.
+type MockExpirer struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpirerMockRecorder
+}
+
+// MockExpirerMockRecorder is the mock recorder for MockExpirer.
+type MockExpirerMockRecorder struct {
+	mock *MockExpirer
+}
+
+// NewMockExpirer creates a new mock instance.
+func NewMockExpirer(ctrl *gomock.Controller) *MockExpirer {
+	mock := &MockExpirer{ctrl: ctrl}
+	mock.recorder = &MockExpirerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpirer) EXPECT() *MockExpirerMockRecorder {
+	return m.recorder
+}
+
+// ExpireMessages mocks base method.
+func (m *MockExpirer) ExpireMessages(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireMessages", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireMessages indicates an expected call of ExpireMessages.
+func (mr *MockExpirerMockRecorder) ExpireMessages(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireMessages", reflect.TypeOf((*MockExpirer)(nil).ExpireMessages), ctx, limit)
+}
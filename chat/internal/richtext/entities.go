@@ -0,0 +1,162 @@
+package richtext
+
+import (
+	"chat/internal/domain"
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// MaxEntities - сколько разметки допускается в одном сообщении
+	MaxEntities = 100
+	// maxURLLength - длина адреса ссылки
+	maxURLLength = 2048
+)
+
+// Validate проверяет разметку текста от клиента и возвращает ее упорядоченной по началу участка.
+// Участки должны лежать внутри текста, не разрезать суррогатные пары и либо не пересекаться, либо
+// вкладываться друг в друга. Внутри кода другой разметки нет, ссылки и упоминания не вкладываются друг в друга
+func Validate(text string, entities []domain.MessageEntity) ([]domain.MessageEntity, error) {
+	if len(entities) == 0 {
+		return nil, nil
+	}
+	if len(entities) > MaxEntities {
+		return nil, fmt.Errorf("%w: more than %d entities", domain.ErrInvalidEntities, MaxEntities)
+	}
+
+	boundaries := runeBoundaries(text)
+	sorted := slices.Clone(entities)
+	sortEntities(sorted)
+
+	var open []domain.MessageEntity
+	for _, e := range sorted {
+		if err := checkEntity(e, boundaries); err != nil {
+			return nil, err
+		}
+
+		for len(open) > 0 && end(open[len(open)-1]) <= e.Offset {
+			open = open[:len(open)-1]
+		}
+		for _, outer := range open {
+			if end(e) > end(outer) {
+				return nil, fmt.Errorf("%w: %s at %d overlaps %s at %d", domain.ErrInvalidEntities, e.Type, e.Offset, outer.Type, outer.Offset)
+			}
+			if !canNest(outer.Type, e.Type) {
+				return nil, fmt.Errorf("%w: %s cannot be inside %s", domain.ErrInvalidEntities, e.Type, outer.Type)
+			}
+		}
+		open = append(open, e)
+	}
+
+	return sorted, nil
+}
+
+// Mentions возвращает упомянутых в разметке пользователей без повторов
+func Mentions(entities []domain.MessageEntity) []int {
+	var ids []int
+	for _, e := range entities {
+		if e.Type == domain.EntityMention && !slices.Contains(ids, e.UserId) {
+			ids = append(ids, e.UserId)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// WithoutMentions убирает упоминания, оставляя их текст обычным
+func WithoutMentions(entities []domain.MessageEntity) []domain.MessageEntity {
+	return slices.DeleteFunc(slices.Clone(entities), func(e domain.MessageEntity) bool {
+		return e.Type == domain.EntityMention
+	})
+}
+
+func checkEntity(e domain.MessageEntity, boundaries []bool) error {
+	if e.Length <= 0 || e.Offset < 0 || end(e) >= len(boundaries) {
+		return fmt.Errorf("%w: %s at %d is out of text", domain.ErrInvalidEntities, e.Type, e.Offset)
+	}
+	if !boundaries[e.Offset] || !boundaries[end(e)] {
+		return fmt.Errorf("%w: %s at %d splits a character", domain.ErrInvalidEntities, e.Type, e.Offset)
+	}
+
+	switch e.Type {
+	case domain.EntityBold, domain.EntityItalic, domain.EntityCode:
+		if e.URL != "" || e.UserId != 0 {
+			return fmt.Errorf("%w: %s has no url or user_id", domain.ErrInvalidEntities, e.Type)
+		}
+	case domain.EntityLink:
+		if e.UserId != 0 || !validURL(e.URL) {
+			return fmt.Errorf("%w: link at %d needs an http, https or mailto url", domain.ErrInvalidEntities, e.Offset)
+		}
+	case domain.EntityMention:
+		if e.URL != "" || e.UserId <= 0 {
+			return fmt.Errorf("%w: mention at %d needs a user_id", domain.ErrInvalidEntities, e.Offset)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", domain.ErrInvalidEntities, e.Type)
+	}
+
+	return nil
+}
+
+// canNest - можно ли разметку inner поместить внутрь outer
+func canNest(outer, inner string) bool {
+	switch {
+	case outer == domain.EntityCode:
+		return false
+	case outer == inner:
+		return false
+	case isTarget(outer) && isTarget(inner):
+		return false
+	}
+	return true
+}
+
+// isTarget - разметка, ведущая куда-то: ссылка или упоминание
+func isTarget(t string) bool {
+	return t == domain.EntityLink || t == domain.EntityMention
+}
+
+// validURL пропускает только ссылки, которые безопасно открыть в браузере
+func validURL(raw string) bool {
+	if raw == "" || len(raw) > maxURLLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	}
+	return false
+}
+
+// runeBoundaries отмечает смещения UTF-16, на которых начинается символ, и конец текста
+func runeBoundaries(text string) []bool {
+	var boundaries []bool
+	for _, r := range text {
+		boundaries = append(boundaries, true)
+		if utf16.RuneLen(r) == 2 {
+			boundaries = append(boundaries, false)
+		}
+	}
+	return append(boundaries, true)
+}
+
+// sortEntities упорядочивает разметку по началу, внешние участки - раньше вложенных
+func sortEntities(entities []domain.MessageEntity) {
+	slices.SortStableFunc(entities, func(a, b domain.MessageEntity) int {
+		return cmp.Or(cmp.Compare(a.Offset, b.Offset), cmp.Compare(b.Length, a.Length))
+	})
+}
+
+func end(e domain.MessageEntity) int {
+	return e.Offset + e.Length
+}
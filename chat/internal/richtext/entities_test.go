@@ -0,0 +1,78 @@
+package richtext
+
+import (
+	"chat/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	const text = "Привет, Алиса! 😀 ok"
+
+	tests := []struct {
+		name     string
+		entities []domain.MessageEntity
+		wantErr  bool
+	}{
+		{"empty", nil, false},
+		{"nested", []domain.MessageEntity{
+			{Type: domain.EntityItalic, Offset: 8, Length: 5},
+			{Type: domain.EntityBold, Offset: 0, Length: 14},
+			{Type: domain.EntityMention, Offset: 8, Length: 5, UserId: 2},
+		}, false},
+		{"after surrogate pair", []domain.MessageEntity{{Type: domain.EntityCode, Offset: 18, Length: 2}}, false},
+		{"splits surrogate pair", []domain.MessageEntity{{Type: domain.EntityBold, Offset: 16, Length: 1}}, true},
+		{"out of text", []domain.MessageEntity{{Type: domain.EntityBold, Offset: 10, Length: 20}}, true},
+		{"zero length", []domain.MessageEntity{{Type: domain.EntityBold, Offset: 1, Length: 0}}, true},
+		{"unknown type", []domain.MessageEntity{{Type: "spoiler", Offset: 0, Length: 1}}, true},
+		{"partial overlap", []domain.MessageEntity{
+			{Type: domain.EntityBold, Offset: 0, Length: 5},
+			{Type: domain.EntityItalic, Offset: 3, Length: 5},
+		}, true},
+		{"inside code", []domain.MessageEntity{
+			{Type: domain.EntityCode, Offset: 0, Length: 6},
+			{Type: domain.EntityBold, Offset: 0, Length: 2},
+		}, true},
+		{"link inside mention", []domain.MessageEntity{
+			{Type: domain.EntityMention, Offset: 8, Length: 5, UserId: 2},
+			{Type: domain.EntityLink, Offset: 8, Length: 2, URL: "https://example.com"},
+		}, true},
+		{"unsafe link", []domain.MessageEntity{{Type: domain.EntityLink, Offset: 0, Length: 6, URL: "javascript:alert(1)"}}, true},
+		{"mailto link", []domain.MessageEntity{{Type: domain.EntityLink, Offset: 0, Length: 6, URL: "mailto:a@example.com"}}, false},
+		{"mention without user", []domain.MessageEntity{{Type: domain.EntityMention, Offset: 8, Length: 5}}, true},
+		{"bold with url", []domain.MessageEntity{{Type: domain.EntityBold, Offset: 0, Length: 6, URL: "https://example.com"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Validate(text, tt.entities)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidEntities)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("sorted", func(t *testing.T) {
+		got, err := Validate(text, []domain.MessageEntity{
+			{Type: domain.EntityItalic, Offset: 8, Length: 5},
+			{Type: domain.EntityBold, Offset: 0, Length: 14},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.EntityBold, got[0].Type)
+	})
+}
+
+func TestMentions(t *testing.T) {
+	entities := []domain.MessageEntity{
+		{Type: domain.EntityMention, Offset: 0, Length: 1, UserId: 3},
+		{Type: domain.EntityBold, Offset: 2, Length: 1},
+		{Type: domain.EntityMention, Offset: 4, Length: 1, UserId: 2},
+		{Type: domain.EntityMention, Offset: 6, Length: 1, UserId: 3},
+	}
+
+	assert.Equal(t, []int{2, 3}, Mentions(entities))
+	assert.Equal(t, []domain.MessageEntity{{Type: domain.EntityBold, Offset: 2, Length: 1}}, WithoutMentions(entities))
+}
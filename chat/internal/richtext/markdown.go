@@ -0,0 +1,278 @@
+package richtext
+
+import (
+	"chat/internal/domain"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// mentionScheme - адрес ссылки-упоминания: [Алиса](mention:42)
+const mentionScheme = "mention:"
+
+// ParseMarkdown выделяет разметку из подмножества Markdown и возвращает текст без служебных символов:
+//
+//	**жирный**, *курсив* или _курсив_, `код`, [текст](https://example.com), [имя](mention:42)
+//
+// Символ после \ выводится как есть. Незакрытая разметка, пустые участки и ссылки с недопустимой схемой
+// остаются обычным текстом, поэтому разбор не завершается ошибкой на пользовательском вводе.
+// Одинаковая разметка не вкладывается сама в себя
+func ParseMarkdown(src string) (string, []domain.MessageEntity, error) {
+	p := &parser{active: make(map[string]bool)}
+	p.parse([]rune(src))
+	if len(p.entities) > MaxEntities {
+		return "", nil, fmt.Errorf("%w: more than %d entities", domain.ErrInvalidEntities, MaxEntities)
+	}
+
+	text := p.out.String()
+	entities, err := Validate(text, p.entities)
+	if err != nil {
+		return "", nil, err
+	}
+	return text, entities, nil
+}
+
+type parser struct {
+	out strings.Builder
+	// offset - длина out в UTF-16
+	offset   int
+	entities []domain.MessageEntity
+	// active - разметка, внутри которой идет разбор. Повторно она не открывается и остается текстом
+	active map[string]bool
+}
+
+func (p *parser) parse(src []rune) {
+	find := newFinder(src)
+
+	for i := 0; i < len(src); {
+		r := src[i]
+		switch {
+		case r == '\\' && isEscapable(at(src, i+1)):
+			p.write(src[i+1])
+			i += 2
+			continue
+
+		case r == '`':
+			if end := indexRune(src, i+1, '`'); end > i+1 {
+				start := p.offset
+				for _, c := range src[i+1 : end] {
+					p.write(c)
+				}
+				p.entities = append(p.entities, domain.MessageEntity{Type: domain.EntityCode, Offset: start, Length: p.offset - start})
+				i = end + 1
+				continue
+			}
+
+		case r == '*' && at(src, i+1) == '*' && !p.active[domain.EntityBold]:
+			if end := find(i+2, "**"); end >= 0 {
+				p.span(domain.MessageEntity{Type: domain.EntityBold}, src[i+2:end])
+				i = end + 2
+				continue
+			}
+			p.write('*')
+			p.write('*')
+			i += 2
+			continue
+
+		case (r == '*' || (r == '_' && !isWordChar(at(src, i-1)))) && !p.active[domain.EntityItalic]:
+			if end := find(i+1, string(r)); end >= 0 {
+				p.span(domain.MessageEntity{Type: domain.EntityItalic}, src[i+1:end])
+				i = end + 1
+				continue
+			}
+
+		case r == '[' && !p.active[domain.EntityLink] && !p.active[domain.EntityMention]:
+			if e, text, next, ok := link(src, i, find); ok {
+				p.span(e, text)
+				i = next
+				continue
+			}
+		}
+
+		p.write(r)
+		i++
+	}
+}
+
+// newFinder ищет закрывающие разделители в src. Если разделителя нет после from, его нет и после
+// любой следующей позиции, поэтому неудачный поиск не повторяется: иначе строка из одних
+// открывающих * разбиралась бы за квадратичное время
+func newFinder(src []rune) func(from int, delim string) int {
+	unclosed := make(map[string]int)
+	return func(from int, delim string) int {
+		// Участок разметки не пустой и не начинается с пробела. Это свойство позиции, а не остатка строки,
+		// его не кэшируем
+		if delim != ")" && (from >= len(src) || unicode.IsSpace(src[from])) {
+			return -1
+		}
+		if delim == "]" && src[from] == ']' {
+			return -1
+		}
+		if last, ok := unclosed[delim]; ok && from >= last {
+			return -1
+		}
+
+		var end int
+		switch delim {
+		case "]":
+			end = closingBracket(src, from)
+		case ")":
+			end = indexRune(src, from, ')')
+		default:
+			end = closing(src, from, delim)
+		}
+		if end < 0 {
+			unclosed[delim] = from
+		}
+		return end
+	}
+}
+
+// span разбирает содержимое участка и добавляет его разметку, если участок не пустой
+func (p *parser) span(e domain.MessageEntity, inner []rune) {
+	start := p.offset
+	p.active[e.Type] = true
+	p.parse(inner)
+	p.active[e.Type] = false
+	if p.offset == start {
+		return
+	}
+	e.Offset, e.Length = start, p.offset-start
+	p.entities = append(p.entities, e)
+}
+
+func (p *parser) write(r rune) {
+	p.out.WriteRune(r)
+	if n := utf16.RuneLen(r); n > 0 {
+		p.offset += n
+	} else {
+		p.offset++
+	}
+}
+
+// closing ищет закрывающий delim для участка, начинающегося с from. Участок не может заканчиваться
+// пробелом, экранированные символы и код внутри пропускаются. Для одиночной * пара ** внутри
+// считается жирным текстом, а не концом курсива, закрывающее _ не должно стоять внутри слова
+func closing(src []rune, from int, delim string) int {
+	d := []rune(delim)
+	for j := from; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			if isEscapable(at(src, j+1)) {
+				j++
+			}
+			continue
+		case '`':
+			if end := indexRune(src, j+1, '`'); end > j+1 {
+				j = end
+			}
+			continue
+		}
+
+		if !hasPrefix(src[j:], d) {
+			continue
+		}
+		if delim == "*" && at(src, j+1) == '*' {
+			j++
+			continue
+		}
+		if j == from || unicode.IsSpace(src[j-1]) {
+			continue
+		}
+		if delim == "_" && isWordChar(at(src, j+1)) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+// link разбирает [текст](адрес), начинающийся с src[i]. Возвращает разметку, текст и позицию после ссылки
+func link(src []rune, i int, find func(from int, delim string) int) (domain.MessageEntity, []rune, int, bool) {
+	textEnd := find(i+1, "]")
+	if textEnd < 0 || at(src, textEnd+1) != '(' {
+		return domain.MessageEntity{}, nil, 0, false
+	}
+	targetEnd := find(textEnd+2, ")")
+	if targetEnd < 0 {
+		return domain.MessageEntity{}, nil, 0, false
+	}
+	target := string(src[textEnd+2 : targetEnd])
+	if strings.ContainsFunc(target, unicode.IsSpace) {
+		return domain.MessageEntity{}, nil, 0, false
+	}
+
+	var e domain.MessageEntity
+	if id, ok := strings.CutPrefix(target, mentionScheme); ok {
+		userID, err := strconv.Atoi(id)
+		if err != nil || userID <= 0 {
+			return domain.MessageEntity{}, nil, 0, false
+		}
+		e = domain.MessageEntity{Type: domain.EntityMention, UserId: userID}
+	} else {
+		if !validURL(target) {
+			return domain.MessageEntity{}, nil, 0, false
+		}
+		e = domain.MessageEntity{Type: domain.EntityLink, URL: target}
+	}
+
+	return e, src[i+1 : textEnd], targetEnd + 1, true
+}
+
+// closingBracket ищет ] текста ссылки, пропуская экранированные символы и код
+func closingBracket(src []rune, from int) int {
+	for j := from; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			if isEscapable(at(src, j+1)) {
+				j++
+			}
+		case '`':
+			if end := indexRune(src, j+1, '`'); end > j+1 {
+				j = end
+			}
+		case ']':
+			return j
+		}
+	}
+	return -1
+}
+
+func isEscapable(r rune) bool {
+	return r != 0 && strings.ContainsRune("\\*_`[]()", r)
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// at возвращает src[i] или 0 за пределами строки
+func at(src []rune, i int) rune {
+	if i < 0 || i >= len(src) {
+		return 0
+	}
+	return src[i]
+}
+
+func indexRune(src []rune, from int, r rune) int {
+	for j := from; j < len(src); j++ {
+		if src[j] == r {
+			return j
+		}
+	}
+	return -1
+}
+
+func hasPrefix(src, prefix []rune) bool {
+	if len(src) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if src[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
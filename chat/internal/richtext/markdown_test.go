@@ -0,0 +1,142 @@
+package richtext
+
+import (
+	"chat/internal/domain"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		text     string
+		entities []domain.MessageEntity
+	}{
+		{
+			name: "plain",
+			src:  "просто текст",
+			text: "просто текст",
+		},
+		{
+			name: "bold and italic",
+			src:  "**жирный** и *курсив* и _тоже_",
+			text: "жирный и курсив и тоже",
+			entities: []domain.MessageEntity{
+				{Type: domain.EntityBold, Offset: 0, Length: 6},
+				{Type: domain.EntityItalic, Offset: 9, Length: 6},
+				{Type: domain.EntityItalic, Offset: 18, Length: 4},
+			},
+		},
+		{
+			name: "nested",
+			src:  "**a *b* c**",
+			text: "a b c",
+			entities: []domain.MessageEntity{
+				{Type: domain.EntityBold, Offset: 0, Length: 5},
+				{Type: domain.EntityItalic, Offset: 2, Length: 1},
+			},
+		},
+		{
+			name: "bold inside italic",
+			src:  "*a **b** c*",
+			text: "a b c",
+			entities: []domain.MessageEntity{
+				{Type: domain.EntityItalic, Offset: 0, Length: 5},
+				{Type: domain.EntityBold, Offset: 2, Length: 1},
+			},
+		},
+		{
+			name:     "code is literal",
+			src:      "`**x** [a](https://a.b)`",
+			text:     "**x** [a](https://a.b)",
+			entities: []domain.MessageEntity{{Type: domain.EntityCode, Offset: 0, Length: 22}},
+		},
+		{
+			name: "link and mention",
+			src:  "[сайт](https://example.com/a_b) для [Алисы](mention:42)",
+			text: "сайт для Алисы",
+			entities: []domain.MessageEntity{
+				{Type: domain.EntityLink, Offset: 0, Length: 4, URL: "https://example.com/a_b"},
+				{Type: domain.EntityMention, Offset: 9, Length: 5, UserId: 42},
+			},
+		},
+		{
+			name: "bold link text",
+			src:  "[**жми**](https://example.com)",
+			text: "жми",
+			entities: []domain.MessageEntity{
+				{Type: domain.EntityBold, Offset: 0, Length: 3},
+				{Type: domain.EntityLink, Offset: 0, Length: 3, URL: "https://example.com"},
+			},
+		},
+		{
+			name: "unsafe link stays text",
+			src:  "[нажми](javascript:alert(1))",
+			text: "[нажми](javascript:alert(1))",
+		},
+		{
+			name: "invalid mention stays text",
+			src:  "[кто-то](mention:abc)",
+			text: "[кто-то](mention:abc)",
+		},
+		{
+			name: "unclosed and spaced markers",
+			src:  "2 * 3 * 4, **не закрыто, ** пусто **",
+			text: "2 * 3 * 4, **не закрыто, ** пусто **",
+		},
+		{
+			name: "underscores inside words",
+			src:  "snake_case_name и https://example.com/a_b_c",
+			text: "snake_case_name и https://example.com/a_b_c",
+		},
+		{
+			name: "escapes",
+			src:  `\*не курсив\* и \[не ссылка\]`,
+			text: "*не курсив* и [не ссылка]",
+		},
+		{
+			name: "repeated italic stays text",
+			src:  "*a _b_ c*",
+			text: "a _b_ c",
+			entities: []domain.MessageEntity{
+				{Type: domain.EntityItalic, Offset: 0, Length: 7},
+			},
+		},
+		{
+			name: "utf16 offsets",
+			src:  "😀 **ok**",
+			text: "😀 ok",
+			entities: []domain.MessageEntity{
+				{Type: domain.EntityBold, Offset: 3, Length: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, entities, err := ParseMarkdown(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, text)
+			assert.Equal(t, tt.entities, entities)
+		})
+	}
+}
+
+func TestParseMarkdown_Limits(t *testing.T) {
+	t.Run("too many entities", func(t *testing.T) {
+		_, _, err := ParseMarkdown(strings.Repeat("*a* ", MaxEntities+1))
+		assert.ErrorIs(t, err, domain.ErrInvalidEntities)
+	})
+
+	t.Run("unclosed markers are linear", func(t *testing.T) {
+		src := "`" + strings.Repeat("*a **b [c](d _e ", 20000)
+		start := time.Now()
+		_, _, err := ParseMarkdown(src)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,10 @@
+package scheduler
+
+import "time"
+
+type Config struct {
+	// Interval - как часто проверять, не подошло ли время отложенных сообщений. За один проход
+	// отправляются все готовые сообщения порциями по BatchSize
+	Interval  time.Duration `env:"SCHEDULER_INTERVAL" envDefault:"1s"`
+	BatchSize int           `env:"SCHEDULER_BATCH_SIZE" envDefault:"100"`
+}
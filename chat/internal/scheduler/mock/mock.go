@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: scheduler.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeliverer is a mock of Deliverer interface.
+type MockDeliverer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDelivererMockRecorder
+}
+
+// MockDelivererMockRecorder is the mock recorder for MockDeliverer.
+type MockDelivererMockRecorder struct {
+	mock *MockDeliverer
+}
+
+// NewMockDeliverer creates a new mock instance.
+func NewMockDeliverer(ctrl *gomock.Controller) *MockDeliverer {
+	mock := &MockDeliverer{ctrl: ctrl}
+	mock.recorder = &MockDelivererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliverer) EXPECT() *MockDelivererMockRecorder {
+	return m.recorder
+}
+
+// DeliverScheduledMessages mocks base method.
+func (m *MockDeliverer) DeliverScheduledMessages(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverScheduledMessages", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverScheduledMessages indicates an expected call of DeliverScheduledMessages.
+func (mr *MockDelivererMockRecorder) DeliverScheduledMessages(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverScheduledMessages", reflect.TypeOf((*MockDeliverer)(nil).DeliverScheduledMessages), ctx, limit)
+}
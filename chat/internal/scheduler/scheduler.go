@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"chat/pkg/logger"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=./mock/mock.go -package=mock -source=scheduler.go
+
+// Deliverer отправляет до limit отложенных сообщений, время которых подошло, и возвращает число взятых в работу
+type Deliverer interface {
+	DeliverScheduledMessages(ctx context.Context, limit int) (int, error)
+}
+
+// Scheduler в фоне отправляет отложенные сообщения. Его можно запускать на нескольких репликах:
+// каждое сообщение достается одной из них
+type Scheduler struct {
+	cfg       Config
+	deliverer Deliverer
+}
+
+func New(cfg Config, deliverer Deliverer) *Scheduler {
+	return &Scheduler{cfg: cfg, deliverer: deliverer}
+}
+
+// Run проверяет отложенные сообщения раз в Interval, пока не отменен ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	if s.cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.drain(ctx)
+		}
+	}
+}
+
+// drain отправляет готовые сообщения порциями, пока порция заполняется целиком
+func (s *Scheduler) drain(ctx context.Context) {
+	batchSize := max(s.cfg.BatchSize, 1)
+	for ctx.Err() == nil {
+		claimed, err := s.deliverer.DeliverScheduledMessages(ctx, batchSize)
+		if err != nil {
+			logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to deliver scheduled messages", err)
+			return
+		}
+		if claimed < batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"chat/internal/scheduler/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestScheduler_Drain(t *testing.T) {
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("delivers batches until a partial one", func(t *testing.T) {
+		deliverer := mock.NewMockDeliverer(gomock.NewController(t))
+		s := New(Config{BatchSize: 2}, deliverer)
+
+		gomock.InOrder(
+			deliverer.EXPECT().DeliverScheduledMessages(gomock.Any(), 2).Return(2, nil),
+			deliverer.EXPECT().DeliverScheduledMessages(gomock.Any(), 2).Return(0, nil),
+		)
+
+		s.drain(ctx)
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		deliverer := mock.NewMockDeliverer(gomock.NewController(t))
+		s := New(Config{BatchSize: 2}, deliverer)
+
+		deliverer.EXPECT().DeliverScheduledMessages(gomock.Any(), 2).Return(1, errors.New("db is down"))
+
+		s.drain(ctx)
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		deliverer := mock.NewMockDeliverer(gomock.NewController(t))
+		s := New(Config{BatchSize: 2}, deliverer)
+
+		ctx, cancel := context.WithCancel(ctx)
+		deliverer.EXPECT().DeliverScheduledMessages(gomock.Any(), 2).DoAndReturn(func(context.Context, int) (int, error) {
+			cancel()
+			return 2, nil
+		})
+
+		s.drain(ctx)
+	})
+}
+
+func TestScheduler_Run(t *testing.T) {
+	deliverer := mock.NewMockDeliverer(gomock.NewController(t))
+	s := New(Config{Interval: 10 * time.Millisecond, BatchSize: 10}, deliverer)
+
+	ctx, cancel := context.WithCancel(logger.InitFromCtx(context.Background(), logger.New()))
+	deliverer.EXPECT().DeliverScheduledMessages(gomock.Any(), 10).DoAndReturn(func(context.Context, int) (int, error) {
+		cancel()
+		return 0, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after cancel")
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultAdminLimit = 50
+	maxAdminLimit     = 200
+)
+
+func (s *ChatSvc) ListUsers(ctx context.Context, admin domain.Identity, q domain.UserQuery) ([]domain.User, error) {
+	if err := requireAdmin(admin); err != nil {
+		return nil, err
+	}
+	q.Limit, q.Offset = adminPage(q.Limit, q.Offset)
+
+	return s.Users.ListUsers(ctx, admin, q)
+}
+
+// SuspendUser блокирует пользователя в auth-сервисе: его токены перестают проходить проверку.
+// Причина обязательна, она попадает в журнал модерации
+func (s *ChatSvc) SuspendUser(ctx context.Context, admin domain.Identity, suspension domain.Suspension) (domain.User, error) {
+	if err := requireAdmin(admin); err != nil {
+		return domain.User{}, err
+	}
+	suspension.Reason = strings.TrimSpace(suspension.Reason)
+	if suspension.Reason == "" {
+		return domain.User{}, fmt.Errorf("%w: reason is required", domain.ErrInvalidSuspension)
+	}
+	if suspension.UserId == admin.UserId {
+		return domain.User{}, fmt.Errorf("%w: cannot suspend yourself", domain.ErrInvalidSuspension)
+	}
+
+	user, err := s.Users.SuspendUser(ctx, admin, suspension)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	entry := domain.AuditEntry{
+		AdminId: admin.UserId,
+		Action:  domain.AuditSuspendUser,
+		UserId:  suspension.UserId,
+		Reason:  suspension.Reason,
+	}
+	if suspension.Until != nil {
+		entry.Details = map[string]any{"until": suspension.Until.UTC()}
+	}
+	s.audit(ctx, entry)
+
+	return user, nil
+}
+
+func (s *ChatSvc) UnsuspendUser(ctx context.Context, admin domain.Identity, userID int) (domain.User, error) {
+	if err := requireAdmin(admin); err != nil {
+		return domain.User{}, err
+	}
+
+	user, err := s.Users.UnsuspendUser(ctx, admin, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	s.audit(ctx, domain.AuditEntry{AdminId: admin.UserId, Action: domain.AuditUnsuspendUser, UserId: userID})
+
+	return user, nil
+}
+
+func (s *ChatSvc) ListChats(ctx context.Context, admin domain.Identity, q domain.ChatQuery) ([]domain.ChatInfo, error) {
+	if err := requireAdmin(admin); err != nil {
+		return nil, err
+	}
+	q.Limit, q.Offset = adminPage(q.Limit, q.Offset)
+
+	return s.ChatRepo.ListChats(ctx, q)
+}
+
+// AdminDeleteMessage удаляет сообщение у всех участников без ограничений по автору и времени
+func (s *ChatSvc) AdminDeleteMessage(ctx context.Context, admin domain.Identity, chatID, messageID int, reason string) error {
+	if err := requireAdmin(admin); err != nil {
+		return err
+	}
+
+	original, err := s.ChatRepo.GetMessage(ctx, chatID, messageID)
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.ChatRepo.DeleteMessage(ctx, chatID, messageID)
+	if err != nil {
+		return err
+	}
+
+	s.removeAttachments(ctx, messageID)
+
+	s.notifyMembers(ctx, domain.EventMessageDeleted, chatID, msg)
+
+	senderID, _ := strconv.Atoi(original.SenderId)
+	s.audit(ctx, domain.AuditEntry{
+		AdminId:   admin.UserId,
+		Action:    domain.AuditDeleteMessage,
+		UserId:    senderID,
+		ChatId:    chatID,
+		MessageId: messageID,
+		Reason:    strings.TrimSpace(reason),
+	})
+
+	return nil
+}
+
+// AdminDeleteChat удаляет чат вместе с перепиской и файлами вложений
+func (s *ChatSvc) AdminDeleteChat(ctx context.Context, admin domain.Identity, chatID int, reason string) error {
+	if err := requireAdmin(admin); err != nil {
+		return err
+	}
+
+	members, attachments, err := s.ChatRepo.DeleteChat(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range attachments {
+		s.deleteBlobs(ctx, a)
+	}
+
+	s.notify(ctx, domain.Event{
+		Type:    domain.EventChatDeleted,
+		ChatId:  chatID,
+		UserIds: members,
+		Payload: domain.ChatMembers{ChatId: chatID, UserIds: members},
+	})
+
+	s.audit(ctx, domain.AuditEntry{
+		AdminId: admin.UserId,
+		Action:  domain.AuditDeleteChat,
+		ChatId:  chatID,
+		Reason:  strings.TrimSpace(reason),
+		Details: map[string]any{"user_ids": members, "attachments": len(attachments)},
+	})
+
+	return nil
+}
+
+func (s *ChatSvc) GetAuditLog(ctx context.Context, admin domain.Identity, limit, offset int) ([]domain.AuditEntry, error) {
+	if err := requireAdmin(admin); err != nil {
+		return nil, err
+	}
+	limit, offset = adminPage(limit, offset)
+
+	return s.ChatRepo.GetAuditLog(ctx, limit, offset)
+}
+
+func requireAdmin(identity domain.Identity) error {
+	if !identity.IsAdmin() {
+		return domain.ErrAdminRequired
+	}
+	return nil
+}
+
+// audit пишется после выполненного действия: ошибка записи уже не может его отменить, поэтому только логируется
+func (s *ChatSvc) audit(ctx context.Context, entry domain.AuditEntry) {
+	if err := s.ChatRepo.AppendAudit(ctx, entry); err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to append moderation audit entry", err)
+	}
+}
+
+func adminPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultAdminLimit
+	}
+	return min(limit, maxAdminLimit), max(offset, 0)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+var (
+	testAdmin = domain.Identity{UserId: 100, Role: domain.RoleAdmin, Token: "admin-token"}
+	testUser  = domain.Identity{UserId: 1, Role: domain.RoleUser, Token: "user-token"}
+)
+
+func TestChatSvc_AdminRequiresRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := &ChatSvc{ChatRepo: mock.NewMockChatRepo(ctrl), Users: mock.NewMockUserDirectory(ctrl)}
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"ListUsers": func() error {
+			_, err := s.ListUsers(ctx, testUser, domain.UserQuery{})
+			return err
+		},
+		"SuspendUser": func() error {
+			_, err := s.SuspendUser(ctx, testUser, domain.Suspension{UserId: 2, Reason: "spam"})
+			return err
+		},
+		"ListChats": func() error {
+			_, err := s.ListChats(ctx, testUser, domain.ChatQuery{})
+			return err
+		},
+		"AdminDeleteChat": func() error {
+			return s.AdminDeleteChat(ctx, testUser, 1, "")
+		},
+		"GetAuditLog": func() error {
+			_, err := s.GetAuditLog(ctx, testUser, 0, 0)
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); !errors.Is(err, domain.ErrAdminRequired) {
+				t.Errorf("ChatSvc.%s() error = %v, wantErr %v", name, err, domain.ErrAdminRequired)
+			}
+		})
+	}
+}
+
+func TestChatSvc_SuspendUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	ud := mock.NewMockUserDirectory(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Users: ud}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("ok writes audit", func(t *testing.T) {
+		suspension := domain.Suspension{UserId: 2, Until: &until, Reason: "spam"}
+		ud.EXPECT().SuspendUser(gomock.Any(), testAdmin, suspension).
+			Return(domain.User{Id: 2, Suspended: true, SuspendedUntil: &until}, nil)
+		cr.EXPECT().AppendAudit(gomock.Any(), domain.AuditEntry{
+			AdminId: testAdmin.UserId,
+			Action:  domain.AuditSuspendUser,
+			UserId:  2,
+			Reason:  "spam",
+			Details: map[string]any{"until": until},
+		}).Return(nil)
+
+		got, err := s.SuspendUser(ctx, testAdmin, domain.Suspension{UserId: 2, Until: &until, Reason: "  spam "})
+		if err != nil || !got.Suspended {
+			t.Errorf("ChatSvc.SuspendUser() = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("reason required", func(t *testing.T) {
+		_, err := s.SuspendUser(ctx, testAdmin, domain.Suspension{UserId: 2})
+		if !errors.Is(err, domain.ErrInvalidSuspension) {
+			t.Errorf("ChatSvc.SuspendUser() error = %v, wantErr %v", err, domain.ErrInvalidSuspension)
+		}
+	})
+
+	t.Run("self", func(t *testing.T) {
+		_, err := s.SuspendUser(ctx, testAdmin, domain.Suspension{UserId: testAdmin.UserId, Reason: "test"})
+		if !errors.Is(err, domain.ErrInvalidSuspension) {
+			t.Errorf("ChatSvc.SuspendUser() error = %v, wantErr %v", err, domain.ErrInvalidSuspension)
+		}
+	})
+
+	t.Run("unknown user is not audited", func(t *testing.T) {
+		ud.EXPECT().SuspendUser(gomock.Any(), testAdmin, gomock.Any()).Return(domain.User{}, domain.ErrUserNotFound)
+
+		_, err := s.SuspendUser(ctx, testAdmin, domain.Suspension{UserId: 3, Reason: "spam"})
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("ChatSvc.SuspendUser() error = %v, wantErr %v", err, domain.ErrUserNotFound)
+		}
+	})
+}
+
+func TestChatSvc_AdminDeleteMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10, SenderId: "2", Text: "spam"}, nil)
+	cr.EXPECT().DeleteMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10, SenderId: "2"}, nil)
+	cr.EXPECT().DeleteMessageAttachments(gomock.Any(), 10).Return(nil, nil)
+	cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+	nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+		if e.Type != domain.EventMessageDeleted || len(e.UserIds) != 2 {
+			t.Errorf("unexpected event %+v", e)
+		}
+		return nil
+	})
+	cr.EXPECT().AppendAudit(gomock.Any(), domain.AuditEntry{
+		AdminId:   testAdmin.UserId,
+		Action:    domain.AuditDeleteMessage,
+		UserId:    2,
+		ChatId:    1,
+		MessageId: 10,
+		Reason:    "spam",
+	}).Return(nil)
+
+	if err := s.AdminDeleteMessage(ctx, testAdmin, 1, 10, "spam"); err != nil {
+		t.Errorf("ChatSvc.AdminDeleteMessage() error = %v", err)
+	}
+}
+
+func TestChatSvc_AdminDeleteChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	bs := mock.NewMockBlobStore(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt, BlobStore: bs}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("ok", func(t *testing.T) {
+		attachments := []domain.Attachment{{Id: 1, StorageKey: "a/1", ThumbnailKey: "a/1-thumb"}}
+		cr.EXPECT().DeleteChat(gomock.Any(), 5).Return([]int{2, 3}, attachments, nil)
+		bs.EXPECT().Delete(gomock.Any(), "a/1").Return(nil)
+		bs.EXPECT().Delete(gomock.Any(), "a/1-thumb").Return(nil)
+		nt.EXPECT().Notify(gomock.Any(), domain.Event{
+			Type:    domain.EventChatDeleted,
+			ChatId:  5,
+			UserIds: []int{2, 3},
+			Payload: domain.ChatMembers{ChatId: 5, UserIds: []int{2, 3}},
+		}).Return(nil)
+		cr.EXPECT().AppendAudit(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.AuditEntry) error {
+			if e.Action != domain.AuditDeleteChat || e.ChatId != 5 || e.AdminId != testAdmin.UserId {
+				t.Errorf("unexpected audit entry %+v", e)
+			}
+			return nil
+		})
+
+		if err := s.AdminDeleteChat(ctx, testAdmin, 5, "abuse"); err != nil {
+			t.Errorf("ChatSvc.AdminDeleteChat() error = %v", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		cr.EXPECT().DeleteChat(gomock.Any(), 6).Return(nil, nil, domain.ErrChatNotFound)
+
+		if err := s.AdminDeleteChat(ctx, testAdmin, 6, ""); !errors.Is(err, domain.ErrChatNotFound) {
+			t.Errorf("ChatSvc.AdminDeleteChat() error = %v, wantErr %v", err, domain.ErrChatNotFound)
+		}
+	})
+}
+
+func TestChatSvc_ListChatsPaging(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	s := &ChatSvc{ChatRepo: cr}
+
+	cr.EXPECT().ListChats(gomock.Any(), domain.ChatQuery{UserId: 2, Limit: maxAdminLimit, Offset: 0}).Return(nil, nil)
+	cr.EXPECT().ListChats(gomock.Any(), domain.ChatQuery{Limit: defaultAdminLimit, Offset: 10}).Return(nil, nil)
+
+	if _, err := s.ListChats(context.Background(), testAdmin, domain.ChatQuery{UserId: 2, Limit: 1000, Offset: -5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ListChats(context.Background(), testAdmin, domain.ChatQuery{Offset: 10}); err != nil {
+		t.Fatal(err)
+	}
+}
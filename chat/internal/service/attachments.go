@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxFileNameLength - ограничение длины имени файла в символах, совпадает с размером колонки в БД
+const maxFileNameLength = 255
+
+// UploadAttachment сохраняет файл в хранилище и создает вложение, которое затем можно отправить в сообщении.
+// Тип файла определяется по содержимому, а не по имени
+func (s *ChatSvc) UploadAttachment(ctx context.Context, chatID, userID int, fileName string, r io.Reader) (domain.Attachment, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	if limit := s.Config.MaxAttachmentSize; limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if limit := s.Config.MaxAttachmentSize; limit > 0 && int64(len(data)) > limit {
+		return domain.Attachment{}, domain.ErrAttachmentTooLarge
+	}
+	if len(data) == 0 {
+		return domain.Attachment{}, fmt.Errorf("attachment is empty: %w", domain.ErrAttachmentType)
+	}
+
+	contentType := http.DetectContentType(data)
+	if !s.allowedType(contentType) {
+		return domain.Attachment{}, domain.ErrAttachmentType
+	}
+
+	key, err := blobKey(chatID)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	a := domain.Attachment{
+		ChatId:      chatID,
+		UploaderId:  userID,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  key,
+	}
+
+	if err := s.BlobStore.Put(ctx, a.StorageKey, bytes.NewReader(data), a.Size, a.ContentType); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	if strings.HasPrefix(contentType, "image/") {
+		s.putThumbnail(ctx, &a, data)
+	}
+
+	a.Id, err = s.ChatRepo.CreateAttachment(ctx, a)
+	if err != nil {
+		s.deleteBlobs(ctx, a)
+		return domain.Attachment{}, err
+	}
+
+	return a, nil
+}
+
+// GetAttachment отдает содержимое вложения или его миниатюры участнику чата.
+// Еще не отправленное вложение доступно только загрузившему его пользователю
+func (s *ChatSvc) GetAttachment(ctx context.Context, chatID, userID, attachmentID int, thumbnail bool) (domain.AttachmentContent, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return domain.AttachmentContent{}, err
+	}
+
+	a, err := s.ChatRepo.GetAttachment(ctx, chatID, attachmentID)
+	if err != nil {
+		return domain.AttachmentContent{}, err
+	}
+	if a.MessageId == nil && a.UploaderId != userID {
+		return domain.AttachmentContent{}, domain.ErrAttachmentNotFound
+	}
+
+	content := domain.AttachmentContent{
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+	}
+	key := a.StorageKey
+	if thumbnail {
+		if a.ThumbnailKey == "" {
+			return domain.AttachmentContent{}, domain.ErrAttachmentNotFound
+		}
+		key = a.ThumbnailKey
+		content.ContentType = thumbnailContentType
+		content.Size = -1
+	}
+
+	content.Body, err = s.BlobStore.Get(ctx, key)
+	if err != nil {
+		return domain.AttachmentContent{}, err
+	}
+
+	return content, nil
+}
+
+// putThumbnail сохраняет миниатюру изображения. Ошибка не мешает загрузке самого файла
+func (s *ChatSvc) putThumbnail(ctx context.Context, a *domain.Attachment, data []byte) {
+	thumb, err := makeThumbnail(data)
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to make thumbnail", err)
+		return
+	}
+
+	key := a.StorageKey + "_thumb"
+	if err := s.BlobStore.Put(ctx, key, bytes.NewReader(thumb), int64(len(thumb)), thumbnailContentType); err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to save thumbnail", err)
+		return
+	}
+	a.ThumbnailKey = key
+}
+
+// removeAttachments удаляет вложения сообщения вместе с файлами. Ошибки только логируются
+func (s *ChatSvc) removeAttachments(ctx context.Context, messageID int) {
+	attachments, err := s.ChatRepo.DeleteMessageAttachments(ctx, messageID)
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to delete message attachments", err)
+		return
+	}
+	for _, a := range attachments {
+		s.deleteBlobs(ctx, a)
+	}
+}
+
+func (s *ChatSvc) deleteBlobs(ctx context.Context, a domain.Attachment) {
+	for _, key := range []string{a.StorageKey, a.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.BlobStore.Delete(ctx, key); err != nil {
+			logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to delete blob", err)
+		}
+	}
+}
+
+func (s *ChatSvc) allowedType(contentType string) bool {
+	if len(s.Config.AllowedAttachmentTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(s.Config.AllowedAttachmentTypes, mediaType)
+}
+
+// blobKey генерирует случайный ключ файла в хранилище, сгруппированный по чатам
+func blobKey(chatID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate blob key: %w", err)
+	}
+	return fmt.Sprintf("chats/%d/%s", chatID, hex.EncodeToString(b)), nil
+}
+
+// cleanFileName оставляет от имени файла только базовое имя без управляющих символов
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7F {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	for utf8.RuneCountInString(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestChatSvc_UploadAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	bs := mock.NewMockBlobStore(ctrl)
+
+	l := logger.New()
+	ctx := logger.InitFromCtx(context.Background(), l)
+
+	pngData := testPNG(t, 640, 320)
+
+	type MockBehavor func(chatID, userID int)
+
+	type args struct {
+		chatID   int
+		userID   int
+		fileName string
+		data     []byte
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		want        domain.Attachment
+		wantErr     error
+	}{
+		{
+			name: "text file",
+			args: args{chatID: 1, userID: 2, fileName: "notes.txt", data: []byte("hello")},
+			MockBehavor: func(chatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				bs.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(5), "text/plain; charset=utf-8").Return(nil)
+				cr.EXPECT().CreateAttachment(gomock.Any(), gomock.Any()).Return(10, nil)
+			},
+			want: domain.Attachment{
+				Id:          10,
+				ChatId:      1,
+				UploaderId:  2,
+				FileName:    "notes.txt",
+				ContentType: "text/plain; charset=utf-8",
+				Size:        5,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "image with thumbnail",
+			args: args{chatID: 1, userID: 2, fileName: "../../photo.png", data: pngData},
+			MockBehavor: func(chatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				bs.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(len(pngData)), "image/png").Return(nil)
+				bs.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "image/jpeg").Return(nil)
+				cr.EXPECT().CreateAttachment(gomock.Any(), gomock.Any()).Return(11, nil)
+			},
+			want: domain.Attachment{
+				Id:          11,
+				ChatId:      1,
+				UploaderId:  2,
+				FileName:    "photo.png",
+				ContentType: "image/png",
+				Size:        int64(len(pngData)),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "too large",
+			args: args{chatID: 1, userID: 2, fileName: "big.txt", data: bytes.Repeat([]byte("a"), 1<<16+1)},
+			MockBehavor: func(chatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+			},
+			wantErr: domain.ErrAttachmentTooLarge,
+		},
+		{
+			name: "type not allowed",
+			args: args{chatID: 1, userID: 2, fileName: "page.html", data: []byte("<html><body>hi</body></html>")},
+			MockBehavor: func(chatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+			},
+			wantErr: domain.ErrAttachmentType,
+		},
+		{
+			name: "with not member",
+			args: args{chatID: 1, userID: 5, fileName: "notes.txt", data: []byte("hello")},
+			MockBehavor: func(chatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(false, nil)
+			},
+			wantErr: domain.ErrNotChatMember,
+		},
+		{
+			name: "repo error removes blob",
+			args: args{chatID: 1, userID: 2, fileName: "notes.txt", data: []byte("hello")},
+			MockBehavor: func(chatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				bs.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(5), gomock.Any()).Return(nil)
+				cr.EXPECT().CreateAttachment(gomock.Any(), gomock.Any()).Return(0, errors.New("db error"))
+				bs.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				Config: Config{
+					MaxAttachmentSize:      1 << 16,
+					AllowedAttachmentTypes: []string{"text/plain", "image/png"},
+				},
+				ChatRepo:  cr,
+				BlobStore: bs,
+			}
+			tt.MockBehavor(tt.args.chatID, tt.args.userID)
+			got, err := s.UploadAttachment(ctx, tt.args.chatID, tt.args.userID, tt.args.fileName, bytes.NewReader(tt.args.data))
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("ChatSvc.UploadAttachment() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ChatSvc.UploadAttachment() unexpected error = %v", err)
+			}
+			if !strings.HasPrefix(got.StorageKey, "chats/1/") {
+				t.Errorf("ChatSvc.UploadAttachment() storage key = %q", got.StorageKey)
+			}
+			if strings.HasPrefix(tt.want.ContentType, "image/") != (got.ThumbnailKey != "") {
+				t.Errorf("ChatSvc.UploadAttachment() thumbnail key = %q", got.ThumbnailKey)
+			}
+			got.StorageKey, got.ThumbnailKey = "", ""
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatSvc.UploadAttachment() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatSvc_GetAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	bs := mock.NewMockBlobStore(ctrl)
+
+	l := logger.New()
+	ctx := logger.InitFromCtx(context.Background(), l)
+
+	messageID := 3
+	sent := domain.Attachment{
+		Id:           7,
+		ChatId:       1,
+		UploaderId:   2,
+		MessageId:    &messageID,
+		FileName:     "photo.png",
+		ContentType:  "image/png",
+		Size:         4,
+		StorageKey:   "chats/1/a",
+		ThumbnailKey: "chats/1/a_thumb",
+	}
+	unsent := domain.Attachment{Id: 8, ChatId: 1, UploaderId: 2, FileName: "draft.txt", ContentType: "text/plain", Size: 4, StorageKey: "chats/1/b"}
+
+	type MockBehavor func(chatID, userID, attachmentID int)
+
+	type args struct {
+		userID       int
+		attachmentID int
+		thumbnail    bool
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		want        domain.AttachmentContent
+		wantErr     error
+	}{
+		{
+			name: "sent attachment",
+			args: args{userID: 1, attachmentID: 7},
+			MockBehavor: func(chatID, userID, attachmentID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				cr.EXPECT().GetAttachment(gomock.Any(), chatID, attachmentID).Return(sent, nil)
+				bs.EXPECT().Get(gomock.Any(), "chats/1/a").Return(io.NopCloser(strings.NewReader("data")), nil)
+			},
+			want:    domain.AttachmentContent{FileName: "photo.png", ContentType: "image/png", Size: 4},
+			wantErr: nil,
+		},
+		{
+			name: "thumbnail",
+			args: args{userID: 1, attachmentID: 7, thumbnail: true},
+			MockBehavor: func(chatID, userID, attachmentID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				cr.EXPECT().GetAttachment(gomock.Any(), chatID, attachmentID).Return(sent, nil)
+				bs.EXPECT().Get(gomock.Any(), "chats/1/a_thumb").Return(io.NopCloser(strings.NewReader("data")), nil)
+			},
+			want:    domain.AttachmentContent{FileName: "photo.png", ContentType: "image/jpeg", Size: -1},
+			wantErr: nil,
+		},
+		{
+			name: "no thumbnail",
+			args: args{userID: 2, attachmentID: 8, thumbnail: true},
+			MockBehavor: func(chatID, userID, attachmentID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				cr.EXPECT().GetAttachment(gomock.Any(), chatID, attachmentID).Return(unsent, nil)
+			},
+			wantErr: domain.ErrAttachmentNotFound,
+		},
+		{
+			name: "unsent attachment of another user",
+			args: args{userID: 1, attachmentID: 8},
+			MockBehavor: func(chatID, userID, attachmentID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				cr.EXPECT().GetAttachment(gomock.Any(), chatID, attachmentID).Return(unsent, nil)
+			},
+			wantErr: domain.ErrAttachmentNotFound,
+		},
+		{
+			name: "with not member",
+			args: args{userID: 5, attachmentID: 7},
+			MockBehavor: func(chatID, userID, attachmentID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(false, nil)
+			},
+			wantErr: domain.ErrNotChatMember,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				ChatRepo:  cr,
+				BlobStore: bs,
+			}
+			tt.MockBehavor(1, tt.args.userID, tt.args.attachmentID)
+			got, err := s.GetAttachment(ctx, 1, tt.args.userID, tt.args.attachmentID, tt.args.thumbnail)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.GetAttachment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got.Body.Close()
+			got.Body = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatSvc.GetAttachment() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatSvc_PostMessageAttachments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+
+	ctx := context.Background()
+	s := &ChatSvc{
+		Config:   Config{MaxAttachmentsPerMessage: 2},
+		ChatRepo: cr,
+		Notifier: nt,
+	}
+
+	t.Run("duplicates are ignored", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+		cr.EXPECT().
+			SendMessage(gomock.Any(), domain.NewMessage{ChatId: 1, SenderId: 2, AttachmentIds: []int{4, 5}}).
+			Return(9, true, nil)
+		expectNewMessage(cr, nt, 1, 9)
+
+		got, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, AttachmentIds: []int{5, 4, 5}})
+		if err != nil || got != 9 {
+			t.Errorf("ChatSvc.PostMessage() = %v, %v, want 9, nil", got, err)
+		}
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+
+		_, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, AttachmentIds: []int{1, 2, 3}})
+		if !errors.Is(err, domain.ErrTooManyAttachments) {
+			t.Errorf("ChatSvc.PostMessage() error = %v, wantErr %v", err, domain.ErrTooManyAttachments)
+		}
+	})
+}
+
+func Test_makeThumbnail(t *testing.T) {
+	thumb, err := makeThumbnail(testPNG(t, 1000, 500))
+	if err != nil {
+		t.Fatalf("makeThumbnail() error = %v", err)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatalf("thumbnail is not jpeg: %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(320, 160) {
+		t.Errorf("thumbnail size = %v, want (320,160)", got)
+	}
+
+	if _, err := makeThumbnail([]byte("not an image")); err == nil {
+		t.Error("makeThumbnail() expected error for invalid image")
+	}
+}
+
+func Test_cleanFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "report.pdf", want: "report.pdf"},
+		{name: "unix path", in: "../../etc/passwd", want: "passwd"},
+		{name: "windows path", in: `C:\Users\me\photo.jpg`, want: "photo.jpg"},
+		{name: "control chars", in: "a\r\nb.txt", want: "ab.txt"},
+		{name: "empty", in: "", want: "file"},
+		{name: "too long", in: strings.Repeat("я", 300), want: strings.Repeat("я", maxFileNameLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanFileName(tt.in); got != tt.want {
+				t.Errorf("cleanFileName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+)
+
+// BlockUser добавляет blockedUserID в черный список userID. Пока блокировка действует,
+// пользователи не могут создать общий чат и писать друг другу
+func (s *ChatSvc) BlockUser(ctx context.Context, userID, blockedUserID int) error {
+	if blockedUserID <= 0 || blockedUserID == userID {
+		return domain.ErrInvalidBlock
+	}
+	return s.ChatRepo.BlockUser(ctx, userID, blockedUserID)
+}
+
+func (s *ChatSvc) UnblockUser(ctx context.Context, userID, blockedUserID int) error {
+	if blockedUserID <= 0 || blockedUserID == userID {
+		return domain.ErrInvalidBlock
+	}
+	return s.ChatRepo.UnblockUser(ctx, userID, blockedUserID)
+}
+
+func (s *ChatSvc) GetBlockedUsers(ctx context.Context, userID int) ([]int, error) {
+	return s.ChatRepo.GetBlockedUsers(ctx, userID)
+}
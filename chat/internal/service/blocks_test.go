@@ -0,0 +1,63 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_BlockUser(t *testing.T) {
+	cr := mock.NewMockChatRepo(gomock.NewController(t))
+
+	ctx := context.Background()
+
+	type MockBehavor func(userID, blockedUserID int)
+
+	type args struct {
+		userID        int
+		blockedUserID int
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		wantErr     error
+	}{
+		{
+			name: "ok",
+			args: args{userID: 1, blockedUserID: 2},
+			MockBehavor: func(userID, blockedUserID int) {
+				cr.EXPECT().BlockUser(gomock.Any(), userID, blockedUserID).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "with yourself",
+			args:        args{userID: 1, blockedUserID: 1},
+			MockBehavor: func(userID, blockedUserID int) {},
+			wantErr:     domain.ErrInvalidBlock,
+		},
+		{
+			name:        "with invalid user",
+			args:        args{userID: 1, blockedUserID: 0},
+			MockBehavor: func(userID, blockedUserID int) {},
+			wantErr:     domain.ErrInvalidBlock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				ChatRepo: cr,
+			}
+			tt.MockBehavor(tt.args.userID, tt.args.blockedUserID)
+			err := s.BlockUser(ctx, tt.args.userID, tt.args.blockedUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.BlockUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+)
+
+const (
+	maxWebhookURLLength = 2048
+	webhookSecretLen    = 32
+)
+
+// SetWebhook устанавливает вебхук бота и возвращает новый ключ подписи. Адрес проверяется еще раз
+// при каждой доставке: во внутреннюю сеть события не отправляются
+func (s *ChatSvc) SetWebhook(ctx context.Context, bot domain.Identity, rawURL string) (domain.Webhook, error) {
+	if err := requireBot(bot); err != nil {
+		return domain.Webhook{}, err
+	}
+	if err := checkWebhookURL(rawURL); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	secret := make([]byte, webhookSecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return s.ChatRepo.SetWebhook(ctx, domain.Webhook{BotId: bot.UserId, URL: rawURL, Secret: hex.EncodeToString(secret)})
+}
+
+// GetWebhook возвращает вебхук бота с числом недоставленных событий и последней ошибкой доставки
+func (s *ChatSvc) GetWebhook(ctx context.Context, bot domain.Identity) (domain.Webhook, error) {
+	if err := requireBot(bot); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return s.ChatRepo.GetWebhook(ctx, bot.UserId)
+}
+
+// DeleteWebhook удаляет вебхук: недоставленные события пропадают, бот получает обновления через getUpdates
+func (s *ChatSvc) DeleteWebhook(ctx context.Context, bot domain.Identity) error {
+	if err := requireBot(bot); err != nil {
+		return err
+	}
+
+	return s.ChatRepo.DeleteWebhook(ctx, bot.UserId)
+}
+
+func requireBot(identity domain.Identity) error {
+	if !identity.IsBot() {
+		return domain.ErrBotRequired
+	}
+	return nil
+}
+
+func checkWebhookURL(rawURL string) error {
+	if len(rawURL) > maxWebhookURLLength {
+		return domain.ErrInvalidWebhookURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return domain.ErrInvalidWebhookURL
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"time"
+)
+
+func (s *ChatSvc) GetChatSettings(ctx context.Context, chatID, userID int) (domain.ChatSettings, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return domain.ChatSettings{}, err
+	}
+	return s.ChatRepo.GetChatSettings(ctx, chatID, userID)
+}
+
+// UpdateChatSettings меняет личные настройки чата: отключение уведомлений, архив и закрепление
+func (s *ChatSvc) UpdateChatSettings(ctx context.Context, chatID, userID int, upd domain.ChatSettingsUpdate) (domain.ChatSettings, error) {
+	if upd.Muted == nil || !*upd.Muted {
+		upd.MutedUntil = nil
+	}
+	if upd.MutedUntil != nil && !upd.MutedUntil.After(time.Now()) {
+		return domain.ChatSettings{}, domain.ErrInvalidMuteTimeout
+	}
+
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return domain.ChatSettings{}, err
+	}
+	return s.ChatRepo.UpdateChatSettings(ctx, chatID, userID, upd)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_UpdateChatSettings(t *testing.T) {
+	cr := mock.NewMockChatRepo(gomock.NewController(t))
+
+	ctx := context.Background()
+
+	yes, no := true, false
+	until := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	type MockBehavor func(upd domain.ChatSettingsUpdate)
+
+	tests := []struct {
+		name        string
+		upd         domain.ChatSettingsUpdate
+		MockBehavor MockBehavor
+		want        domain.ChatSettings
+		wantErr     error
+	}{
+		{
+			name: "mute until",
+			upd:  domain.ChatSettingsUpdate{Muted: &yes, MutedUntil: &until},
+			MockBehavor: func(upd domain.ChatSettingsUpdate) {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+				cr.EXPECT().
+					UpdateChatSettings(gomock.Any(), 1, 2, upd).
+					Return(domain.ChatSettings{ChatId: 1, Muted: true, MutedUntil: &until}, nil)
+			},
+			want:    domain.ChatSettings{ChatId: 1, Muted: true, MutedUntil: &until},
+			wantErr: nil,
+		},
+		{
+			name: "unmute ignores expiration",
+			upd:  domain.ChatSettingsUpdate{Muted: &no, MutedUntil: &past, Pinned: &yes},
+			MockBehavor: func(upd domain.ChatSettingsUpdate) {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+				cr.EXPECT().
+					UpdateChatSettings(gomock.Any(), 1, 2, domain.ChatSettingsUpdate{Muted: &no, Pinned: &yes}).
+					Return(domain.ChatSettings{ChatId: 1, Pinned: true}, nil)
+			},
+			want:    domain.ChatSettings{ChatId: 1, Pinned: true},
+			wantErr: nil,
+		},
+		{
+			name:        "mute until past",
+			upd:         domain.ChatSettingsUpdate{Muted: &yes, MutedUntil: &past},
+			MockBehavor: func(upd domain.ChatSettingsUpdate) {},
+			want:        domain.ChatSettings{},
+			wantErr:     domain.ErrInvalidMuteTimeout,
+		},
+		{
+			name: "with not member",
+			upd:  domain.ChatSettingsUpdate{Archived: &yes},
+			MockBehavor: func(upd domain.ChatSettingsUpdate) {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(false, nil)
+			},
+			want:    domain.ChatSettings{},
+			wantErr: domain.ErrNotChatMember,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				ChatRepo: cr,
+			}
+			tt.MockBehavor(tt.upd)
+			got, err := s.UpdateChatSettings(ctx, 1, 2, tt.upd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.UpdateChatSettings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatSvc.UpdateChatSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatSvc_notifyNewMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+
+	ctx := context.Background()
+	s := &ChatSvc{
+		ChatRepo: cr,
+		Notifier: nt,
+	}
+
+	msg := domain.Message{Id: 5, SenderId: "1", Text: "hello"}
+
+	cr.EXPECT().GetMessage(gomock.Any(), 1, 5).Return(msg, nil)
+	cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{1, 2}, nil)
+	cr.EXPECT().GetMutedMembers(gomock.Any(), 1).Return([]int{1, 2}, nil)
+	nt.EXPECT().Notify(gomock.Any(), domain.Event{
+		Type:         domain.EventMessageCreated,
+		ChatId:       1,
+		UserIds:      []int{2},
+		MutedUserIds: []int{2},
+		Payload:      msg,
+	}).Return(nil)
+
+	s.notifyNewMessage(ctx, 1, 5, 1)
+}
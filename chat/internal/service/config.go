@@ -0,0 +1,63 @@
+package service
+
+import "time"
+
+type Config struct {
+	// Окна, в течение которых отправитель может изменить или удалить сообщение у всех; 0 - без ограничения
+	EditWindow   time.Duration `env:"MESSAGE_EDIT_WINDOW" envDefault:"48h"`
+	DeleteWindow time.Duration `env:"MESSAGE_DELETE_WINDOW" envDefault:"48h"`
+
+	// Ограничения вложений: размер файла в байтах, число вложений в сообщении и допустимые MIME-типы.
+	// 0 и пустой список - без ограничения
+	MaxAttachmentSize        int64    `env:"ATTACHMENT_MAX_SIZE" envDefault:"10485760"`
+	MaxAttachmentsPerMessage int      `env:"ATTACHMENT_MAX_PER_MESSAGE" envDefault:"10"`
+	AllowedAttachmentTypes   []string `env:"ATTACHMENT_ALLOWED_TYPES" envSeparator:"," envDefault:"image/jpeg,image/png,image/gif,image/webp,application/pdf,text/plain,application/zip"`
+
+	// MaxMessageLength - максимальная длина текста сообщения в символах; 0 - без ограничения
+	MaxMessageLength int `env:"MESSAGE_MAX_LENGTH" envDefault:"4096"`
+
+	// Лимиты отправки сообщений: Burst сообщений подряд, затем одно сообщение в Interval.
+	// Считаются для отправителя, для чата и для одинакового текста от одного отправителя. Burst 0 - без ограничения
+	UserRateBurst         int           `env:"RATE_LIMIT_USER_BURST" envDefault:"20"`
+	UserRateInterval      time.Duration `env:"RATE_LIMIT_USER_INTERVAL" envDefault:"1s"`
+	ChatRateBurst         int           `env:"RATE_LIMIT_CHAT_BURST" envDefault:"30"`
+	ChatRateInterval      time.Duration `env:"RATE_LIMIT_CHAT_INTERVAL" envDefault:"500ms"`
+	DuplicateRateBurst    int           `env:"RATE_LIMIT_DUPLICATE_BURST" envDefault:"3"`
+	DuplicateRateInterval time.Duration `env:"RATE_LIMIT_DUPLICATE_INTERVAL" envDefault:"1m"`
+	// Лимит входящего вебхука заменяет лимит отправителя: у каждого вебхука своя корзина
+	IncomingWebhookRateBurst    int           `env:"RATE_LIMIT_INCOMING_WEBHOOK_BURST" envDefault:"10"`
+	IncomingWebhookRateInterval time.Duration `env:"RATE_LIMIT_INCOMING_WEBHOOK_INTERVAL" envDefault:"1s"`
+
+	// Ограничения конверта зашифрованного сообщения: размер шифротекста в байтах и число заголовков устройств.
+	// 0 - без ограничения
+	MaxCiphertextSize  int `env:"E2E_MAX_CIPHERTEXT_SIZE" envDefault:"65536"`
+	MaxEnvelopeHeaders int `env:"E2E_MAX_HEADERS" envDefault:"32"`
+
+	// MessageRetention - глобальный срок хранения сообщений: более старые удаляются фоновой очисткой
+	// во всех чатах, и срок жизни сообщений чата не может быть больше. 0 - сообщения хранятся бессрочно
+	MessageRetention time.Duration `env:"MESSAGE_RETENTION" envDefault:"0"`
+
+	// ExportTTL - сколько готовый архив выгрузки данных доступен для скачивания,
+	// ExportTimeout - через сколько незавершенная выгрузка считается брошенной и собирается заново
+	ExportTTL     time.Duration `env:"EXPORT_TTL" envDefault:"48h"`
+	ExportTimeout time.Duration `env:"EXPORT_TIMEOUT" envDefault:"30m"`
+
+	// MaxPinnedMessages - сколько сообщений можно закрепить в одном чате, 0 - без ограничения
+	MaxPinnedMessages int `env:"PINNED_MAX_PER_CHAT" envDefault:"50"`
+
+	// MaxIncomingWebhooks - сколько входящих вебхуков можно создать в одном чате, 0 - без ограничения
+	MaxIncomingWebhooks int `env:"INCOMING_WEBHOOK_MAX_PER_CHAT" envDefault:"10"`
+
+	// Отложенные сообщения: насколько вперед можно запланировать отправку, сколько неотправленных сообщений
+	// может быть у пользователя и через сколько незавершенная отправка считается брошенной. 0 - без ограничения
+	MaxScheduleAhead     time.Duration `env:"SCHEDULED_MAX_AHEAD" envDefault:"8760h"`
+	MaxScheduledMessages int           `env:"SCHEDULED_MAX_PER_USER" envDefault:"100"`
+	ScheduledSendTimeout time.Duration `env:"SCHEDULED_SEND_TIMEOUT" envDefault:"1m"`
+
+	// ReportHideThreshold - сколько разных пользователей должны пожаловаться на сообщения отправителя,
+	// чтобы они скрылись до решения модератора; 0 - не скрывать автоматически
+	ReportHideThreshold int `env:"REPORT_HIDE_THRESHOLD" envDefault:"3"`
+
+	// VAPIDPublicKey - applicationServerKey для подписки браузера на push-уведомления; пустой - push отключен
+	VAPIDPublicKey string `env:"VAPID_PUBLIC_KEY"`
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"time"
+)
+
+// SaveDraft сохраняет черновик userID и возвращает актуальную версию: если на другом устройстве черновик
+// изменили позже, сохраненная версия не меняется и возвращается она. Пустой черновик удаляет сохраненный.
+// Время правки из будущего сдвигается на текущее, чтобы устройство со спешащими часами не блокировало
+// правки с остальных устройств
+func (s *ChatSvc) SaveDraft(ctx context.Context, userID int, draft domain.Draft, format string) (domain.Draft, error) {
+	text, entities, err := formatText(draft.Text, format, draft.Entities)
+	if err != nil {
+		return domain.Draft{}, err
+	}
+	draft.Text, draft.Entities = text, entities
+	if err := s.checkLength(draft.Text); err != nil {
+		return domain.Draft{}, err
+	}
+
+	now := time.Now()
+	if draft.UpdatedAt.IsZero() || draft.UpdatedAt.After(now) {
+		draft.UpdatedAt = now
+	}
+
+	if err := s.checkMember(ctx, draft.ChatId, userID); err != nil {
+		return domain.Draft{}, err
+	}
+	if err := s.checkReplyTo(ctx, draft.ChatId, draft.ReplyToId); err != nil {
+		return domain.Draft{}, err
+	}
+
+	saved, ok, err := s.ChatRepo.SaveDraft(ctx, userID, draft)
+	if err != nil {
+		return domain.Draft{}, err
+	}
+	if ok {
+		s.notify(ctx, domain.Event{Type: domain.EventDraftUpdated, ChatId: draft.ChatId, UserIds: []int{userID}, Payload: saved})
+	}
+
+	return saved, nil
+}
+
+// GetDrafts возвращает непустые черновики пользователя по всем чатам
+func (s *ChatSvc) GetDrafts(ctx context.Context, userID int) ([]domain.Draft, error) {
+	return s.ChatRepo.GetDrafts(ctx, userID)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+)
+
+// Ограничения служебных полей конверта. Размеры шифротекста и заголовков задаются в Config
+const (
+	maxEnvelopeFieldLength = 64
+	maxKeyHeaderSize       = 1024
+)
+
+// checkEnvelope проверяет структуру и размеры конверта зашифрованного сообщения, не расшифровывая его.
+// Открытого текста и вложений у такого сообщения нет, а ключ сообщения должен быть
+// зашифрован хотя бы для одного устройства каждого собеседника
+func (s *ChatSvc) checkEnvelope(ctx context.Context, msg domain.NewMessage) error {
+	env := msg.Envelope
+	if msg.Text != "" || len(msg.AttachmentIds) > 0 {
+		return fmt.Errorf("%w: text and attachments must be encrypted", domain.ErrInvalidEnvelope)
+	}
+	if !validEnvelopeField(env.Algorithm) {
+		return fmt.Errorf("%w: algorithm", domain.ErrInvalidEnvelope)
+	}
+	if !validEnvelopeField(env.SenderDeviceId) {
+		return fmt.Errorf("%w: sender_device_id", domain.ErrInvalidEnvelope)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(env.Ciphertext)
+	if err != nil || len(ciphertext) == 0 {
+		return fmt.Errorf("%w: ciphertext must be non-empty base64", domain.ErrInvalidEnvelope)
+	}
+	if limit := s.Config.MaxCiphertextSize; limit > 0 && len(ciphertext) > limit {
+		return fmt.Errorf("%w: ciphertext is larger than %d bytes", domain.ErrInvalidEnvelope, limit)
+	}
+
+	if len(env.Headers) == 0 {
+		return fmt.Errorf("%w: headers are required", domain.ErrInvalidEnvelope)
+	}
+	if limit := s.Config.MaxEnvelopeHeaders; limit > 0 && len(env.Headers) > limit {
+		return fmt.Errorf("%w: more than %d headers", domain.ErrInvalidEnvelope, limit)
+	}
+
+	members, err := s.ChatRepo.GetChatMembers(ctx, msg.ChatId)
+	if err != nil {
+		return err
+	}
+
+	type device struct {
+		userID   int
+		deviceID string
+	}
+	seen := make(map[device]bool, len(env.Headers))
+	covered := make(map[int]bool, len(members))
+	for _, h := range env.Headers {
+		if !slices.Contains(members, h.UserId) {
+			return fmt.Errorf("%w: user %d is not a chat member", domain.ErrInvalidEnvelope, h.UserId)
+		}
+		if !validEnvelopeField(h.DeviceId) {
+			return fmt.Errorf("%w: device_id", domain.ErrInvalidEnvelope)
+		}
+		d := device{userID: h.UserId, deviceID: h.DeviceId}
+		if seen[d] {
+			return fmt.Errorf("%w: duplicate header for device %q of user %d", domain.ErrInvalidEnvelope, h.DeviceId, h.UserId)
+		}
+		seen[d] = true
+
+		header, err := base64.StdEncoding.DecodeString(h.Header)
+		if err != nil || len(header) == 0 || len(header) > maxKeyHeaderSize {
+			return fmt.Errorf("%w: header must be base64 of at most %d bytes", domain.ErrInvalidEnvelope, maxKeyHeaderSize)
+		}
+		covered[h.UserId] = true
+	}
+
+	for _, id := range members {
+		if id != msg.SenderId && !covered[id] {
+			return fmt.Errorf("%w: no header for user %d", domain.ErrInvalidEnvelope, id)
+		}
+	}
+
+	return nil
+}
+
+func validEnvelopeField(v string) bool {
+	return v != "" && len(v) <= maxEnvelopeFieldLength
+}
+
+// viewEnvelopes оставляет в конвертах сообщений только заголовки устройств userID:
+// ключи для чужих устройств ему все равно не расшифровать
+func viewEnvelopes(messages []domain.Message, userID int) {
+	for i := range messages {
+		env := messages[i].Envelope
+		if env == nil {
+			continue
+		}
+		headers := make([]domain.KeyHeader, 0, len(env.Headers))
+		for _, h := range env.Headers {
+			if h.UserId == userID {
+				headers = append(headers, h)
+			}
+		}
+		env.Headers = headers
+	}
+}
+
+// checkNotEncrypted запрещает операции, которым нужен открытый текст сообщения
+func checkNotEncrypted(msg domain.Message) error {
+	if msg.Envelope != nil {
+		return domain.ErrMessageEncrypted
+	}
+	return nil
+}
+
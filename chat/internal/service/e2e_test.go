@@ -0,0 +1,178 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_PostEncryptedMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+
+	ctx := context.Background()
+	s := &ChatSvc{
+		Config:   Config{MaxCiphertextSize: 16, MaxEnvelopeHeaders: 3},
+		ChatRepo: cr,
+		Notifier: nt,
+	}
+
+	b64 := func(n int) string { return base64.StdEncoding.EncodeToString(make([]byte, n)) }
+	valid := func() *domain.Envelope {
+		return &domain.Envelope{
+			Algorithm:      "x3dh+double-ratchet",
+			SenderDeviceId: "phone",
+			Ciphertext:     b64(16),
+			Headers: []domain.KeyHeader{
+				{UserId: 2, DeviceId: "laptop", Header: b64(32)},
+				{UserId: 1, DeviceId: "phone", Header: b64(32)},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		envelope func(env *domain.Envelope)
+		wantErr  error
+	}{
+		{
+			name:     "ok",
+			envelope: func(env *domain.Envelope) {},
+		},
+		{
+			name:     "plaintext with envelope",
+			text:     "secret",
+			envelope: func(env *domain.Envelope) {},
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+		{
+			name:     "no algorithm",
+			envelope: func(env *domain.Envelope) { env.Algorithm = "" },
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+		{
+			name:     "ciphertext not base64",
+			envelope: func(env *domain.Envelope) { env.Ciphertext = "not base64!" },
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+		{
+			name:     "ciphertext too large",
+			envelope: func(env *domain.Envelope) { env.Ciphertext = b64(17) },
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+		{
+			name: "too many headers",
+			envelope: func(env *domain.Envelope) {
+				env.Headers = append(env.Headers, domain.KeyHeader{UserId: 2, DeviceId: "tablet", Header: b64(32)},
+					domain.KeyHeader{UserId: 2, DeviceId: "desktop", Header: b64(32)})
+			},
+			wantErr: domain.ErrInvalidEnvelope,
+		},
+		{
+			name:     "duplicate device",
+			envelope: func(env *domain.Envelope) { env.Headers[1] = env.Headers[0] },
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+		{
+			name:     "header too large",
+			envelope: func(env *domain.Envelope) { env.Headers[0].Header = b64(maxKeyHeaderSize + 1) },
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+		{
+			name:     "device id too long",
+			envelope: func(env *domain.Envelope) { env.Headers[0].DeviceId = strings.Repeat("d", 65) },
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+		{
+			name:     "header for outsider",
+			envelope: func(env *domain.Envelope) { env.Headers[1].UserId = 3 },
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+		{
+			name:     "no header for recipient",
+			envelope: func(env *domain.Envelope) { env.Headers = env.Headers[1:] },
+			wantErr:  domain.ErrInvalidEnvelope,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := valid()
+			tt.envelope(env)
+			msg := domain.NewMessage{ChatId: 1, SenderId: 1, Text: tt.text, Envelope: env}
+
+			cr.EXPECT().IsChatMember(gomock.Any(), 1, 1).Return(true, nil)
+			cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+			cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{1, 2}, nil).MaxTimes(1)
+			if tt.wantErr == nil {
+				cr.EXPECT().SendMessage(gomock.Any(), msg).Return(5, true, nil)
+				expectNewMessage(cr, nt, 1, 5)
+			}
+
+			_, err := s.PostMessage(ctx, msg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.PostMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChatSvc_GetMessagesFiltersHeaders(t *testing.T) {
+	cr := mock.NewMockChatRepo(gomock.NewController(t))
+	s := &ChatSvc{ChatRepo: cr}
+
+	cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+	cr.EXPECT().GetMessages(gomock.Any(), 1, 2, 10, 0).Return([]domain.Message{
+		{Id: 1, Text: "plain"},
+		{Id: 2, Envelope: &domain.Envelope{Headers: []domain.KeyHeader{
+			{UserId: 1, DeviceId: "phone", Header: "AQ=="},
+			{UserId: 2, DeviceId: "laptop", Header: "Ag=="},
+			{UserId: 2, DeviceId: "tablet", Header: "Aw=="},
+		}}},
+	}, nil)
+
+	got, err := s.GetMessages(context.Background(), 1, 2, 10, 0)
+	if err != nil {
+		t.Fatalf("ChatSvc.GetMessages() error = %v", err)
+	}
+	headers := got[1].Envelope.Headers
+	if len(headers) != 2 || headers[0].DeviceId != "laptop" || headers[1].DeviceId != "tablet" {
+		t.Errorf("ChatSvc.GetMessages() headers = %+v, want only devices of user 2", headers)
+	}
+}
+
+func TestChatSvc_EncryptedMessageOperations(t *testing.T) {
+	cr := mock.NewMockChatRepo(gomock.NewController(t))
+	s := &ChatSvc{ChatRepo: cr}
+	ctx := context.Background()
+
+	encrypted := domain.Message{Id: 3, SenderId: "1", Envelope: &domain.Envelope{Ciphertext: "AQID"}}
+
+	t.Run("edit", func(t *testing.T) {
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 3).Return(encrypted, nil)
+
+		_, err := s.EditMessage(ctx, 1, 1, 3, domain.TextEdit{Text: "new text"})
+		if !errors.Is(err, domain.ErrMessageEncrypted) {
+			t.Errorf("ChatSvc.EditMessage() error = %v, wantErr %v", err, domain.ErrMessageEncrypted)
+		}
+	})
+
+	t.Run("forward", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 1).Return(true, nil)
+		cr.EXPECT().IsChatMember(gomock.Any(), 2, 1).Return(true, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 2).Return(false, nil)
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 3).Return(encrypted, nil)
+
+		_, err := s.ForwardMessage(ctx, 1, 3, 2, 1)
+		if !errors.Is(err, domain.ErrMessageEncrypted) {
+			t.Errorf("ChatSvc.ForwardMessage() error = %v, wantErr %v", err, domain.ErrMessageEncrypted)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/export"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exportPageSize - сколько сообщений чата читается из базы за раз при сборке выгрузки
+const exportPageSize = 500
+
+// RequestExport ставит в очередь выгрузку данных пользователя. Профиль и сессии берутся из auth-сервиса
+// сразу по токену запроса: воркеру, который соберет архив позже, токен не нужен
+func (s *ChatSvc) RequestExport(ctx context.Context, userID int, token string) (domain.Export, error) {
+	account, err := s.Accounts.GetAccount(ctx, token)
+	if err != nil {
+		return domain.Export{}, fmt.Errorf("failed to get account: %w", err)
+	}
+	account.UserId = userID
+
+	return s.ChatRepo.CreateExport(ctx, userID, account)
+}
+
+func (s *ChatSvc) GetExport(ctx context.Context, userID int, exportID string) (domain.Export, error) {
+	if uuid.Validate(exportID) != nil {
+		return domain.Export{}, domain.ErrExportNotFound
+	}
+	return s.ChatRepo.GetExport(ctx, userID, exportID)
+}
+
+func (s *ChatSvc) ListExports(ctx context.Context, userID int) ([]domain.Export, error) {
+	return s.ChatRepo.ListExports(ctx, userID)
+}
+
+// GetExportArchive открывает готовый архив выгрузки, пока ссылка на него не истекла
+func (s *ChatSvc) GetExportArchive(ctx context.Context, userID int, exportID string) (domain.AttachmentContent, error) {
+	exp, err := s.GetExport(ctx, userID, exportID)
+	if err != nil {
+		return domain.AttachmentContent{}, err
+	}
+	if exp.Status != domain.ExportReady {
+		return domain.AttachmentContent{}, domain.ErrExportNotReady
+	}
+	if exp.ExpiresAt != nil && !exp.ExpiresAt.After(time.Now()) {
+		return domain.AttachmentContent{}, domain.ErrExportExpired
+	}
+
+	body, err := s.BlobStore.Get(ctx, exp.StorageKey)
+	if err != nil {
+		return domain.AttachmentContent{}, err
+	}
+
+	return domain.AttachmentContent{
+		FileName:    fmt.Sprintf("export-%s.zip", exp.CreatedAt.Format("2006-01-02")),
+		ContentType: "application/zip",
+		Size:        exp.Size,
+		Body:        body,
+	}, nil
+}
+
+// ProcessExport собирает архив самой старой ожидающей выгрузки. false - очередь пуста.
+// Ошибка сборки не возвращается, а переводит выгрузку в статус failed
+func (s *ChatSvc) ProcessExport(ctx context.Context) (bool, error) {
+	job, err := s.ChatRepo.ClaimExport(ctx, s.Config.ExportTimeout)
+	if errors.Is(err, domain.ErrExportNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	key := fmt.Sprintf("exports/%d/%s.zip", job.UserId, job.Id)
+	size, err := s.buildExport(ctx, job, key)
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to build export", err)
+		return true, s.ChatRepo.FailExport(ctx, job.Id, "failed to build archive")
+	}
+
+	expiresAt := time.Now().Add(s.Config.ExportTTL)
+	if err := s.ChatRepo.FinishExport(ctx, job.Id, key, size, expiresAt); err != nil {
+		return true, err
+	}
+
+	job.Status, job.Size, job.ExpiresAt = domain.ExportReady, size, &expiresAt
+	s.notify(ctx, domain.Event{Type: domain.EventExportReady, UserIds: []int{job.UserId}, Payload: job})
+
+	return true, nil
+}
+
+// buildExport пишет архив во временный файл, чтобы отдать хранилищу с известным размером
+func (s *ChatSvc) buildExport(ctx context.Context, job domain.Export, key string) (int64, error) {
+	f, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	blocked, err := s.ChatRepo.GetBlockedUsers(ctx, job.UserId)
+	if err != nil {
+		return 0, err
+	}
+
+	archive := export.NewArchive(f, export.Profile{
+		ExportedAt:   time.Now().UTC(),
+		Account:      job.Account,
+		BlockedUsers: blocked,
+	})
+
+	chatIDs, err := s.ChatRepo.GetUserChats(ctx, job.UserId)
+	if err != nil {
+		return 0, err
+	}
+	for _, chatID := range chatIDs {
+		chat, err := s.exportChat(ctx, chatID, job.UserId)
+		if err != nil {
+			return 0, fmt.Errorf("failed to export chat %d: %w", chatID, err)
+		}
+		if err := archive.AddChat(chat); err != nil {
+			return 0, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return 0, err
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get archive size: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to rewind archive: %w", err)
+	}
+	if err := s.BlobStore.Put(ctx, key, f, size, "application/zip"); err != nil {
+		return 0, fmt.Errorf("failed to store archive: %w", err)
+	}
+
+	return size, nil
+}
+
+// exportChat собирает чат так, как его видит userID: без скрытых им сообщений,
+// с ключами шифрования только его устройств
+func (s *ChatSvc) exportChat(ctx context.Context, chatID, userID int) (export.Chat, error) {
+	chat := export.Chat{Id: chatID, Messages: []domain.Message{}}
+
+	var err error
+	if chat.Members, err = s.ChatRepo.GetChatMembers(ctx, chatID); err != nil {
+		return export.Chat{}, err
+	}
+	if chat.Settings, err = s.ChatRepo.GetChatSettings(ctx, chatID, userID); err != nil {
+		return export.Chat{}, err
+	}
+	if chat.Retention, err = s.ChatRepo.GetChatRetention(ctx, chatID); err != nil {
+		return export.Chat{}, err
+	}
+
+	for offset := 0; ; offset += exportPageSize {
+		page, err := s.ChatRepo.GetMessages(ctx, chatID, userID, exportPageSize, offset)
+		if err != nil {
+			return export.Chat{}, err
+		}
+		viewEnvelopes(page, userID)
+		viewHidden(page, userID)
+		chat.Messages = append(chat.Messages, page...)
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	return chat, nil
+}
+
+// DeleteExpiredExports удаляет выгрузки с истекшей ссылкой вместе с архивами
+func (s *ChatSvc) DeleteExpiredExports(ctx context.Context) (int, error) {
+	keys, err := s.ChatRepo.DeleteExpiredExports(ctx, s.Config.ExportTTL)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		if err := s.BlobStore.Delete(ctx, key); err != nil {
+			logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to delete export archive", err)
+		}
+	}
+
+	return len(keys), nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_RequestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	ap := mock.NewMockAccountProvider(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Accounts: ap}
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		ap.EXPECT().GetAccount(gomock.Any(), "token").Return(domain.Account{Login: "alice"}, nil)
+		cr.EXPECT().CreateExport(gomock.Any(), 1, domain.Account{UserId: 1, Login: "alice"}).
+			Return(domain.Export{Id: "e1", Status: domain.ExportPending}, nil)
+
+		got, err := s.RequestExport(ctx, 1, "token")
+		if err != nil || got.Id != "e1" {
+			t.Errorf("ChatSvc.RequestExport() = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("already running", func(t *testing.T) {
+		ap.EXPECT().GetAccount(gomock.Any(), "token").Return(domain.Account{}, nil)
+		cr.EXPECT().CreateExport(gomock.Any(), 1, gomock.Any()).Return(domain.Export{}, domain.ErrExportInProgress)
+
+		_, err := s.RequestExport(ctx, 1, "token")
+		if !errors.Is(err, domain.ErrExportInProgress) {
+			t.Errorf("ChatSvc.RequestExport() error = %v, wantErr %v", err, domain.ErrExportInProgress)
+		}
+	})
+}
+
+func TestChatSvc_GetExportArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	bs := mock.NewMockBlobStore(ctrl)
+	s := &ChatSvc{ChatRepo: cr, BlobStore: bs}
+	ctx := context.Background()
+
+	const id = "6f1c3b1e-8a5e-4d2b-9d43-0c5b8f2a7e11"
+	future, past := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		id      string
+		export  domain.Export
+		wantErr error
+	}{
+		{
+			name:   "ready",
+			id:     id,
+			export: domain.Export{Id: id, Status: domain.ExportReady, Size: 3, StorageKey: "exports/1/x.zip", CreatedAt: created, ExpiresAt: &future},
+		},
+		{
+			name:    "invalid id",
+			id:      "../etc/passwd",
+			wantErr: domain.ErrExportNotFound,
+		},
+		{
+			name:    "pending",
+			id:      id,
+			export:  domain.Export{Id: id, Status: domain.ExportPending},
+			wantErr: domain.ErrExportNotReady,
+		},
+		{
+			name:    "expired",
+			id:      id,
+			export:  domain.Export{Id: id, Status: domain.ExportReady, ExpiresAt: &past},
+			wantErr: domain.ErrExportExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.id == id {
+				cr.EXPECT().GetExport(gomock.Any(), 1, id).Return(tt.export, nil)
+			}
+			if tt.wantErr == nil {
+				bs.EXPECT().Get(gomock.Any(), "exports/1/x.zip").Return(io.NopCloser(bytes.NewReader([]byte("zip"))), nil)
+			}
+
+			got, err := s.GetExportArchive(ctx, 1, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChatSvc.GetExportArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.FileName != "export-2024-05-01.zip" || got.ContentType != "application/zip" || got.Size != 3) {
+				t.Errorf("ChatSvc.GetExportArchive() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestChatSvc_ProcessExport(t *testing.T) {
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("empty queue", func(t *testing.T) {
+		cr := mock.NewMockChatRepo(gomock.NewController(t))
+		s := &ChatSvc{Config: Config{ExportTimeout: time.Minute}, ChatRepo: cr}
+
+		cr.EXPECT().ClaimExport(gomock.Any(), time.Minute).Return(domain.Export{}, domain.ErrExportNotFound)
+
+		processed, err := s.ProcessExport(ctx)
+		if processed || err != nil {
+			t.Errorf("ChatSvc.ProcessExport() = %v, %v, want false, nil", processed, err)
+		}
+	})
+
+	t.Run("builds archive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cr := mock.NewMockChatRepo(ctrl)
+		bs := mock.NewMockBlobStore(ctrl)
+		nt := mock.NewMockNotifier(ctrl)
+		s := &ChatSvc{Config: Config{ExportTTL: time.Hour}, ChatRepo: cr, BlobStore: bs, Notifier: nt}
+
+		job := domain.Export{Id: "e1", UserId: 1, Status: domain.ExportRunning, Account: domain.Account{UserId: 1, Login: "alice"}}
+		cr.EXPECT().ClaimExport(gomock.Any(), gomock.Any()).Return(job, nil)
+		cr.EXPECT().GetBlockedUsers(gomock.Any(), 1).Return([]int{9}, nil)
+		cr.EXPECT().GetUserChats(gomock.Any(), 1).Return([]int{5}, nil)
+		cr.EXPECT().GetChatMembers(gomock.Any(), 5).Return([]int{1, 2}, nil)
+		cr.EXPECT().GetChatSettings(gomock.Any(), 5, 1).Return(domain.ChatSettings{}, nil)
+		cr.EXPECT().GetChatRetention(gomock.Any(), 5).Return(domain.ChatRetention{ChatId: 5}, nil)
+		cr.EXPECT().GetMessages(gomock.Any(), 5, 1, exportPageSize, 0).Return([]domain.Message{
+			{Id: 1, SenderId: "1", Text: "hi"},
+			{Id: 2, SenderId: "2", Envelope: &domain.Envelope{Headers: []domain.KeyHeader{
+				{UserId: 1, DeviceId: "phone", Header: "AQ=="},
+				{UserId: 2, DeviceId: "laptop", Header: "Ag=="},
+			}}},
+		}, nil)
+
+		var files []string
+		bs.EXPECT().Put(gomock.Any(), "exports/1/e1.zip", gomock.Any(), gomock.Any(), "application/zip").
+			DoAndReturn(func(_ context.Context, _ string, r io.Reader, size int64, _ string) error {
+				data, err := io.ReadAll(r)
+				if err != nil || int64(len(data)) != size {
+					t.Errorf("archive size = %d, want %d", len(data), size)
+				}
+				zr, err := zip.NewReader(bytes.NewReader(data), size)
+				if err != nil {
+					return err
+				}
+				for _, f := range zr.File {
+					files = append(files, f.Name)
+				}
+				return nil
+			})
+		cr.EXPECT().FinishExport(gomock.Any(), "e1", "exports/1/e1.zip", gomock.Any(), gomock.Any()).Return(nil)
+		nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+			if e.Type != domain.EventExportReady || len(e.UserIds) != 1 || e.UserIds[0] != 1 {
+				t.Errorf("Notify() event = %+v", e)
+			}
+			return nil
+		})
+
+		processed, err := s.ProcessExport(ctx)
+		if !processed || err != nil {
+			t.Fatalf("ChatSvc.ProcessExport() = %v, %v, want true, nil", processed, err)
+		}
+		if len(files) != 4 {
+			t.Errorf("archive files = %v", files)
+		}
+	})
+
+	t.Run("build failure marks export failed", func(t *testing.T) {
+		cr := mock.NewMockChatRepo(gomock.NewController(t))
+		s := &ChatSvc{ChatRepo: cr}
+
+		cr.EXPECT().ClaimExport(gomock.Any(), gomock.Any()).Return(domain.Export{Id: "e1", UserId: 1}, nil)
+		cr.EXPECT().GetBlockedUsers(gomock.Any(), 1).Return(nil, errors.New("db is down"))
+		cr.EXPECT().FailExport(gomock.Any(), "e1", gomock.Any()).Return(nil)
+
+		processed, err := s.ProcessExport(ctx)
+		if !processed || err != nil {
+			t.Errorf("ChatSvc.ProcessExport() = %v, %v, want true, nil", processed, err)
+		}
+	})
+}
+
+func TestChatSvc_DeleteExpiredExports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	bs := mock.NewMockBlobStore(ctrl)
+	s := &ChatSvc{Config: Config{ExportTTL: time.Hour}, ChatRepo: cr, BlobStore: bs}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	cr.EXPECT().DeleteExpiredExports(gomock.Any(), time.Hour).Return([]string{"a.zip", "b.zip"}, nil)
+	bs.EXPECT().Delete(gomock.Any(), "a.zip").Return(errors.New("gone"))
+	bs.EXPECT().Delete(gomock.Any(), "b.zip").Return(nil)
+
+	got, err := s.DeleteExpiredExports(ctx)
+	if got != 2 || err != nil {
+		t.Errorf("ChatSvc.DeleteExpiredExports() = %d, %v, want 2, nil", got, err)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxIncomingWebhookName  = 80
+	incomingWebhookTokenLen = 32
+)
+
+// CreateIncomingWebhook создает входящий вебхук чата. Создать его может любой участник чата,
+// секрет адреса есть только в ответе на создание
+func (s *ChatSvc) CreateIncomingWebhook(ctx context.Context, chatID, userID int, name string) (domain.IncomingWebhook, error) {
+	name, err := checkWebhookName(name)
+	if err != nil {
+		return domain.IncomingWebhook{}, err
+	}
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return domain.IncomingWebhook{}, err
+	}
+
+	raw := make([]byte, incomingWebhookTokenLen)
+	if _, err := rand.Read(raw); err != nil {
+		return domain.IncomingWebhook{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	webhook := domain.IncomingWebhook{ChatId: chatID, CreatorId: userID, Name: name}
+	created, err := s.ChatRepo.CreateIncomingWebhook(ctx, webhook, hashWebhookToken(token), s.Config.MaxIncomingWebhooks)
+	if err != nil {
+		return domain.IncomingWebhook{}, err
+	}
+	created.Token = token
+
+	return created, nil
+}
+
+func (s *ChatSvc) ListIncomingWebhooks(ctx context.Context, chatID, userID int) ([]domain.IncomingWebhook, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.ChatRepo.GetIncomingWebhooks(ctx, chatID)
+}
+
+// DeleteIncomingWebhook отзывает вебхук: его адрес сразу перестает принимать сообщения.
+// Отозвать можно и вебхук, созданный собеседником
+func (s *ChatSvc) DeleteIncomingWebhook(ctx context.Context, chatID, userID, webhookID int) error {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return err
+	}
+
+	return s.ChatRepo.DeleteIncomingWebhook(ctx, chatID, webhookID)
+}
+
+// PostIncomingWebhook отправляет в чат сообщение, присланное на адрес вебхука с секретом token.
+// Сообщение проходит те же проверки, что и сообщение создателя вебхука, кроме лимита отправителя
+func (s *ChatSvc) PostIncomingWebhook(ctx context.Context, token string, post domain.IncomingWebhookPost) (int, error) {
+	if token == "" {
+		return -1, domain.ErrIncomingWebhookNotFound
+	}
+	webhook, err := s.ChatRepo.GetIncomingWebhookByToken(ctx, hashWebhookToken(token))
+	if err != nil {
+		return -1, err
+	}
+
+	name := webhook.Name
+	if strings.TrimSpace(post.Username) != "" {
+		if name, err = checkWebhookName(post.Username); err != nil {
+			return -1, err
+		}
+	}
+	if strings.TrimSpace(post.Text) == "" {
+		return -1, fmt.Errorf("%w: text is required", domain.ErrInvalidIncomingWebhook)
+	}
+
+	return s.PostMessage(ctx, domain.NewMessage{
+		ChatId:            webhook.ChatId,
+		SenderId:          webhook.CreatorId,
+		Text:              post.Text,
+		Format:            post.Format,
+		IncomingWebhookId: webhook.Id,
+		WebhookName:       name,
+	})
+}
+
+func checkWebhookName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxIncomingWebhookName {
+		return "", fmt.Errorf("%w: name must be 1-%d characters", domain.ErrInvalidIncomingWebhook, maxIncomingWebhookName)
+	}
+	return name, nil
+}
+
+// hashWebhookToken - в базе хранится только хеш секрета: утечка базы не дает писать в чаты
+func hashWebhookToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
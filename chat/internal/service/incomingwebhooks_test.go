@@ -0,0 +1,170 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_CreateIncomingWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Config: Config{MaxIncomingWebhooks: 5}}
+	ctx := context.Background()
+
+	t.Run("token is returned once and stored hashed", func(t *testing.T) {
+		var storedHash string
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().
+			CreateIncomingWebhook(gomock.Any(), domain.IncomingWebhook{ChatId: 1, CreatorId: 2, Name: "CI"}, gomock.Any(), 5).
+			DoAndReturn(func(_ context.Context, w domain.IncomingWebhook, hash string, _ int) (domain.IncomingWebhook, error) {
+				storedHash = hash
+				w.Id = 7
+				return w, nil
+			})
+
+		w, err := s.CreateIncomingWebhook(ctx, 1, 2, "  CI ")
+		if err != nil {
+			t.Fatalf("ChatSvc.CreateIncomingWebhook() error = %v", err)
+		}
+		if w.Id != 7 || len(w.Token) != 43 {
+			t.Errorf("ChatSvc.CreateIncomingWebhook() = %+v, want id 7 and 43-char token", w)
+		}
+		if storedHash != hashWebhookToken(w.Token) || strings.Contains(storedHash, w.Token) {
+			t.Errorf("stored hash = %q, want sha256 of the token", storedHash)
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		for _, name := range []string{" ", strings.Repeat("я", maxIncomingWebhookName+1)} {
+			if _, err := s.CreateIncomingWebhook(ctx, 1, 2, name); !errors.Is(err, domain.ErrInvalidIncomingWebhook) {
+				t.Errorf("ChatSvc.CreateIncomingWebhook(%q) error = %v, want %v", name, err, domain.ErrInvalidIncomingWebhook)
+			}
+		}
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 3).Return(false, nil)
+
+		if _, err := s.CreateIncomingWebhook(ctx, 1, 3, "CI"); !errors.Is(err, domain.ErrNotChatMember) {
+			t.Errorf("ChatSvc.CreateIncomingWebhook() error = %v, want %v", err, domain.ErrNotChatMember)
+		}
+	})
+}
+
+func TestChatSvc_PostIncomingWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	rl := mock.NewMockRateLimiter(ctrl)
+	s := &ChatSvc{
+		ChatRepo: cr,
+		Notifier: nt,
+		Limiter:  rl,
+		Config: Config{
+			UserRateBurst:               20,
+			UserRateInterval:            time.Second,
+			IncomingWebhookRateBurst:    2,
+			IncomingWebhookRateInterval: time.Minute,
+		},
+	}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	webhook := domain.IncomingWebhook{Id: 7, ChatId: 1, CreatorId: 2, Name: "CI"}
+	webhookLimit := domain.RateLimit{Burst: 2, Interval: time.Minute}
+	expectWebhook := func() {
+		cr.EXPECT().GetIncomingWebhookByToken(gomock.Any(), hashWebhookToken("secret")).Return(webhook, nil)
+	}
+
+	t.Run("message is sent as webhook and creator is notified", func(t *testing.T) {
+		expectWebhook()
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+		rl.EXPECT().Take(gomock.Any(), "incoming_webhook:7", webhookLimit).Return(time.Duration(0), nil)
+		rl.EXPECT().Take(gomock.Any(), "chat:1", gomock.Any()).Return(time.Duration(0), nil)
+		rl.EXPECT().
+			Take(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key string, _ domain.RateLimit) (time.Duration, error) {
+				if !strings.HasPrefix(key, "duplicate:incoming_webhook:7:") {
+					t.Errorf("duplicate key = %q, want webhook key", key)
+				}
+				return 0, nil
+			})
+		cr.EXPECT().
+			SendMessage(gomock.Any(), domain.NewMessage{
+				ChatId:            1,
+				SenderId:          2,
+				Text:              "Сборка упала",
+				IncomingWebhookId: 7,
+				WebhookName:       "Jenkins",
+			}).
+			Return(30, true, nil)
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 30).Return(domain.Message{Id: 30, SenderId: "2"}, nil)
+		cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+		cr.EXPECT().GetMutedMembers(gomock.Any(), 1).Return(nil, nil)
+		nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+			if len(e.UserIds) != 2 {
+				t.Errorf("event recipients = %v, want creator and the other member", e.UserIds)
+			}
+			return nil
+		})
+
+		id, err := s.PostIncomingWebhook(ctx, "secret", domain.IncomingWebhookPost{Text: "Сборка упала", Username: "Jenkins"})
+		if err != nil || id != 30 {
+			t.Errorf("ChatSvc.PostIncomingWebhook() = %v, %v, want 30, nil", id, err)
+		}
+	})
+
+	t.Run("webhook limit", func(t *testing.T) {
+		expectWebhook()
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+		rl.EXPECT().Take(gomock.Any(), "incoming_webhook:7", webhookLimit).Return(30*time.Second, nil)
+
+		_, err := s.PostIncomingWebhook(ctx, "secret", domain.IncomingWebhookPost{Text: "again"})
+		var rateErr *domain.RateLimitError
+		if !errors.As(err, &rateErr) || rateErr.RetryAfter != 30*time.Second {
+			t.Errorf("ChatSvc.PostIncomingWebhook() error = %v, want RateLimitError with 30s", err)
+		}
+	})
+
+	t.Run("empty text", func(t *testing.T) {
+		expectWebhook()
+
+		if _, err := s.PostIncomingWebhook(ctx, "secret", domain.IncomingWebhookPost{Text: " "}); !errors.Is(err, domain.ErrInvalidIncomingWebhook) {
+			t.Errorf("ChatSvc.PostIncomingWebhook() error = %v, want %v", err, domain.ErrInvalidIncomingWebhook)
+		}
+	})
+
+	t.Run("revoked or unknown token", func(t *testing.T) {
+		cr.EXPECT().GetIncomingWebhookByToken(gomock.Any(), hashWebhookToken("revoked")).Return(domain.IncomingWebhook{}, domain.ErrIncomingWebhookNotFound)
+
+		if _, err := s.PostIncomingWebhook(ctx, "revoked", domain.IncomingWebhookPost{Text: "hi"}); !errors.Is(err, domain.ErrIncomingWebhookNotFound) {
+			t.Errorf("ChatSvc.PostIncomingWebhook() error = %v, want %v", err, domain.ErrIncomingWebhookNotFound)
+		}
+		if _, err := s.PostIncomingWebhook(ctx, "", domain.IncomingWebhookPost{Text: "hi"}); !errors.Is(err, domain.ErrIncomingWebhookNotFound) {
+			t.Errorf("ChatSvc.PostIncomingWebhook() error = %v, want %v", err, domain.ErrIncomingWebhookNotFound)
+		}
+	})
+}
+
+func TestChatSvc_DeleteIncomingWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	s := &ChatSvc{ChatRepo: cr}
+	ctx := context.Background()
+
+	cr.EXPECT().IsChatMember(gomock.Any(), 1, 3).Return(true, nil)
+	cr.EXPECT().DeleteIncomingWebhook(gomock.Any(), 1, 7).Return(domain.ErrIncomingWebhookNotFound)
+
+	if err := s.DeleteIncomingWebhook(ctx, 1, 3, 7); !errors.Is(err, domain.ErrIncomingWebhookNotFound) {
+		t.Errorf("ChatSvc.DeleteIncomingWebhook() error = %v, want %v", err, domain.ErrIncomingWebhookNotFound)
+	}
+}
@@ -7,7 +7,9 @@ package mock
 import (
 	domain "chat/internal/domain"
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +37,107 @@ func (m *MockChatRepo) EXPECT() *MockChatRepoMockRecorder {
 	return m.recorder
 }
 
+// AddReaction mocks base method.
+func (m *MockChatRepo) AddReaction(ctx context.Context, reaction domain.Reaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, reaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockChatRepoMockRecorder) AddReaction(ctx, reaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockChatRepo)(nil).AddReaction), ctx, reaction)
+}
+
+// AppendAudit mocks base method.
+func (m *MockChatRepo) AppendAudit(ctx context.Context, e domain.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendAudit", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendAudit indicates an expected call of AppendAudit.
+func (mr *MockChatRepoMockRecorder) AppendAudit(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAudit", reflect.TypeOf((*MockChatRepo)(nil).AppendAudit), ctx, e)
+}
+
+// BlockUser mocks base method.
+func (m *MockChatRepo) BlockUser(ctx context.Context, userID, blockedUserID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", ctx, userID, blockedUserID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockChatRepoMockRecorder) BlockUser(ctx, userID, blockedUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockChatRepo)(nil).BlockUser), ctx, userID, blockedUserID)
+}
+
+// ClaimExport mocks base method.
+func (m *MockChatRepo) ClaimExport(ctx context.Context, timeout time.Duration) (domain.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExport", ctx, timeout)
+	ret0, _ := ret[0].(domain.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExport indicates an expected call of ClaimExport.
+func (mr *MockChatRepoMockRecorder) ClaimExport(ctx, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExport", reflect.TypeOf((*MockChatRepo)(nil).ClaimExport), ctx, timeout)
+}
+
+// ClaimScheduledMessage mocks base method.
+func (m *MockChatRepo) ClaimScheduledMessage(ctx context.Context, timeout time.Duration) (domain.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimScheduledMessage", ctx, timeout)
+	ret0, _ := ret[0].(domain.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimScheduledMessage indicates an expected call of ClaimScheduledMessage.
+func (mr *MockChatRepoMockRecorder) ClaimScheduledMessage(ctx, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimScheduledMessage", reflect.TypeOf((*MockChatRepo)(nil).ClaimScheduledMessage), ctx, timeout)
+}
+
+// CompleteScheduledMessage mocks base method.
+func (m *MockChatRepo) CompleteScheduledMessage(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteScheduledMessage", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteScheduledMessage indicates an expected call of CompleteScheduledMessage.
+func (mr *MockChatRepoMockRecorder) CompleteScheduledMessage(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteScheduledMessage", reflect.TypeOf((*MockChatRepo)(nil).CompleteScheduledMessage), ctx, id)
+}
+
+// CreateAttachment mocks base method.
+func (m *MockChatRepo) CreateAttachment(ctx context.Context, a domain.Attachment) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttachment", ctx, a)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAttachment indicates an expected call of CreateAttachment.
+func (mr *MockChatRepoMockRecorder) CreateAttachment(ctx, a interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttachment", reflect.TypeOf((*MockChatRepo)(nil).CreateAttachment), ctx, a)
+}
+
 // CreateChat mocks base method.
 func (m *MockChatRepo) CreateChat(ctx context.Context, userID1, userID2 int) (int, error) {
 	m.ctrl.T.Helper()
@@ -50,47 +153,1264 @@ func (mr *MockChatRepoMockRecorder) CreateChat(ctx, userID1, userID2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChat", reflect.TypeOf((*MockChatRepo)(nil).CreateChat), ctx, userID1, userID2)
 }
 
-// GetMessages mocks base method.
-func (m *MockChatRepo) GetMessages(ctx context.Context, chatID, limit, offset int) ([]domain.Message, error) {
+// CreateExport mocks base method.
+func (m *MockChatRepo) CreateExport(ctx context.Context, userID int, account domain.Account) (domain.Export, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMessages", ctx, chatID, limit, offset)
-	ret0, _ := ret[0].([]domain.Message)
+	ret := m.ctrl.Call(m, "CreateExport", ctx, userID, account)
+	ret0, _ := ret[0].(domain.Export)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMessages indicates an expected call of GetMessages.
-func (mr *MockChatRepoMockRecorder) GetMessages(ctx, chatID, limit, offset interface{}) *gomock.Call {
+// CreateExport indicates an expected call of CreateExport.
+func (mr *MockChatRepoMockRecorder) CreateExport(ctx, userID, account interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockChatRepo)(nil).GetMessages), ctx, chatID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExport", reflect.TypeOf((*MockChatRepo)(nil).CreateExport), ctx, userID, account)
 }
 
-// GetUserChats mocks base method.
-func (m *MockChatRepo) GetUserChats(ctx context.Context, userID int) ([]int, error) {
+// CreateIncomingWebhook mocks base method.
+func (m *MockChatRepo) CreateIncomingWebhook(ctx context.Context, w domain.IncomingWebhook, tokenHash string, limit int) (domain.IncomingWebhook, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserChats", ctx, userID)
+	ret := m.ctrl.Call(m, "CreateIncomingWebhook", ctx, w, tokenHash, limit)
+	ret0, _ := ret[0].(domain.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIncomingWebhook indicates an expected call of CreateIncomingWebhook.
+func (mr *MockChatRepoMockRecorder) CreateIncomingWebhook(ctx, w, tokenHash, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncomingWebhook", reflect.TypeOf((*MockChatRepo)(nil).CreateIncomingWebhook), ctx, w, tokenHash, limit)
+}
+
+// CreateScheduledMessage mocks base method.
+func (m_2 *MockChatRepo) CreateScheduledMessage(ctx context.Context, m domain.ScheduledMessage, limit int) (domain.ScheduledMessage, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "CreateScheduledMessage", ctx, m, limit)
+	ret0, _ := ret[0].(domain.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledMessage indicates an expected call of CreateScheduledMessage.
+func (mr *MockChatRepoMockRecorder) CreateScheduledMessage(ctx, m, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledMessage", reflect.TypeOf((*MockChatRepo)(nil).CreateScheduledMessage), ctx, m, limit)
+}
+
+// DeleteChat mocks base method.
+func (m *MockChatRepo) DeleteChat(ctx context.Context, chatID int) ([]int, []domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChat", ctx, chatID)
 	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].([]domain.Attachment)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteChat indicates an expected call of DeleteChat.
+func (mr *MockChatRepoMockRecorder) DeleteChat(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChat", reflect.TypeOf((*MockChatRepo)(nil).DeleteChat), ctx, chatID)
+}
+
+// DeleteExpiredExports mocks base method.
+func (m *MockChatRepo) DeleteExpiredExports(ctx context.Context, olderThan time.Duration) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredExports", ctx, olderThan)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserChats indicates an expected call of GetUserChats.
-func (mr *MockChatRepoMockRecorder) GetUserChats(ctx, userID interface{}) *gomock.Call {
+// DeleteExpiredExports indicates an expected call of DeleteExpiredExports.
+func (mr *MockChatRepoMockRecorder) DeleteExpiredExports(ctx, olderThan interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserChats", reflect.TypeOf((*MockChatRepo)(nil).GetUserChats), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredExports", reflect.TypeOf((*MockChatRepo)(nil).DeleteExpiredExports), ctx, olderThan)
 }
 
-// SendMessage mocks base method.
-func (m *MockChatRepo) SendMessage(ctx context.Context, userID, chatID int, text string) (int, error) {
+// DeleteExpiredMessages mocks base method.
+func (m *MockChatRepo) DeleteExpiredMessages(ctx context.Context, maxAge time.Duration, limit int) ([]domain.ExpiredMessages, []domain.Attachment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendMessage", ctx, userID, chatID, text)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "DeleteExpiredMessages", ctx, maxAge, limit)
+	ret0, _ := ret[0].([]domain.ExpiredMessages)
+	ret1, _ := ret[1].([]domain.Attachment)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteExpiredMessages indicates an expected call of DeleteExpiredMessages.
+func (mr *MockChatRepoMockRecorder) DeleteExpiredMessages(ctx, maxAge, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredMessages", reflect.TypeOf((*MockChatRepo)(nil).DeleteExpiredMessages), ctx, maxAge, limit)
+}
+
+// DeleteIncomingWebhook mocks base method.
+func (m *MockChatRepo) DeleteIncomingWebhook(ctx context.Context, chatID, webhookID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIncomingWebhook", ctx, chatID, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIncomingWebhook indicates an expected call of DeleteIncomingWebhook.
+func (mr *MockChatRepoMockRecorder) DeleteIncomingWebhook(ctx, chatID, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncomingWebhook", reflect.TypeOf((*MockChatRepo)(nil).DeleteIncomingWebhook), ctx, chatID, webhookID)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockChatRepo) DeleteMessage(ctx context.Context, chatID, messageID int) (domain.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(domain.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SendMessage indicates an expected call of SendMessage.
-func (mr *MockChatRepoMockRecorder) SendMessage(ctx, userID, chatID, text interface{}) *gomock.Call {
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockChatRepoMockRecorder) DeleteMessage(ctx, chatID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockChatRepo)(nil).DeleteMessage), ctx, chatID, messageID)
+}
+
+// DeleteMessageAttachments mocks base method.
+func (m *MockChatRepo) DeleteMessageAttachments(ctx context.Context, messageID int) ([]domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessageAttachments", ctx, messageID)
+	ret0, _ := ret[0].([]domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMessageAttachments indicates an expected call of DeleteMessageAttachments.
+func (mr *MockChatRepoMockRecorder) DeleteMessageAttachments(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessageAttachments", reflect.TypeOf((*MockChatRepo)(nil).DeleteMessageAttachments), ctx, messageID)
+}
+
+// DeletePushSubscription mocks base method.
+func (m *MockChatRepo) DeletePushSubscription(ctx context.Context, userID int, endpoint string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePushSubscription", ctx, userID, endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePushSubscription indicates an expected call of DeletePushSubscription.
+func (mr *MockChatRepoMockRecorder) DeletePushSubscription(ctx, userID, endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePushSubscription", reflect.TypeOf((*MockChatRepo)(nil).DeletePushSubscription), ctx, userID, endpoint)
+}
+
+// DeleteScheduledMessage mocks base method.
+func (m *MockChatRepo) DeleteScheduledMessage(ctx context.Context, userID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledMessage", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduledMessage indicates an expected call of DeleteScheduledMessage.
+func (mr *MockChatRepoMockRecorder) DeleteScheduledMessage(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledMessage", reflect.TypeOf((*MockChatRepo)(nil).DeleteScheduledMessage), ctx, userID, id)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockChatRepo) DeleteWebhook(ctx context.Context, botID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, botID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockChatRepoMockRecorder) DeleteWebhook(ctx, botID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockChatRepo)(nil).DeleteWebhook), ctx, botID)
+}
+
+// EditMessage mocks base method.
+func (m *MockChatRepo) EditMessage(ctx context.Context, chatID, messageID int, edit domain.TextEdit) (domain.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditMessage", ctx, chatID, messageID, edit)
+	ret0, _ := ret[0].(domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditMessage indicates an expected call of EditMessage.
+func (mr *MockChatRepoMockRecorder) EditMessage(ctx, chatID, messageID, edit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditMessage", reflect.TypeOf((*MockChatRepo)(nil).EditMessage), ctx, chatID, messageID, edit)
+}
+
+// FailExport mocks base method.
+func (m *MockChatRepo) FailExport(ctx context.Context, exportID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailExport", ctx, exportID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailExport indicates an expected call of FailExport.
+func (mr *MockChatRepoMockRecorder) FailExport(ctx, exportID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailExport", reflect.TypeOf((*MockChatRepo)(nil).FailExport), ctx, exportID, reason)
+}
+
+// FailScheduledMessage mocks base method.
+func (m *MockChatRepo) FailScheduledMessage(ctx context.Context, id int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailScheduledMessage", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailScheduledMessage indicates an expected call of FailScheduledMessage.
+func (mr *MockChatRepoMockRecorder) FailScheduledMessage(ctx, id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailScheduledMessage", reflect.TypeOf((*MockChatRepo)(nil).FailScheduledMessage), ctx, id, reason)
+}
+
+// FinishExport mocks base method.
+func (m *MockChatRepo) FinishExport(ctx context.Context, exportID, storageKey string, size int64, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishExport", ctx, exportID, storageKey, size, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishExport indicates an expected call of FinishExport.
+func (mr *MockChatRepoMockRecorder) FinishExport(ctx, exportID, storageKey, size, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishExport", reflect.TypeOf((*MockChatRepo)(nil).FinishExport), ctx, exportID, storageKey, size, expiresAt)
+}
+
+// GetAttachment mocks base method.
+func (m *MockChatRepo) GetAttachment(ctx context.Context, chatID, attachmentID int) (domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, chatID, attachmentID)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockChatRepoMockRecorder) GetAttachment(ctx, chatID, attachmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockChatRepo)(nil).GetAttachment), ctx, chatID, attachmentID)
+}
+
+// GetAuditLog mocks base method.
+func (m *MockChatRepo) GetAuditLog(ctx context.Context, limit, offset int) ([]domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", ctx, limit, offset)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockChatRepoMockRecorder) GetAuditLog(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockChatRepo)(nil).GetAuditLog), ctx, limit, offset)
+}
+
+// GetBlockedUsers mocks base method.
+func (m *MockChatRepo) GetBlockedUsers(ctx context.Context, userID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockedUsers", ctx, userID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockedUsers indicates an expected call of GetBlockedUsers.
+func (mr *MockChatRepoMockRecorder) GetBlockedUsers(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockedUsers", reflect.TypeOf((*MockChatRepo)(nil).GetBlockedUsers), ctx, userID)
+}
+
+// GetChanges mocks base method.
+func (m *MockChatRepo) GetChanges(ctx context.Context, userID int, since int64, limit int) (domain.SyncBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChanges", ctx, userID, since, limit)
+	ret0, _ := ret[0].(domain.SyncBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChanges indicates an expected call of GetChanges.
+func (mr *MockChatRepoMockRecorder) GetChanges(ctx, userID, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChanges", reflect.TypeOf((*MockChatRepo)(nil).GetChanges), ctx, userID, since, limit)
+}
+
+// GetChatMembers mocks base method.
+func (m *MockChatRepo) GetChatMembers(ctx context.Context, chatID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatMembers", ctx, chatID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatMembers indicates an expected call of GetChatMembers.
+func (mr *MockChatRepoMockRecorder) GetChatMembers(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatMembers", reflect.TypeOf((*MockChatRepo)(nil).GetChatMembers), ctx, chatID)
+}
+
+// GetChatRetention mocks base method.
+func (m *MockChatRepo) GetChatRetention(ctx context.Context, chatID int) (domain.ChatRetention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatRetention", ctx, chatID)
+	ret0, _ := ret[0].(domain.ChatRetention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatRetention indicates an expected call of GetChatRetention.
+func (mr *MockChatRepoMockRecorder) GetChatRetention(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatRetention", reflect.TypeOf((*MockChatRepo)(nil).GetChatRetention), ctx, chatID)
+}
+
+// GetChatSettings mocks base method.
+func (m *MockChatRepo) GetChatSettings(ctx context.Context, chatID, userID int) (domain.ChatSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatSettings", ctx, chatID, userID)
+	ret0, _ := ret[0].(domain.ChatSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatSettings indicates an expected call of GetChatSettings.
+func (mr *MockChatRepoMockRecorder) GetChatSettings(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatSettings", reflect.TypeOf((*MockChatRepo)(nil).GetChatSettings), ctx, chatID, userID)
+}
+
+// GetDrafts mocks base method.
+func (m *MockChatRepo) GetDrafts(ctx context.Context, userID int) ([]domain.Draft, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDrafts", ctx, userID)
+	ret0, _ := ret[0].([]domain.Draft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDrafts indicates an expected call of GetDrafts.
+func (mr *MockChatRepoMockRecorder) GetDrafts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDrafts", reflect.TypeOf((*MockChatRepo)(nil).GetDrafts), ctx, userID)
+}
+
+// GetExport mocks base method.
+func (m *MockChatRepo) GetExport(ctx context.Context, userID int, exportID string) (domain.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExport", ctx, userID, exportID)
+	ret0, _ := ret[0].(domain.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExport indicates an expected call of GetExport.
+func (mr *MockChatRepoMockRecorder) GetExport(ctx, userID, exportID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExport", reflect.TypeOf((*MockChatRepo)(nil).GetExport), ctx, userID, exportID)
+}
+
+// GetIncomingWebhookByToken mocks base method.
+func (m *MockChatRepo) GetIncomingWebhookByToken(ctx context.Context, tokenHash string) (domain.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingWebhookByToken", ctx, tokenHash)
+	ret0, _ := ret[0].(domain.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingWebhookByToken indicates an expected call of GetIncomingWebhookByToken.
+func (mr *MockChatRepoMockRecorder) GetIncomingWebhookByToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingWebhookByToken", reflect.TypeOf((*MockChatRepo)(nil).GetIncomingWebhookByToken), ctx, tokenHash)
+}
+
+// GetIncomingWebhooks mocks base method.
+func (m *MockChatRepo) GetIncomingWebhooks(ctx context.Context, chatID int) ([]domain.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingWebhooks", ctx, chatID)
+	ret0, _ := ret[0].([]domain.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingWebhooks indicates an expected call of GetIncomingWebhooks.
+func (mr *MockChatRepoMockRecorder) GetIncomingWebhooks(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingWebhooks", reflect.TypeOf((*MockChatRepo)(nil).GetIncomingWebhooks), ctx, chatID)
+}
+
+// GetMessage mocks base method.
+func (m *MockChatRepo) GetMessage(ctx context.Context, chatID, messageID int) (domain.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessage indicates an expected call of GetMessage.
+func (mr *MockChatRepoMockRecorder) GetMessage(ctx, chatID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessage", reflect.TypeOf((*MockChatRepo)(nil).GetMessage), ctx, chatID, messageID)
+}
+
+// GetMessageEdits mocks base method.
+func (m *MockChatRepo) GetMessageEdits(ctx context.Context, messageID int) ([]domain.MessageEdit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessageEdits", ctx, messageID)
+	ret0, _ := ret[0].([]domain.MessageEdit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessageEdits indicates an expected call of GetMessageEdits.
+func (mr *MockChatRepoMockRecorder) GetMessageEdits(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageEdits", reflect.TypeOf((*MockChatRepo)(nil).GetMessageEdits), ctx, messageID)
+}
+
+// GetMessageIdByClientId mocks base method.
+func (m *MockChatRepo) GetMessageIdByClientId(ctx context.Context, chatID, senderID int, clientID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessageIdByClientId", ctx, chatID, senderID, clientID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessageIdByClientId indicates an expected call of GetMessageIdByClientId.
+func (mr *MockChatRepoMockRecorder) GetMessageIdByClientId(ctx, chatID, senderID, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageIdByClientId", reflect.TypeOf((*MockChatRepo)(nil).GetMessageIdByClientId), ctx, chatID, senderID, clientID)
+}
+
+// GetMessages mocks base method.
+func (m *MockChatRepo) GetMessages(ctx context.Context, chatID, userID, limit, offset int) ([]domain.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessages", ctx, chatID, userID, limit, offset)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessages indicates an expected call of GetMessages.
+func (mr *MockChatRepoMockRecorder) GetMessages(ctx, chatID, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockChatRepo)(nil).GetMessages), ctx, chatID, userID, limit, offset)
+}
+
+// GetMutedMembers mocks base method.
+func (m *MockChatRepo) GetMutedMembers(ctx context.Context, chatID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMutedMembers", ctx, chatID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMutedMembers indicates an expected call of GetMutedMembers.
+func (mr *MockChatRepoMockRecorder) GetMutedMembers(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMutedMembers", reflect.TypeOf((*MockChatRepo)(nil).GetMutedMembers), ctx, chatID)
+}
+
+// GetPinnedMessages mocks base method.
+func (m *MockChatRepo) GetPinnedMessages(ctx context.Context, chatID, userID int) ([]domain.PinnedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPinnedMessages", ctx, chatID, userID)
+	ret0, _ := ret[0].([]domain.PinnedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPinnedMessages indicates an expected call of GetPinnedMessages.
+func (mr *MockChatRepoMockRecorder) GetPinnedMessages(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPinnedMessages", reflect.TypeOf((*MockChatRepo)(nil).GetPinnedMessages), ctx, chatID, userID)
+}
+
+// GetReactions mocks base method.
+func (m *MockChatRepo) GetReactions(ctx context.Context, messageIDs []int, userID int) (map[int][]domain.ReactionCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReactions", ctx, messageIDs, userID)
+	ret0, _ := ret[0].(map[int][]domain.ReactionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReactions indicates an expected call of GetReactions.
+func (mr *MockChatRepoMockRecorder) GetReactions(ctx, messageIDs, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReactions", reflect.TypeOf((*MockChatRepo)(nil).GetReactions), ctx, messageIDs, userID)
+}
+
+// GetReportedMessage mocks base method.
+func (m *MockChatRepo) GetReportedMessage(ctx context.Context, messageID int) (domain.ReportedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportedMessage", ctx, messageID)
+	ret0, _ := ret[0].(domain.ReportedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportedMessage indicates an expected call of GetReportedMessage.
+func (mr *MockChatRepoMockRecorder) GetReportedMessage(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportedMessage", reflect.TypeOf((*MockChatRepo)(nil).GetReportedMessage), ctx, messageID)
+}
+
+// GetReportedMessages mocks base method.
+func (m *MockChatRepo) GetReportedMessages(ctx context.Context, status string, limit, offset int) ([]domain.ReportedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportedMessages", ctx, status, limit, offset)
+	ret0, _ := ret[0].([]domain.ReportedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportedMessages indicates an expected call of GetReportedMessages.
+func (mr *MockChatRepoMockRecorder) GetReportedMessages(ctx, status, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportedMessages", reflect.TypeOf((*MockChatRepo)(nil).GetReportedMessages), ctx, status, limit, offset)
+}
+
+// GetSyncCursor mocks base method.
+func (m *MockChatRepo) GetSyncCursor(ctx context.Context, userID int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncCursor", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncCursor indicates an expected call of GetSyncCursor.
+func (mr *MockChatRepoMockRecorder) GetSyncCursor(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncCursor", reflect.TypeOf((*MockChatRepo)(nil).GetSyncCursor), ctx, userID)
+}
+
+// GetUnreadCounts mocks base method.
+func (m *MockChatRepo) GetUnreadCounts(ctx context.Context, userID int) ([]domain.UnreadCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadCounts", ctx, userID)
+	ret0, _ := ret[0].([]domain.UnreadCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadCounts indicates an expected call of GetUnreadCounts.
+func (mr *MockChatRepoMockRecorder) GetUnreadCounts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadCounts", reflect.TypeOf((*MockChatRepo)(nil).GetUnreadCounts), ctx, userID)
+}
+
+// GetUserChats mocks base method.
+func (m *MockChatRepo) GetUserChats(ctx context.Context, userID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserChats", ctx, userID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserChats indicates an expected call of GetUserChats.
+func (mr *MockChatRepoMockRecorder) GetUserChats(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserChats", reflect.TypeOf((*MockChatRepo)(nil).GetUserChats), ctx, userID)
+}
+
+// GetWebhook mocks base method.
+func (m *MockChatRepo) GetWebhook(ctx context.Context, botID int) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, botID)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockChatRepoMockRecorder) GetWebhook(ctx, botID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockChatRepo)(nil).GetWebhook), ctx, botID)
+}
+
+// HideMessage mocks base method.
+func (m *MockChatRepo) HideMessage(ctx context.Context, chatID, messageID, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideMessage", ctx, chatID, messageID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HideMessage indicates an expected call of HideMessage.
+func (mr *MockChatRepoMockRecorder) HideMessage(ctx, chatID, messageID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideMessage", reflect.TypeOf((*MockChatRepo)(nil).HideMessage), ctx, chatID, messageID, userID)
+}
+
+// IsBlocked mocks base method.
+func (m *MockChatRepo) IsBlocked(ctx context.Context, userID1, userID2 int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBlocked", ctx, userID1, userID2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBlocked indicates an expected call of IsBlocked.
+func (mr *MockChatRepoMockRecorder) IsBlocked(ctx, userID1, userID2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBlocked", reflect.TypeOf((*MockChatRepo)(nil).IsBlocked), ctx, userID1, userID2)
+}
+
+// IsChatBlocked mocks base method.
+func (m *MockChatRepo) IsChatBlocked(ctx context.Context, chatID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsChatBlocked", ctx, chatID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsChatBlocked indicates an expected call of IsChatBlocked.
+func (mr *MockChatRepoMockRecorder) IsChatBlocked(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsChatBlocked", reflect.TypeOf((*MockChatRepo)(nil).IsChatBlocked), ctx, chatID)
+}
+
+// IsChatMember mocks base method.
+func (m *MockChatRepo) IsChatMember(ctx context.Context, chatID, userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsChatMember", ctx, chatID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsChatMember indicates an expected call of IsChatMember.
+func (mr *MockChatRepoMockRecorder) IsChatMember(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsChatMember", reflect.TypeOf((*MockChatRepo)(nil).IsChatMember), ctx, chatID, userID)
+}
+
+// ListChats mocks base method.
+func (m *MockChatRepo) ListChats(ctx context.Context, q domain.ChatQuery) ([]domain.ChatInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", ctx, q)
+	ret0, _ := ret[0].([]domain.ChatInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockChatRepoMockRecorder) ListChats(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockChatRepo)(nil).ListChats), ctx, q)
+}
+
+// ListExports mocks base method.
+func (m *MockChatRepo) ListExports(ctx context.Context, userID int) ([]domain.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExports", ctx, userID)
+	ret0, _ := ret[0].([]domain.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExports indicates an expected call of ListExports.
+func (mr *MockChatRepoMockRecorder) ListExports(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExports", reflect.TypeOf((*MockChatRepo)(nil).ListExports), ctx, userID)
+}
+
+// ListScheduledMessages mocks base method.
+func (m *MockChatRepo) ListScheduledMessages(ctx context.Context, userID, chatID int) ([]domain.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledMessages", ctx, userID, chatID)
+	ret0, _ := ret[0].([]domain.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledMessages indicates an expected call of ListScheduledMessages.
+func (mr *MockChatRepoMockRecorder) ListScheduledMessages(ctx, userID, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledMessages", reflect.TypeOf((*MockChatRepo)(nil).ListScheduledMessages), ctx, userID, chatID)
+}
+
+// MarkRead mocks base method.
+func (m *MockChatRepo) MarkRead(ctx context.Context, chatID, userID, messageID int) (domain.ReadReceipt, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, chatID, userID, messageID)
+	ret0, _ := ret[0].(domain.ReadReceipt)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockChatRepoMockRecorder) MarkRead(ctx, chatID, userID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockChatRepo)(nil).MarkRead), ctx, chatID, userID, messageID)
+}
+
+// PinMessage mocks base method.
+func (m *MockChatRepo) PinMessage(ctx context.Context, pin domain.PinnedMessage, limit int) (domain.PinnedMessage, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinMessage", ctx, pin, limit)
+	ret0, _ := ret[0].(domain.PinnedMessage)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PinMessage indicates an expected call of PinMessage.
+func (mr *MockChatRepoMockRecorder) PinMessage(ctx, pin, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinMessage", reflect.TypeOf((*MockChatRepo)(nil).PinMessage), ctx, pin, limit)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockChatRepo) RemoveReaction(ctx context.Context, reaction domain.Reaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, reaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockChatRepoMockRecorder) RemoveReaction(ctx, reaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockChatRepo)(nil).RemoveReaction), ctx, reaction)
+}
+
+// ReportMessage mocks base method.
+func (m *MockChatRepo) ReportMessage(ctx context.Context, report domain.Report, hideThreshold int) ([]domain.MessageVisibility, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportMessage", ctx, report, hideThreshold)
+	ret0, _ := ret[0].([]domain.MessageVisibility)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportMessage indicates an expected call of ReportMessage.
+func (mr *MockChatRepoMockRecorder) ReportMessage(ctx, report, hideThreshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportMessage", reflect.TypeOf((*MockChatRepo)(nil).ReportMessage), ctx, report, hideThreshold)
+}
+
+// ResolveReport mocks base method.
+func (m *MockChatRepo) ResolveReport(ctx context.Context, messageID, adminID int, status string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReport", ctx, messageID, adminID, status)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReport indicates an expected call of ResolveReport.
+func (mr *MockChatRepoMockRecorder) ResolveReport(ctx, messageID, adminID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReport", reflect.TypeOf((*MockChatRepo)(nil).ResolveReport), ctx, messageID, adminID, status)
+}
+
+// SaveDraft mocks base method.
+func (m *MockChatRepo) SaveDraft(ctx context.Context, userID int, d domain.Draft) (domain.Draft, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDraft", ctx, userID, d)
+	ret0, _ := ret[0].(domain.Draft)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SaveDraft indicates an expected call of SaveDraft.
+func (mr *MockChatRepoMockRecorder) SaveDraft(ctx, userID, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDraft", reflect.TypeOf((*MockChatRepo)(nil).SaveDraft), ctx, userID, d)
+}
+
+// SavePushSubscription mocks base method.
+func (m *MockChatRepo) SavePushSubscription(ctx context.Context, sub domain.PushSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePushSubscription", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePushSubscription indicates an expected call of SavePushSubscription.
+func (mr *MockChatRepoMockRecorder) SavePushSubscription(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePushSubscription", reflect.TypeOf((*MockChatRepo)(nil).SavePushSubscription), ctx, sub)
+}
+
+// SearchMessages mocks base method.
+func (m *MockChatRepo) SearchMessages(ctx context.Context, q domain.SearchQuery) ([]domain.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchMessages", ctx, q)
+	ret0, _ := ret[0].([]domain.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchMessages indicates an expected call of SearchMessages.
+func (mr *MockChatRepoMockRecorder) SearchMessages(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMessages", reflect.TypeOf((*MockChatRepo)(nil).SearchMessages), ctx, q)
+}
+
+// SendMessage mocks base method.
+func (m *MockChatRepo) SendMessage(ctx context.Context, msg domain.NewMessage) (int, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", ctx, msg)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockChatRepoMockRecorder) SendMessage(ctx, msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockChatRepo)(nil).SendMessage), ctx, msg)
+}
+
+// SetChatRetention mocks base method.
+func (m *MockChatRepo) SetChatRetention(ctx context.Context, chatID, userID, ttl int) (domain.ChatRetention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChatRetention", ctx, chatID, userID, ttl)
+	ret0, _ := ret[0].(domain.ChatRetention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetChatRetention indicates an expected call of SetChatRetention.
+func (mr *MockChatRepoMockRecorder) SetChatRetention(ctx, chatID, userID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChatRetention", reflect.TypeOf((*MockChatRepo)(nil).SetChatRetention), ctx, chatID, userID, ttl)
+}
+
+// SetWebhook mocks base method.
+func (m *MockChatRepo) SetWebhook(ctx context.Context, w domain.Webhook) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWebhook", ctx, w)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWebhook indicates an expected call of SetWebhook.
+func (mr *MockChatRepoMockRecorder) SetWebhook(ctx, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWebhook", reflect.TypeOf((*MockChatRepo)(nil).SetWebhook), ctx, w)
+}
+
+// UnblockUser mocks base method.
+func (m *MockChatRepo) UnblockUser(ctx context.Context, userID, blockedUserID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockUser", ctx, userID, blockedUserID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockChatRepoMockRecorder) UnblockUser(ctx, userID, blockedUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockChatRepo)(nil).UnblockUser), ctx, userID, blockedUserID)
+}
+
+// UnpinMessage mocks base method.
+func (m *MockChatRepo) UnpinMessage(ctx context.Context, chatID, messageID int) (domain.PinnedMessage, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(domain.PinnedMessage)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UnpinMessage indicates an expected call of UnpinMessage.
+func (mr *MockChatRepoMockRecorder) UnpinMessage(ctx, chatID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinMessage", reflect.TypeOf((*MockChatRepo)(nil).UnpinMessage), ctx, chatID, messageID)
+}
+
+// UpdateChatSettings mocks base method.
+func (m *MockChatRepo) UpdateChatSettings(ctx context.Context, chatID, userID int, upd domain.ChatSettingsUpdate) (domain.ChatSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChatSettings", ctx, chatID, userID, upd)
+	ret0, _ := ret[0].(domain.ChatSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChatSettings indicates an expected call of UpdateChatSettings.
+func (mr *MockChatRepoMockRecorder) UpdateChatSettings(ctx, chatID, userID, upd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChatSettings", reflect.TypeOf((*MockChatRepo)(nil).UpdateChatSettings), ctx, chatID, userID, upd)
+}
+
+// UpdateScheduledMessage mocks base method.
+func (m *MockChatRepo) UpdateScheduledMessage(ctx context.Context, userID, id int, edit domain.ScheduledEdit) (domain.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledMessage", ctx, userID, id, edit)
+	ret0, _ := ret[0].(domain.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduledMessage indicates an expected call of UpdateScheduledMessage.
+func (mr *MockChatRepoMockRecorder) UpdateScheduledMessage(ctx, userID, id, edit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledMessage", reflect.TypeOf((*MockChatRepo)(nil).UpdateScheduledMessage), ctx, userID, id, edit)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, event domain.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, event)
+}
+
+// MockBlobStore is a mock of BlobStore interface.
+type MockBlobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStoreMockRecorder
+}
+
+// MockBlobStoreMockRecorder is the mock recorder for MockBlobStore.
+type MockBlobStoreMockRecorder struct {
+	mock *MockBlobStore
+}
+
+// NewMockBlobStore creates a new mock instance.
+func NewMockBlobStore(ctrl *gomock.Controller) *MockBlobStore {
+	mock := &MockBlobStore{ctrl: ctrl}
+	mock.recorder = &MockBlobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStore) EXPECT() *MockBlobStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobStoreMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobStore)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBlobStoreMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBlobStore)(nil).Get), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, r, size, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockBlobStoreMockRecorder) Put(ctx, key, r, size, contentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBlobStore)(nil).Put), ctx, key, r, size, contentType)
+}
+
+// MockRateLimiter is a mock of RateLimiter interface.
+type MockRateLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimiterMockRecorder
+}
+
+// MockRateLimiterMockRecorder is the mock recorder for MockRateLimiter.
+type MockRateLimiterMockRecorder struct {
+	mock *MockRateLimiter
+}
+
+// NewMockRateLimiter creates a new mock instance.
+func NewMockRateLimiter(ctrl *gomock.Controller) *MockRateLimiter {
+	mock := &MockRateLimiter{ctrl: ctrl}
+	mock.recorder = &MockRateLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimiter) EXPECT() *MockRateLimiterMockRecorder {
+	return m.recorder
+}
+
+// Refund mocks base method.
+func (m *MockRateLimiter) Refund(ctx context.Context, key string, limit domain.RateLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, key, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockRateLimiterMockRecorder) Refund(ctx, key, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockRateLimiter)(nil).Refund), ctx, key, limit)
+}
+
+// Take mocks base method.
+func (m *MockRateLimiter) Take(ctx context.Context, key string, limit domain.RateLimit) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, key, limit)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockRateLimiterMockRecorder) Take(ctx, key, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockRateLimiter)(nil).Take), ctx, key, limit)
+}
+
+// MockEventSource is a mock of EventSource interface.
+type MockEventSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventSourceMockRecorder
+}
+
+// MockEventSourceMockRecorder is the mock recorder for MockEventSource.
+type MockEventSourceMockRecorder struct {
+	mock *MockEventSource
+}
+
+// NewMockEventSource creates a new mock instance.
+func NewMockEventSource(ctrl *gomock.Controller) *MockEventSource {
+	mock := &MockEventSource{ctrl: ctrl}
+	mock.recorder = &MockEventSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventSource) EXPECT() *MockEventSourceMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockEventSource) Subscribe(userID int) (<-chan domain.Event, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", userID)
+	ret0, _ := ret[0].(<-chan domain.Event)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventSourceMockRecorder) Subscribe(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventSource)(nil).Subscribe), userID)
+}
+
+// MockAccountProvider is a mock of AccountProvider interface.
+type MockAccountProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountProviderMockRecorder
+}
+
+// MockAccountProviderMockRecorder is the mock recorder for MockAccountProvider.
+type MockAccountProviderMockRecorder struct {
+	mock *MockAccountProvider
+}
+
+// NewMockAccountProvider creates a new mock instance.
+func NewMockAccountProvider(ctrl *gomock.Controller) *MockAccountProvider {
+	mock := &MockAccountProvider{ctrl: ctrl}
+	mock.recorder = &MockAccountProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountProvider) EXPECT() *MockAccountProviderMockRecorder {
+	return m.recorder
+}
+
+// GetAccount mocks base method.
+func (m *MockAccountProvider) GetAccount(ctx context.Context, token string) (domain.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", ctx, token)
+	ret0, _ := ret[0].(domain.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockAccountProviderMockRecorder) GetAccount(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccountProvider)(nil).GetAccount), ctx, token)
+}
+
+// MockUserDirectory is a mock of UserDirectory interface.
+type MockUserDirectory struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserDirectoryMockRecorder
+}
+
+// MockUserDirectoryMockRecorder is the mock recorder for MockUserDirectory.
+type MockUserDirectoryMockRecorder struct {
+	mock *MockUserDirectory
+}
+
+// NewMockUserDirectory creates a new mock instance.
+func NewMockUserDirectory(ctrl *gomock.Controller) *MockUserDirectory {
+	mock := &MockUserDirectory{ctrl: ctrl}
+	mock.recorder = &MockUserDirectoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserDirectory) EXPECT() *MockUserDirectoryMockRecorder {
+	return m.recorder
+}
+
+// ListUsers mocks base method.
+func (m *MockUserDirectory) ListUsers(ctx context.Context, admin domain.Identity, q domain.UserQuery) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, admin, q)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockUserDirectoryMockRecorder) ListUsers(ctx, admin, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserDirectory)(nil).ListUsers), ctx, admin, q)
+}
+
+// SuspendUser mocks base method.
+func (m *MockUserDirectory) SuspendUser(ctx context.Context, admin domain.Identity, s domain.Suspension) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuspendUser", ctx, admin, s)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuspendUser indicates an expected call of SuspendUser.
+func (mr *MockUserDirectoryMockRecorder) SuspendUser(ctx, admin, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuspendUser", reflect.TypeOf((*MockUserDirectory)(nil).SuspendUser), ctx, admin, s)
+}
+
+// UnsuspendUser mocks base method.
+func (m *MockUserDirectory) UnsuspendUser(ctx context.Context, admin domain.Identity, userID int) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsuspendUser", ctx, admin, userID)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnsuspendUser indicates an expected call of UnsuspendUser.
+func (mr *MockUserDirectoryMockRecorder) UnsuspendUser(ctx, admin, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsuspendUser", reflect.TypeOf((*MockUserDirectory)(nil).UnsuspendUser), ctx, admin, userID)
+}
+
+// MockContentFilter is a mock of ContentFilter interface.
+type MockContentFilter struct {
+	ctrl     *gomock.Controller
+	recorder *MockContentFilterMockRecorder
+}
+
+// MockContentFilterMockRecorder is the mock recorder for MockContentFilter.
+type MockContentFilterMockRecorder struct {
+	mock *MockContentFilter
+}
+
+// NewMockContentFilter creates a new mock instance.
+func NewMockContentFilter(ctrl *gomock.Controller) *MockContentFilter {
+	mock := &MockContentFilter{ctrl: ctrl}
+	mock.recorder = &MockContentFilterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContentFilter) EXPECT() *MockContentFilterMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockContentFilter) Check(ctx context.Context, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockContentFilterMockRecorder) Check(ctx, text interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockChatRepo)(nil).SendMessage), ctx, userID, chatID, text)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockContentFilter)(nil).Check), ctx, text)
 }
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+)
+
+// PinMessage закрепляет сообщение в чате для обоих участников. Повторное закрепление ничего не меняет
+func (s *ChatSvc) PinMessage(ctx context.Context, chatID, userID, messageID int) (domain.PinnedMessage, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return domain.PinnedMessage{}, err
+	}
+
+	msg, err := s.ChatRepo.GetMessage(ctx, chatID, messageID)
+	if err != nil {
+		return domain.PinnedMessage{}, err
+	}
+	if msg.DeletedAt != nil {
+		return domain.PinnedMessage{}, domain.ErrMessageDeleted
+	}
+	if msg.HiddenAt != nil {
+		return domain.PinnedMessage{}, domain.ErrMessageHidden
+	}
+
+	pin, created, err := s.ChatRepo.PinMessage(ctx, domain.PinnedMessage{ChatId: chatID, MessageId: messageID, PinnedBy: userID}, s.Config.MaxPinnedMessages)
+	if err != nil {
+		return domain.PinnedMessage{}, err
+	}
+	if created {
+		s.notifyMembers(ctx, domain.EventMessagePinned, chatID, pin)
+	}
+
+	return pin, nil
+}
+
+// UnpinMessage открепляет сообщение. Открепить можно и чужое закрепление, и уже открепленное сообщение
+func (s *ChatSvc) UnpinMessage(ctx context.Context, chatID, userID, messageID int) error {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return err
+	}
+
+	pin, removed, err := s.ChatRepo.UnpinMessage(ctx, chatID, messageID)
+	if err != nil {
+		return err
+	}
+	if removed {
+		s.notifyMembers(ctx, domain.EventMessageUnpinned, chatID, pin)
+	}
+
+	return nil
+}
+
+// GetPinnedMessages возвращает закрепленные сообщения чата вместе с самими сообщениями так, как их видит userID
+func (s *ChatSvc) GetPinnedMessages(ctx context.Context, chatID, userID int) ([]domain.PinnedMessage, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	pins, err := s.ChatRepo.GetPinnedMessages(ctx, chatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]domain.Message, 0, len(pins))
+	for _, p := range pins {
+		msg, err := s.ChatRepo.GetMessage(ctx, chatID, p.MessageId)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	viewEnvelopes(messages, userID)
+	viewHidden(messages, userID)
+
+	for i := range pins {
+		pins[i].Message = &messages[i]
+	}
+
+	return pins, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_PinMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt, Config: Config{MaxPinnedMessages: 2}}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("pin notifies both members", func(t *testing.T) {
+		pin := domain.PinnedMessage{ChatId: 1, MessageId: 10, PinnedBy: 2}
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10}, nil)
+		cr.EXPECT().PinMessage(gomock.Any(), pin, 2).Return(pin, true, nil)
+		cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+		nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+			if e.Type != domain.EventMessagePinned || len(e.UserIds) != 2 {
+				t.Errorf("unexpected event %+v", e)
+			}
+			return nil
+		})
+
+		if _, err := s.PinMessage(ctx, 1, 2, 10); err != nil {
+			t.Errorf("ChatSvc.PinMessage() error = %v", err)
+		}
+	})
+
+	t.Run("repeated pin is silent", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10}, nil)
+		cr.EXPECT().PinMessage(gomock.Any(), gomock.Any(), 2).Return(domain.PinnedMessage{ChatId: 1, MessageId: 10, PinnedBy: 3}, false, nil)
+
+		pin, err := s.PinMessage(ctx, 1, 2, 10)
+		if err != nil || pin.PinnedBy != 3 {
+			t.Errorf("ChatSvc.PinMessage() = %+v, %v", pin, err)
+		}
+	})
+
+	t.Run("hidden message", func(t *testing.T) {
+		now := time.Now()
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 11).Return(domain.Message{Id: 11, HiddenAt: &now}, nil)
+
+		if _, err := s.PinMessage(ctx, 1, 2, 11); !errors.Is(err, domain.ErrMessageHidden) {
+			t.Errorf("ChatSvc.PinMessage() error = %v, want %v", err, domain.ErrMessageHidden)
+		}
+	})
+}
+
+func TestChatSvc_GetPinnedMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	s := &ChatSvc{ChatRepo: cr}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	now := time.Now()
+	cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+	cr.EXPECT().GetPinnedMessages(gomock.Any(), 1, 2).Return([]domain.PinnedMessage{
+		{ChatId: 1, MessageId: 11, PinnedBy: 3},
+		{ChatId: 1, MessageId: 10, PinnedBy: 2},
+	}, nil)
+	cr.EXPECT().GetMessage(gomock.Any(), 1, 11).Return(domain.Message{Id: 11, SenderId: "3", Text: "скрыто", HiddenAt: &now}, nil)
+	cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10, SenderId: "2", Text: "важное"}, nil)
+
+	pins, err := s.GetPinnedMessages(ctx, 1, 2)
+	if err != nil || len(pins) != 2 {
+		t.Fatalf("ChatSvc.GetPinnedMessages() = %+v, %v", pins, err)
+	}
+	// Скрытое модерацией сообщение собеседника закреплено, но его текст не виден
+	if pins[0].Message.Text != "" || pins[1].Message.Text != "важное" {
+		t.Errorf("unexpected pinned messages %+v, %+v", *pins[0].Message, *pins[1].Message)
+	}
+}
+
+func TestChatSvc_SaveDraft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("newer draft is synced to other devices", func(t *testing.T) {
+		at := time.Now().Add(-time.Second)
+		want := domain.Draft{ChatId: 1, Text: "жирный", Entities: []domain.MessageEntity{{Type: domain.EntityBold, Offset: 0, Length: 6}}, UpdatedAt: at}
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().SaveDraft(gomock.Any(), 2, want).Return(want, true, nil)
+		nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+			if e.Type != domain.EventDraftUpdated || len(e.UserIds) != 1 || e.UserIds[0] != 2 {
+				t.Errorf("unexpected event %+v", e)
+			}
+			return nil
+		})
+
+		if _, err := s.SaveDraft(ctx, 2, domain.Draft{ChatId: 1, Text: "**жирный**", UpdatedAt: at}, domain.FormatMarkdown); err != nil {
+			t.Errorf("ChatSvc.SaveDraft() error = %v", err)
+		}
+	})
+
+	t.Run("stale draft returns stored one", func(t *testing.T) {
+		stored := domain.Draft{ChatId: 1, Text: "новее", UpdatedAt: time.Now()}
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().SaveDraft(gomock.Any(), 2, gomock.Any()).Return(stored, false, nil)
+
+		got, err := s.SaveDraft(ctx, 2, domain.Draft{ChatId: 1, Text: "старее", UpdatedAt: time.Now().Add(-time.Hour)}, "")
+		if err != nil || got.Text != "новее" {
+			t.Errorf("ChatSvc.SaveDraft() = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("future timestamp is clamped", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().SaveDraft(gomock.Any(), 2, gomock.Any()).DoAndReturn(func(_ context.Context, _ int, d domain.Draft) (domain.Draft, bool, error) {
+			if d.UpdatedAt.After(time.Now()) {
+				t.Errorf("draft from the future saved: %v", d.UpdatedAt)
+			}
+			return d, false, nil
+		})
+
+		if _, err := s.SaveDraft(ctx, 2, domain.Draft{ChatId: 1, Text: "x", UpdatedAt: time.Now().Add(time.Hour)}, ""); err != nil {
+			t.Errorf("ChatSvc.SaveDraft() error = %v", err)
+		}
+	})
+}
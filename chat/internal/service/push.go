@@ -0,0 +1,64 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Размеры ключей подписки: несжатая точка P-256 и auth-секрет из RFC 8291
+const (
+	pushKeyLen    = 65
+	pushSecretLen = 16
+)
+
+// GetVAPIDPublicKey возвращает ключ сервера, которым браузер подписывается на push-уведомления
+func (s *ChatSvc) GetVAPIDPublicKey() (string, error) {
+	if s.Config.VAPIDPublicKey == "" {
+		return "", domain.ErrPushDisabled
+	}
+	return s.Config.VAPIDPublicKey, nil
+}
+
+// SubscribePush сохраняет push-подписку устройства пользователя
+func (s *ChatSvc) SubscribePush(ctx context.Context, sub domain.PushSubscription) error {
+	if s.Config.VAPIDPublicKey == "" {
+		return domain.ErrPushDisabled
+	}
+	if !validPushSubscription(sub) {
+		return domain.ErrInvalidPushSubscription
+	}
+	return s.ChatRepo.SavePushSubscription(ctx, sub)
+}
+
+func (s *ChatSvc) UnsubscribePush(ctx context.Context, userID int, endpoint string) error {
+	if endpoint == "" {
+		return domain.ErrInvalidPushSubscription
+	}
+	return s.ChatRepo.DeletePushSubscription(ctx, userID, endpoint)
+}
+
+// validPushSubscription проверяет, что endpoint - https-адрес push-сервиса, а ключи подходят для шифрования
+func validPushSubscription(sub domain.PushSubscription) bool {
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return false
+	}
+	if sub.ExpiresAt != nil && !sub.ExpiresAt.After(time.Now()) {
+		return false
+	}
+
+	p256dh, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(sub.P256dh, "="))
+	if err != nil || len(p256dh) != pushKeyLen || p256dh[0] != 0x04 {
+		return false
+	}
+	auth, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(sub.Auth, "="))
+	if err != nil || len(auth) != pushSecretLen {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_SubscribePush(t *testing.T) {
+	cr := mock.NewMockChatRepo(gomock.NewController(t))
+
+	ctx := context.Background()
+	key := base64.RawURLEncoding.EncodeToString(append([]byte{0x04}, make([]byte, 64)...))
+	secret := base64.URLEncoding.EncodeToString(make([]byte, 16))
+	past := time.Now().Add(-time.Minute)
+
+	valid := domain.PushSubscription{UserId: 1, Endpoint: "https://push.example.com/abc", P256dh: key, Auth: secret}
+
+	type MockBehavor func(sub domain.PushSubscription)
+
+	tests := []struct {
+		name        string
+		publicKey   string
+		sub         func() domain.PushSubscription
+		MockBehavor MockBehavor
+		wantErr     error
+	}{
+		{
+			name:      "ok",
+			publicKey: "vapid",
+			sub:       func() domain.PushSubscription { return valid },
+			MockBehavor: func(sub domain.PushSubscription) {
+				cr.EXPECT().SavePushSubscription(gomock.Any(), sub).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "push disabled",
+			sub:         func() domain.PushSubscription { return valid },
+			MockBehavor: func(sub domain.PushSubscription) {},
+			wantErr:     domain.ErrPushDisabled,
+		},
+		{
+			name:      "http endpoint",
+			publicKey: "vapid",
+			sub: func() domain.PushSubscription {
+				sub := valid
+				sub.Endpoint = "http://push.example.com/abc"
+				return sub
+			},
+			MockBehavor: func(sub domain.PushSubscription) {},
+			wantErr:     domain.ErrInvalidPushSubscription,
+		},
+		{
+			name:      "short key",
+			publicKey: "vapid",
+			sub: func() domain.PushSubscription {
+				sub := valid
+				sub.P256dh = strings.Repeat("A", 10)
+				return sub
+			},
+			MockBehavor: func(sub domain.PushSubscription) {},
+			wantErr:     domain.ErrInvalidPushSubscription,
+		},
+		{
+			name:      "invalid auth",
+			publicKey: "vapid",
+			sub: func() domain.PushSubscription {
+				sub := valid
+				sub.Auth = "!!!"
+				return sub
+			},
+			MockBehavor: func(sub domain.PushSubscription) {},
+			wantErr:     domain.ErrInvalidPushSubscription,
+		},
+		{
+			name:      "already expired",
+			publicKey: "vapid",
+			sub: func() domain.PushSubscription {
+				sub := valid
+				sub.ExpiresAt = &past
+				return sub
+			},
+			MockBehavor: func(sub domain.PushSubscription) {},
+			wantErr:     domain.ErrInvalidPushSubscription,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				Config:   Config{VAPIDPublicKey: tt.publicKey},
+				ChatRepo: cr,
+			}
+			sub := tt.sub()
+			tt.MockBehavor(sub)
+			err := s.SubscribePush(ctx, sub)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.SubscribePush() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
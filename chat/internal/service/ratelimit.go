@@ -0,0 +1,108 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// rateCheck - корзина, из которой списывается токен при отправке сообщения
+type rateCheck struct {
+	key   string
+	limit domain.RateLimit
+}
+
+func (s *ChatSvc) checkLength(text string) error {
+	if limit := s.Config.MaxMessageLength; limit > 0 && utf8.RuneCountInString(text) > limit {
+		return domain.ErrMessageTooLong
+	}
+	return nil
+}
+
+// checkRateLimits списывает токены отправки сообщения msg: отправителя, чата и, для непустого текста,
+// одинаковых сообщений отправителя во всех чатах. Отправителем сообщения входящего вебхука считается
+// сам вебхук, а не его создатель. Токены списываются только вместе: если одна корзина пуста,
+// уже списанные возвращаются. Без лимитера проверки отключены
+func (s *ChatSvc) checkRateLimits(ctx context.Context, msg domain.NewMessage) error {
+	if s.Limiter == nil {
+		return nil
+	}
+
+	checks := s.rateChecks(msg)
+	for i, c := range checks {
+		wait, err := s.Limiter.Take(ctx, c.key, c.limit)
+		if err != nil {
+			err = fmt.Errorf("failed to check rate limit: %w", err)
+		} else if wait > 0 {
+			err = &domain.RateLimitError{RetryAfter: wait}
+		}
+		if err != nil {
+			if refundErr := s.refund(ctx, checks[:i]); refundErr != nil {
+				return refundErr
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// refundRateLimits возвращает токены, списанные checkRateLimits за сообщение, которое не удалось сохранить
+func (s *ChatSvc) refundRateLimits(ctx context.Context, msg domain.NewMessage) error {
+	if s.Limiter == nil {
+		return nil
+	}
+	return s.refund(ctx, s.rateChecks(msg))
+}
+
+// rateChecks - корзины, из которых списывается отправка сообщения msg
+func (s *ChatSvc) rateChecks(msg domain.NewMessage) []rateCheck {
+	sender := rateCheck{
+		key:   fmt.Sprintf("user:%d", msg.SenderId),
+		limit: domain.RateLimit{Burst: s.Config.UserRateBurst, Interval: s.Config.UserRateInterval},
+	}
+	duplicateKey := strconv.Itoa(msg.SenderId)
+	if msg.IncomingWebhookId != 0 {
+		sender = rateCheck{
+			key:   fmt.Sprintf("incoming_webhook:%d", msg.IncomingWebhookId),
+			limit: domain.RateLimit{Burst: s.Config.IncomingWebhookRateBurst, Interval: s.Config.IncomingWebhookRateInterval},
+		}
+		duplicateKey = sender.key
+	}
+
+	checks := []rateCheck{
+		sender,
+		{
+			key:   fmt.Sprintf("chat:%d", msg.ChatId),
+			limit: domain.RateLimit{Burst: s.Config.ChatRateBurst, Interval: s.Config.ChatRateInterval},
+		},
+	}
+	if text := normalizeText(msg.Text); text != "" {
+		sum := sha256.Sum256([]byte(text))
+		checks = append(checks, rateCheck{
+			key:   fmt.Sprintf("duplicate:%s:%s", duplicateKey, hex.EncodeToString(sum[:16])),
+			limit: domain.RateLimit{Burst: s.Config.DuplicateRateBurst, Interval: s.Config.DuplicateRateInterval},
+		})
+	}
+	return checks
+}
+
+// refund возвращает токены, списанные до отказа одной из корзин
+func (s *ChatSvc) refund(ctx context.Context, taken []rateCheck) error {
+	for _, c := range taken {
+		if err := s.Limiter.Refund(ctx, c.key, c.limit); err != nil {
+			return fmt.Errorf("failed to refund rate limit: %w", err)
+		}
+	}
+	return nil
+}
+
+// normalizeText приводит текст к виду, в котором повторы с другим регистром и пробелами совпадают
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/internal/storage/memory"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_PostMessageLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	rl := mock.NewMockRateLimiter(ctrl)
+
+	ctx := context.Background()
+	s := &ChatSvc{
+		Config: Config{
+			MaxMessageLength:      10,
+			UserRateBurst:         20,
+			UserRateInterval:      time.Second,
+			ChatRateBurst:         30,
+			ChatRateInterval:      time.Second / 2,
+			DuplicateRateBurst:    3,
+			DuplicateRateInterval: time.Minute,
+		},
+		ChatRepo: cr,
+		Notifier: nt,
+		Limiter:  rl,
+	}
+
+	userLimit := domain.RateLimit{Burst: 20, Interval: time.Second}
+	chatLimit := domain.RateLimit{Burst: 30, Interval: time.Second / 2}
+	duplicateLimit := domain.RateLimit{Burst: 3, Interval: time.Minute}
+
+	expectMember := func() {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		expectMember()
+		gomock.InOrder(
+			rl.EXPECT().Take(gomock.Any(), "user:2", userLimit).Return(time.Duration(0), nil),
+			rl.EXPECT().Take(gomock.Any(), "chat:1", chatLimit).Return(time.Duration(0), nil),
+			rl.EXPECT().Take(gomock.Any(), gomock.Any(), duplicateLimit).Return(time.Duration(0), nil),
+		)
+		cr.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(3, true, nil)
+		expectNewMessage(cr, nt, 1, 3)
+
+		got, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "hello"})
+		if err != nil || got != 3 {
+			t.Errorf("ChatSvc.PostMessage() = %v, %v, want 3, nil", got, err)
+		}
+	})
+
+	t.Run("user limit", func(t *testing.T) {
+		expectMember()
+		rl.EXPECT().Take(gomock.Any(), "user:2", userLimit).Return(1500*time.Millisecond, nil)
+
+		_, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "hello"})
+		var rateErr *domain.RateLimitError
+		if !errors.As(err, &rateErr) || rateErr.RetryAfter != 1500*time.Millisecond {
+			t.Errorf("ChatSvc.PostMessage() error = %v, want RateLimitError with 1.5s", err)
+		}
+		if !errors.Is(err, domain.ErrRateLimited) {
+			t.Errorf("ChatSvc.PostMessage() error = %v, want %v", err, domain.ErrRateLimited)
+		}
+	})
+
+	t.Run("duplicates are normalized", func(t *testing.T) {
+		var keys []string
+		for _, text := range []string{"Buy  now", " buy now "} {
+			expectMember()
+			rl.EXPECT().Take(gomock.Any(), "user:2", userLimit).Return(time.Duration(0), nil)
+			rl.EXPECT().Take(gomock.Any(), "chat:1", chatLimit).Return(time.Duration(0), nil)
+			rl.EXPECT().
+				Take(gomock.Any(), gomock.Any(), duplicateLimit).
+				DoAndReturn(func(_ context.Context, key string, _ domain.RateLimit) (time.Duration, error) {
+					keys = append(keys, key)
+					return time.Minute, nil
+				})
+			rl.EXPECT().Refund(gomock.Any(), "user:2", userLimit).Return(nil)
+			rl.EXPECT().Refund(gomock.Any(), "chat:1", chatLimit).Return(nil)
+
+			_, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: text})
+			if !errors.Is(err, domain.ErrRateLimited) {
+				t.Errorf("ChatSvc.PostMessage() error = %v, want %v", err, domain.ErrRateLimited)
+			}
+		}
+		if len(keys) != 2 || keys[0] != keys[1] || !strings.HasPrefix(keys[0], "duplicate:2:") {
+			t.Errorf("duplicate keys = %v, want two equal keys", keys)
+		}
+	})
+
+	t.Run("replay is not charged", func(t *testing.T) {
+		clientID := "6f9619ff-8b86-d011-b42d-00c04fc964ff"
+		cr.EXPECT().GetMessageIdByClientId(gomock.Any(), 1, 2, clientID).Return(3, nil)
+
+		got, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "hello", ClientMessageId: clientID})
+		if err != nil || got != 3 {
+			t.Errorf("ChatSvc.PostMessage() = %v, %v, want 3, nil", got, err)
+		}
+	})
+
+	t.Run("chat limit refunds user token", func(t *testing.T) {
+		expectMember()
+		gomock.InOrder(
+			rl.EXPECT().Take(gomock.Any(), "user:2", userLimit).Return(time.Duration(0), nil),
+			rl.EXPECT().Take(gomock.Any(), "chat:1", chatLimit).Return(time.Second, nil),
+			rl.EXPECT().Refund(gomock.Any(), "user:2", userLimit).Return(nil),
+		)
+
+		_, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "hello"})
+		if !errors.Is(err, domain.ErrRateLimited) {
+			t.Errorf("ChatSvc.PostMessage() error = %v, want %v", err, domain.ErrRateLimited)
+		}
+	})
+
+	t.Run("failed insert refunds tokens", func(t *testing.T) {
+		expectMember()
+		rl.EXPECT().Take(gomock.Any(), gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(3)
+		cr.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(-1, false, errors.New("db is down"))
+		rl.EXPECT().Refund(gomock.Any(), "user:2", userLimit).Return(nil)
+		rl.EXPECT().Refund(gomock.Any(), "chat:1", chatLimit).Return(nil)
+		rl.EXPECT().Refund(gomock.Any(), gomock.Any(), duplicateLimit).Return(nil)
+
+		if _, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "hello"}); err == nil {
+			t.Errorf("ChatSvc.PostMessage() error = nil, want error")
+		}
+	})
+
+	t.Run("attachments only", func(t *testing.T) {
+		expectMember()
+		rl.EXPECT().Take(gomock.Any(), "user:2", userLimit).Return(time.Duration(0), nil)
+		rl.EXPECT().Take(gomock.Any(), "chat:1", chatLimit).Return(time.Duration(0), nil)
+		cr.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(4, true, nil)
+		expectNewMessage(cr, nt, 1, 4)
+
+		if _, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, AttachmentIds: []int{7}}); err != nil {
+			t.Errorf("ChatSvc.PostMessage() error = %v", err)
+		}
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		_, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "приветствую"})
+		if !errors.Is(err, domain.ErrMessageTooLong) {
+			t.Errorf("ChatSvc.PostMessage() error = %v, want %v", err, domain.ErrMessageTooLong)
+		}
+	})
+
+	t.Run("limiter error", func(t *testing.T) {
+		expectMember()
+		rl.EXPECT().Take(gomock.Any(), "user:2", userLimit).Return(time.Duration(0), errors.New("test err"))
+
+		if _, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "hello"}); err == nil {
+			t.Errorf("ChatSvc.PostMessage() error = nil, want error")
+		}
+	})
+}
+
+func TestChatSvc_PostMessageRejectedNotCharged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	cf := mock.NewMockContentFilter(ctrl)
+	ctx := context.Background()
+	s := &ChatSvc{
+		Config:   Config{UserRateBurst: 1, UserRateInterval: time.Hour},
+		ChatRepo: cr,
+		Filter:   cf,
+		Limiter:  memory.NewRateLimiter(),
+	}
+
+	cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+	cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+	cf.EXPECT().Check(gomock.Any(), "buy now").Return(domain.ErrContentRejected)
+
+	if _, err := s.PostMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "buy now"}); !errors.Is(err, domain.ErrContentRejected) {
+		t.Fatalf("ChatSvc.PostMessage() error = %v, want %v", err, domain.ErrContentRejected)
+	}
+
+	// Единственный токен отправителя остался в корзине
+	if err := s.checkRateLimits(ctx, domain.NewMessage{ChatId: 1, SenderId: 2}); err != nil {
+		t.Errorf("ChatSvc.checkRateLimits() error = %v, want nil", err)
+	}
+}
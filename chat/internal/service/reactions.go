@@ -0,0 +1,83 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxEmojiLength - ограничение длины реакции в символах, с запасом на составные эмодзи (ZWJ-последовательности, флаги)
+const maxEmojiLength = 16
+
+func (s *ChatSvc) AddReaction(ctx context.Context, chatID, userID, messageID int, emoji string) ([]domain.ReactionCount, error) {
+	return s.changeReaction(ctx, chatID, userID, messageID, emoji, true)
+}
+
+func (s *ChatSvc) RemoveReaction(ctx context.Context, chatID, userID, messageID int, emoji string) ([]domain.ReactionCount, error) {
+	return s.changeReaction(ctx, chatID, userID, messageID, emoji, false)
+}
+
+// changeReaction ставит или снимает реакцию и возвращает актуальные реакции на сообщение
+func (s *ChatSvc) changeReaction(ctx context.Context, chatID, userID, messageID int, emoji string, add bool) ([]domain.ReactionCount, error) {
+	if !validEmoji(emoji) {
+		return nil, domain.ErrInvalidEmoji
+	}
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	msg, err := s.ChatRepo.GetMessage(ctx, chatID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.DeletedAt != nil {
+		return nil, domain.ErrMessageDeleted
+	}
+
+	reaction := domain.Reaction{MessageId: messageID, UserId: userID, Emoji: emoji}
+	eventType := domain.EventReactionAdded
+	if add {
+		err = s.ChatRepo.AddReaction(ctx, reaction)
+	} else {
+		eventType = domain.EventReactionRemoved
+		err = s.ChatRepo.RemoveReaction(ctx, reaction)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyMembers(ctx, eventType, chatID, reaction)
+
+	reactions, err := s.ChatRepo.GetReactions(ctx, []int{messageID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	return reactions[messageID], nil
+}
+
+// validEmoji пропускает только пиктограммы и служебные символы составных эмодзи:
+// модификаторы цвета кожи, вариационные селекторы, ZWJ и keycap
+func validEmoji(emoji string) bool {
+	if emoji == "" || utf8.RuneCountInString(emoji) > maxEmojiLength || !utf8.ValidString(emoji) {
+		return false
+	}
+
+	hasSymbol := false
+	for _, r := range emoji {
+		switch {
+		case unicode.Is(unicode.So, r), unicode.Is(unicode.Me, r):
+			hasSymbol = true
+		case r >= 0x1F3FB && r <= 0x1F3FF, r == 0xFE0E, r == 0xFE0F, r == 0x200D:
+			// цвет кожи, вариационные селекторы, ZWJ
+		case r >= 0xE0020 && r <= 0xE007F:
+			// теги субрегиональных флагов
+		case r == '#' || r == '*' || unicode.IsDigit(r):
+			// основа keycap-эмодзи, без U+20E3 строка не пройдет проверку hasSymbol
+		default:
+			return false
+		}
+	}
+
+	return hasSymbol
+}
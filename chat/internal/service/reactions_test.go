@@ -0,0 +1,142 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func Test_validEmoji(t *testing.T) {
+	tests := []struct {
+		name  string
+		emoji string
+		want  bool
+	}{
+		{name: "simple", emoji: "👍", want: true},
+		{name: "with skin tone", emoji: "👍🏽", want: true},
+		{name: "zwj sequence", emoji: "👩‍💻", want: true},
+		{name: "with variation selector", emoji: "❤️", want: true},
+		{name: "keycap", emoji: "1️⃣", want: true},
+		{name: "flag", emoji: "🇷🇺", want: true},
+		{name: "empty", emoji: "", want: false},
+		{name: "text", emoji: "like", want: false},
+		{name: "digit", emoji: "1", want: false},
+		{name: "emoji with text", emoji: "👍ok", want: false},
+		{name: "too long", emoji: "👍👍👍👍👍👍👍👍👍👍👍👍👍👍👍👍👍", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validEmoji(tt.emoji); got != tt.want {
+				t.Errorf("validEmoji(%q) = %v, want %v", tt.emoji, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatSvc_AddReaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+
+	l := logger.New()
+	ctx := logger.InitFromCtx(context.Background(), l)
+
+	deletedAt := time.Now()
+
+	type MockBehavor func(chatID, userID, messageID int, emoji string)
+
+	type args struct {
+		chatID    int
+		userID    int
+		messageID int
+		emoji     string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		want        []domain.ReactionCount
+		wantErr     error
+	}{
+		{
+			name: "ok",
+			args: args{chatID: 1, userID: 2, messageID: 3, emoji: "👍"},
+			MockBehavor: func(chatID, userID, messageID int, emoji string) {
+				reaction := domain.Reaction{MessageId: messageID, UserId: userID, Emoji: emoji}
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID}, nil)
+				cr.EXPECT().AddReaction(gomock.Any(), reaction).Return(nil)
+				cr.EXPECT().GetChatMembers(gomock.Any(), chatID).Return([]int{1, 2}, nil)
+				nt.EXPECT().
+					Notify(gomock.Any(), domain.Event{
+						Type:    domain.EventReactionAdded,
+						ChatId:  chatID,
+						UserIds: []int{1, 2},
+						Payload: reaction,
+					}).
+					Return(nil)
+				cr.EXPECT().
+					GetReactions(gomock.Any(), []int{messageID}, userID).
+					Return(map[int][]domain.ReactionCount{
+						messageID: {{Emoji: emoji, Count: 2, ReactedByMe: true}},
+					}, nil)
+			},
+			want:    []domain.ReactionCount{{Emoji: "👍", Count: 2, ReactedByMe: true}},
+			wantErr: nil,
+		},
+		{
+			name:        "with invalid emoji",
+			args:        args{chatID: 1, userID: 2, messageID: 3, emoji: "like"},
+			MockBehavor: func(chatID, userID, messageID int, emoji string) {},
+			want:        nil,
+			wantErr:     domain.ErrInvalidEmoji,
+		},
+		{
+			name: "with not member",
+			args: args{chatID: 1, userID: 5, messageID: 3, emoji: "👍"},
+			MockBehavor: func(chatID, userID, messageID int, emoji string) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(false, nil)
+			},
+			want:    nil,
+			wantErr: domain.ErrNotChatMember,
+		},
+		{
+			name: "with deleted message",
+			args: args{chatID: 1, userID: 2, messageID: 3, emoji: "👍"},
+			MockBehavor: func(chatID, userID, messageID int, emoji string) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID, DeletedAt: &deletedAt}, nil)
+			},
+			want:    nil,
+			wantErr: domain.ErrMessageDeleted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				ChatRepo: cr,
+				Notifier: nt,
+			}
+			tt.MockBehavor(tt.args.chatID, tt.args.userID, tt.args.messageID, tt.args.emoji)
+			got, err := s.AddReaction(ctx, tt.args.chatID, tt.args.userID, tt.args.messageID, tt.args.emoji)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.AddReaction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatSvc.AddReaction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
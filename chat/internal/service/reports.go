@@ -0,0 +1,139 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxReportComment - длина пояснения к жалобе в символах
+const maxReportComment = 500
+
+// ReportMessage принимает жалобу участника чата на сообщение собеседника. Если после нее сообщения
+// отправителя скрываются, участники их чатов получают событие message_hidden
+func (s *ChatSvc) ReportMessage(ctx context.Context, report domain.Report) error {
+	if !slices.Contains(domain.ReportReasons, report.Reason) {
+		return fmt.Errorf("%w: unknown reason %q", domain.ErrInvalidReport, report.Reason)
+	}
+	report.Comment = strings.TrimSpace(report.Comment)
+	if utf8.RuneCountInString(report.Comment) > maxReportComment {
+		return fmt.Errorf("%w: comment is too long", domain.ErrInvalidReport)
+	}
+
+	if err := s.checkMember(ctx, report.ChatId, report.ReporterId); err != nil {
+		return err
+	}
+	msg, err := s.ChatRepo.GetMessage(ctx, report.ChatId, report.MessageId)
+	if err != nil {
+		return err
+	}
+	if msg.DeletedAt != nil {
+		return domain.ErrMessageDeleted
+	}
+	if msg.SenderId == strconv.Itoa(report.ReporterId) {
+		return fmt.Errorf("%w: cannot report own message", domain.ErrInvalidReport)
+	}
+
+	hidden, err := s.ChatRepo.ReportMessage(ctx, report, s.Config.ReportHideThreshold)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range hidden {
+		s.notifyMembers(ctx, domain.EventMessageHidden, v.ChatId, v)
+	}
+
+	return nil
+}
+
+// GetReportedMessages - очередь модерации. Пустой status - сообщения, ждущие решения
+func (s *ChatSvc) GetReportedMessages(ctx context.Context, admin domain.Identity, status string, limit, offset int) ([]domain.ReportedMessage, error) {
+	if err := requireAdmin(admin); err != nil {
+		return nil, err
+	}
+	switch status {
+	case "", domain.ModerationPending, domain.ModerationHidden, domain.ModerationDismissed, domain.ModerationRemoved:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidReport, status)
+	}
+	limit, offset = adminPage(limit, offset)
+
+	return s.ChatRepo.GetReportedMessages(ctx, status, limit, offset)
+}
+
+// ResolveReport закрывает жалобы на сообщение. dismiss оставляет сообщение и снимает скрытие,
+// remove удаляет его у всех, как AdminDeleteMessage
+func (s *ChatSvc) ResolveReport(ctx context.Context, admin domain.Identity, messageID int, action, reason string) (domain.ReportedMessage, error) {
+	if err := requireAdmin(admin); err != nil {
+		return domain.ReportedMessage{}, err
+	}
+
+	report, err := s.ChatRepo.GetReportedMessage(ctx, messageID)
+	if err != nil {
+		return domain.ReportedMessage{}, err
+	}
+	if report.Status != domain.ModerationPending && report.Status != domain.ModerationHidden {
+		return domain.ReportedMessage{}, domain.ErrReportResolved
+	}
+
+	switch action {
+	case domain.ResolveDismiss:
+		restored, err := s.ChatRepo.ResolveReport(ctx, messageID, admin.UserId, domain.ModerationDismissed)
+		if err != nil {
+			return domain.ReportedMessage{}, err
+		}
+		if restored {
+			s.notifyMembers(ctx, domain.EventMessageRestored, report.ChatId,
+				domain.MessageVisibility{ChatId: report.ChatId, MessageId: messageID})
+		}
+		s.audit(ctx, domain.AuditEntry{
+			AdminId:   admin.UserId,
+			Action:    domain.AuditDismissReport,
+			UserId:    report.SenderId,
+			ChatId:    report.ChatId,
+			MessageId: messageID,
+			Reason:    strings.TrimSpace(reason),
+		})
+	case domain.ResolveRemove:
+		// Отправитель мог удалить сообщение сам, пока жалоба ждала решения
+		err := s.AdminDeleteMessage(ctx, admin, report.ChatId, messageID, reason)
+		if err != nil && !errors.Is(err, domain.ErrMessageDeleted) {
+			return domain.ReportedMessage{}, err
+		}
+		if _, err := s.ChatRepo.ResolveReport(ctx, messageID, admin.UserId, domain.ModerationRemoved); err != nil {
+			return domain.ReportedMessage{}, err
+		}
+	default:
+		return domain.ReportedMessage{}, fmt.Errorf("%w: unknown action %q", domain.ErrInvalidReport, action)
+	}
+
+	return s.ChatRepo.GetReportedMessage(ctx, messageID)
+}
+
+// checkContent пропускает текст через фильтр содержимого, если он настроен
+func (s *ChatSvc) checkContent(ctx context.Context, text string) error {
+	if s.Filter == nil || text == "" {
+		return nil
+	}
+	return s.Filter.Check(ctx, text)
+}
+
+// viewHidden убирает содержимое скрытых модерацией сообщений у всех, кроме отправителя
+func viewHidden(messages []domain.Message, userID int) {
+	viewer := strconv.Itoa(userID)
+	for i := range messages {
+		if messages[i].HiddenAt == nil || messages[i].SenderId == viewer {
+			continue
+		}
+		messages[i].Text = ""
+		messages[i].Envelope = nil
+		messages[i].Attachments = nil
+		messages[i].LinkPreviews = nil
+		messages[i].Entities = nil
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_ReportMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt, Config: Config{ReportHideThreshold: 3}}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	report := domain.Report{ChatId: 1, MessageId: 10, ReporterId: 2, Reason: domain.ReportSpam}
+
+	t.Run("hides after threshold", func(t *testing.T) {
+		hiddenAt := time.Now()
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10, SenderId: "3"}, nil)
+		cr.EXPECT().ReportMessage(gomock.Any(), report, 3).
+			Return([]domain.MessageVisibility{{ChatId: 1, MessageId: 10, HiddenAt: &hiddenAt}}, nil)
+		cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+		nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+			if e.Type != domain.EventMessageHidden {
+				t.Errorf("event type = %s, want %s", e.Type, domain.EventMessageHidden)
+			}
+			return nil
+		})
+
+		if err := s.ReportMessage(ctx, report); err != nil {
+			t.Errorf("ChatSvc.ReportMessage() error = %v", err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		report  domain.Report
+		setup   func()
+		wantErr error
+	}{
+		{
+			name:    "unknown reason",
+			report:  domain.Report{ChatId: 1, MessageId: 10, ReporterId: 2, Reason: "boring"},
+			wantErr: domain.ErrInvalidReport,
+		},
+		{
+			name:   "own message",
+			report: report,
+			setup: func() {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+				cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10, SenderId: "2"}, nil)
+			},
+			wantErr: domain.ErrInvalidReport,
+		},
+		{
+			name:   "not a member",
+			report: report,
+			setup: func() {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(false, nil)
+			},
+			wantErr: domain.ErrNotChatMember,
+		},
+		{
+			name:   "already reported",
+			report: report,
+			setup: func() {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+				cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10, SenderId: "3"}, nil)
+				cr.EXPECT().ReportMessage(gomock.Any(), report, 3).Return(nil, domain.ErrAlreadyReported)
+			},
+			wantErr: domain.ErrAlreadyReported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			if err := s.ReportMessage(ctx, tt.report); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.ReportMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChatSvc_ResolveReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	hidden := domain.ReportedMessage{ChatId: 1, MessageId: 10, SenderId: 3, Status: domain.ModerationHidden}
+
+	t.Run("dismiss restores and audits", func(t *testing.T) {
+		cr.EXPECT().GetReportedMessage(gomock.Any(), 10).Return(hidden, nil)
+		cr.EXPECT().ResolveReport(gomock.Any(), 10, testAdmin.UserId, domain.ModerationDismissed).Return(true, nil)
+		cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+		nt.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(nil)
+		cr.EXPECT().AppendAudit(gomock.Any(), domain.AuditEntry{
+			AdminId:   testAdmin.UserId,
+			Action:    domain.AuditDismissReport,
+			UserId:    3,
+			ChatId:    1,
+			MessageId: 10,
+		}).Return(nil)
+		cr.EXPECT().GetReportedMessage(gomock.Any(), 10).
+			Return(domain.ReportedMessage{MessageId: 10, Status: domain.ModerationDismissed}, nil)
+
+		got, err := s.ResolveReport(ctx, testAdmin, 10, domain.ResolveDismiss, "")
+		if err != nil || got.Status != domain.ModerationDismissed {
+			t.Errorf("ChatSvc.ResolveReport() = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("remove already deleted by sender", func(t *testing.T) {
+		cr.EXPECT().GetReportedMessage(gomock.Any(), 10).Return(hidden, nil)
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10, SenderId: "3"}, nil)
+		cr.EXPECT().DeleteMessage(gomock.Any(), 1, 10).Return(domain.Message{}, domain.ErrMessageDeleted)
+		cr.EXPECT().ResolveReport(gomock.Any(), 10, testAdmin.UserId, domain.ModerationRemoved).Return(false, nil)
+		cr.EXPECT().GetReportedMessage(gomock.Any(), 10).
+			Return(domain.ReportedMessage{MessageId: 10, Status: domain.ModerationRemoved}, nil)
+
+		got, err := s.ResolveReport(ctx, testAdmin, 10, domain.ResolveRemove, "spam")
+		if err != nil || got.Status != domain.ModerationRemoved {
+			t.Errorf("ChatSvc.ResolveReport() = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		cr.EXPECT().GetReportedMessage(gomock.Any(), 10).
+			Return(domain.ReportedMessage{MessageId: 10, Status: domain.ModerationDismissed}, nil)
+
+		_, err := s.ResolveReport(ctx, testAdmin, 10, domain.ResolveRemove, "")
+		if !errors.Is(err, domain.ErrReportResolved) {
+			t.Errorf("ChatSvc.ResolveReport() error = %v, wantErr %v", err, domain.ErrReportResolved)
+		}
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		cr.EXPECT().GetReportedMessage(gomock.Any(), 10).Return(hidden, nil)
+
+		_, err := s.ResolveReport(ctx, testAdmin, 10, "ban", "")
+		if !errors.Is(err, domain.ErrInvalidReport) {
+			t.Errorf("ChatSvc.ResolveReport() error = %v, wantErr %v", err, domain.ErrInvalidReport)
+		}
+	})
+}
+
+func TestChatSvc_PostMessageContentFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	cf := mock.NewMockContentFilter(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Filter: cf}
+
+	cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+	cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+	cf.EXPECT().Check(gomock.Any(), "buy now").Return(domain.ErrContentRejected)
+
+	_, err := s.PostMessage(context.Background(), domain.NewMessage{ChatId: 1, SenderId: 2, Text: "buy now"})
+	if !errors.Is(err, domain.ErrContentRejected) {
+		t.Errorf("ChatSvc.PostMessage() error = %v, wantErr %v", err, domain.ErrContentRejected)
+	}
+}
+
+func TestViewHidden(t *testing.T) {
+	now := time.Now()
+	messages := []domain.Message{
+		{Id: 1, SenderId: "2", Text: "spam https://spam.example", HiddenAt: &now, Attachments: []domain.Attachment{{Id: 1}},
+			LinkPreviews: []domain.LinkPreview{{URL: "https://spam.example", Title: "spam"}}},
+		{Id: 2, SenderId: "3", Text: "mine", HiddenAt: &now},
+		{Id: 3, SenderId: "2", Text: "ok"},
+	}
+
+	viewHidden(messages, 3)
+
+	if messages[0].Text != "" || messages[0].Attachments != nil || messages[0].LinkPreviews != nil {
+		t.Errorf("hidden message of other sender is visible: %+v", messages[0])
+	}
+	if messages[1].Text != "mine" || messages[2].Text != "ok" {
+		t.Errorf("visible messages changed: %+v", messages[1:])
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"time"
+)
+
+// Допустимый срок жизни сообщений чата. Сверху он также ограничен глобальным MessageRetention
+const (
+	minMessageTTL = time.Minute
+	maxMessageTTL = 365 * 24 * time.Hour
+)
+
+func (s *ChatSvc) GetChatRetention(ctx context.Context, chatID, userID int) (domain.ChatRetention, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return domain.ChatRetention{}, err
+	}
+	return s.ChatRepo.GetChatRetention(ctx, chatID)
+}
+
+// SetChatRetention включает исчезающие сообщения в чате (ttl > 0) или выключает их (ttl = 0).
+// Срок меняет любой участник, второй получает событие chat_retention_changed
+func (s *ChatSvc) SetChatRetention(ctx context.Context, chatID, userID int, ttl time.Duration) (domain.ChatRetention, error) {
+	if ttl != 0 && (ttl < minMessageTTL || ttl > maxMessageTTL || ttl%time.Second != 0) {
+		return domain.ChatRetention{}, domain.ErrInvalidRetention
+	}
+	if limit := s.Config.MessageRetention; limit > 0 && ttl > limit {
+		return domain.ChatRetention{}, domain.ErrInvalidRetention
+	}
+
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return domain.ChatRetention{}, err
+	}
+
+	retention, err := s.ChatRepo.SetChatRetention(ctx, chatID, userID, int(ttl/time.Second))
+	if err != nil {
+		return domain.ChatRetention{}, err
+	}
+
+	s.notifyMembers(ctx, domain.EventChatRetentionChanged, chatID, retention, userID)
+
+	return retention, nil
+}
+
+// ExpireMessages удаляет одну порцию (до limit) истекших сообщений вместе с файлами вложений
+// и рассылает участникам чатов событие messages_expired. Возвращает число удаленных сообщений
+func (s *ChatSvc) ExpireMessages(ctx context.Context, limit int) (int, error) {
+	expired, attachments, err := s.ChatRepo.DeleteExpiredMessages(ctx, s.Config.MessageRetention, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, a := range attachments {
+		s.deleteBlobs(ctx, a)
+	}
+
+	deleted := 0
+	for _, e := range expired {
+		deleted += len(e.MessageIds)
+		s.notifyMembers(ctx, domain.EventMessagesExpired, e.ChatId, e)
+	}
+
+	return deleted, nil
+}
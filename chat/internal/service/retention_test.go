@@ -0,0 +1,134 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_SetChatRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+
+	ctx := context.Background()
+	s := &ChatSvc{Config: Config{MessageRetention: 30 * 24 * time.Hour}, ChatRepo: cr, Notifier: nt}
+
+	type MockBehavor func()
+
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		MockBehavor MockBehavor
+		want        domain.ChatRetention
+		wantErr     error
+	}{
+		{
+			name: "a day",
+			ttl:  24 * time.Hour,
+			MockBehavor: func() {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+				cr.EXPECT().SetChatRetention(gomock.Any(), 1, 2, 86400).Return(domain.ChatRetention{ChatId: 1, TTL: 86400, UpdatedBy: 2}, nil)
+				cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+				nt.EXPECT().Notify(gomock.Any(), domain.Event{
+					Type:    domain.EventChatRetentionChanged,
+					ChatId:  1,
+					UserIds: []int{3},
+					Payload: domain.ChatRetention{ChatId: 1, TTL: 86400, UpdatedBy: 2},
+				}).Return(nil)
+			},
+			want: domain.ChatRetention{ChatId: 1, TTL: 86400, UpdatedBy: 2},
+		},
+		{
+			name: "turn off",
+			ttl:  0,
+			MockBehavor: func() {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+				cr.EXPECT().SetChatRetention(gomock.Any(), 1, 2, 0).Return(domain.ChatRetention{ChatId: 1, UpdatedBy: 2}, nil)
+				cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+				nt.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			want: domain.ChatRetention{ChatId: 1, UpdatedBy: 2},
+		},
+		{
+			name:        "too short",
+			ttl:         time.Second,
+			MockBehavor: func() {},
+			wantErr:     domain.ErrInvalidRetention,
+		},
+		{
+			name:        "longer than global retention",
+			ttl:         31 * 24 * time.Hour,
+			MockBehavor: func() {},
+			wantErr:     domain.ErrInvalidRetention,
+		},
+		{
+			name: "not member",
+			ttl:  time.Hour,
+			MockBehavor: func() {
+				cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(false, nil)
+			},
+			wantErr: domain.ErrNotChatMember,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.MockBehavor()
+
+			got, err := s.SetChatRetention(ctx, 1, 2, tt.ttl)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.SetChatRetention() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatSvc.SetChatRetention() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatSvc_ExpireMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	bs := mock.NewMockBlobStore(ctrl)
+
+	ctx := context.Background()
+	s := &ChatSvc{Config: Config{MessageRetention: time.Hour}, ChatRepo: cr, Notifier: nt, BlobStore: bs}
+
+	cr.EXPECT().DeleteExpiredMessages(gomock.Any(), time.Hour, 100).Return(
+		[]domain.ExpiredMessages{
+			{ChatId: 1, MessageIds: []int{3, 4}},
+			{ChatId: 2, MessageIds: []int{7}},
+		},
+		[]domain.Attachment{{Id: 1, StorageKey: "a", ThumbnailKey: "a_thumb"}},
+		nil,
+	)
+	bs.EXPECT().Delete(gomock.Any(), "a").Return(nil)
+	bs.EXPECT().Delete(gomock.Any(), "a_thumb").Return(nil)
+	cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{1, 2}, nil)
+	cr.EXPECT().GetChatMembers(gomock.Any(), 2).Return([]int{1, 3}, nil)
+	nt.EXPECT().Notify(gomock.Any(), domain.Event{
+		Type:    domain.EventMessagesExpired,
+		ChatId:  1,
+		UserIds: []int{1, 2},
+		Payload: domain.ExpiredMessages{ChatId: 1, MessageIds: []int{3, 4}},
+	}).Return(nil)
+	nt.EXPECT().Notify(gomock.Any(), domain.Event{
+		Type:    domain.EventMessagesExpired,
+		ChatId:  2,
+		UserIds: []int{1, 3},
+		Payload: domain.ExpiredMessages{ChatId: 2, MessageIds: []int{7}},
+	}).Return(nil)
+
+	got, err := s.ExpireMessages(ctx, 100)
+	if err != nil || got != 3 {
+		t.Errorf("ChatSvc.ExpireMessages() = %v, %v, want 3, nil", got, err)
+	}
+}
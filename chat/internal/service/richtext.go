@@ -0,0 +1,55 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/richtext"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// formatText приводит текст к хранимому виду: разбирает Markdown или проверяет разметку, присланную клиентом
+func formatText(text, format string, entities []domain.MessageEntity) (string, []domain.MessageEntity, error) {
+	switch format {
+	case "":
+		entities, err := richtext.Validate(text, entities)
+		return text, entities, err
+	case domain.FormatMarkdown:
+		if len(entities) > 0 {
+			return "", nil, fmt.Errorf("%w: entities are built from markdown", domain.ErrInvalidEntities)
+		}
+		return richtext.ParseMarkdown(text)
+	default:
+		return "", nil, fmt.Errorf("%w: unknown format %q", domain.ErrInvalidEntities, format)
+	}
+}
+
+// resolveMentions проверяет, что упомянутые пользователи состоят в чате, и возвращает их без отправителя
+func (s *ChatSvc) resolveMentions(ctx context.Context, chatID, senderID int, entities []domain.MessageEntity) ([]int, error) {
+	mentioned := richtext.Mentions(entities)
+	if len(mentioned) == 0 {
+		return nil, nil
+	}
+
+	members, err := s.ChatRepo.GetChatMembers(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(mentioned))
+	for _, id := range mentioned {
+		if !slices.Contains(members, id) {
+			return nil, fmt.Errorf("%w: user %d is not a chat member", domain.ErrInvalidEntities, id)
+		}
+		if id != senderID {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// GetUnreadCounts возвращает по каждому чату пользователя число непрочитанных сообщений и упоминаний.
+// Чаты без непрочитанного не попадают в ответ
+func (s *ChatSvc) GetUnreadCounts(ctx context.Context, userID int) ([]domain.UnreadCount, error) {
+	return s.ChatRepo.GetUnreadCounts(ctx, userID)
+}
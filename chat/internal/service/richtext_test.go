@@ -0,0 +1,131 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_PostMessage_Entities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("markdown with mentions", func(t *testing.T) {
+		mention := domain.MessageEntity{Type: domain.EntityMention, Offset: 8, Length: 5, UserId: 3}
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+		cr.EXPECT().SendMessage(gomock.Any(), domain.NewMessage{
+			ChatId:   1,
+			SenderId: 2,
+			Text:     "Привет, Алиса и я",
+			Format:   domain.FormatMarkdown,
+			Entities: []domain.MessageEntity{
+				mention,
+				{Type: domain.EntityMention, Offset: 16, Length: 1, UserId: 2},
+			},
+			MentionIds: []int{3},
+		}).Return(10, true, nil)
+
+		// Участник 3 отключил уведомления чата, но упоминание до него доходит
+		cr.EXPECT().GetMessage(gomock.Any(), 1, 10).
+			Return(domain.Message{Id: 10, SenderId: "2", Entities: []domain.MessageEntity{mention}}, nil)
+		cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+		cr.EXPECT().GetMutedMembers(gomock.Any(), 1).Return([]int{3}, nil)
+		nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+			if !reflect.DeepEqual(e.UserIds, []int{3}) || len(e.MutedUserIds) != 0 {
+				t.Errorf("unexpected event %+v", e)
+			}
+			return nil
+		})
+
+		_, err := s.PostMessage(ctx, domain.NewMessage{
+			ChatId:   1,
+			SenderId: 2,
+			Text:     "Привет, [Алиса](mention:3) и [я](mention:2)",
+			Format:   domain.FormatMarkdown,
+		})
+		if err != nil {
+			t.Errorf("ChatSvc.PostMessage() error = %v", err)
+		}
+	})
+
+	t.Run("mention of not member", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+
+		_, err := s.PostMessage(ctx, domain.NewMessage{
+			ChatId:   1,
+			SenderId: 2,
+			Text:     "Привет, Боб",
+			Entities: []domain.MessageEntity{{Type: domain.EntityMention, Offset: 8, Length: 3, UserId: 4}},
+		})
+		if !errors.Is(err, domain.ErrInvalidEntities) {
+			t.Errorf("ChatSvc.PostMessage() error = %v, want %v", err, domain.ErrInvalidEntities)
+		}
+	})
+
+	invalid := []struct {
+		name string
+		msg  domain.NewMessage
+	}{
+		{
+			name: "unknown format",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "<b>hi</b>", Format: "html"},
+		},
+		{
+			name: "markdown with entities",
+			msg: domain.NewMessage{ChatId: 1, SenderId: 2, Text: "**hi**", Format: domain.FormatMarkdown,
+				Entities: []domain.MessageEntity{{Type: domain.EntityBold, Offset: 0, Length: 2}}},
+		},
+		{
+			name: "entity out of text",
+			msg: domain.NewMessage{ChatId: 1, SenderId: 2, Text: "hi",
+				Entities: []domain.MessageEntity{{Type: domain.EntityBold, Offset: 0, Length: 3}}},
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.PostMessage(ctx, tt.msg)
+			if !errors.Is(err, domain.ErrInvalidEntities) {
+				t.Errorf("ChatSvc.PostMessage() error = %v, want %v", err, domain.ErrInvalidEntities)
+			}
+		})
+	}
+}
+
+func TestChatSvc_EditMessage_Entities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	cr.EXPECT().GetMessage(gomock.Any(), 1, 5).Return(domain.Message{Id: 5, SenderId: "2", CreatedAt: time.Now()}, nil)
+	cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+	cr.EXPECT().EditMessage(gomock.Any(), 1, 5, domain.TextEdit{
+		Text: "ок, Алиса",
+		Entities: []domain.MessageEntity{
+			{Type: domain.EntityItalic, Offset: 0, Length: 2},
+			{Type: domain.EntityMention, Offset: 4, Length: 5, UserId: 3},
+		},
+		MentionIds: []int{3},
+	}).Return(domain.Message{Id: 5}, nil)
+	cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 3}, nil)
+	nt.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := s.EditMessage(ctx, 1, 2, 5, domain.TextEdit{Text: "_ок_, [Алиса](mention:3)", Format: domain.FormatMarkdown})
+	if err != nil {
+		t.Errorf("ChatSvc.EditMessage() error = %v", err)
+	}
+}
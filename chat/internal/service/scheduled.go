@@ -0,0 +1,210 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleMessage откладывает сообщение до sendAt. Сообщение проверяется так же, как при отправке,
+// и сразу расходует лимиты отправки: в момент отправки они уже не проверяются
+func (s *ChatSvc) ScheduleMessage(ctx context.Context, msg domain.NewMessage, sendAt time.Time) (domain.ScheduledMessage, error) {
+	// Заголовки ключей конверта привязаны к устройствам на момент шифрования
+	if msg.Envelope != nil {
+		return domain.ScheduledMessage{}, domain.ErrMessageEncrypted
+	}
+	if err := s.checkSendAt(sendAt); err != nil {
+		return domain.ScheduledMessage{}, err
+	}
+	if msg.ClientMessageId == "" {
+		msg.ClientMessageId = uuid.NewString()
+	}
+	if err := s.checkNewMessage(ctx, &msg); err != nil {
+		return domain.ScheduledMessage{}, err
+	}
+	if err := s.checkAttachmentsOwned(ctx, msg); err != nil {
+		return domain.ScheduledMessage{}, errors.Join(err, s.refundRateLimits(ctx, msg))
+	}
+
+	scheduled, err := s.ChatRepo.CreateScheduledMessage(ctx, domain.ScheduledMessage{
+		ChatId:          msg.ChatId,
+		SenderId:        msg.SenderId,
+		Text:            msg.Text,
+		Entities:        msg.Entities,
+		ReplyToId:       msg.ReplyToId,
+		AttachmentIds:   msg.AttachmentIds,
+		ClientMessageId: msg.ClientMessageId,
+		SendAt:          sendAt,
+	}, s.Config.MaxScheduledMessages)
+	if err != nil {
+		return domain.ScheduledMessage{}, errors.Join(err, s.refundRateLimits(ctx, msg))
+	}
+	return scheduled, nil
+}
+
+// ListScheduledMessages возвращает неотправленные сообщения пользователя, chatID 0 - по всем чатам
+func (s *ChatSvc) ListScheduledMessages(ctx context.Context, userID, chatID int) ([]domain.ScheduledMessage, error) {
+	return s.ChatRepo.ListScheduledMessages(ctx, userID, chatID)
+}
+
+// EditScheduledMessage меняет текст или время отправки. Упоминания проверяются при отправке:
+// к тому времени состав чата может измениться
+func (s *ChatSvc) EditScheduledMessage(ctx context.Context, userID, id int, edit domain.ScheduledEdit) (domain.ScheduledMessage, error) {
+	if edit.SendAt != nil {
+		if err := s.checkSendAt(*edit.SendAt); err != nil {
+			return domain.ScheduledMessage{}, err
+		}
+	}
+	if edit.Text != nil {
+		text, entities, err := formatText(edit.Text.Text, edit.Text.Format, edit.Text.Entities)
+		if err != nil {
+			return domain.ScheduledMessage{}, err
+		}
+		if text == "" {
+			return domain.ScheduledMessage{}, errors.New("text is required")
+		}
+		if err := s.checkLength(text); err != nil {
+			return domain.ScheduledMessage{}, err
+		}
+		if err := s.checkContent(ctx, text); err != nil {
+			return domain.ScheduledMessage{}, err
+		}
+		edit.Text = &domain.TextEdit{Text: text, Entities: entities}
+	}
+
+	return s.ChatRepo.UpdateScheduledMessage(ctx, userID, id, edit)
+}
+
+func (s *ChatSvc) CancelScheduledMessage(ctx context.Context, userID, id int) error {
+	return s.ChatRepo.DeleteScheduledMessage(ctx, userID, id)
+}
+
+// DeliverScheduledMessages отправляет до limit отложенных сообщений, время которых подошло, и возвращает
+// число взятых в работу. Каждое сообщение берется из базы с блокировкой, поэтому доставку могут вести
+// несколько реплик. Если реплика упала посреди отправки, сообщение берется заново по таймауту
+// и уходит с тем же client_message_id, так что в чате оно появится один раз
+func (s *ChatSvc) DeliverScheduledMessages(ctx context.Context, limit int) (int, error) {
+	claimed := 0
+	for claimed < limit && ctx.Err() == nil {
+		sm, err := s.ChatRepo.ClaimScheduledMessage(ctx, s.Config.ScheduledSendTimeout)
+		if errors.Is(err, domain.ErrScheduledNotFound) {
+			break
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed++
+
+		s.deliverScheduled(ctx, sm)
+	}
+	return claimed, nil
+}
+
+// deliverScheduled отправляет сообщение и сообщает отправителю результат. Если отправка невозможна,
+// сообщение остается в списке отложенных со статусом failed, временная ошибка повторяется по таймауту
+func (s *ChatSvc) deliverScheduled(ctx context.Context, sm domain.ScheduledMessage) {
+	l := logger.GetFromCtx(ctx)
+
+	messageID, err := s.sendScheduled(ctx, sm)
+	switch {
+	case err == nil:
+		if err := s.ChatRepo.CompleteScheduledMessage(ctx, sm.Id); err != nil {
+			l.ErrorContext(ctx, "failed to complete scheduled message", err)
+		}
+		sm.MessageId = messageID
+		s.notify(ctx, domain.Event{Type: domain.EventScheduledSent, ChatId: sm.ChatId, UserIds: []int{sm.SenderId}, Payload: sm})
+
+	case undeliverable(err):
+		if err := s.ChatRepo.FailScheduledMessage(ctx, sm.Id, err.Error()); err != nil {
+			l.ErrorContext(ctx, "failed to mark scheduled message failed", err)
+			return
+		}
+		sm.Status, sm.Error = domain.ScheduledFailed, err.Error()
+		s.notify(ctx, domain.Event{Type: domain.EventScheduledFailed, ChatId: sm.ChatId, UserIds: []int{sm.SenderId}, Payload: sm})
+
+	default:
+		l.ErrorContext(ctx, "failed to send scheduled message", err)
+	}
+}
+
+// sendScheduled повторяет проверки, результат которых мог измениться с момента откладывания, и отправляет сообщение
+func (s *ChatSvc) sendScheduled(ctx context.Context, sm domain.ScheduledMessage) (int, error) {
+	msg := domain.NewMessage{
+		ChatId:          sm.ChatId,
+		SenderId:        sm.SenderId,
+		Text:            sm.Text,
+		Entities:        sm.Entities,
+		ReplyToId:       sm.ReplyToId,
+		AttachmentIds:   sm.AttachmentIds,
+		ClientMessageId: sm.ClientMessageId,
+	}
+
+	if err := s.checkMember(ctx, msg.ChatId, msg.SenderId); err != nil {
+		return -1, err
+	}
+	if err := s.checkNotBlocked(ctx, msg.ChatId); err != nil {
+		return -1, err
+	}
+	mentionIDs, err := s.resolveMentions(ctx, msg.ChatId, msg.SenderId, msg.Entities)
+	if err != nil {
+		return -1, err
+	}
+	msg.MentionIds = mentionIDs
+	if err := s.checkContent(ctx, msg.Text); err != nil {
+		return -1, err
+	}
+	if err := s.checkReplyTo(ctx, msg.ChatId, msg.ReplyToId); err != nil {
+		return -1, err
+	}
+
+	return s.sendMessage(ctx, msg)
+}
+
+// undeliverable - ошибки, которые не исчезнут при повторной попытке отправки
+func undeliverable(err error) bool {
+	for _, target := range []error{
+		domain.ErrNotChatMember,
+		domain.ErrUserBlocked,
+		domain.ErrMessageNotFound,
+		domain.ErrMessageDeleted,
+		domain.ErrAttachmentNotFound,
+		domain.ErrInvalidEntities,
+		domain.ErrContentRejected,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSendAt разрешает откладывать сообщение в будущее, но не дальше MaxScheduleAhead
+func (s *ChatSvc) checkSendAt(sendAt time.Time) error {
+	now := time.Now()
+	if !sendAt.After(now) {
+		return domain.ErrInvalidSendAt
+	}
+	if limit := s.Config.MaxScheduleAhead; limit > 0 && sendAt.After(now.Add(limit)) {
+		return domain.ErrInvalidSendAt
+	}
+	return nil
+}
+
+// checkAttachmentsOwned проверяет, что вложения загружены отправителем в этот чат и еще не отправлены:
+// иначе отложенное сообщение не удалось бы отправить
+func (s *ChatSvc) checkAttachmentsOwned(ctx context.Context, msg domain.NewMessage) error {
+	for _, id := range msg.AttachmentIds {
+		a, err := s.ChatRepo.GetAttachment(ctx, msg.ChatId, id)
+		if err != nil {
+			return err
+		}
+		if a.UploaderId != msg.SenderId || a.MessageId != nil {
+			return domain.ErrAttachmentNotFound
+		}
+	}
+	return nil
+}
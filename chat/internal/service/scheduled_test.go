@@ -0,0 +1,150 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_ScheduleMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Config: Config{MaxScheduleAhead: 24 * time.Hour, MaxScheduledMessages: 10}}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	t.Run("ok", func(t *testing.T) {
+		sendAt := time.Now().Add(time.Hour)
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+		cr.EXPECT().GetAttachment(gomock.Any(), 1, 7).Return(domain.Attachment{Id: 7, UploaderId: 2}, nil)
+		cr.EXPECT().CreateScheduledMessage(gomock.Any(), gomock.Any(), 10).
+			DoAndReturn(func(_ context.Context, m domain.ScheduledMessage, _ int) (domain.ScheduledMessage, error) {
+				if m.ClientMessageId == "" || m.Text != "утром" || !m.SendAt.Equal(sendAt) || len(m.AttachmentIds) != 1 {
+					t.Errorf("unexpected scheduled message %+v", m)
+				}
+				m.Id = 1
+				return m, nil
+			})
+
+		got, err := s.ScheduleMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, Text: "утром", AttachmentIds: []int{7, 7}}, sendAt)
+		if err != nil || got.Id != 1 {
+			t.Errorf("ChatSvc.ScheduleMessage() = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("attachment of another user", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil)
+		cr.EXPECT().GetAttachment(gomock.Any(), 1, 7).Return(domain.Attachment{Id: 7, UploaderId: 3}, nil)
+
+		_, err := s.ScheduleMessage(ctx, domain.NewMessage{ChatId: 1, SenderId: 2, AttachmentIds: []int{7}}, time.Now().Add(time.Hour))
+		if !errors.Is(err, domain.ErrAttachmentNotFound) {
+			t.Errorf("ChatSvc.ScheduleMessage() error = %v, want %v", err, domain.ErrAttachmentNotFound)
+		}
+	})
+
+	invalid := []struct {
+		name   string
+		msg    domain.NewMessage
+		sendAt time.Time
+		want   error
+	}{
+		{"in the past", domain.NewMessage{ChatId: 1, SenderId: 2, Text: "x"}, time.Now().Add(-time.Minute), domain.ErrInvalidSendAt},
+		{"too far ahead", domain.NewMessage{ChatId: 1, SenderId: 2, Text: "x"}, time.Now().Add(48 * time.Hour), domain.ErrInvalidSendAt},
+		{"encrypted", domain.NewMessage{ChatId: 1, SenderId: 2, Envelope: &domain.Envelope{}}, time.Now().Add(time.Hour), domain.ErrMessageEncrypted},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.ScheduleMessage(ctx, tt.msg, tt.sendAt); !errors.Is(err, tt.want) {
+				t.Errorf("ChatSvc.ScheduleMessage() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatSvc_EditScheduledMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	s := &ChatSvc{ChatRepo: cr}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	cr.EXPECT().UpdateScheduledMessage(gomock.Any(), 2, 1, domain.ScheduledEdit{
+		Text: &domain.TextEdit{Text: "жирный", Entities: []domain.MessageEntity{{Type: domain.EntityBold, Offset: 0, Length: 6}}},
+	}).Return(domain.ScheduledMessage{Id: 1}, nil)
+
+	_, err := s.EditScheduledMessage(ctx, 2, 1, domain.ScheduledEdit{Text: &domain.TextEdit{Text: "**жирный**", Format: domain.FormatMarkdown}})
+	if err != nil {
+		t.Errorf("ChatSvc.EditScheduledMessage() error = %v", err)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	if _, err := s.EditScheduledMessage(ctx, 2, 1, domain.ScheduledEdit{SendAt: &past}); !errors.Is(err, domain.ErrInvalidSendAt) {
+		t.Errorf("ChatSvc.EditScheduledMessage() error = %v, want %v", err, domain.ErrInvalidSendAt)
+	}
+}
+
+func TestChatSvc_DeliverScheduledMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	s := &ChatSvc{ChatRepo: cr, Notifier: nt, Config: Config{ScheduledSendTimeout: time.Minute}}
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	sent := domain.ScheduledMessage{Id: 1, ChatId: 1, SenderId: 2, Text: "привет", ClientMessageId: "c1"}
+	left := domain.ScheduledMessage{Id: 2, ChatId: 3, SenderId: 2, Text: "пока", ClientMessageId: "c2"}
+	broken := domain.ScheduledMessage{Id: 3, ChatId: 1, SenderId: 2, Text: "ой", ClientMessageId: "c3"}
+
+	gomock.InOrder(
+		cr.EXPECT().ClaimScheduledMessage(gomock.Any(), time.Minute).Return(sent, nil),
+		cr.EXPECT().ClaimScheduledMessage(gomock.Any(), time.Minute).Return(left, nil),
+		cr.EXPECT().ClaimScheduledMessage(gomock.Any(), time.Minute).Return(broken, nil),
+		cr.EXPECT().ClaimScheduledMessage(gomock.Any(), time.Minute).Return(domain.ScheduledMessage{}, domain.ErrScheduledNotFound),
+	)
+
+	// Первое сообщение отправляется с тем же client_message_id, отправитель узнает id сообщения
+	cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil).Times(2)
+	cr.EXPECT().IsChatBlocked(gomock.Any(), 1).Return(false, nil).Times(2)
+	cr.EXPECT().SendMessage(gomock.Any(), domain.NewMessage{ChatId: 1, SenderId: 2, Text: "привет", ClientMessageId: "c1"}).Return(10, true, nil)
+	cr.EXPECT().GetMessage(gomock.Any(), 1, 10).Return(domain.Message{Id: 10, SenderId: "2"}, nil)
+	cr.EXPECT().GetChatMembers(gomock.Any(), 1).Return([]int{2, 4}, nil)
+	cr.EXPECT().GetMutedMembers(gomock.Any(), 1).Return(nil, nil)
+	nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+		if e.Type != domain.EventMessageCreated {
+			t.Errorf("unexpected event %+v", e)
+		}
+		return nil
+	})
+	cr.EXPECT().CompleteScheduledMessage(gomock.Any(), 1).Return(nil)
+	nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+		sm, _ := e.Payload.(domain.ScheduledMessage)
+		if e.Type != domain.EventScheduledSent || e.UserIds[0] != 2 || sm.MessageId != 10 {
+			t.Errorf("unexpected event %+v", e)
+		}
+		return nil
+	})
+
+	// Отправитель вышел из чата: сообщение помечается неотправленным
+	cr.EXPECT().IsChatMember(gomock.Any(), 3, 2).Return(false, nil)
+	cr.EXPECT().FailScheduledMessage(gomock.Any(), 2, domain.ErrNotChatMember.Error()).Return(nil)
+	nt.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.Event) error {
+		sm, _ := e.Payload.(domain.ScheduledMessage)
+		if e.Type != domain.EventScheduledFailed || sm.Status != domain.ScheduledFailed {
+			t.Errorf("unexpected event %+v", e)
+		}
+		return nil
+	})
+
+	// Временная ошибка: сообщение остается взятым и будет повторено по таймауту
+	cr.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(-1, false, errors.New("db is down"))
+
+	claimed, err := s.DeliverScheduledMessages(ctx, 10)
+	if err != nil || claimed != 3 {
+		t.Errorf("ChatSvc.DeliverScheduledMessages() = %v, %v, want 3", claimed, err)
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	// maxSearchQueryLength - ограничение длины поискового запроса в символах
+	maxSearchQueryLength = 256
+)
+
+// SearchMessages ищет сообщения по всем чатам пользователя или, если chatID не 0, по одному чату.
+// cursor - значение next_cursor предыдущей страницы
+func (s *ChatSvc) SearchMessages(ctx context.Context, userID, chatID int, text, cursor string, limit int) (domain.SearchPage, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return domain.SearchPage{}, domain.ErrEmptySearchQuery
+	}
+	if utf8.RuneCountInString(text) > maxSearchQueryLength {
+		text = string([]rune(text)[:maxSearchQueryLength])
+	}
+
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	q := domain.SearchQuery{
+		UserId: userID,
+		ChatId: chatID,
+		Text:   text,
+		Limit:  limit + 1,
+	}
+	if cursor != "" {
+		after, err := decodeSearchCursor(cursor)
+		if err != nil {
+			return domain.SearchPage{}, err
+		}
+		q.After = &after
+	}
+
+	if chatID != 0 {
+		if err := s.checkMember(ctx, chatID, userID); err != nil {
+			return domain.SearchPage{}, err
+		}
+	}
+
+	results, err := s.ChatRepo.SearchMessages(ctx, q)
+	if err != nil {
+		return domain.SearchPage{}, err
+	}
+
+	// Лишний результат означает, что есть следующая страница
+	page := domain.SearchPage{Results: results}
+	if len(results) > limit {
+		page.Results = results[:limit]
+		last := page.Results[limit-1]
+		page.NextCursor = encodeSearchCursor(domain.SearchCursor{CreatedAt: last.CreatedAt, MessageId: last.MessageId})
+	}
+
+	return page, nil
+}
+
+func encodeSearchCursor(c domain.SearchCursor) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d", c.CreatedAt.UnixNano(), c.MessageId))
+}
+
+func decodeSearchCursor(cursor string) (domain.SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return domain.SearchCursor{}, domain.ErrInvalidCursor
+	}
+
+	var nanos int64
+	var messageID int
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &messageID); err != nil || messageID <= 0 {
+		return domain.SearchCursor{}, domain.ErrInvalidCursor
+	}
+
+	return domain.SearchCursor{CreatedAt: time.Unix(0, nanos).UTC(), MessageId: messageID}, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_SearchMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+
+	ctx := context.Background()
+
+	created := time.Date(2025, 5, 21, 12, 0, 0, 123000, time.UTC)
+	results := []domain.SearchResult{
+		{ChatId: 1, MessageId: 9, SenderId: "2", Snippet: "<mark>привет</mark>", CreatedAt: created.Add(time.Minute)},
+		{ChatId: 1, MessageId: 8, SenderId: "1", Snippet: "<mark>привет</mark>", CreatedAt: created},
+		{ChatId: 3, MessageId: 4, SenderId: "1", Snippet: "<mark>привет</mark>", CreatedAt: created.Add(-time.Hour)},
+	}
+	cursor := encodeSearchCursor(domain.SearchCursor{CreatedAt: created, MessageId: 8})
+
+	type MockBehavor func()
+
+	type args struct {
+		chatID int
+		text   string
+		cursor string
+		limit  int
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		want        domain.SearchPage
+		wantErr     error
+	}{
+		{
+			name: "first page",
+			args: args{text: "  привет ", limit: 2},
+			MockBehavor: func() {
+				cr.EXPECT().
+					SearchMessages(gomock.Any(), domain.SearchQuery{UserId: 1, Text: "привет", Limit: 3}).
+					Return(results, nil)
+			},
+			want:    domain.SearchPage{Results: results[:2], NextCursor: cursor},
+			wantErr: nil,
+		},
+		{
+			name: "last page",
+			args: args{text: "привет", cursor: cursor, limit: 2},
+			MockBehavor: func() {
+				cr.EXPECT().
+					SearchMessages(gomock.Any(), domain.SearchQuery{
+						UserId: 1,
+						Text:   "привет",
+						Limit:  3,
+						After:  &domain.SearchCursor{CreatedAt: created, MessageId: 8},
+					}).
+					Return(results[2:], nil)
+			},
+			want:    domain.SearchPage{Results: results[2:]},
+			wantErr: nil,
+		},
+		{
+			name: "in chat",
+			args: args{chatID: 3, text: "привет"},
+			MockBehavor: func() {
+				cr.EXPECT().IsChatMember(gomock.Any(), 3, 1).Return(true, nil)
+				cr.EXPECT().
+					SearchMessages(gomock.Any(), domain.SearchQuery{UserId: 1, ChatId: 3, Text: "привет", Limit: defaultSearchLimit + 1}).
+					Return(results[2:], nil)
+			},
+			want:    domain.SearchPage{Results: results[2:]},
+			wantErr: nil,
+		},
+		{
+			name: "in chat with not member",
+			args: args{chatID: 5, text: "привет"},
+			MockBehavor: func() {
+				cr.EXPECT().IsChatMember(gomock.Any(), 5, 1).Return(false, nil)
+			},
+			wantErr: domain.ErrNotChatMember,
+		},
+		{
+			name:        "empty query",
+			args:        args{text: "   "},
+			MockBehavor: func() {},
+			wantErr:     domain.ErrEmptySearchQuery,
+		},
+		{
+			name:        "invalid cursor",
+			args:        args{text: "привет", cursor: "not-a-cursor"},
+			MockBehavor: func() {},
+			wantErr:     domain.ErrInvalidCursor,
+		},
+		{
+			name: "long query and limit are truncated",
+			args: args{text: strings.Repeat("я", 300), limit: 1000},
+			MockBehavor: func() {
+				cr.EXPECT().
+					SearchMessages(gomock.Any(), domain.SearchQuery{UserId: 1, Text: strings.Repeat("я", maxSearchQueryLength), Limit: maxSearchLimit + 1}).
+					Return(nil, nil)
+			},
+			want:    domain.SearchPage{},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				ChatRepo: cr,
+			}
+			tt.MockBehavor()
+			got, err := s.SearchMessages(ctx, 1, tt.args.chatID, tt.args.text, tt.args.cursor, tt.args.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.SearchMessages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatSvc.SearchMessages() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
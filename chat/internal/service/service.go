@@ -2,36 +2,359 @@ package service
 
 import (
 	"chat/internal/domain"
+	"chat/internal/richtext"
+	"chat/pkg/logger"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 //go:generate mockgen -destination=./mock/mock.go -package=mock -source=service.go
 
 type ChatRepo interface{
 	CreateChat(ctx context.Context, userID1, userID2 int) (int, error)
-	SendMessage(ctx context.Context, userID, chatID int, text string) (int, error)
-	GetMessages(ctx context.Context, chatID int, limit, offset int) ([]domain.Message, error)
+	SendMessage(ctx context.Context, msg domain.NewMessage) (int, bool, error)
+	GetMessageIdByClientId(ctx context.Context, chatID, senderID int, clientID string) (int, error)
+	GetMessages(ctx context.Context, chatID, userID int, limit, offset int) ([]domain.Message, error)
 	GetUserChats(ctx context.Context, userID int) ([]int, error)
+	GetChatMembers(ctx context.Context, chatID int) ([]int, error)
+	MarkRead(ctx context.Context, chatID, userID, messageID int) (domain.ReadReceipt, bool, error)
+	IsChatMember(ctx context.Context, chatID, userID int) (bool, error)
+	GetMessage(ctx context.Context, chatID, messageID int) (domain.Message, error)
+	EditMessage(ctx context.Context, chatID, messageID int, edit domain.TextEdit) (domain.Message, error)
+	DeleteMessage(ctx context.Context, chatID, messageID int) (domain.Message, error)
+	HideMessage(ctx context.Context, chatID, messageID, userID int) error
+	GetMessageEdits(ctx context.Context, messageID int) ([]domain.MessageEdit, error)
+	AddReaction(ctx context.Context, reaction domain.Reaction) error
+	RemoveReaction(ctx context.Context, reaction domain.Reaction) error
+	GetReactions(ctx context.Context, messageIDs []int, userID int) (map[int][]domain.ReactionCount, error)
+	CreateAttachment(ctx context.Context, a domain.Attachment) (int, error)
+	GetAttachment(ctx context.Context, chatID, attachmentID int) (domain.Attachment, error)
+	DeleteMessageAttachments(ctx context.Context, messageID int) ([]domain.Attachment, error)
+	SearchMessages(ctx context.Context, q domain.SearchQuery) ([]domain.SearchResult, error)
+	GetChanges(ctx context.Context, userID int, since int64, limit int) (domain.SyncBatch, error)
+	GetSyncCursor(ctx context.Context, userID int) (int64, error)
+	BlockUser(ctx context.Context, userID, blockedUserID int) error
+	UnblockUser(ctx context.Context, userID, blockedUserID int) error
+	GetBlockedUsers(ctx context.Context, userID int) ([]int, error)
+	IsBlocked(ctx context.Context, userID1, userID2 int) (bool, error)
+	IsChatBlocked(ctx context.Context, chatID int) (bool, error)
+	GetChatSettings(ctx context.Context, chatID, userID int) (domain.ChatSettings, error)
+	UpdateChatSettings(ctx context.Context, chatID, userID int, upd domain.ChatSettingsUpdate) (domain.ChatSettings, error)
+	GetMutedMembers(ctx context.Context, chatID int) ([]int, error)
+	SavePushSubscription(ctx context.Context, sub domain.PushSubscription) error
+	DeletePushSubscription(ctx context.Context, userID int, endpoint string) error
+	GetChatRetention(ctx context.Context, chatID int) (domain.ChatRetention, error)
+	SetChatRetention(ctx context.Context, chatID, userID, ttl int) (domain.ChatRetention, error)
+	DeleteExpiredMessages(ctx context.Context, maxAge time.Duration, limit int) ([]domain.ExpiredMessages, []domain.Attachment, error)
+	CreateExport(ctx context.Context, userID int, account domain.Account) (domain.Export, error)
+	GetExport(ctx context.Context, userID int, exportID string) (domain.Export, error)
+	ListExports(ctx context.Context, userID int) ([]domain.Export, error)
+	ClaimExport(ctx context.Context, timeout time.Duration) (domain.Export, error)
+	FinishExport(ctx context.Context, exportID, storageKey string, size int64, expiresAt time.Time) error
+	FailExport(ctx context.Context, exportID, reason string) error
+	DeleteExpiredExports(ctx context.Context, olderThan time.Duration) ([]string, error)
+	ListChats(ctx context.Context, q domain.ChatQuery) ([]domain.ChatInfo, error)
+	DeleteChat(ctx context.Context, chatID int) ([]int, []domain.Attachment, error)
+	ReportMessage(ctx context.Context, report domain.Report, hideThreshold int) ([]domain.MessageVisibility, error)
+	GetReportedMessages(ctx context.Context, status string, limit, offset int) ([]domain.ReportedMessage, error)
+	GetReportedMessage(ctx context.Context, messageID int) (domain.ReportedMessage, error)
+	ResolveReport(ctx context.Context, messageID, adminID int, status string) (bool, error)
+	AppendAudit(ctx context.Context, e domain.AuditEntry) error
+	GetAuditLog(ctx context.Context, limit, offset int) ([]domain.AuditEntry, error)
+	GetUnreadCounts(ctx context.Context, userID int) ([]domain.UnreadCount, error)
+	CreateScheduledMessage(ctx context.Context, m domain.ScheduledMessage, limit int) (domain.ScheduledMessage, error)
+	ListScheduledMessages(ctx context.Context, userID, chatID int) ([]domain.ScheduledMessage, error)
+	UpdateScheduledMessage(ctx context.Context, userID, id int, edit domain.ScheduledEdit) (domain.ScheduledMessage, error)
+	DeleteScheduledMessage(ctx context.Context, userID, id int) error
+	ClaimScheduledMessage(ctx context.Context, timeout time.Duration) (domain.ScheduledMessage, error)
+	CompleteScheduledMessage(ctx context.Context, id int) error
+	FailScheduledMessage(ctx context.Context, id int, reason string) error
+	PinMessage(ctx context.Context, pin domain.PinnedMessage, limit int) (domain.PinnedMessage, bool, error)
+	UnpinMessage(ctx context.Context, chatID, messageID int) (domain.PinnedMessage, bool, error)
+	GetPinnedMessages(ctx context.Context, chatID, userID int) ([]domain.PinnedMessage, error)
+	SaveDraft(ctx context.Context, userID int, d domain.Draft) (domain.Draft, bool, error)
+	GetDrafts(ctx context.Context, userID int) ([]domain.Draft, error)
+	SetWebhook(ctx context.Context, w domain.Webhook) (domain.Webhook, error)
+	GetWebhook(ctx context.Context, botID int) (domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, botID int) error
+	CreateIncomingWebhook(ctx context.Context, w domain.IncomingWebhook, tokenHash string, limit int) (domain.IncomingWebhook, error)
+	GetIncomingWebhooks(ctx context.Context, chatID int) ([]domain.IncomingWebhook, error)
+	GetIncomingWebhookByToken(ctx context.Context, tokenHash string) (domain.IncomingWebhook, error)
+	DeleteIncomingWebhook(ctx context.Context, chatID, webhookID int) error
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, event domain.Event) error
+}
+
+// BlobStore хранит содержимое вложений по ключу
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// RateLimiter хранит корзины токенов для лимитов отправки. Take списывает токен из корзины key
+// и возвращает 0 или время, через которое в корзине появится токен. Refund возвращает списанный токен
+type RateLimiter interface {
+	Take(ctx context.Context, key string, limit domain.RateLimit) (time.Duration, error)
+	Refund(ctx context.Context, key string, limit domain.RateLimit) error
+}
+
+// EventSource - подписка на события, которые эта реплика рассылает пользователю.
+// Функция отмены закрывает канал событий
+type EventSource interface {
+	Subscribe(userID int) (<-chan domain.Event, func())
+}
+
+// AccountProvider отдает данные учетной записи пользователя по его токену
+type AccountProvider interface {
+	GetAccount(ctx context.Context, token string) (domain.Account, error)
+}
+
+// UserDirectory - учетные записи в auth-сервисе. Вызовы идут с токеном администратора,
+// права проверяет и сам auth-сервис
+type UserDirectory interface {
+	ListUsers(ctx context.Context, admin domain.Identity, q domain.UserQuery) ([]domain.User, error)
+	SuspendUser(ctx context.Context, admin domain.Identity, s domain.Suspension) (domain.User, error)
+	UnsuspendUser(ctx context.Context, admin domain.Identity, userID int) (domain.User, error)
+}
+
+// ContentFilter проверяет текст сообщения перед сохранением. Чтобы отклонить сообщение,
+// возвращает ошибку, оборачивающую domain.ErrContentRejected
+type ContentFilter interface {
+	Check(ctx context.Context, text string) error
 }
 
 type ChatSvc struct {
-	ChatRepo ChatRepo
+	Config    Config
+	ChatRepo  ChatRepo
+	Notifier  Notifier
+	BlobStore BlobStore
+	Limiter   RateLimiter
+	Events    EventSource
+	Accounts  AccountProvider
+	Users     UserDirectory
+	Filter    ContentFilter
 }
 
-func New(chatRepo ChatRepo) *ChatSvc {
-	return &ChatSvc{ChatRepo: chatRepo}
+func New(cfg Config, chatRepo ChatRepo, notifier Notifier, blobStore BlobStore, limiter RateLimiter, events EventSource, accounts AccountProvider, users UserDirectory, filter ContentFilter) *ChatSvc {
+	return &ChatSvc{Config: cfg, ChatRepo: chatRepo, Notifier: notifier, BlobStore: blobStore, Limiter: limiter, Events: events, Accounts: accounts, Users: users, Filter: filter}
 }
 
 func (s *ChatSvc) StartChat(ctx context.Context, userID1, userID2 int) (int, error) {
 	if userID1 == userID2 {
 		return -1, errors.New("cannot create chat with yourself")
 	}
+
+	blocked, err := s.ChatRepo.IsBlocked(ctx, userID1, userID2)
+	if err != nil {
+		return -1, err
+	}
+	if blocked {
+		return -1, domain.ErrUserBlocked
+	}
+
 	return s.ChatRepo.CreateChat(ctx, userID1, userID2)
 }
 
-func (s *ChatSvc) PostMessage(ctx context.Context, chatID, userID int, text string) (int, error) {
-	return s.ChatRepo.SendMessage(ctx, userID, chatID, text)
+func (s *ChatSvc) PostMessage(ctx context.Context, msg domain.NewMessage) (int, error) {
+	// Повтор отправки возвращает сохраненное сообщение до проверок: лимиты за него уже списаны
+	sentID, err := s.sentMessageID(ctx, msg)
+	if err != nil {
+		return -1, err
+	}
+	if sentID != 0 {
+		return sentID, nil
+	}
+	if err := s.checkNewMessage(ctx, &msg); err != nil {
+		return -1, err
+	}
+
+	messageID, err := s.sendMessage(ctx, msg)
+	if err != nil {
+		return -1, errors.Join(err, s.refundRateLimits(ctx, msg))
+	}
+	return messageID, nil
+}
+
+// sentMessageID возвращает id сообщения, уже отправленного с msg.ClientMessageId, или 0.
+// Поиск идет среди сообщений самого отправителя, поэтому членство в чате не проверяется
+func (s *ChatSvc) sentMessageID(ctx context.Context, msg domain.NewMessage) (int, error) {
+	if msg.ClientMessageId == "" {
+		return 0, nil
+	}
+	clientID, err := uuid.Parse(msg.ClientMessageId)
+	if err != nil {
+		return 0, domain.ErrInvalidClientId
+	}
+
+	id, err := s.ChatRepo.GetMessageIdByClientId(ctx, msg.ChatId, msg.SenderId, clientID.String())
+	if errors.Is(err, domain.ErrMessageNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// checkNewMessage проверяет сообщение перед отправкой или откладыванием и приводит его к сохраняемому виду:
+// разбирает разметку, находит упомянутых и упорядочивает вложения
+func (s *ChatSvc) checkNewMessage(ctx context.Context, msg *domain.NewMessage) error {
+	if msg.ClientMessageId != "" {
+		clientID, err := uuid.Parse(msg.ClientMessageId)
+		if err != nil {
+			return domain.ErrInvalidClientId
+		}
+		msg.ClientMessageId = clientID.String()
+	}
+	text, entities, err := formatText(msg.Text, msg.Format, msg.Entities)
+	if err != nil {
+		return err
+	}
+	msg.Text, msg.Entities = text, entities
+	if err := s.checkLength(msg.Text); err != nil {
+		return err
+	}
+
+	if err := s.checkMember(ctx, msg.ChatId, msg.SenderId); err != nil {
+		return err
+	}
+	msg.MentionIds, err = s.resolveMentions(ctx, msg.ChatId, msg.SenderId, msg.Entities)
+	if err != nil {
+		return err
+	}
+	if err := s.checkNotBlocked(ctx, msg.ChatId); err != nil {
+		return err
+	}
+	if msg.Envelope != nil {
+		if err := s.checkEnvelope(ctx, *msg); err != nil {
+			return err
+		}
+	}
+	if err := s.checkContent(ctx, msg.Text); err != nil {
+		return err
+	}
+	if err := s.checkReplyTo(ctx, msg.ChatId, msg.ReplyToId); err != nil {
+		return err
+	}
+
+	if len(msg.AttachmentIds) > 0 {
+		msg.AttachmentIds = slices.Compact(slices.Sorted(slices.Values(msg.AttachmentIds)))
+		if limit := s.Config.MaxAttachmentsPerMessage; limit > 0 && len(msg.AttachmentIds) > limit {
+			return domain.ErrTooManyAttachments
+		}
+	}
+
+	// Лимиты списываются последними: отклоненное проверками сообщение их не расходует
+	return s.checkRateLimits(ctx, *msg)
+}
+
+// checkReplyTo разрешает отвечать только на неудаленное сообщение из того же чата
+func (s *ChatSvc) checkReplyTo(ctx context.Context, chatID, replyToID int) error {
+	if replyToID == 0 {
+		return nil
+	}
+	replyTo, err := s.ChatRepo.GetMessage(ctx, chatID, replyToID)
+	if err != nil {
+		return err
+	}
+	if replyTo.DeletedAt != nil {
+		return domain.ErrMessageDeleted
+	}
+	return nil
+}
+
+// ForwardMessage пересылает сообщение из fromChatID в toChatID от имени userID.
+// Пользователь должен быть участником обоих чатов, авторство первоисточника сохраняется
+func (s *ChatSvc) ForwardMessage(ctx context.Context, fromChatID, messageID, toChatID, userID int) (int, error) {
+	if err := s.checkMember(ctx, fromChatID, userID); err != nil {
+		return -1, err
+	}
+	if err := s.checkMember(ctx, toChatID, userID); err != nil {
+		return -1, err
+	}
+	if err := s.checkNotBlocked(ctx, toChatID); err != nil {
+		return -1, err
+	}
+
+	src, err := s.ChatRepo.GetMessage(ctx, fromChatID, messageID)
+	if err != nil {
+		return -1, err
+	}
+	if src.DeletedAt != nil {
+		return -1, domain.ErrMessageDeleted
+	}
+	if src.HiddenAt != nil {
+		return -1, domain.ErrMessageHidden
+	}
+	// Ключ зашифрованного сообщения есть только у устройств исходного чата
+	if err := checkNotEncrypted(src); err != nil {
+		return -1, err
+	}
+
+	msg := domain.NewMessage{
+		ChatId:                 toChatID,
+		SenderId:               userID,
+		Text:                   src.Text,
+		ForwardedFromMessageId: src.Id,
+		// Упоминания относятся к участникам исходного чата, в пересланном сообщении они становятся текстом
+		Entities: richtext.WithoutMentions(src.Entities),
+	}
+	originalSender := src.SenderId
+	if src.ForwardedFrom != nil {
+		originalSender = src.ForwardedFrom.SenderId
+		msg.ForwardedFromMessageId = 0
+		if src.ForwardedFrom.MessageId != nil {
+			msg.ForwardedFromMessageId = *src.ForwardedFrom.MessageId
+		}
+	}
+	msg.ForwardedFromSenderId, err = strconv.Atoi(originalSender)
+	if err != nil {
+		return -1, fmt.Errorf("invalid original sender id %q: %w", originalSender, err)
+	}
+
+	if err := s.checkContent(ctx, msg.Text); err != nil {
+		return -1, err
+	}
+	if err := s.checkRateLimits(ctx, msg); err != nil {
+		return -1, err
+	}
+
+	forwardedID, err := s.sendMessage(ctx, msg)
+	if err != nil {
+		return -1, errors.Join(err, s.refundRateLimits(ctx, msg))
+	}
+	return forwardedID, nil
+}
+
+// sendMessage сохраняет сообщение и рассылает его остальным участникам чата
+func (s *ChatSvc) sendMessage(ctx context.Context, msg domain.NewMessage) (int, error) {
+	messageID, created, err := s.ChatRepo.SendMessage(ctx, msg)
+	if err != nil {
+		return -1, err
+	}
+	// Повтор с тем же client_message_id уже разослан при первой отправке
+	if !created {
+		return messageID, nil
+	}
+
+	// Сообщение вебхука создатель не писал сам, поэтому получает его наравне с собеседником
+	exclude := msg.SenderId
+	if msg.IncomingWebhookId != 0 {
+		exclude = 0
+	}
+	s.notifyNewMessage(ctx, msg.ChatId, messageID, exclude)
+
+	return messageID, nil
 }
 
 func (s *ChatSvc) GetUserChats(ctx context.Context, userID int) ([]int, error) {
@@ -41,12 +364,238 @@ func (s *ChatSvc) GetUserChats(ctx context.Context, userID int) ([]int, error) {
 	return s.ChatRepo.GetUserChats(ctx, userID)
 }
 
-func (s *ChatSvc) GetMessages(ctx context.Context, chatID int, limit, offset int) ([]domain.Message, error) {
+func (s *ChatSvc) GetMessages(ctx context.Context, chatID, userID int, limit, offset int) ([]domain.Message, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
 	if limit <= 0 {
 		limit = 10
 	}
 	if offset < 0 {
 		offset = 0
 	}
-	return s.ChatRepo.GetMessages(ctx, chatID, limit, offset)
+
+	messages, err := s.ChatRepo.GetMessages(ctx, chatID, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	viewEnvelopes(messages, userID)
+	viewHidden(messages, userID)
+
+	return messages, nil
+}
+
+// MarkRead двигает указатель прочитанного до messageID (0 - до последнего сообщения чата)
+// и, если указатель сдвинулся, рассылает уведомление остальным участникам
+func (s *ChatSvc) MarkRead(ctx context.Context, chatID, userID, messageID int) (domain.ReadReceipt, error) {
+	if messageID < 0 {
+		return domain.ReadReceipt{}, errors.New("invalid message ID")
+	}
+
+	receipt, moved, err := s.ChatRepo.MarkRead(ctx, chatID, userID, messageID)
+	if err != nil {
+		return domain.ReadReceipt{}, err
+	}
+
+	// Повторная отметка или отметка старого сообщения ничего не меняет, будить подписчиков незачем
+	if moved {
+		s.notifyMembers(ctx, domain.EventRead, chatID, receipt, userID)
+	}
+
+	return receipt, nil
+}
+
+func (s *ChatSvc) EditMessage(ctx context.Context, chatID, userID, messageID int, edit domain.TextEdit) (domain.Message, error) {
+	text, entities, err := formatText(edit.Text, edit.Format, edit.Entities)
+	if err != nil {
+		return domain.Message{}, err
+	}
+	if text == "" {
+		return domain.Message{}, errors.New("text is required")
+	}
+	if err := s.checkLength(text); err != nil {
+		return domain.Message{}, err
+	}
+
+	own, err := s.ownMessage(ctx, chatID, userID, messageID, s.Config.EditWindow)
+	if err != nil {
+		return domain.Message{}, err
+	}
+	if err := checkNotEncrypted(own); err != nil {
+		return domain.Message{}, err
+	}
+	if err := s.checkContent(ctx, text); err != nil {
+		return domain.Message{}, err
+	}
+	mentionIDs, err := s.resolveMentions(ctx, chatID, userID, entities)
+	if err != nil {
+		return domain.Message{}, err
+	}
+
+	msg, err := s.ChatRepo.EditMessage(ctx, chatID, messageID, domain.TextEdit{Text: text, Entities: entities, MentionIds: mentionIDs})
+	if err != nil {
+		return domain.Message{}, err
+	}
+
+	s.notifyMembers(ctx, domain.EventMessageEdited, chatID, msg)
+
+	return msg, nil
+}
+
+// DeleteMessage удаляет сообщение у всех участников (только отправитель, в пределах окна)
+// или только у самого пользователя
+func (s *ChatSvc) DeleteMessage(ctx context.Context, chatID, userID, messageID int, forEveryone bool) error {
+	if !forEveryone {
+		if err := s.checkMember(ctx, chatID, userID); err != nil {
+			return err
+		}
+		if err := s.ChatRepo.HideMessage(ctx, chatID, messageID, userID); err != nil {
+			return err
+		}
+
+		s.notify(ctx, domain.Event{
+			Type:    domain.EventMessageDeleted,
+			ChatId:  chatID,
+			UserIds: []int{userID},
+			Payload: domain.Message{Id: messageID},
+		})
+		return nil
+	}
+
+	if _, err := s.ownMessage(ctx, chatID, userID, messageID, s.Config.DeleteWindow); err != nil {
+		return err
+	}
+
+	msg, err := s.ChatRepo.DeleteMessage(ctx, chatID, messageID)
+	if err != nil {
+		return err
+	}
+
+	s.removeAttachments(ctx, messageID)
+
+	s.notifyMembers(ctx, domain.EventMessageDeleted, chatID, msg)
+
+	return nil
+}
+
+func (s *ChatSvc) GetMessageHistory(ctx context.Context, chatID, userID, messageID int) ([]domain.MessageEdit, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.ChatRepo.GetMessage(ctx, chatID, messageID); err != nil {
+		return nil, err
+	}
+	return s.ChatRepo.GetMessageEdits(ctx, messageID)
+}
+
+// ownMessage возвращает сообщение, если userID - его отправитель, оно не удалено и окно window не истекло
+func (s *ChatSvc) ownMessage(ctx context.Context, chatID, userID, messageID int, window time.Duration) (domain.Message, error) {
+	msg, err := s.ChatRepo.GetMessage(ctx, chatID, messageID)
+	if err != nil {
+		return domain.Message{}, err
+	}
+	if msg.DeletedAt != nil {
+		return domain.Message{}, domain.ErrMessageDeleted
+	}
+	if msg.SenderId != strconv.Itoa(userID) {
+		return domain.Message{}, domain.ErrNotMessageSender
+	}
+	if window > 0 && time.Since(msg.CreatedAt) > window {
+		return domain.Message{}, domain.ErrEditWindowExpired
+	}
+	return msg, nil
+}
+
+// checkNotBlocked запрещает писать в чат, один из участников которого заблокировал другого
+func (s *ChatSvc) checkNotBlocked(ctx context.Context, chatID int) error {
+	blocked, err := s.ChatRepo.IsChatBlocked(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return domain.ErrUserBlocked
+	}
+	return nil
+}
+
+func (s *ChatSvc) checkMember(ctx context.Context, chatID, userID int) error {
+	isMember, err := s.ChatRepo.IsChatMember(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return domain.ErrNotChatMember
+	}
+	return nil
+}
+
+// notifyMembers отправляет событие участникам чата, кроме exclude.
+// Ошибки доставки не прерывают операцию, а только логируются
+func (s *ChatSvc) notifyMembers(ctx context.Context, eventType string, chatID int, payload any, exclude ...int) {
+	event, ok := s.membersEvent(ctx, eventType, chatID, payload, exclude...)
+	if !ok {
+		return
+	}
+
+	s.notify(ctx, event)
+}
+
+// notifyNewMessage рассылает сохраненное сообщение участникам чата, кроме отправителя.
+// Участники, отключившие уведомления чата, перечисляются в MutedUserIds, если сообщение их не упоминает
+func (s *ChatSvc) notifyNewMessage(ctx context.Context, chatID, messageID, senderID int) {
+	msg, err := s.ChatRepo.GetMessage(ctx, chatID, messageID)
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to get message for event", err)
+		return
+	}
+
+	event, ok := s.membersEvent(ctx, domain.EventMessageCreated, chatID, msg, senderID)
+	if !ok {
+		return
+	}
+
+	muted, err := s.ChatRepo.GetMutedMembers(ctx, chatID)
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to get muted chat members", err)
+	}
+	mentioned := richtext.Mentions(msg.Entities)
+	for _, id := range muted {
+		if slices.Contains(event.UserIds, id) && !slices.Contains(mentioned, id) {
+			event.MutedUserIds = append(event.MutedUserIds, id)
+		}
+	}
+
+	s.notify(ctx, event)
+}
+
+// membersEvent собирает событие для участников чата, кроме exclude. ok = false, если получателей нет
+func (s *ChatSvc) membersEvent(ctx context.Context, eventType string, chatID int, payload any, exclude ...int) (domain.Event, bool) {
+	members, err := s.ChatRepo.GetChatMembers(ctx, chatID)
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to get chat members for event", err)
+		return domain.Event{}, false
+	}
+
+	recipients := make([]int, 0, len(members))
+	for _, id := range members {
+		if !slices.Contains(exclude, id) {
+			recipients = append(recipients, id)
+		}
+	}
+	if len(recipients) == 0 {
+		return domain.Event{}, false
+	}
+
+	return domain.Event{
+		Type:    eventType,
+		ChatId:  chatID,
+		UserIds: recipients,
+		Payload: payload,
+	}, true
+}
+
+func (s *ChatSvc) notify(ctx context.Context, event domain.Event) {
+	if err := s.Notifier.Notify(ctx, event); err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to notify chat members", err)
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"chat/internal/service/mock"
 	"chat/pkg/logger"
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -40,6 +41,9 @@ func TestChatSvc_StartChat(t *testing.T) {
 				userID2: 2,
 			},
 			MockBehavor: func(user1, user2 int) {
+				cr.EXPECT().
+					IsBlocked(ctx, user1, user2).
+					Return(false, nil)
 				cr.EXPECT().
 					CreateChat(ctx, user1, user2).
 					Return(1, nil)
@@ -64,6 +68,9 @@ func TestChatSvc_StartChat(t *testing.T) {
 				userID2: 2,
 			},
 			MockBehavor: func(user1, user2 int) {
+				cr.EXPECT().
+					IsBlocked(ctx, user1, user2).
+					Return(false, nil)
 				cr.EXPECT().
 					CreateChat(ctx, user1, user2).
 					Return(-1, fmt.Errorf("test err"))
@@ -71,6 +78,20 @@ func TestChatSvc_StartChat(t *testing.T) {
 			want:    -1,
 			wantErr: true,
 		},
+		{
+			name: "with blocked",
+			args: args{
+				userID1: 1,
+				userID2: 2,
+			},
+			MockBehavor: func(user1, user2 int) {
+				cr.EXPECT().
+					IsBlocked(ctx, user1, user2).
+					Return(true, nil)
+			},
+			want:    -1,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -92,51 +113,185 @@ func TestChatSvc_StartChat(t *testing.T) {
 }
 
 func TestChatSvc_PostMessage(t *testing.T) {
-	cr := mock.NewMockChatRepo(gomock.NewController(t))
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
 
 	l := logger.New()
 	ctx := logger.InitFromCtx(context.Background(), l)
 
-	type MockBehavor func(chatID, userID int, text string)
+	deletedAt := time.Now()
+
+	type MockBehavor func(msg domain.NewMessage)
 
-	type args struct {
-		chatID int
-		userID int
-		text   string
-	}
 	tests := []struct {
 		name        string
-		args        args
+		msg         domain.NewMessage
 		MockBehavor MockBehavor
 		want        int
 		wantErr     bool
 	}{
 		{
 			name: "ok",
-			args: args{
-				chatID: 1,
-				userID: 2,
-				text:   "test",
-			},
-			MockBehavor: func(chatID, userID int, text string) {
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test"},
+			MockBehavor: func(msg domain.NewMessage) {
 				cr.EXPECT().
-					SendMessage(ctx, userID, chatID, text).
-					Return(1, nil)
+					IsChatMember(ctx, msg.ChatId, msg.SenderId).
+					Return(true, nil)
+				cr.EXPECT().
+					IsChatBlocked(ctx, msg.ChatId).
+					Return(false, nil)
+				cr.EXPECT().
+					SendMessage(ctx, msg).
+					Return(1, true, nil)
+				expectNewMessage(cr, nt, msg.ChatId, 1)
 			},
 			want:    1,
 			wantErr: false,
 		},
 		{
 			name: "with err repo",
-			args: args{
-				chatID: 1,
-				userID: 2,
-				text:   "test",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test"},
+			MockBehavor: func(msg domain.NewMessage) {
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), msg.ChatId, msg.SenderId).
+					Return(true, nil)
+				cr.EXPECT().
+					IsChatBlocked(gomock.Any(), msg.ChatId).
+					Return(false, nil)
+				cr.EXPECT().
+					SendMessage(gomock.Any(), msg).
+					Return(-1, false, fmt.Errorf("test err"))
 			},
-			MockBehavor: func(chatID, userID int, text string) {
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name: "with client message id",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test", ClientMessageId: "6F9619FF-8B86-D011-B42D-00C04FC964FF"},
+			MockBehavor: func(msg domain.NewMessage) {
 				cr.EXPECT().
-					SendMessage(gomock.Any(), userID, chatID, text).
-					Return(-1, fmt.Errorf("test err"))
+					GetMessageIdByClientId(gomock.Any(), msg.ChatId, msg.SenderId, "6f9619ff-8b86-d011-b42d-00c04fc964ff").
+					Return(0, domain.ErrMessageNotFound)
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), msg.ChatId, msg.SenderId).
+					Return(true, nil)
+				cr.EXPECT().
+					IsChatBlocked(gomock.Any(), msg.ChatId).
+					Return(false, nil)
+				msg.ClientMessageId = "6f9619ff-8b86-d011-b42d-00c04fc964ff"
+				cr.EXPECT().
+					SendMessage(gomock.Any(), msg).
+					Return(7, true, nil)
+				expectNewMessage(cr, nt, msg.ChatId, 7)
+			},
+			want:    7,
+			wantErr: false,
+		},
+		{
+			name: "client message id replay is not notified again",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test", ClientMessageId: "6f9619ff-8b86-d011-b42d-00c04fc964ff"},
+			MockBehavor: func(msg domain.NewMessage) {
+				cr.EXPECT().
+					GetMessageIdByClientId(gomock.Any(), msg.ChatId, msg.SenderId, msg.ClientMessageId).
+					Return(0, domain.ErrMessageNotFound)
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), msg.ChatId, msg.SenderId).
+					Return(true, nil)
+				cr.EXPECT().
+					IsChatBlocked(gomock.Any(), msg.ChatId).
+					Return(false, nil)
+				// Параллельный повтор успел сохранить сообщение между поиском и вставкой
+				cr.EXPECT().
+					SendMessage(gomock.Any(), msg).
+					Return(7, false, nil)
+			},
+			want:    7,
+			wantErr: false,
+		},
+		{
+			name:        "with invalid client message id",
+			msg:         domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test", ClientMessageId: "retry-1"},
+			MockBehavor: func(msg domain.NewMessage) {},
+			want:        -1,
+			wantErr:     true,
+		},
+		{
+			name: "with not member",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 3, Text: "test"},
+			MockBehavor: func(msg domain.NewMessage) {
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), msg.ChatId, msg.SenderId).
+					Return(false, nil)
+			},
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name: "with blocked",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test"},
+			MockBehavor: func(msg domain.NewMessage) {
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), msg.ChatId, msg.SenderId).
+					Return(true, nil)
+				cr.EXPECT().
+					IsChatBlocked(gomock.Any(), msg.ChatId).
+					Return(true, nil)
+			},
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name: "with reply",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test", ReplyToId: 5},
+			MockBehavor: func(msg domain.NewMessage) {
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), msg.ChatId, msg.SenderId).
+					Return(true, nil)
+				cr.EXPECT().
+					IsChatBlocked(gomock.Any(), msg.ChatId).
+					Return(false, nil)
+				cr.EXPECT().
+					GetMessage(gomock.Any(), msg.ChatId, msg.ReplyToId).
+					Return(domain.Message{Id: msg.ReplyToId}, nil)
+				cr.EXPECT().
+					SendMessage(gomock.Any(), msg).
+					Return(6, true, nil)
+				expectNewMessage(cr, nt, msg.ChatId, 6)
+			},
+			want:    6,
+			wantErr: false,
+		},
+		{
+			name: "with reply to other chat",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test", ReplyToId: 5},
+			MockBehavor: func(msg domain.NewMessage) {
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), msg.ChatId, msg.SenderId).
+					Return(true, nil)
+				cr.EXPECT().
+					IsChatBlocked(gomock.Any(), msg.ChatId).
+					Return(false, nil)
+				cr.EXPECT().
+					GetMessage(gomock.Any(), msg.ChatId, msg.ReplyToId).
+					Return(domain.Message{}, domain.ErrMessageNotFound)
+			},
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name: "with reply to deleted",
+			msg:  domain.NewMessage{ChatId: 1, SenderId: 2, Text: "test", ReplyToId: 5},
+			MockBehavor: func(msg domain.NewMessage) {
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), msg.ChatId, msg.SenderId).
+					Return(true, nil)
+				cr.EXPECT().
+					IsChatBlocked(gomock.Any(), msg.ChatId).
+					Return(false, nil)
+				cr.EXPECT().
+					GetMessage(gomock.Any(), msg.ChatId, msg.ReplyToId).
+					Return(domain.Message{Id: msg.ReplyToId, DeletedAt: &deletedAt}, nil)
 			},
 			want:    -1,
 			wantErr: true,
@@ -146,9 +301,10 @@ func TestChatSvc_PostMessage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &ChatSvc{
 				ChatRepo: cr,
+				Notifier: nt,
 			}
-			tt.MockBehavor(tt.args.chatID, tt.args.userID, tt.args.text)
-			got, err := s.PostMessage(ctx, tt.args.chatID, tt.args.userID, tt.args.text)
+			tt.MockBehavor(tt.msg)
+			got, err := s.PostMessage(ctx, tt.msg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ChatSvc.PostMessage() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -160,6 +316,139 @@ func TestChatSvc_PostMessage(t *testing.T) {
 	}
 }
 
+func TestChatSvc_ForwardMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+
+	l := logger.New()
+	ctx := logger.InitFromCtx(context.Background(), l)
+
+	originalId := 3
+
+	type MockBehavor func(fromChatID, messageID, toChatID, userID int)
+
+	type args struct {
+		fromChatID int
+		messageID  int
+		toChatID   int
+		userID     int
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		want        int
+		wantErr     error
+	}{
+		{
+			name: "ok",
+			args: args{fromChatID: 1, messageID: 5, toChatID: 2, userID: 1},
+			MockBehavor: func(fromChatID, messageID, toChatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), fromChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatMember(gomock.Any(), toChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatBlocked(gomock.Any(), toChatID).Return(false, nil)
+				cr.EXPECT().
+					GetMessage(gomock.Any(), fromChatID, messageID).
+					Return(domain.Message{Id: messageID, SenderId: "7", Text: "hello"}, nil)
+				cr.EXPECT().
+					SendMessage(gomock.Any(), domain.NewMessage{
+						ChatId:                 toChatID,
+						SenderId:               userID,
+						Text:                   "hello",
+						ForwardedFromSenderId:  7,
+						ForwardedFromMessageId: messageID,
+					}).
+					Return(10, true, nil)
+				expectNewMessage(cr, nt, toChatID, 10)
+			},
+			want:    10,
+			wantErr: nil,
+		},
+		{
+			name: "forward of forwarded keeps original",
+			args: args{fromChatID: 1, messageID: 5, toChatID: 2, userID: 1},
+			MockBehavor: func(fromChatID, messageID, toChatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), fromChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatMember(gomock.Any(), toChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatBlocked(gomock.Any(), toChatID).Return(false, nil)
+				cr.EXPECT().
+					GetMessage(gomock.Any(), fromChatID, messageID).
+					Return(domain.Message{
+						Id:            messageID,
+						SenderId:      "1",
+						Text:          "hello",
+						ForwardedFrom: &domain.ForwardInfo{SenderId: "9", MessageId: &originalId},
+					}, nil)
+				cr.EXPECT().
+					SendMessage(gomock.Any(), domain.NewMessage{
+						ChatId:                 toChatID,
+						SenderId:               userID,
+						Text:                   "hello",
+						ForwardedFromSenderId:  9,
+						ForwardedFromMessageId: originalId,
+					}).
+					Return(11, true, nil)
+				expectNewMessage(cr, nt, toChatID, 11)
+			},
+			want:    11,
+			wantErr: nil,
+		},
+		{
+			name: "with not member of target",
+			args: args{fromChatID: 1, messageID: 5, toChatID: 2, userID: 1},
+			MockBehavor: func(fromChatID, messageID, toChatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), fromChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatMember(gomock.Any(), toChatID, userID).Return(false, nil)
+			},
+			want:    -1,
+			wantErr: domain.ErrNotChatMember,
+		},
+		{
+			name: "with blocked target",
+			args: args{fromChatID: 1, messageID: 5, toChatID: 2, userID: 1},
+			MockBehavor: func(fromChatID, messageID, toChatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), fromChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatMember(gomock.Any(), toChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatBlocked(gomock.Any(), toChatID).Return(true, nil)
+			},
+			want:    -1,
+			wantErr: domain.ErrUserBlocked,
+		},
+		{
+			name: "with message from other chat",
+			args: args{fromChatID: 1, messageID: 5, toChatID: 2, userID: 1},
+			MockBehavor: func(fromChatID, messageID, toChatID, userID int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), fromChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatMember(gomock.Any(), toChatID, userID).Return(true, nil)
+				cr.EXPECT().IsChatBlocked(gomock.Any(), toChatID).Return(false, nil)
+				cr.EXPECT().
+					GetMessage(gomock.Any(), fromChatID, messageID).
+					Return(domain.Message{}, domain.ErrMessageNotFound)
+			},
+			want:    -1,
+			wantErr: domain.ErrMessageNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				ChatRepo: cr,
+				Notifier: nt,
+			}
+			tt.MockBehavor(tt.args.fromChatID, tt.args.messageID, tt.args.toChatID, tt.args.userID)
+			got, err := s.ForwardMessage(ctx, tt.args.fromChatID, tt.args.messageID, tt.args.toChatID, tt.args.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.ForwardMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ChatSvc.ForwardMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestChatSvc_GetUserChats(t *testing.T) {
 	cr := mock.NewMockChatRepo(gomock.NewController(t))
 
@@ -238,10 +527,11 @@ func TestChatSvc_GetMessages(t *testing.T) {
 	l := logger.New()
 	ctx := logger.InitFromCtx(context.Background(), l)
 
-	type MockBehavor func(chatID, limit, offset int)
+	type MockBehavor func(chatID, userID, limit, offset int)
 
 	type args struct {
 		chatID int
+		userID int
 		limit  int
 		offset int
 	}
@@ -256,27 +546,31 @@ func TestChatSvc_GetMessages(t *testing.T) {
 			name: "ok",
 			args: args{
 				chatID: 1,
+				userID: 2,
 				limit:  10,
 				offset: 0,
 			},
-			MockBehavor: func(chatID, limit, offset int) {
+			MockBehavor: func(chatID, userID, limit, offset int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
 				cr.EXPECT().
-					GetMessages(gomock.Any(), chatID, limit, offset).
-					Return([]domain.Message{{"test", "test", time.Unix(1,1), true}}, nil)
+					GetMessages(gomock.Any(), chatID, userID, limit, offset).
+					Return([]domain.Message{{Id: 1, SenderId: "test", Text: "test", CreatedAt: time.Unix(1, 1), IsRead: true}}, nil)
 			},
-			want:    []domain.Message{{"test", "test", time.Unix(1,1), true}},
+			want:    []domain.Message{{Id: 1, SenderId: "test", Text: "test", CreatedAt: time.Unix(1, 1), IsRead: true}},
 			wantErr: false,
 		},
 		{
 			name: "with err repo",
 			args: args{
 				chatID: 1,
+				userID: 2,
 				limit:  10,
 				offset: 0,
 			},
-			MockBehavor: func(chatID, limit, offset int) {
+			MockBehavor: func(chatID, userID, limit, offset int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
 				cr.EXPECT().
-					GetMessages(gomock.Any(), chatID, limit, offset).
+					GetMessages(gomock.Any(), chatID, userID, limit, offset).
 					Return(nil, fmt.Errorf("test err"))
 			},
 			want:    nil,
@@ -286,40 +580,58 @@ func TestChatSvc_GetMessages(t *testing.T) {
 			name: "with offset<0",
 			args: args{
 				chatID: 1,
+				userID: 2,
 				limit:  10,
 				offset: -1,
 			},
-			MockBehavor: func(chatID, limit, offset int) {
+			MockBehavor: func(chatID, userID, limit, offset int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
 				cr.EXPECT().
-					GetMessages(gomock.Any(), chatID, limit, 0).
-					Return([]domain.Message{{"test","test", time.Unix(1,1), true}}, nil)
+					GetMessages(gomock.Any(), chatID, userID, limit, 0).
+					Return([]domain.Message{{Id: 1, SenderId: "test", Text: "test", CreatedAt: time.Unix(1, 1), IsRead: true}}, nil)
 			},
-			want:    []domain.Message{{"test","test", time.Unix(1,1), true}},
+			want:    []domain.Message{{Id: 1, SenderId: "test", Text: "test", CreatedAt: time.Unix(1, 1), IsRead: true}},
 			wantErr: false,
 		},
 		{
 			name: "with limit<0",
 			args: args{
 				chatID: 1,
+				userID: 2,
 				limit:  -1,
 				offset: 0,
 			},
-			MockBehavor: func(chatID, limit, offset int) {	
+			MockBehavor: func(chatID, userID, limit, offset int) {	
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(true, nil)
 				cr.EXPECT().
-					GetMessages(gomock.Any(), chatID, 10, offset).
-					Return([]domain.Message{{"test","test", time.Unix(1,1), true}}, nil)
+					GetMessages(gomock.Any(), chatID, userID, 10, offset).
+					Return([]domain.Message{{Id: 1, SenderId: "test", Text: "test", CreatedAt: time.Unix(1, 1), IsRead: true}}, nil)
 			},
-			want:    []domain.Message{{"test","test", time.Unix(1,1), true}},
+			want:    []domain.Message{{Id: 1, SenderId: "test", Text: "test", CreatedAt: time.Unix(1, 1), IsRead: true}},
 			wantErr: false,
 		},
+		{
+			name: "not a member",
+			args: args{
+				chatID: 1,
+				userID: 3,
+				limit:  10,
+				offset: 0,
+			},
+			MockBehavor: func(chatID, userID, limit, offset int) {
+				cr.EXPECT().IsChatMember(gomock.Any(), chatID, userID).Return(false, nil)
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &ChatSvc{
 				ChatRepo: cr,
 			}
-			tt.MockBehavor(tt.args.chatID, tt.args.limit, tt.args.offset)
-			got, err := s.GetMessages(ctx, tt.args.chatID, tt.args.limit, tt.args.offset)
+			tt.MockBehavor(tt.args.chatID, tt.args.userID, tt.args.limit, tt.args.offset)
+			got, err := s.GetMessages(ctx, tt.args.chatID, tt.args.userID, tt.args.limit, tt.args.offset)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ChatSvc.GetMessages() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -330,3 +642,353 @@ func TestChatSvc_GetMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestChatSvc_MarkRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+
+	l := logger.New()
+	ctx := logger.InitFromCtx(context.Background(), l)
+
+	type MockBehavor func(chatID, userID, messageID int)
+
+	type args struct {
+		chatID    int
+		userID    int
+		messageID int
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		want        domain.ReadReceipt
+		wantErr     bool
+	}{
+		{
+			name: "ok",
+			args: args{
+				chatID:    1,
+				userID:    2,
+				messageID: 5,
+			},
+			MockBehavor: func(chatID, userID, messageID int) {
+				receipt := domain.ReadReceipt{ChatId: chatID, UserId: userID, LastReadMessageId: messageID}
+				cr.EXPECT().
+					MarkRead(gomock.Any(), chatID, userID, messageID).
+					Return(receipt, true, nil)
+				cr.EXPECT().
+					GetChatMembers(gomock.Any(), chatID).
+					Return([]int{1, 2}, nil)
+				nt.EXPECT().
+					Notify(gomock.Any(), domain.Event{
+						Type:    domain.EventRead,
+						ChatId:  chatID,
+						UserIds: []int{1},
+						Payload: receipt,
+					}).
+					Return(nil)
+			},
+			want:    domain.ReadReceipt{ChatId: 1, UserId: 2, LastReadMessageId: 5},
+			wantErr: false,
+		},
+		{
+			name: "with err notifier",
+			args: args{
+				chatID:    1,
+				userID:    2,
+				messageID: 0,
+			},
+			MockBehavor: func(chatID, userID, messageID int) {
+				cr.EXPECT().
+					MarkRead(gomock.Any(), chatID, userID, messageID).
+					Return(domain.ReadReceipt{ChatId: chatID, UserId: userID, LastReadMessageId: 7}, true, nil)
+				cr.EXPECT().
+					GetChatMembers(gomock.Any(), chatID).
+					Return([]int{1, 2}, nil)
+				nt.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("test err"))
+			},
+			want:    domain.ReadReceipt{ChatId: 1, UserId: 2, LastReadMessageId: 7},
+			wantErr: false,
+		},
+		{
+			name: "pointer did not move",
+			args: args{
+				chatID:    1,
+				userID:    2,
+				messageID: 3,
+			},
+			MockBehavor: func(chatID, userID, messageID int) {
+				cr.EXPECT().
+					MarkRead(gomock.Any(), chatID, userID, messageID).
+					Return(domain.ReadReceipt{ChatId: chatID, UserId: userID, LastReadMessageId: 5}, false, nil)
+			},
+			want:    domain.ReadReceipt{ChatId: 1, UserId: 2, LastReadMessageId: 5},
+			wantErr: false,
+		},
+		{
+			name: "with not member",
+			args: args{
+				chatID:    1,
+				userID:    3,
+				messageID: 5,
+			},
+			MockBehavor: func(chatID, userID, messageID int) {
+				cr.EXPECT().
+					MarkRead(gomock.Any(), chatID, userID, messageID).
+					Return(domain.ReadReceipt{}, false, domain.ErrNotChatMember)
+			},
+			want:    domain.ReadReceipt{},
+			wantErr: true,
+		},
+		{
+			name: "with messageID<0",
+			args: args{
+				chatID:    1,
+				userID:    2,
+				messageID: -1,
+			},
+			MockBehavor: func(chatID, userID, messageID int) {},
+			want:        domain.ReadReceipt{},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				ChatRepo: cr,
+				Notifier: nt,
+			}
+			tt.MockBehavor(tt.args.chatID, tt.args.userID, tt.args.messageID)
+			got, err := s.MarkRead(ctx, tt.args.chatID, tt.args.userID, tt.args.messageID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChatSvc.MarkRead() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatSvc.MarkRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatSvc_EditMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+
+	l := logger.New()
+	ctx := logger.InitFromCtx(context.Background(), l)
+
+	deletedAt := time.Now()
+
+	type MockBehavor func(chatID, messageID int, text string)
+
+	type args struct {
+		chatID    int
+		userID    int
+		messageID int
+		text      string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		wantErr     error
+	}{
+		{
+			name: "ok",
+			args: args{chatID: 1, userID: 2, messageID: 3, text: "edited"},
+			MockBehavor: func(chatID, messageID int, text string) {
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID, SenderId: "2", CreatedAt: time.Now()}, nil)
+				cr.EXPECT().
+					EditMessage(gomock.Any(), chatID, messageID, domain.TextEdit{Text: text}).
+					Return(domain.Message{Id: messageID, SenderId: "2", Text: text}, nil)
+				cr.EXPECT().
+					GetChatMembers(gomock.Any(), chatID).
+					Return([]int{1, 2}, nil)
+				nt.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, e domain.Event) error {
+						if e.Type != domain.EventMessageEdited || !reflect.DeepEqual(e.UserIds, []int{1, 2}) {
+							t.Errorf("unexpected event %+v", e)
+						}
+						return nil
+					})
+			},
+			wantErr: nil,
+		},
+		{
+			name: "with not sender",
+			args: args{chatID: 1, userID: 1, messageID: 3, text: "edited"},
+			MockBehavor: func(chatID, messageID int, text string) {
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID, SenderId: "2", CreatedAt: time.Now()}, nil)
+			},
+			wantErr: domain.ErrNotMessageSender,
+		},
+		{
+			name: "with expired window",
+			args: args{chatID: 1, userID: 2, messageID: 3, text: "edited"},
+			MockBehavor: func(chatID, messageID int, text string) {
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID, SenderId: "2", CreatedAt: time.Now().Add(-time.Hour)}, nil)
+			},
+			wantErr: domain.ErrEditWindowExpired,
+		},
+		{
+			name: "with deleted message",
+			args: args{chatID: 1, userID: 2, messageID: 3, text: "edited"},
+			MockBehavor: func(chatID, messageID int, text string) {
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID, SenderId: "2", CreatedAt: time.Now(), DeletedAt: &deletedAt}, nil)
+			},
+			wantErr: domain.ErrMessageDeleted,
+		},
+		{
+			name: "with not found",
+			args: args{chatID: 1, userID: 2, messageID: 3, text: "edited"},
+			MockBehavor: func(chatID, messageID int, text string) {
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{}, domain.ErrMessageNotFound)
+			},
+			wantErr: domain.ErrMessageNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				Config:   Config{EditWindow: time.Minute},
+				ChatRepo: cr,
+				Notifier: nt,
+			}
+			tt.MockBehavor(tt.args.chatID, tt.args.messageID, tt.args.text)
+			_, err := s.EditMessage(ctx, tt.args.chatID, tt.args.userID, tt.args.messageID, domain.TextEdit{Text: tt.args.text})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.EditMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChatSvc_DeleteMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockChatRepo(ctrl)
+	nt := mock.NewMockNotifier(ctrl)
+	bs := mock.NewMockBlobStore(ctrl)
+
+	l := logger.New()
+	ctx := logger.InitFromCtx(context.Background(), l)
+
+	type MockBehavor func(chatID, userID, messageID int)
+
+	type args struct {
+		chatID      int
+		userID      int
+		messageID   int
+		forEveryone bool
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		wantErr     error
+	}{
+		{
+			name: "for me",
+			args: args{chatID: 1, userID: 1, messageID: 3, forEveryone: false},
+			MockBehavor: func(chatID, userID, messageID int) {
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), chatID, userID).
+					Return(true, nil)
+				cr.EXPECT().
+					HideMessage(gomock.Any(), chatID, messageID, userID).
+					Return(nil)
+				nt.EXPECT().
+					Notify(gomock.Any(), domain.Event{
+						Type:    domain.EventMessageDeleted,
+						ChatId:  chatID,
+						UserIds: []int{userID},
+						Payload: domain.Message{Id: messageID},
+					}).
+					Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "for me with not member",
+			args: args{chatID: 1, userID: 5, messageID: 3, forEveryone: false},
+			MockBehavor: func(chatID, userID, messageID int) {
+				cr.EXPECT().
+					IsChatMember(gomock.Any(), chatID, userID).
+					Return(false, nil)
+			},
+			wantErr: domain.ErrNotChatMember,
+		},
+		{
+			name: "for everyone",
+			args: args{chatID: 1, userID: 2, messageID: 3, forEveryone: true},
+			MockBehavor: func(chatID, userID, messageID int) {
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID, SenderId: "2", CreatedAt: time.Now()}, nil)
+				cr.EXPECT().
+					DeleteMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID, SenderId: "2"}, nil)
+				cr.EXPECT().
+					DeleteMessageAttachments(gomock.Any(), messageID).
+					Return([]domain.Attachment{{Id: 7, StorageKey: "chats/1/a", ThumbnailKey: "chats/1/a_thumb"}}, nil)
+				bs.EXPECT().Delete(gomock.Any(), "chats/1/a").Return(nil)
+				bs.EXPECT().Delete(gomock.Any(), "chats/1/a_thumb").Return(nil)
+				cr.EXPECT().
+					GetChatMembers(gomock.Any(), chatID).
+					Return([]int{1, 2}, nil)
+				nt.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "for everyone with not sender",
+			args: args{chatID: 1, userID: 1, messageID: 3, forEveryone: true},
+			MockBehavor: func(chatID, userID, messageID int) {
+				cr.EXPECT().
+					GetMessage(gomock.Any(), chatID, messageID).
+					Return(domain.Message{Id: messageID, SenderId: "2", CreatedAt: time.Now()}, nil)
+			},
+			wantErr: domain.ErrNotMessageSender,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				Config:    Config{DeleteWindow: time.Minute},
+				ChatRepo:  cr,
+				Notifier:  nt,
+				BlobStore: bs,
+			}
+			tt.MockBehavor(tt.args.chatID, tt.args.userID, tt.args.messageID)
+			err := s.DeleteMessage(ctx, tt.args.chatID, tt.args.userID, tt.args.messageID, tt.args.forEveryone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.DeleteMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// expectNewMessage ожидает рассылку отправленного сообщения messageID собеседнику
+func expectNewMessage(cr *mock.MockChatRepo, nt *mock.MockNotifier, chatID, messageID int) {
+	cr.EXPECT().GetMessage(gomock.Any(), chatID, messageID).Return(domain.Message{Id: messageID, SenderId: "1"}, nil)
+	cr.EXPECT().GetChatMembers(gomock.Any(), chatID).Return([]int{1, 2}, nil)
+	cr.EXPECT().GetMutedMembers(gomock.Any(), chatID).Return(nil, nil)
+	nt.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(nil)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+)
+
+func (s *ChatSvc) CheckMembership(ctx context.Context, chatID, userID int) (bool, error) {
+	return s.ChatRepo.IsChatMember(ctx, chatID, userID)
+}
+
+// SubscribeChat подписывает участника чата на события этого чата. Канал закрывается после отмены ctx
+// или если подписчик не успевает читать события - тогда пропущенное нужно догнать через SyncChanges
+func (s *ChatSvc) SubscribeChat(ctx context.Context, chatID, userID int) (<-chan domain.Event, error) {
+	if err := s.checkMember(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	events, cancel := s.Events.Subscribe(userID)
+	out := make(chan domain.Event)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if event.ChatId != chatID {
+					continue
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// SubscribeUser подписывает пользователя на события всех его чатов. Канал закрывается после отмены ctx
+// или если подписчик не успевает читать события
+func (s *ChatSvc) SubscribeUser(ctx context.Context, userID int) <-chan domain.Event {
+	events, cancel := s.Events.Subscribe(userID)
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+
+	return events
+}
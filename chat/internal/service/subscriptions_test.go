@@ -0,0 +1,70 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/events"
+	"chat/internal/service/mock"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatSvc_SubscribeChat(t *testing.T) {
+	cr := mock.NewMockChatRepo(gomock.NewController(t))
+	hub := events.NewHub()
+
+	s := &ChatSvc{
+		ChatRepo: cr,
+		Events:   hub,
+	}
+
+	t.Run("only events of the chat", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 2).Return(true, nil)
+		sub, err := s.SubscribeChat(ctx, 1, 2)
+		require.NoError(t, err)
+
+		other := domain.Event{Type: domain.EventRead, ChatId: 3, UserIds: []int{2}}
+		event := domain.Event{Type: domain.EventMessageCreated, ChatId: 1, UserIds: []int{2}}
+		require.NoError(t, hub.Notify(ctx, other))
+		require.NoError(t, hub.Notify(ctx, event))
+
+		assert.Equal(t, event, <-sub)
+
+		cancel()
+		for range sub {
+		}
+	})
+
+	t.Run("not member", func(t *testing.T) {
+		cr.EXPECT().IsChatMember(gomock.Any(), 1, 3).Return(false, nil)
+
+		_, err := s.SubscribeChat(context.Background(), 1, 3)
+		if !errors.Is(err, domain.ErrNotChatMember) {
+			t.Errorf("ChatSvc.SubscribeChat() error = %v, wantErr %v", err, domain.ErrNotChatMember)
+		}
+	})
+}
+
+func TestChatSvc_SubscribeUser(t *testing.T) {
+	hub := events.NewHub()
+	s := &ChatSvc{Events: hub}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := s.SubscribeUser(ctx, 2)
+
+	event := domain.Event{Type: domain.EventMessageCreated, ChatId: 1, UserIds: []int{2}}
+	require.NoError(t, hub.Notify(ctx, domain.Event{Type: domain.EventRead, ChatId: 1, UserIds: []int{3}}))
+	require.NoError(t, hub.Notify(ctx, event))
+
+	assert.Equal(t, event, <-sub)
+
+	cancel()
+	for range sub {
+	}
+}
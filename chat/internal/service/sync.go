@@ -0,0 +1,30 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"context"
+)
+
+const (
+	defaultSyncLimit = 100
+	maxSyncLimit     = 500
+)
+
+// SyncChanges возвращает изменения в чатах пользователя после курсора since,
+// полученного из предыдущего ответа (0 - с начала журнала)
+func (s *ChatSvc) SyncChanges(ctx context.Context, userID int, since int64, limit int) (domain.SyncBatch, error) {
+	if since < 0 {
+		return domain.SyncBatch{}, domain.ErrInvalidCursor
+	}
+	if limit <= 0 {
+		limit = defaultSyncLimit
+	}
+	limit = min(limit, maxSyncLimit)
+
+	return s.ChatRepo.GetChanges(ctx, userID, since, limit)
+}
+
+// GetSyncCursor возвращает курсор, с которого клиент получит только будущие изменения
+func (s *ChatSvc) GetSyncCursor(ctx context.Context, userID int) (int64, error) {
+	return s.ChatRepo.GetSyncCursor(ctx, userID)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"chat/internal/domain"
+	"chat/internal/service/mock"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestChatSvc_SyncChanges(t *testing.T) {
+	cr := mock.NewMockChatRepo(gomock.NewController(t))
+
+	ctx := context.Background()
+
+	batch := domain.SyncBatch{
+		Changes: []domain.Change{{Seq: 6, ChatId: 1, Type: domain.EventMessageCreated}},
+		Cursor:  6,
+	}
+
+	type MockBehavor func()
+
+	type args struct {
+		since int64
+		limit int
+	}
+	tests := []struct {
+		name        string
+		args        args
+		MockBehavor MockBehavor
+		want        domain.SyncBatch
+		wantErr     error
+	}{
+		{
+			name: "default limit",
+			args: args{since: 5},
+			MockBehavor: func() {
+				cr.EXPECT().GetChanges(gomock.Any(), 1, int64(5), defaultSyncLimit).Return(batch, nil)
+			},
+			want:    batch,
+			wantErr: nil,
+		},
+		{
+			name: "limit is truncated",
+			args: args{since: 5, limit: 10000},
+			MockBehavor: func() {
+				cr.EXPECT().GetChanges(gomock.Any(), 1, int64(5), maxSyncLimit).Return(batch, nil)
+			},
+			want:    batch,
+			wantErr: nil,
+		},
+		{
+			name:        "negative since",
+			args:        args{since: -1},
+			MockBehavor: func() {},
+			want:        domain.SyncBatch{},
+			wantErr:     domain.ErrInvalidCursor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ChatSvc{
+				ChatRepo: cr,
+			}
+			tt.MockBehavor()
+			got, err := s.SyncChanges(ctx, 1, tt.args.since, tt.args.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChatSvc.SyncChanges() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatSvc.SyncChanges() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// thumbnailSize - максимальная сторона миниатюры в пикселях
+	thumbnailSize = 320
+	// maxThumbnailSourcePixels защищает от изображений, которые занимают гигабайты после декодирования
+	maxThumbnailSourcePixels = 50_000_000
+
+	thumbnailContentType = "image/jpeg"
+)
+
+// makeThumbnail уменьшает изображение до thumbnailSize по большей стороне и кодирует в JPEG.
+// Прозрачные области заливаются белым
+func makeThumbnail(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image config: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxThumbnailSourcePixels {
+		return nil, fmt.Errorf("unsupported image size %dx%d", cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	width, height := thumbnailBounds(cfg.Width, cfg.Height)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func thumbnailBounds(width, height int) (int, int) {
+	if width <= thumbnailSize && height <= thumbnailSize {
+		return width, height
+	}
+	if width >= height {
+		return thumbnailSize, max(1, height*thumbnailSize/width)
+	}
+	return max(1, width*thumbnailSize/height), thumbnailSize
+}
@@ -0,0 +1,5 @@
+package filesystem
+
+type Config struct {
+	Root string `env:"BLOB_LOCAL_ROOT" envDefault:"./data/attachments"`
+}
@@ -0,0 +1,49 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/storage/filesystem"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := filesystem.New(filesystem.Config{Root: t.TempDir()})
+	require.NoError(t, err)
+
+	t.Run("put and get", func(t *testing.T) {
+		data := []byte("hello")
+		require.NoError(t, store.Put(ctx, "chats/1/file", bytes.NewReader(data), int64(len(data)), "text/plain"))
+
+		r, err := store.Get(ctx, "chats/1/file")
+		require.NoError(t, err)
+		defer r.Close()
+
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "chats/1/deleted", bytes.NewReader([]byte("x")), 1, "text/plain"))
+		require.NoError(t, store.Delete(ctx, "chats/1/deleted"))
+		require.NoError(t, store.Delete(ctx, "chats/1/deleted"))
+
+		_, err := store.Get(ctx, "chats/1/deleted")
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+	})
+
+	t.Run("key outside root", func(t *testing.T) {
+		err := store.Put(ctx, "../escape", bytes.NewReader([]byte("x")), 1, "text/plain")
+		assert.Error(t, err)
+
+		_, err = store.Get(ctx, "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"chat/internal/domain"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто удаляются полные корзины, которые ничем не отличаются от отсутствующих
+const sweepInterval = time.Minute
+
+// RateLimiter хранит корзины токенов в памяти процесса. Лимиты считаются отдельно в каждой реплике
+// chat-сервиса, для общего счета нужна реализация service.RateLimiter с общим хранилищем
+type RateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens   float64
+	burst    int
+	interval time.Duration
+	updated  time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Take списывает токен из корзины key. Если токенов нет, возвращает время до появления следующего
+func (l *RateLimiter) Take(_ context.Context, key string, limit domain.RateLimit) (time.Duration, error) {
+	if limit.Burst <= 0 || limit.Interval <= 0 {
+		return 0, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.burst, b.interval = limit.Burst, limit.Interval
+	b.refill(now)
+
+	if b.tokens < 1 {
+		wait := time.Duration(math.Ceil((1 - b.tokens) * float64(limit.Interval)))
+		return wait, nil
+	}
+	b.tokens--
+
+	return 0, nil
+}
+
+// Refund возвращает в корзину key токен, списанный Take, но не больше burst
+func (l *RateLimiter) Refund(_ context.Context, key string, limit domain.RateLimit) error {
+	if limit.Burst <= 0 || limit.Interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		return nil
+	}
+	b.refill(l.now())
+	b.tokens = math.Min(float64(b.burst), b.tokens+1)
+
+	return nil
+}
+
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.burst) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// refill начисляет токены за время с прошлого обращения, но не больше burst
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(b.burst), b.tokens+float64(elapsed)/float64(b.interval))
+	b.updated = now
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"chat/internal/domain"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Take(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 21, 12, 0, 0, 0, time.UTC)
+
+	l := NewRateLimiter()
+	l.now = func() time.Time { return now }
+	l.lastSweep = now
+
+	limit := domain.RateLimit{Burst: 3, Interval: time.Second}
+
+	t.Run("burst then wait", func(t *testing.T) {
+		for i := 0; i < limit.Burst; i++ {
+			wait, err := l.Take(ctx, "user:1", limit)
+			require.NoError(t, err)
+			assert.Zero(t, wait)
+		}
+
+		wait, err := l.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, wait)
+
+		now = now.Add(400 * time.Millisecond)
+		wait, err = l.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.Equal(t, 600*time.Millisecond, wait)
+
+		now = now.Add(600 * time.Millisecond)
+		wait, err = l.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		wait, err := l.Take(ctx, "user:2", limit)
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			wait, err := l.Take(ctx, "chat:1", domain.RateLimit{})
+			require.NoError(t, err)
+			assert.Zero(t, wait)
+		}
+	})
+
+	t.Run("full buckets are swept", func(t *testing.T) {
+		now = now.Add(sweepInterval)
+		_, err := l.Take(ctx, "user:3", limit)
+		require.NoError(t, err)
+
+		assert.Len(t, l.buckets, 1)
+		assert.Contains(t, l.buckets, "user:3")
+	})
+}
+
+func TestRateLimiter_Refund(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 21, 12, 0, 0, 0, time.UTC)
+
+	l := NewRateLimiter()
+	l.now = func() time.Time { return now }
+	l.lastSweep = now
+
+	limit := domain.RateLimit{Burst: 2, Interval: time.Second}
+
+	for i := 0; i < limit.Burst; i++ {
+		_, err := l.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+	}
+	require.NoError(t, l.Refund(ctx, "user:1", limit))
+
+	wait, err := l.Take(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.Zero(t, wait, "refunded token can be taken again")
+
+	require.NoError(t, l.Refund(ctx, "user:2", limit))
+	require.NoError(t, l.Refund(ctx, "user:2", limit))
+	assert.NotContains(t, l.buckets, "user:2", "refund does not create a bucket")
+
+	now = now.Add(time.Hour)
+	require.NoError(t, l.Refund(ctx, "user:1", limit))
+	assert.Equal(t, float64(limit.Burst), l.buckets["user:1"].tokens, "refund does not exceed burst")
+}
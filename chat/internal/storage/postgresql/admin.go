@@ -0,0 +1,144 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ListChats возвращает чаты от недавно обновленных к старым, при q.UserId - только чаты этого пользователя
+func (s *ChatStorage) ListChats(ctx context.Context, q domain.ChatQuery) ([]domain.ChatInfo, error) {
+	query := `
+		SELECT c.id, c.user_1_id, c.user_2_id, c.created_at, c.updated_at,
+		       (SELECT COUNT(*) FROM messages m WHERE m.chat_id = c.id AND m.deleted_at IS NULL)
+		FROM chats c
+		WHERE $1 = 0 OR c.user_1_id = $1 OR c.user_2_id = $1
+		ORDER BY c.updated_at DESC, c.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := s.db.Query(ctx, query, q.UserId, q.Limit, q.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chats: %w", err)
+	}
+	defer rows.Close()
+
+	chats := []domain.ChatInfo{}
+	for rows.Next() {
+		var c domain.ChatInfo
+		var user1, user2 int
+		if err := rows.Scan(&c.Id, &user1, &user2, &c.CreatedAt, &c.UpdatedAt, &c.Messages); err != nil {
+			return nil, fmt.Errorf("failed to scan chat: %w", err)
+		}
+		c.UserIds = []int{user1, user2}
+		chats = append(chats, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return chats, nil
+}
+
+// DeleteChat удаляет чат со всей перепиской. Возвращает участников, которых нужно уведомить,
+// и вложения, файлы которых нужно удалить из хранилища
+func (s *ChatStorage) DeleteChat(ctx context.Context, chatID int) ([]int, []domain.Attachment, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var user1, user2 int
+	err = tx.QueryRow(ctx, `SELECT user_1_id, user_2_id FROM chats WHERE id = $1 FOR UPDATE`, chatID).Scan(&user1, &user2)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, domain.ErrChatNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get chat: %w", err)
+	}
+	members := []int{user1, user2}
+
+	rows, err := tx.Query(ctx, `DELETE FROM attachments WHERE chat_id = $1 RETURNING `+attachmentColumns, chatID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to delete attachments: %w", err)
+	}
+	attachments, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Attachment, error) {
+		return scanAttachment(row)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan attachment: %w", err)
+	}
+
+	// Журнал изменений не ссылается на чат внешним ключом, поэтому чистится явно: после удаления
+	// клиентам остается только событие chat_deleted
+	if _, err := tx.Exec(ctx, `DELETE FROM user_changes WHERE chat_id = $1`, chatID); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete chat changes: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM chats WHERE id = $1`, chatID); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete chat: %w", err)
+	}
+
+	payload := domain.ChatMembers{ChatId: chatID, UserIds: members}
+	if err := recordChange(ctx, tx, chatID, domain.EventChatDeleted, payload, members...); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return members, attachments, nil
+}
+
+// AppendAudit добавляет запись в журнал модерации
+func (s *ChatStorage) AppendAudit(ctx context.Context, e domain.AuditEntry) error {
+	query := `
+		INSERT INTO moderation_log (admin_id, action, user_id, chat_id, message_id, reason, details)
+		VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, 0), NULLIF($5, 0), $6, $7)
+	`
+
+	_, err := s.db.Exec(ctx, query, e.AdminId, e.Action, e.UserId, e.ChatId, e.MessageId, e.Reason, e.Details)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditLog возвращает журнал модерации от новых записей к старым
+func (s *ChatStorage) GetAuditLog(ctx context.Context, limit, offset int) ([]domain.AuditEntry, error) {
+	query := `
+		SELECT id, admin_id, action, COALESCE(user_id, 0), COALESCE(chat_id, 0), COALESCE(message_id, 0),
+		       reason, details, created_at
+		FROM moderation_log
+		ORDER BY id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := s.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var e domain.AuditEntry
+		err := rows.Scan(&e.Id, &e.AdminId, &e.Action, &e.UserId, &e.ChatId, &e.MessageId, &e.Reason, &e.Details, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,97 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminChats(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+	otherChatID, err := storage.CreateChat(ctx, 3, 4)
+	require.NoError(t, err)
+
+	_, _, err = storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 1, Text: "spam"})
+	require.NoError(t, err)
+
+	t.Run("list by user", func(t *testing.T) {
+		chats, err := storage.ListChats(ctx, domain.ChatQuery{UserId: 2, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, chats, 1)
+		assert.Equal(t, chatID, chats[0].Id)
+		assert.Equal(t, []int{1, 2}, chats[0].UserIds)
+		assert.Equal(t, 1, chats[0].Messages)
+
+		all, err := storage.ListChats(ctx, domain.ChatQuery{Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+
+	t.Run("delete chat", func(t *testing.T) {
+		_, err := storage.CreateAttachment(ctx, domain.Attachment{
+			ChatId: chatID, UploaderId: 1, FileName: "a.txt", ContentType: "text/plain", Size: 1, StorageKey: "k",
+		})
+		require.NoError(t, err)
+		cursor, err := storage.GetSyncCursor(ctx, 1)
+		require.NoError(t, err)
+
+		members, attachments, err := storage.DeleteChat(ctx, chatID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, members)
+		require.Len(t, attachments, 1)
+		assert.Equal(t, "k", attachments[0].StorageKey)
+
+		chats, err := storage.ListChats(ctx, domain.ChatQuery{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, chats, 1)
+		assert.Equal(t, otherChatID, chats[0].Id)
+
+		batch, err := storage.GetChanges(ctx, 1, cursor, 10)
+		require.NoError(t, err)
+		require.Len(t, batch.Changes, 1)
+		assert.Equal(t, domain.EventChatDeleted, batch.Changes[0].Type)
+
+		_, _, err = storage.DeleteChat(ctx, chatID)
+		assert.ErrorIs(t, err, domain.ErrChatNotFound)
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	require.NoError(t, storage.AppendAudit(ctx, domain.AuditEntry{
+		AdminId: 100, Action: domain.AuditSuspendUser, UserId: 2, Reason: "spam",
+	}))
+	require.NoError(t, storage.AppendAudit(ctx, domain.AuditEntry{
+		AdminId: 100, Action: domain.AuditDeleteChat, ChatId: 7, Details: map[string]any{"attachments": 3},
+	}))
+
+	entries, err := storage.GetAuditLog(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, domain.AuditDeleteChat, entries[0].Action)
+	assert.Equal(t, 7, entries[0].ChatId)
+	assert.Equal(t, 0, entries[0].UserId)
+	assert.EqualValues(t, 3, entries[0].Details["attachments"])
+	assert.Equal(t, "spam", entries[1].Reason)
+
+	t.Run("append only", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `UPDATE moderation_log SET reason = ''`)
+		assert.Error(t, err)
+		_, err = pool.Exec(ctx, `DELETE FROM moderation_log`)
+		assert.Error(t, err)
+		_, err = pool.Exec(ctx, `TRUNCATE moderation_log`)
+		assert.Error(t, err)
+	})
+}
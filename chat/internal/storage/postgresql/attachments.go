@@ -0,0 +1,145 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const attachmentColumns = `id, chat_id, uploader_id, message_id, file_name, content_type, size,
+		       storage_key, COALESCE(thumbnail_key, ''), created_at`
+
+func (s *ChatStorage) CreateAttachment(ctx context.Context, a domain.Attachment) (int, error) {
+	query := `
+		INSERT INTO attachments (chat_id, uploader_id, file_name, content_type, size, storage_key, thumbnail_key)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+		RETURNING id
+	`
+
+	var id int
+	err := s.db.QueryRow(ctx, query,
+		a.ChatId,
+		a.UploaderId,
+		a.FileName,
+		a.ContentType,
+		a.Size,
+		a.StorageKey,
+		a.ThumbnailKey,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *ChatStorage) GetAttachment(ctx context.Context, chatID, attachmentID int) (domain.Attachment, error) {
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM attachments
+		WHERE id = $1 AND chat_id = $2
+	`
+
+	a, err := scanAttachment(s.db.QueryRow(ctx, query, attachmentID, chatID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Attachment{}, domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return a, nil
+}
+
+// DeleteMessageAttachments удаляет вложения сообщения и возвращает их, чтобы удалить файлы из хранилища
+func (s *ChatStorage) DeleteMessageAttachments(ctx context.Context, messageID int) ([]domain.Attachment, error) {
+	query := `
+		DELETE FROM attachments
+		WHERE message_id = $1
+		RETURNING ` + attachmentColumns
+
+	rows, err := s.db.Query(ctx, query, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// attachAttachments дополняет сообщения их вложениями в порядке загрузки
+func (s *ChatStorage) attachAttachments(ctx context.Context, messages []domain.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(messages))
+	for _, m := range messages {
+		ids = append(ids, m.Id)
+	}
+
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM attachments
+		WHERE message_id = ANY($1)
+		ORDER BY id
+	`
+
+	rows, err := s.db.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get attachments: %w", err)
+	}
+	defer rows.Close()
+
+	byMessage := make(map[int][]domain.Attachment)
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		byMessage[*a.MessageId] = append(byMessage[*a.MessageId], a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+
+	for i := range messages {
+		messages[i].Attachments = byMessage[messages[i].Id]
+	}
+
+	return nil
+}
+
+func scanAttachment(row pgx.Row) (domain.Attachment, error) {
+	var a domain.Attachment
+	err := row.Scan(
+		&a.Id,
+		&a.ChatId,
+		&a.UploaderId,
+		&a.MessageId,
+		&a.FileName,
+		&a.ContentType,
+		&a.Size,
+		&a.StorageKey,
+		&a.ThumbnailKey,
+		&a.CreatedAt,
+	)
+	return a, err
+}
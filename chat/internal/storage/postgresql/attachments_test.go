@@ -0,0 +1,76 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachments(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+
+	chatID, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+
+	newAttachment := func(uploader int, key string) int {
+		id, err := storage.CreateAttachment(ctx, domain.Attachment{
+			ChatId:       chatID,
+			UploaderId:   uploader,
+			FileName:     "photo.png",
+			ContentType:  "image/png",
+			Size:         10,
+			StorageKey:   key,
+			ThumbnailKey: key + "_thumb",
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	t.Run("send message with attachments", func(t *testing.T) {
+		a1 := newAttachment(user1, "chats/1/a")
+		a2 := newAttachment(user1, "chats/1/b")
+
+		msgID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, AttachmentIds: []int{a1, a2}})
+		require.NoError(t, err)
+
+		messages, err := storage.GetMessages(ctx, chatID, user2, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		require.Len(t, messages[0].Attachments, 2)
+		assert.Equal(t, a1, messages[0].Attachments[0].Id)
+		assert.Equal(t, "chats/1/a_thumb", messages[0].Attachments[0].ThumbnailKey)
+		require.NotNil(t, messages[0].Attachments[0].MessageId)
+		assert.Equal(t, msgID, *messages[0].Attachments[0].MessageId)
+
+		_, _, err = storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "again", AttachmentIds: []int{a1}})
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+
+		deleted, err := storage.DeleteMessageAttachments(ctx, msgID)
+		require.NoError(t, err)
+		assert.Len(t, deleted, 2)
+
+		_, err = storage.GetAttachment(ctx, chatID, a1)
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+	})
+
+	t.Run("attachment of another user", func(t *testing.T) {
+		a := newAttachment(user2, "chats/1/c")
+
+		_, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "stolen", AttachmentIds: []int{a}})
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+
+		got, err := storage.GetAttachment(ctx, chatID, a)
+		require.NoError(t, err)
+		assert.Nil(t, got.MessageId)
+		assert.Equal(t, user2, got.UploaderId)
+	})
+}
@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s *ChatStorage) BlockUser(ctx context.Context, userID, blockedUserID int) error {
+	query := `
+		INSERT INTO user_blocks (user_id, blocked_user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, blocked_user_id) DO NOTHING
+	`
+
+	if _, err := s.db.Exec(ctx, query, userID, blockedUserID); err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ChatStorage) UnblockUser(ctx context.Context, userID, blockedUserID int) error {
+	query := `
+		DELETE FROM user_blocks
+		WHERE user_id = $1 AND blocked_user_id = $2
+	`
+
+	if _, err := s.db.Exec(ctx, query, userID, blockedUserID); err != nil {
+		return fmt.Errorf("failed to unblock user: %w", err)
+	}
+
+	return nil
+}
+
+// GetBlockedUsers возвращает черный список userID в порядке добавления
+func (s *ChatStorage) GetBlockedUsers(ctx context.Context, userID int) ([]int, error) {
+	query := `
+		SELECT blocked_user_id
+		FROM user_blocks
+		WHERE user_id = $1
+		ORDER BY created_at, blocked_user_id
+	`
+
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+	defer rows.Close()
+
+	blocked := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked user: %w", err)
+		}
+		blocked = append(blocked, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return blocked, nil
+}
+
+// IsBlocked сообщает, заблокировал ли один из пользователей другого
+func (s *ChatStorage) IsBlocked(ctx context.Context, userID1, userID2 int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM user_blocks
+			WHERE (user_id = $1 AND blocked_user_id = $2)
+			   OR (user_id = $2 AND blocked_user_id = $1)
+		)
+	`
+
+	var blocked bool
+	if err := s.db.QueryRow(ctx, query, userID1, userID2).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+
+	return blocked, nil
+}
+
+// IsChatBlocked сообщает, заблокировал ли один из участников чата другого
+func (s *ChatStorage) IsChatBlocked(ctx context.Context, chatID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM chats c
+			JOIN user_blocks b
+			  ON (b.user_id = c.user_1_id AND b.blocked_user_id = c.user_2_id)
+			  OR (b.user_id = c.user_2_id AND b.blocked_user_id = c.user_1_id)
+			WHERE c.id = $1
+		)
+	`
+
+	var blocked bool
+	if err := s.db.QueryRow(ctx, query, chatID).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("failed to check chat block: %w", err)
+	}
+
+	return blocked, nil
+}
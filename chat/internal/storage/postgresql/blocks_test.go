@@ -0,0 +1,62 @@
+package postgresql_test
+
+import (
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocks(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+	user3 := 3
+
+	chatID, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+	otherChatID, err := storage.CreateChat(ctx, user1, user3)
+	require.NoError(t, err)
+
+	t.Run("block works both ways", func(t *testing.T) {
+		require.NoError(t, storage.BlockUser(ctx, user2, user1))
+		require.NoError(t, storage.BlockUser(ctx, user2, user1))
+
+		blocked, err := storage.IsBlocked(ctx, user1, user2)
+		require.NoError(t, err)
+		assert.True(t, blocked)
+
+		blocked, err = storage.IsChatBlocked(ctx, chatID)
+		require.NoError(t, err)
+		assert.True(t, blocked)
+
+		blocked, err = storage.IsChatBlocked(ctx, otherChatID)
+		require.NoError(t, err)
+		assert.False(t, blocked)
+
+		users, err := storage.GetBlockedUsers(ctx, user2)
+		require.NoError(t, err)
+		assert.Equal(t, []int{user1}, users)
+
+		users, err = storage.GetBlockedUsers(ctx, user1)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("unblock", func(t *testing.T) {
+		require.NoError(t, storage.UnblockUser(ctx, user2, user1))
+
+		blocked, err := storage.IsBlocked(ctx, user2, user1)
+		require.NoError(t, err)
+		assert.False(t, blocked)
+
+		blocked, err = storage.IsChatBlocked(ctx, chatID)
+		require.NoError(t, err)
+		assert.False(t, blocked)
+	})
+}
@@ -0,0 +1,130 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// changeLogLength - сколько последних изменений хранится на пользователя.
+// Клиент, отставший сильнее, получает сигнал полной пересинхронизации
+const changeLogLength = 1000
+
+// recordChange добавляет изменение в журналы userIDs, а если они не переданы - всех участников чата.
+// Вызывается в транзакции самого изменения, чтобы журнал не расходился с данными
+func recordChange(ctx context.Context, tx pgx.Tx, chatID int, changeType string, payload any, userIDs ...int) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal change: %w", err)
+	}
+
+	if len(userIDs) == 0 {
+		var user1, user2 int
+		err := tx.QueryRow(ctx, `SELECT user_1_id, user_2_id FROM chats WHERE id = $1`, chatID).Scan(&user1, &user2)
+		if err != nil {
+			return fmt.Errorf("failed to get chat members: %w", err)
+		}
+		userIDs = []int{user1, user2}
+	}
+	// Строки user_sync_state блокируются в одном порядке во всех транзакциях, иначе возможен deadlock
+	userIDs = slices.Compact(slices.Sorted(slices.Values(userIDs)))
+
+	_, err = tx.Exec(ctx, `
+		WITH seqs AS (
+			INSERT INTO user_sync_state (user_id, last_seq)
+			SELECT unnest($1::bigint[]), 1
+			ON CONFLICT (user_id) DO UPDATE SET last_seq = user_sync_state.last_seq + 1
+			RETURNING user_id, last_seq
+		)
+		INSERT INTO user_changes (user_id, seq, chat_id, type, payload)
+		SELECT user_id, last_seq, $2, $3, $4 FROM seqs
+	`, userIDs, chatID, changeType, data)
+	if err != nil {
+		return fmt.Errorf("failed to record change: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM user_changes c
+		USING user_sync_state s
+		WHERE c.user_id = ANY($1) AND s.user_id = c.user_id AND c.seq <= s.last_seq - $2
+	`, userIDs, changeLogLength)
+	if err != nil {
+		return fmt.Errorf("failed to prune changes: %w", err)
+	}
+
+	return nil
+}
+
+// GetChanges возвращает до limit изменений пользователя после since. Если часть изменений уже удалена
+// из журнала или since больше последнего номера, вместо изменений возвращается сигнал пересинхронизации
+func (s *ChatStorage) GetChanges(ctx context.Context, userID int, since int64, limit int) (domain.SyncBatch, error) {
+	var lastSeq, oldestSeq int64
+	err := s.db.QueryRow(ctx, `
+		SELECT COALESCE((SELECT last_seq FROM user_sync_state WHERE user_id = $1), 0),
+		       COALESCE((SELECT MIN(seq) FROM user_changes WHERE user_id = $1), 0)
+	`, userID).Scan(&lastSeq, &oldestSeq)
+	if err != nil {
+		return domain.SyncBatch{}, fmt.Errorf("failed to get sync state: %w", err)
+	}
+
+	batch := domain.SyncBatch{Changes: []domain.Change{}, Cursor: since}
+	if since == lastSeq {
+		return batch, nil
+	}
+	if since > lastSeq || oldestSeq == 0 || oldestSeq > since+1 {
+		batch.Cursor = lastSeq
+		batch.ResyncRequired = true
+		return batch, nil
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT seq, chat_id, type, payload, created_at
+		FROM user_changes
+		WHERE user_id = $1 AND seq > $2
+		ORDER BY seq
+		LIMIT $3
+	`, userID, since, limit+1)
+	if err != nil {
+		return domain.SyncBatch{}, fmt.Errorf("failed to get changes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.Change
+		if err := rows.Scan(&c.Seq, &c.ChatId, &c.Type, &c.Payload, &c.CreatedAt); err != nil {
+			return domain.SyncBatch{}, fmt.Errorf("failed to scan change: %w", err)
+		}
+		batch.Changes = append(batch.Changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return domain.SyncBatch{}, fmt.Errorf("rows error: %w", err)
+	}
+
+	if len(batch.Changes) > limit {
+		batch.Changes = batch.Changes[:limit]
+		batch.HasMore = true
+	}
+	if len(batch.Changes) > 0 {
+		batch.Cursor = batch.Changes[len(batch.Changes)-1].Seq
+	}
+
+	return batch, nil
+}
+
+// GetSyncCursor возвращает номер последнего изменения пользователя, 0 - если изменений еще не было
+func (s *ChatStorage) GetSyncCursor(ctx context.Context, userID int) (int64, error) {
+	var lastSeq int64
+	err := s.db.QueryRow(ctx, `
+		SELECT COALESCE((SELECT last_seq FROM user_sync_state WHERE user_id = $1), 0)
+	`, userID).Scan(&lastSeq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sync cursor: %w", err)
+	}
+
+	return lastSeq, nil
+}
@@ -0,0 +1,120 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChanges(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+
+	chatID, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+
+	msgID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "hello"})
+	require.NoError(t, err)
+	_, err = storage.EditMessage(ctx, chatID, msgID, domain.TextEdit{Text: "hello!"})
+	require.NoError(t, err)
+	_, _, err = storage.MarkRead(ctx, chatID, user2, msgID)
+	require.NoError(t, err)
+	_, _, err = storage.MarkRead(ctx, chatID, user2, msgID)
+	require.NoError(t, err)
+	require.NoError(t, storage.HideMessage(ctx, chatID, msgID, user2))
+
+	types := func(changes []domain.Change) []string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, c.Type)
+		}
+		return out
+	}
+
+	t.Run("changes in order", func(t *testing.T) {
+		batch, err := storage.GetChanges(ctx, user1, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			domain.EventChatCreated,
+			domain.EventMessageCreated,
+			domain.EventMessageEdited,
+			domain.EventRead,
+		}, types(batch.Changes))
+		assert.Equal(t, int64(4), batch.Cursor)
+		assert.False(t, batch.HasMore)
+		assert.False(t, batch.ResyncRequired)
+
+		batch, err = storage.GetChanges(ctx, user2, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			domain.EventChatCreated,
+			domain.EventMessageCreated,
+			domain.EventMessageEdited,
+			domain.EventRead,
+			domain.EventMessageDeleted,
+		}, types(batch.Changes))
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		batch, err := storage.GetChanges(ctx, user1, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{domain.EventMessageCreated, domain.EventMessageEdited}, types(batch.Changes))
+		assert.Equal(t, int64(3), batch.Cursor)
+		assert.True(t, batch.HasMore)
+
+		batch, err = storage.GetChanges(ctx, user1, 4, 2)
+		require.NoError(t, err)
+		assert.Empty(t, batch.Changes)
+		assert.Equal(t, int64(4), batch.Cursor)
+	})
+
+	t.Run("cursor from the future", func(t *testing.T) {
+		batch, err := storage.GetChanges(ctx, user1, 100, 10)
+		require.NoError(t, err)
+		assert.True(t, batch.ResyncRequired)
+		assert.Equal(t, int64(4), batch.Cursor)
+	})
+
+	t.Run("pruned changes", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `DELETE FROM user_changes WHERE user_id = $1 AND seq <= 2`, user1)
+		require.NoError(t, err)
+
+		batch, err := storage.GetChanges(ctx, user1, 1, 10)
+		require.NoError(t, err)
+		assert.True(t, batch.ResyncRequired)
+		assert.Empty(t, batch.Changes)
+		assert.Equal(t, int64(4), batch.Cursor)
+
+		batch, err = storage.GetChanges(ctx, user1, 2, 10)
+		require.NoError(t, err)
+		assert.False(t, batch.ResyncRequired)
+		assert.Len(t, batch.Changes, 2)
+	})
+}
+
+func TestGetSyncCursor(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	cursor, err := storage.GetSyncCursor(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cursor)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+	_, _, err = storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 1, Text: "hello"})
+	require.NoError(t, err)
+
+	cursor, err = storage.GetSyncCursor(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), cursor)
+}
@@ -0,0 +1,100 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetChatSettings возвращает настройки чата userID. Истекшее отключение уведомлений не учитывается
+func (s *ChatStorage) GetChatSettings(ctx context.Context, chatID, userID int) (domain.ChatSettings, error) {
+	query := `
+		SELECT m.muted, CASE WHEN m.muted THEN cs.muted_until END, cs.archived, cs.pinned_at IS NOT NULL
+		FROM chat_settings cs,
+		     LATERAL (SELECT cs.muted AND (cs.muted_until IS NULL OR cs.muted_until > NOW()) AS muted) m
+		WHERE cs.chat_id = $1 AND cs.user_id = $2
+	`
+
+	settings := domain.ChatSettings{ChatId: chatID}
+	err := s.db.QueryRow(ctx, query, chatID, userID).Scan(
+		&settings.Muted,
+		&settings.MutedUntil,
+		&settings.Archived,
+		&settings.Pinned,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return settings, nil
+	}
+	if err != nil {
+		return domain.ChatSettings{}, fmt.Errorf("failed to get chat settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpdateChatSettings применяет изменение настроек чата userID и возвращает итоговые настройки.
+// Повторное закрепление не меняет позицию чата в списке
+func (s *ChatStorage) UpdateChatSettings(ctx context.Context, chatID, userID int, upd domain.ChatSettingsUpdate) (domain.ChatSettings, error) {
+	query := `
+		INSERT INTO chat_settings AS cs (chat_id, user_id, muted, muted_until, archived, pinned_at)
+		VALUES (
+			$1, $2,
+			COALESCE($3::boolean, FALSE),
+			CASE WHEN $3::boolean THEN $4::timestamptz END,
+			COALESCE($5::boolean, FALSE),
+			CASE WHEN $6::boolean THEN NOW() END
+		)
+		ON CONFLICT (chat_id, user_id) DO UPDATE SET
+			muted = COALESCE($3::boolean, cs.muted),
+			muted_until = CASE
+				WHEN $3::boolean IS NULL THEN cs.muted_until
+				WHEN $3::boolean THEN $4::timestamptz
+			END,
+			archived = COALESCE($5::boolean, cs.archived),
+			pinned_at = CASE
+				WHEN $6::boolean IS NULL THEN cs.pinned_at
+				WHEN $6::boolean THEN COALESCE(cs.pinned_at, NOW())
+			END
+	`
+
+	_, err := s.db.Exec(ctx, query, chatID, userID, upd.Muted, upd.MutedUntil, upd.Archived, upd.Pinned)
+	if err != nil {
+		return domain.ChatSettings{}, fmt.Errorf("failed to update chat settings: %w", err)
+	}
+
+	return s.GetChatSettings(ctx, chatID, userID)
+}
+
+// GetMutedMembers возвращает участников чата, у которых сейчас отключены уведомления
+func (s *ChatStorage) GetMutedMembers(ctx context.Context, chatID int) ([]int, error) {
+	query := `
+		SELECT user_id
+		FROM chat_settings
+		WHERE chat_id = $1 AND muted AND (muted_until IS NULL OR muted_until > NOW())
+		ORDER BY user_id
+	`
+
+	rows, err := s.db.Query(ctx, query, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get muted members: %w", err)
+	}
+	defer rows.Close()
+
+	var muted []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan muted member: %w", err)
+		}
+		muted = append(muted, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return muted, nil
+}
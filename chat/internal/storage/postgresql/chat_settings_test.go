@@ -0,0 +1,104 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatSettings(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user := 1
+	chat1, err := storage.CreateChat(ctx, user, 2)
+	require.NoError(t, err)
+	chat2, err := storage.CreateChat(ctx, user, 3)
+	require.NoError(t, err)
+	chat3, err := storage.CreateChat(ctx, user, 4)
+	require.NoError(t, err)
+
+	yes, no := true, false
+
+	t.Run("defaults", func(t *testing.T) {
+		settings, err := storage.GetChatSettings(ctx, chat1, user)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ChatSettings{ChatId: chat1}, settings)
+	})
+
+	t.Run("mute", func(t *testing.T) {
+		until := time.Now().Add(time.Hour)
+		settings, err := storage.UpdateChatSettings(ctx, chat1, user, domain.ChatSettingsUpdate{Muted: &yes, MutedUntil: &until})
+		require.NoError(t, err)
+		assert.True(t, settings.Muted)
+		require.NotNil(t, settings.MutedUntil)
+		assert.WithinDuration(t, until, *settings.MutedUntil, time.Second)
+
+		muted, err := storage.GetMutedMembers(ctx, chat1)
+		require.NoError(t, err)
+		assert.Equal(t, []int{user}, muted)
+
+		settings, err = storage.UpdateChatSettings(ctx, chat1, user, domain.ChatSettingsUpdate{Archived: &yes})
+		require.NoError(t, err)
+		assert.True(t, settings.Muted)
+		assert.True(t, settings.Archived)
+
+		settings, err = storage.UpdateChatSettings(ctx, chat1, user, domain.ChatSettingsUpdate{Muted: &no})
+		require.NoError(t, err)
+		assert.False(t, settings.Muted)
+		assert.Nil(t, settings.MutedUntil)
+	})
+
+	t.Run("expired mute", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `
+			UPDATE chat_settings SET muted = TRUE, muted_until = NOW() - INTERVAL '1 minute'
+			WHERE chat_id = $1 AND user_id = $2
+		`, chat1, user)
+		require.NoError(t, err)
+
+		settings, err := storage.GetChatSettings(ctx, chat1, user)
+		require.NoError(t, err)
+		assert.False(t, settings.Muted)
+		assert.Nil(t, settings.MutedUntil)
+
+		muted, err := storage.GetMutedMembers(ctx, chat1)
+		require.NoError(t, err)
+		assert.Empty(t, muted)
+	})
+
+	t.Run("chat order", func(t *testing.T) {
+		chats, err := storage.GetUserChats(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, []int{chat2, chat3, chat1}, chats)
+
+		_, err = storage.UpdateChatSettings(ctx, chat3, user, domain.ChatSettingsUpdate{Pinned: &yes})
+		require.NoError(t, err)
+		_, err = storage.UpdateChatSettings(ctx, chat2, user, domain.ChatSettingsUpdate{Pinned: &yes})
+		require.NoError(t, err)
+
+		chats, err = storage.GetUserChats(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, []int{chat2, chat3, chat1}, chats)
+
+		// повторное закрепление не поднимает чат выше
+		_, err = storage.UpdateChatSettings(ctx, chat3, user, domain.ChatSettingsUpdate{Pinned: &yes})
+		require.NoError(t, err)
+		_, err = storage.UpdateChatSettings(ctx, chat2, user, domain.ChatSettingsUpdate{Pinned: &no})
+		require.NoError(t, err)
+
+		chats, err = storage.GetUserChats(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, []int{chat3, chat2, chat1}, chats)
+
+		// настройки одного участника не влияют на список собеседника
+		chats, err = storage.GetUserChats(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []int{chat2}, chats)
+	})
+}
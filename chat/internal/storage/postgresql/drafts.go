@@ -0,0 +1,93 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const draftColumns = `chat_id, text, entities, COALESCE(reply_to_message_id, 0), updated_at`
+
+// SaveDraft сохраняет черновик userID, если он новее сохраненного, и возвращает актуальную версию.
+// saved = false, если сохраненный черновик новее и d отброшен
+func (s *ChatStorage) SaveDraft(ctx context.Context, userID int, d domain.Draft) (domain.Draft, bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return domain.Draft{}, false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	saved, err := scanDraft(tx.QueryRow(ctx, `
+		INSERT INTO drafts AS d (chat_id, user_id, text, entities, reply_to_message_id, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6)
+		ON CONFLICT (user_id, chat_id) DO UPDATE
+		SET text = EXCLUDED.text,
+		    entities = EXCLUDED.entities,
+		    reply_to_message_id = EXCLUDED.reply_to_message_id,
+		    updated_at = EXCLUDED.updated_at
+		WHERE d.updated_at < EXCLUDED.updated_at
+		RETURNING `+draftColumns,
+		d.ChatId, userID, d.Text, entitiesValue(d.Entities), d.ReplyToId, d.UpdatedAt,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		current, err := scanDraft(tx.QueryRow(ctx, `
+			SELECT `+draftColumns+` FROM drafts WHERE user_id = $1 AND chat_id = $2
+		`, userID, d.ChatId))
+		if err != nil {
+			return domain.Draft{}, false, fmt.Errorf("failed to get draft: %w", err)
+		}
+		return current, false, nil
+	}
+	if err != nil {
+		return domain.Draft{}, false, fmt.Errorf("failed to save draft: %w", err)
+	}
+	if err := recordChange(ctx, tx, d.ChatId, domain.EventDraftUpdated, saved, userID); err != nil {
+		return domain.Draft{}, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Draft{}, false, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return saved, true, nil
+}
+
+// GetDrafts возвращает непустые черновики userID, начиная с последнего измененного
+func (s *ChatStorage) GetDrafts(ctx context.Context, userID int) ([]domain.Draft, error) {
+	query := `
+		SELECT ` + draftColumns + `
+		FROM drafts
+		WHERE user_id = $1 AND (text <> '' OR reply_to_message_id IS NOT NULL)
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drafts: %w", err)
+	}
+	defer rows.Close()
+
+	drafts := []domain.Draft{}
+	for rows.Next() {
+		d, err := scanDraft(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan draft: %w", err)
+		}
+		drafts = append(drafts, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return drafts, nil
+}
+
+func scanDraft(row pgx.Row) (domain.Draft, error) {
+	var d domain.Draft
+	err := row.Scan(&d.ChatId, &d.Text, &d.Entities, &d.ReplyToId, &d.UpdatedAt)
+	return d, err
+}
@@ -0,0 +1,74 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrafts(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+
+	at := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+
+	t.Run("last writer wins", func(t *testing.T) {
+		saved, ok, err := storage.SaveDraft(ctx, 1, domain.Draft{ChatId: chatID, Text: "с телефона", UpdatedAt: at.Add(time.Minute)})
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "с телефона", saved.Text)
+
+		// Правка с ноутбука сделана раньше, но пришла позже
+		current, ok, err := storage.SaveDraft(ctx, 1, domain.Draft{ChatId: chatID, Text: "с ноутбука", UpdatedAt: at})
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "с телефона", current.Text)
+
+		drafts, err := storage.GetDrafts(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, drafts, 1)
+		assert.Equal(t, "с телефона", drafts[0].Text)
+
+		drafts, err = storage.GetDrafts(ctx, 2)
+		require.NoError(t, err)
+		assert.Empty(t, drafts)
+	})
+
+	t.Run("cleared draft is not resurrected", func(t *testing.T) {
+		_, ok, err := storage.SaveDraft(ctx, 1, domain.Draft{ChatId: chatID, UpdatedAt: at.Add(2 * time.Minute)})
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		_, ok, err = storage.SaveDraft(ctx, 1, domain.Draft{ChatId: chatID, Text: "устаревший", UpdatedAt: at.Add(time.Minute)})
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		drafts, err := storage.GetDrafts(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, drafts)
+	})
+
+	t.Run("synced via changes", func(t *testing.T) {
+		batch, err := storage.GetChanges(ctx, 1, 1, 100)
+		require.NoError(t, err)
+		var types []string
+		for _, c := range batch.Changes {
+			types = append(types, c.Type)
+		}
+		assert.Equal(t, []string{domain.EventDraftUpdated, domain.EventDraftUpdated}, types)
+
+		// Собеседник черновиков не видит
+		cursor, err := storage.GetSyncCursor(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), cursor)
+	})
+}
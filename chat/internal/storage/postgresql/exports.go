@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const exportColumns = `id, user_id, status, account, COALESCE(storage_key, ''), COALESCE(size, 0), COALESCE(error, ''),
+		created_at, finished_at, expires_at`
+
+// CreateExport ставит в очередь выгрузку данных пользователя. Если у него уже есть незавершенная выгрузка,
+// возвращается ErrExportInProgress
+func (s *ChatStorage) CreateExport(ctx context.Context, userID int, account domain.Account) (domain.Export, error) {
+	query := `
+		INSERT INTO exports (user_id, account)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) WHERE status IN ('pending', 'running') DO NOTHING
+		RETURNING ` + exportColumns
+
+	export, err := scanExport(s.db.QueryRow(ctx, query, userID, account))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Export{}, domain.ErrExportInProgress
+	}
+	if err != nil {
+		return domain.Export{}, fmt.Errorf("failed to create export: %w", err)
+	}
+
+	return export, nil
+}
+
+func (s *ChatStorage) GetExport(ctx context.Context, userID int, exportID string) (domain.Export, error) {
+	query := `SELECT ` + exportColumns + ` FROM exports WHERE id = $1 AND user_id = $2`
+
+	export, err := scanExport(s.db.QueryRow(ctx, query, exportID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Export{}, domain.ErrExportNotFound
+	}
+	if err != nil {
+		return domain.Export{}, fmt.Errorf("failed to get export: %w", err)
+	}
+
+	return export, nil
+}
+
+// ListExports возвращает выгрузки пользователя от новых к старым
+func (s *ChatStorage) ListExports(ctx context.Context, userID int) ([]domain.Export, error) {
+	query := `SELECT ` + exportColumns + ` FROM exports WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exports: %w", err)
+	}
+	defer rows.Close()
+
+	exports := []domain.Export{}
+	for rows.Next() {
+		export, err := scanExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan export: %w", err)
+		}
+		exports = append(exports, export)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return exports, nil
+}
+
+// ClaimExport берет в работу самую старую ожидающую выгрузку. Выгрузка, которая выполняется дольше timeout,
+// считается брошенной упавшей репликой и берется заново. Без подходящих выгрузок возвращает ErrExportNotFound
+func (s *ChatStorage) ClaimExport(ctx context.Context, timeout time.Duration) (domain.Export, error) {
+	query := `
+		UPDATE exports
+		SET status = 'running', started_at = NOW()
+		WHERE id = (
+			SELECT id FROM exports
+			WHERE status = 'pending'
+			   OR (status = 'running' AND started_at < NOW() - make_interval(secs => $1::int))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + exportColumns
+
+	export, err := scanExport(s.db.QueryRow(ctx, query, int(timeout.Seconds())))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Export{}, domain.ErrExportNotFound
+	}
+	if err != nil {
+		return domain.Export{}, fmt.Errorf("failed to claim export: %w", err)
+	}
+
+	return export, nil
+}
+
+// FinishExport отмечает выгрузку готовой: архив лежит в хранилище по storageKey и доступен до expiresAt
+func (s *ChatStorage) FinishExport(ctx context.Context, exportID, storageKey string, size int64, expiresAt time.Time) error {
+	query := `
+		UPDATE exports
+		SET status = 'ready', storage_key = $2, size = $3, finished_at = NOW(), expires_at = $4
+		WHERE id = $1
+	`
+
+	if _, err := s.db.Exec(ctx, query, exportID, storageKey, size, expiresAt); err != nil {
+		return fmt.Errorf("failed to finish export: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ChatStorage) FailExport(ctx context.Context, exportID, reason string) error {
+	query := `
+		UPDATE exports
+		SET status = 'failed', error = $2, finished_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := s.db.Exec(ctx, query, exportID, reason); err != nil {
+		return fmt.Errorf("failed to fail export: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredExports удаляет выгрузки с истекшей ссылкой и неудачные старше olderThan.
+// Возвращает ключи архивов, которые нужно удалить из хранилища
+func (s *ChatStorage) DeleteExpiredExports(ctx context.Context, olderThan time.Duration) ([]string, error) {
+	query := `
+		DELETE FROM exports
+		WHERE expires_at < NOW()
+		   OR (status = 'failed' AND finished_at < NOW() - make_interval(secs => $1::int))
+		RETURNING COALESCE(storage_key, '')
+	`
+
+	rows, err := s.db.Query(ctx, query, int(olderThan.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete expired exports: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan export key: %w", err)
+		}
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return keys, nil
+}
+
+func scanExport(row pgx.Row) (domain.Export, error) {
+	var e domain.Export
+	err := row.Scan(&e.Id, &e.UserId, &e.Status, &e.Account, &e.StorageKey, &e.Size, &e.Error,
+		&e.CreatedAt, &e.FinishedAt, &e.ExpiresAt)
+	return e, err
+}
@@ -0,0 +1,81 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExports(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	account := domain.Account{UserId: 1, Login: "alice", Devices: []domain.Device{{DeviceId: "phone"}}}
+
+	created, err := storage.CreateExport(ctx, 1, account)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ExportPending, created.Status)
+	assert.Equal(t, account.Login, created.Account.Login)
+
+	t.Run("one active export per user", func(t *testing.T) {
+		_, err := storage.CreateExport(ctx, 1, account)
+		assert.ErrorIs(t, err, domain.ErrExportInProgress)
+	})
+
+	t.Run("owner only", func(t *testing.T) {
+		_, err := storage.GetExport(ctx, 2, created.Id)
+		assert.ErrorIs(t, err, domain.ErrExportNotFound)
+	})
+
+	t.Run("claim and finish", func(t *testing.T) {
+		job, err := storage.ClaimExport(ctx, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, created.Id, job.Id)
+		assert.Equal(t, domain.ExportRunning, job.Status)
+
+		_, err = storage.ClaimExport(ctx, time.Hour)
+		assert.ErrorIs(t, err, domain.ErrExportNotFound)
+
+		require.NoError(t, storage.FinishExport(ctx, job.Id, "exports/1/a.zip", 42, time.Now().Add(time.Hour)))
+
+		got, err := storage.GetExport(ctx, 1, job.Id)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ExportReady, got.Status)
+		assert.Equal(t, int64(42), got.Size)
+		require.NotNil(t, got.ExpiresAt)
+	})
+
+	t.Run("new export after finished one", func(t *testing.T) {
+		next, err := storage.CreateExport(ctx, 1, account)
+		require.NoError(t, err)
+
+		exports, err := storage.ListExports(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, exports, 2)
+		assert.Equal(t, next.Id, exports[0].Id)
+
+		job, err := storage.ClaimExport(ctx, time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, storage.FailExport(ctx, job.Id, "boom"))
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `UPDATE exports SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, created.Id)
+		require.NoError(t, err)
+
+		keys, err := storage.DeleteExpiredExports(ctx, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"exports/1/a.zip"}, keys)
+
+		exports, err := storage.ListExports(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, exports, 1)
+		assert.Equal(t, domain.ExportFailed, exports[0].Status)
+	})
+}
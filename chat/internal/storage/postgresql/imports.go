@@ -0,0 +1,140 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ImportChat переносит историю личного чата одной транзакцией. Чат пары пользователей создается, если его еще нет.
+// Сообщения загружаются через COPY во временную таблицу, а оттуда вставляются с пропуском уже
+// импортированных import_id, поэтому файл можно импортировать повторно
+func (s *ChatStorage) ImportChat(ctx context.Context, chat domain.ImportedChat) (domain.ImportResult, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return domain.ImportResult{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var res domain.ImportResult
+	err = tx.QueryRow(ctx, `
+		SELECT id FROM chats
+		WHERE (user_1_id = $1 AND user_2_id = $2) OR (user_1_id = $2 AND user_2_id = $1)
+	`, chat.User1Id, chat.User2Id).Scan(&res.ChatId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		res.Created = true
+		err = tx.QueryRow(ctx, `
+			INSERT INTO chats (user_1_id, user_2_id, created_at)
+			VALUES ($1, $2, COALESCE($3, NOW()))
+			RETURNING id
+		`, chat.User1Id, chat.User2Id, firstMessageTime(chat)).Scan(&res.ChatId)
+		if err != nil {
+			return domain.ImportResult{}, fmt.Errorf("failed to create chat: %w", err)
+		}
+
+		members := domain.ChatMembers{ChatId: res.ChatId, UserIds: []int{chat.User1Id, chat.User2Id}}
+		if err := recordChange(ctx, tx, res.ChatId, domain.EventChatCreated, members, chat.User1Id, chat.User2Id); err != nil {
+			return domain.ImportResult{}, err
+		}
+	}
+	if err != nil {
+		return domain.ImportResult{}, fmt.Errorf("failed to find chat: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		CREATE TEMP TABLE message_import (
+			import_id TEXT NOT NULL,
+			sender_id BIGINT NOT NULL,
+			text TEXT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			edited_at TIMESTAMP WITH TIME ZONE,
+			reply_to_import_id TEXT
+		) ON COMMIT DROP
+	`)
+	if err != nil {
+		return domain.ImportResult{}, fmt.Errorf("failed to create import table: %w", err)
+	}
+
+	columns := []string{"import_id", "sender_id", "text", "created_at", "edited_at", "reply_to_import_id"}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"message_import"}, columns,
+		pgx.CopyFromSlice(len(chat.Messages), func(i int) ([]any, error) {
+			m := chat.Messages[i]
+			var replyTo *string
+			if m.ReplyToImportId != "" {
+				replyTo = &m.ReplyToImportId
+			}
+			return []any{m.ImportId, m.SenderId, m.Text, m.CreatedAt, m.EditedAt, replyTo}, nil
+		}))
+	if err != nil {
+		return domain.ImportResult{}, fmt.Errorf("failed to copy messages: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO messages (chat_id, sender_id, text, created_at, edited_at, import_id)
+		SELECT $1, sender_id, text, created_at, edited_at, import_id
+		FROM message_import
+		ORDER BY created_at, import_id
+		ON CONFLICT (chat_id, import_id) DO NOTHING
+	`, res.ChatId)
+	if err != nil {
+		return domain.ImportResult{}, fmt.Errorf("failed to insert messages: %w", err)
+	}
+	res.Imported = int(tag.RowsAffected())
+	res.Duplicates = len(chat.Messages) - res.Imported
+
+	// Ответы связываются после вставки: исходное сообщение может оказаться в этом же файле
+	_, err = tx.Exec(ctx, `
+		UPDATE messages m
+		SET reply_to_message_id = r.id
+		FROM message_import i
+		JOIN messages r ON r.chat_id = $1 AND r.import_id = i.reply_to_import_id
+		WHERE m.chat_id = $1 AND m.import_id = i.import_id AND m.reply_to_message_id IS NULL
+	`, res.ChatId)
+	if err != nil {
+		return domain.ImportResult{}, fmt.Errorf("failed to link replies: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE chats
+		SET updated_at = GREATEST(updated_at, (SELECT MAX(created_at) FROM message_import))
+		WHERE id = $1
+	`, res.ChatId)
+	if err != nil {
+		return domain.ImportResult{}, fmt.Errorf("failed to update chat: %w", err)
+	}
+
+	// В новом чате перенесенная история считается прочитанной. В существующем указатели не двигаются,
+	// чтобы не пометить прочитанными его собственные непрочитанные сообщения
+	if res.Created {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO chat_reads (chat_id, user_id, last_read_message_id)
+			SELECT $1, u, COALESCE((SELECT MAX(id) FROM messages WHERE chat_id = $1), 0)
+			FROM unnest($2::bigint[]) AS u
+		`, res.ChatId, []int{chat.User1Id, chat.User2Id})
+		if err != nil {
+			return domain.ImportResult{}, fmt.Errorf("failed to mark history read: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ImportResult{}, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return res, nil
+}
+
+func firstMessageTime(chat domain.ImportedChat) any {
+	if len(chat.Messages) == 0 {
+		return nil
+	}
+	first := chat.Messages[0].CreatedAt
+	for _, m := range chat.Messages[1:] {
+		if m.CreatedAt.Before(first) {
+			first = m.CreatedAt
+		}
+	}
+	return first
+}
@@ -0,0 +1,62 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportChat(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	start := time.Date(2023, 1, 15, 10, 0, 0, 0, time.UTC)
+	chat := domain.ImportedChat{
+		User1Id: 1,
+		User2Id: 2,
+		Messages: []domain.ImportedMessage{
+			{ImportId: "telegram:200:2", SenderId: 2, Text: "ответ", CreatedAt: start.Add(time.Minute), ReplyToImportId: "telegram:200:1"},
+			{ImportId: "telegram:200:1", SenderId: 1, Text: "привет", CreatedAt: start},
+		},
+	}
+
+	res, err := storage.ImportChat(ctx, chat)
+	require.NoError(t, err)
+	assert.True(t, res.Created)
+	assert.Equal(t, 2, res.Imported)
+	assert.Equal(t, 0, res.Duplicates)
+
+	messages, err := storage.GetMessages(ctx, res.ChatId, 1, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "привет", messages[0].Text)
+	assert.True(t, messages[0].CreatedAt.Equal(start))
+	assert.True(t, messages[0].IsRead)
+	require.NotNil(t, messages[1].ReplyTo)
+	assert.Equal(t, messages[0].Id, messages[1].ReplyTo.Id)
+
+	t.Run("reimport skips duplicates", func(t *testing.T) {
+		chat := chat
+		chat.User1Id, chat.User2Id = 2, 1
+		chat.Messages = append(chat.Messages, domain.ImportedMessage{ImportId: "telegram:200:3", SenderId: 1, Text: "еще", CreatedAt: start.Add(2 * time.Minute)})
+
+		again, err := storage.ImportChat(ctx, chat)
+		require.NoError(t, err)
+		assert.Equal(t, res.ChatId, again.ChatId)
+		assert.False(t, again.Created)
+		assert.Equal(t, 1, again.Imported)
+		assert.Equal(t, 2, again.Duplicates)
+	})
+
+	t.Run("found by search", func(t *testing.T) {
+		page, err := storage.SearchMessages(ctx, domain.SearchQuery{UserId: 2, ChatId: res.ChatId, Text: "привет", Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, page, 1)
+	})
+}
@@ -0,0 +1,109 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const incomingWebhookColumns = `id, chat_id, creator_id, name, created_at`
+
+// CreateIncomingWebhook создает входящий вебхук чата с хешем секрета tokenHash. Если в чате уже limit
+// вебхуков (0 - без ограничения), возвращается ErrTooManyIncomingWebhooks
+func (s *ChatStorage) CreateIncomingWebhook(ctx context.Context, w domain.IncomingWebhook, tokenHash string, limit int) (domain.IncomingWebhook, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return domain.IncomingWebhook{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка чата сериализует создание вебхуков, иначе параллельные запросы превысят лимит
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM chats WHERE id = $1 FOR UPDATE`, w.ChatId); err != nil {
+		return domain.IncomingWebhook{}, fmt.Errorf("failed to lock chat: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM incoming_webhooks WHERE chat_id = $1`, w.ChatId).Scan(&count); err != nil {
+		return domain.IncomingWebhook{}, fmt.Errorf("failed to count incoming webhooks: %w", err)
+	}
+	if limit > 0 && count >= limit {
+		return domain.IncomingWebhook{}, domain.ErrTooManyIncomingWebhooks
+	}
+
+	created, err := scanIncomingWebhook(tx.QueryRow(ctx, `
+		INSERT INTO incoming_webhooks (chat_id, creator_id, name, token_hash)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+incomingWebhookColumns, w.ChatId, w.CreatorId, w.Name, tokenHash))
+	if err != nil {
+		return domain.IncomingWebhook{}, fmt.Errorf("failed to create incoming webhook: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.IncomingWebhook{}, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetIncomingWebhooks возвращает вебхуки чата в порядке создания
+func (s *ChatStorage) GetIncomingWebhooks(ctx context.Context, chatID int) ([]domain.IncomingWebhook, error) {
+	query := `SELECT ` + incomingWebhookColumns + ` FROM incoming_webhooks WHERE chat_id = $1 ORDER BY id`
+
+	rows, err := s.db.Query(ctx, query, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incoming webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []domain.IncomingWebhook{}
+	for rows.Next() {
+		w, err := scanIncomingWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incoming webhook: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// GetIncomingWebhookByToken находит вебхук по хешу секрета из его адреса
+func (s *ChatStorage) GetIncomingWebhookByToken(ctx context.Context, tokenHash string) (domain.IncomingWebhook, error) {
+	query := `SELECT ` + incomingWebhookColumns + ` FROM incoming_webhooks WHERE token_hash = $1`
+
+	w, err := scanIncomingWebhook(s.db.QueryRow(ctx, query, tokenHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.IncomingWebhook{}, domain.ErrIncomingWebhookNotFound
+	}
+	if err != nil {
+		return domain.IncomingWebhook{}, fmt.Errorf("failed to get incoming webhook: %w", err)
+	}
+
+	return w, nil
+}
+
+// DeleteIncomingWebhook отзывает вебхук чата. Отправленные им сообщения остаются с его именем
+func (s *ChatStorage) DeleteIncomingWebhook(ctx context.Context, chatID, webhookID int) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM incoming_webhooks WHERE id = $1 AND chat_id = $2`, webhookID, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to delete incoming webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrIncomingWebhookNotFound
+	}
+
+	return nil
+}
+
+func scanIncomingWebhook(row pgx.Row) (domain.IncomingWebhook, error) {
+	var w domain.IncomingWebhook
+	err := row.Scan(&w.Id, &w.ChatId, &w.CreatorId, &w.Name, &w.CreatedAt)
+	return w, err
+}
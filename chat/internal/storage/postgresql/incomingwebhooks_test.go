@@ -0,0 +1,87 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncomingWebhooks(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+
+	hash := strings.Repeat("a", 64)
+	w, err := storage.CreateIncomingWebhook(ctx, domain.IncomingWebhook{ChatId: chatID, CreatorId: 1, Name: "CI"}, hash, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "CI", w.Name)
+	assert.Equal(t, 1, w.CreatorId)
+
+	t.Run("limit per chat", func(t *testing.T) {
+		_, err := storage.CreateIncomingWebhook(ctx, domain.IncomingWebhook{ChatId: chatID, CreatorId: 2, Name: "Alerts"}, strings.Repeat("b", 64), 2)
+		require.NoError(t, err)
+		_, err = storage.CreateIncomingWebhook(ctx, domain.IncomingWebhook{ChatId: chatID, CreatorId: 2, Name: "Extra"}, strings.Repeat("c", 64), 2)
+		assert.ErrorIs(t, err, domain.ErrTooManyIncomingWebhooks)
+
+		webhooks, err := storage.GetIncomingWebhooks(ctx, chatID)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+		assert.Equal(t, "CI", webhooks[0].Name)
+		assert.Equal(t, "Alerts", webhooks[1].Name)
+	})
+
+	t.Run("find by token hash", func(t *testing.T) {
+		found, err := storage.GetIncomingWebhookByToken(ctx, hash)
+		require.NoError(t, err)
+		assert.Equal(t, w.Id, found.Id)
+		assert.Equal(t, chatID, found.ChatId)
+
+		_, err = storage.GetIncomingWebhookByToken(ctx, strings.Repeat("f", 64))
+		assert.ErrorIs(t, err, domain.ErrIncomingWebhookNotFound)
+	})
+
+	t.Run("message keeps webhook name after revocation", func(t *testing.T) {
+		id, _, err := storage.SendMessage(ctx, domain.NewMessage{
+			ChatId:            chatID,
+			SenderId:          1,
+			Text:              "сборка упала",
+			IncomingWebhookId: w.Id,
+			WebhookName:       "CI #1024",
+		})
+		require.NoError(t, err)
+
+		msg, err := storage.GetMessage(ctx, chatID, id)
+		require.NoError(t, err)
+		require.NotNil(t, msg.Webhook)
+		require.NotNil(t, msg.Webhook.Id)
+		assert.Equal(t, w.Id, *msg.Webhook.Id)
+		assert.Equal(t, "CI #1024", msg.Webhook.Name)
+
+		require.NoError(t, storage.DeleteIncomingWebhook(ctx, chatID, w.Id))
+		assert.ErrorIs(t, storage.DeleteIncomingWebhook(ctx, chatID, w.Id), domain.ErrIncomingWebhookNotFound)
+
+		messages, err := storage.GetMessages(ctx, chatID, 2, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		require.NotNil(t, messages[0].Webhook)
+		assert.Nil(t, messages[0].Webhook.Id)
+		assert.Equal(t, "CI #1024", messages[0].Webhook.Name)
+	})
+
+	t.Run("ordinary message has no webhook", func(t *testing.T) {
+		id, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 2, Text: "смотрю"})
+		require.NoError(t, err)
+
+		msg, err := storage.GetMessage(ctx, chatID, id)
+		require.NoError(t, err)
+		assert.Nil(t, msg.Webhook)
+	})
+}
@@ -0,0 +1,172 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const linkPreviewColumns = `url, title, description, image_url, site_name, fetched_at`
+
+// GetLinkPreview возвращает карточку ссылки из кэша. found = false, если url еще не загружался
+func (s *ChatStorage) GetLinkPreview(ctx context.Context, url string) (domain.LinkPreview, bool, error) {
+	query := `SELECT ` + linkPreviewColumns + ` FROM link_previews WHERE url = $1`
+
+	p, err := scanLinkPreview(s.db.QueryRow(ctx, query, url))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.LinkPreview{}, false, nil
+	}
+	if err != nil {
+		return domain.LinkPreview{}, false, fmt.Errorf("failed to get link preview: %w", err)
+	}
+
+	return p, true, nil
+}
+
+// SaveLinkPreview кладет карточку в кэш или обновляет устаревшую
+func (s *ChatStorage) SaveLinkPreview(ctx context.Context, p domain.LinkPreview) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO link_previews (url, title, description, image_url, site_name, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (url) DO UPDATE
+		SET title = EXCLUDED.title,
+		    description = EXCLUDED.description,
+		    image_url = EXCLUDED.image_url,
+		    site_name = EXCLUDED.site_name,
+		    fetched_at = EXCLUDED.fetched_at
+	`, p.URL, p.Title, p.Description, p.ImageURL, p.SiteName)
+	if err != nil {
+		return fmt.Errorf("failed to save link preview: %w", err)
+	}
+
+	return nil
+}
+
+// AttachLinkPreviews привязывает к сообщению карточки ссылок urls из кэша, пропуская пустые.
+// text - текст, из которого взяты ссылки: если сообщение с тех пор отредактировали или удалили,
+// ничего не меняется и возвращается пустой список
+func (s *ChatStorage) AttachLinkPreviews(ctx context.Context, chatID, messageID int, text string, urls []string) ([]domain.LinkPreview, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, `
+		SELECT text FROM messages
+		WHERE id = $1 AND chat_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, messageID, chatID).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock message: %w", err)
+	}
+	if current != text {
+		return nil, nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM message_link_previews WHERE message_id = $1`, messageID); err != nil {
+		return nil, fmt.Errorf("failed to clear link previews: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO message_link_previews (message_id, url, position)
+		SELECT $1, p.url, u.position
+		FROM UNNEST($2::text[]) WITH ORDINALITY AS u(url, position)
+		JOIN link_previews p ON p.url = u.url
+		WHERE p.title <> ''
+		ON CONFLICT (message_id, url) DO NOTHING
+	`, messageID, urls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach link previews: %w", err)
+	}
+
+	previews, err := messageLinkPreviews(ctx, tx, []int{messageID})
+	if err != nil {
+		return nil, err
+	}
+	attached := previews[messageID]
+	if len(attached) == 0 {
+		return nil, nil
+	}
+
+	payload := domain.MessageLinkPreviews{ChatId: chatID, MessageId: messageID, LinkPreviews: attached}
+	if err := recordChange(ctx, tx, chatID, domain.EventLinkPreviews, payload); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return attached, nil
+}
+
+func (s *ChatStorage) attachLinkPreviews(ctx context.Context, messages []domain.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(messages))
+	for _, m := range messages {
+		ids = append(ids, m.Id)
+	}
+
+	byMessage, err := messageLinkPreviews(ctx, s.db, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		messages[i].LinkPreviews = byMessage[messages[i].Id]
+	}
+
+	return nil
+}
+
+type rowsQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func messageLinkPreviews(ctx context.Context, q rowsQuerier, messageIDs []int) (map[int][]domain.LinkPreview, error) {
+	query := `
+		SELECT mp.message_id, p.url, p.title, p.description, p.image_url, p.site_name, p.fetched_at
+		FROM message_link_previews mp
+		JOIN link_previews p ON p.url = mp.url
+		WHERE mp.message_id = ANY($1)
+		ORDER BY mp.message_id, mp.position
+	`
+
+	rows, err := q.Query(ctx, query, messageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link previews: %w", err)
+	}
+	defer rows.Close()
+
+	byMessage := make(map[int][]domain.LinkPreview)
+	for rows.Next() {
+		var messageID int
+		var p domain.LinkPreview
+		if err := rows.Scan(&messageID, &p.URL, &p.Title, &p.Description, &p.ImageURL, &p.SiteName, &p.FetchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan link preview: %w", err)
+		}
+		byMessage[messageID] = append(byMessage[messageID], p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return byMessage, nil
+}
+
+func scanLinkPreview(row pgx.Row) (domain.LinkPreview, error) {
+	var p domain.LinkPreview
+	err := row.Scan(&p.URL, &p.Title, &p.Description, &p.ImageURL, &p.SiteName, &p.FetchedAt)
+	return p, err
+}
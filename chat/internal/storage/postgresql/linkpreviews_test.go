@@ -0,0 +1,79 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkPreviews(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+	text := "https://a.example и https://b.example"
+	messageID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 1, Text: text})
+	require.NoError(t, err)
+	urls := []string{"https://a.example", "https://b.example"}
+
+	t.Run("cache", func(t *testing.T) {
+		_, found, err := storage.GetLinkPreview(ctx, urls[0])
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		require.NoError(t, storage.SaveLinkPreview(ctx, domain.LinkPreview{URL: urls[0], Title: "old"}))
+		require.NoError(t, storage.SaveLinkPreview(ctx, domain.LinkPreview{URL: urls[0], Title: "A", SiteName: "a"}))
+		// Неудачная загрузка кэшируется пустой карточкой
+		require.NoError(t, storage.SaveLinkPreview(ctx, domain.LinkPreview{URL: urls[1]}))
+
+		p, found, err := storage.GetLinkPreview(ctx, urls[0])
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "A", p.Title)
+		assert.Equal(t, "a", p.SiteName)
+		assert.False(t, p.FetchedAt.IsZero())
+	})
+
+	t.Run("attach", func(t *testing.T) {
+		cursor, err := storage.GetSyncCursor(ctx, 2)
+		require.NoError(t, err)
+
+		previews, err := storage.AttachLinkPreviews(ctx, chatID, messageID, text, urls)
+		require.NoError(t, err)
+		require.Len(t, previews, 1)
+		assert.Equal(t, urls[0], previews[0].URL)
+
+		messages, err := storage.GetMessages(ctx, chatID, 2, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		require.Len(t, messages[0].LinkPreviews, 1)
+		assert.Equal(t, "A", messages[0].LinkPreviews[0].Title)
+
+		batch, err := storage.GetChanges(ctx, 2, cursor, 10)
+		require.NoError(t, err)
+		require.Len(t, batch.Changes, 1)
+		assert.Equal(t, domain.EventLinkPreviews, batch.Changes[0].Type)
+	})
+
+	t.Run("stale text", func(t *testing.T) {
+		previews, err := storage.AttachLinkPreviews(ctx, chatID, messageID, "другой текст", urls)
+		require.NoError(t, err)
+		assert.Empty(t, previews)
+	})
+
+	t.Run("edit clears previews", func(t *testing.T) {
+		_, err := storage.EditMessage(ctx, chatID, messageID, domain.TextEdit{Text: "без ссылок"})
+		require.NoError(t, err)
+
+		messages, err := storage.GetMessages(ctx, chatID, 2, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Empty(t, messages[0].LinkPreviews)
+	})
+}
@@ -0,0 +1,76 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// entitiesValue сохраняет отсутствие разметки как NULL, а не как пустой JSON-массив
+func entitiesValue(entities []domain.MessageEntity) any {
+	if len(entities) == 0 {
+		return nil
+	}
+	return entities
+}
+
+// saveMentions отмечает упомянутых в сообщении пользователей
+func saveMentions(ctx context.Context, tx pgx.Tx, chatID, messageID int, userIDs []int) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO message_mentions (message_id, chat_id, user_id)
+		SELECT $1, $2, unnest($3::bigint[])
+		ON CONFLICT (message_id, user_id) DO NOTHING
+	`, messageID, chatID, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to save mentions: %w", err)
+	}
+
+	return nil
+}
+
+// GetUnreadCounts считает по чатам userID чужие сообщения после его указателя прочитанного и упоминания
+// среди них. Удаленные, истекшие и скрытые пользователем у себя сообщения не учитываются
+func (s *ChatStorage) GetUnreadCounts(ctx context.Context, userID int) ([]domain.UnreadCount, error) {
+	query := `
+		SELECT c.id, COUNT(m.id), COUNT(mm.message_id)
+		FROM chats c
+		JOIN messages m ON m.chat_id = c.id
+		LEFT JOIN chat_reads r ON r.chat_id = c.id AND r.user_id = $1
+		LEFT JOIN message_mentions mm ON mm.message_id = m.id AND mm.user_id = $1
+		WHERE (c.user_1_id = $1 OR c.user_2_id = $1)
+		  AND m.sender_id <> $1
+		  AND m.id > COALESCE(r.last_read_message_id, 0)
+		  AND m.deleted_at IS NULL
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		  AND NOT EXISTS (SELECT 1 FROM message_deletions d WHERE d.message_id = m.id AND d.user_id = $1)
+		GROUP BY c.id
+		ORDER BY c.id
+	`
+
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []domain.UnreadCount
+	for rows.Next() {
+		var c domain.UnreadCount
+		if err := rows.Scan(&c.ChatId, &c.Unread, &c.Mentions); err != nil {
+			return nil, fmt.Errorf("failed to scan unread count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,75 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionsAndUnreadCounts(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+
+	mention := []domain.MessageEntity{{Type: domain.EntityMention, Offset: 0, Length: 3, UserId: 2}}
+	first, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 1, Text: "Боб, привет", Entities: mention, MentionIds: []int{2}})
+	require.NoError(t, err)
+	second, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 1, Text: "как дела?"})
+	require.NoError(t, err)
+	_, _, err = storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 2, Text: "свое не считается"})
+	require.NoError(t, err)
+
+	t.Run("entities and mentioned", func(t *testing.T) {
+		messages, err := storage.GetMessages(ctx, chatID, 2, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 3)
+		assert.Equal(t, mention, messages[0].Entities)
+		assert.True(t, messages[0].Mentioned)
+		assert.False(t, messages[1].Mentioned)
+
+		// Для отправителя сообщение не помечено упоминанием
+		messages, err = storage.GetMessages(ctx, chatID, 1, 10, 0)
+		require.NoError(t, err)
+		assert.False(t, messages[0].Mentioned)
+	})
+
+	t.Run("unread", func(t *testing.T) {
+		counts, err := storage.GetUnreadCounts(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.UnreadCount{{ChatId: chatID, Unread: 2, Mentions: 1}}, counts)
+
+		_, _, err = storage.MarkRead(ctx, chatID, 2, first)
+		require.NoError(t, err)
+		counts, err = storage.GetUnreadCounts(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.UnreadCount{{ChatId: chatID, Unread: 1, Mentions: 0}}, counts)
+	})
+
+	t.Run("edit replaces mentions", func(t *testing.T) {
+		_, err := storage.EditMessage(ctx, chatID, second, domain.TextEdit{Text: "Боб, как дела?", Entities: mention, MentionIds: []int{2}})
+		require.NoError(t, err)
+		counts, err := storage.GetUnreadCounts(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.UnreadCount{{ChatId: chatID, Unread: 1, Mentions: 1}}, counts)
+
+		edits, err := storage.GetMessageEdits(ctx, second)
+		require.NoError(t, err)
+		require.Len(t, edits, 1)
+		assert.Empty(t, edits[0].Entities)
+	})
+
+	t.Run("delete clears mentions", func(t *testing.T) {
+		_, err := storage.DeleteMessage(ctx, chatID, second)
+		require.NoError(t, err)
+		counts, err := storage.GetUnreadCounts(ctx, 2)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+}
@@ -0,0 +1,131 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const pinnedColumns = `chat_id, message_id, pinned_by, pinned_at`
+
+// PinMessage закрепляет сообщение в чате. Если в чате уже limit закрепленных сообщений (0 - без ограничения),
+// возвращается ErrTooManyPinned. Повторное закрепление возвращает прежнюю запись с created = false
+func (s *ChatStorage) PinMessage(ctx context.Context, pin domain.PinnedMessage, limit int) (domain.PinnedMessage, bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка чата сериализует закрепления, иначе параллельные запросы превысят лимит
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM chats WHERE id = $1 FOR UPDATE`, pin.ChatId); err != nil {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to lock chat: %w", err)
+	}
+
+	existing, err := scanPinned(tx.QueryRow(ctx, `
+		SELECT `+pinnedColumns+` FROM pinned_messages WHERE chat_id = $1 AND message_id = $2
+	`, pin.ChatId, pin.MessageId))
+	if err == nil {
+		return existing, false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to get pinned message: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM pinned_messages WHERE chat_id = $1`, pin.ChatId).Scan(&count); err != nil {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to count pinned messages: %w", err)
+	}
+	if limit > 0 && count >= limit {
+		return domain.PinnedMessage{}, false, domain.ErrTooManyPinned
+	}
+
+	created, err := scanPinned(tx.QueryRow(ctx, `
+		INSERT INTO pinned_messages (chat_id, message_id, pinned_by)
+		VALUES ($1, $2, $3)
+		RETURNING `+pinnedColumns, pin.ChatId, pin.MessageId, pin.PinnedBy))
+	if err != nil {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to pin message: %w", err)
+	}
+	if err := recordChange(ctx, tx, pin.ChatId, domain.EventMessagePinned, created); err != nil {
+		return domain.PinnedMessage{}, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return created, true, nil
+}
+
+// UnpinMessage открепляет сообщение и возвращает снятую запись. removed = false, если сообщение не было закреплено
+func (s *ChatStorage) UnpinMessage(ctx context.Context, chatID, messageID int) (domain.PinnedMessage, bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	removed, err := scanPinned(tx.QueryRow(ctx, `
+		DELETE FROM pinned_messages WHERE chat_id = $1 AND message_id = $2
+		RETURNING `+pinnedColumns, chatID, messageID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.PinnedMessage{}, false, nil
+	}
+	if err != nil {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to unpin message: %w", err)
+	}
+	if err := recordChange(ctx, tx, chatID, domain.EventMessageUnpinned, removed); err != nil {
+		return domain.PinnedMessage{}, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.PinnedMessage{}, false, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return removed, true, nil
+}
+
+// GetPinnedMessages возвращает закрепленные сообщения чата, начиная с последнего закрепленного.
+// Истекшие и удаленные userID у себя сообщения пропускаются
+func (s *ChatStorage) GetPinnedMessages(ctx context.Context, chatID, userID int) ([]domain.PinnedMessage, error) {
+	query := `
+		SELECT p.chat_id, p.message_id, p.pinned_by, p.pinned_at
+		FROM pinned_messages p
+		JOIN messages m ON m.id = p.message_id
+		WHERE p.chat_id = $1
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		  AND NOT EXISTS (SELECT 1 FROM message_deletions d WHERE d.message_id = m.id AND d.user_id = $2)
+		ORDER BY p.pinned_at DESC, p.message_id DESC
+	`
+
+	rows, err := s.db.Query(ctx, query, chatID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned messages: %w", err)
+	}
+	defer rows.Close()
+
+	pins := []domain.PinnedMessage{}
+	for rows.Next() {
+		p, err := scanPinned(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pinned message: %w", err)
+		}
+		pins = append(pins, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return pins, nil
+}
+
+func scanPinned(row pgx.Row) (domain.PinnedMessage, error) {
+	var p domain.PinnedMessage
+	err := row.Scan(&p.ChatId, &p.MessageId, &p.PinnedBy, &p.PinnedAt)
+	return p, err
+}
@@ -0,0 +1,77 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedMessages(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+
+	var ids []int
+	for _, text := range []string{"первое", "второе", "третье"} {
+		id, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 1, Text: text})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	t.Run("pin with limit", func(t *testing.T) {
+		pin, created, err := storage.PinMessage(ctx, domain.PinnedMessage{ChatId: chatID, MessageId: ids[0], PinnedBy: 1}, 2)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, 1, pin.PinnedBy)
+
+		again, created, err := storage.PinMessage(ctx, domain.PinnedMessage{ChatId: chatID, MessageId: ids[0], PinnedBy: 2}, 2)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, 1, again.PinnedBy)
+
+		_, _, err = storage.PinMessage(ctx, domain.PinnedMessage{ChatId: chatID, MessageId: ids[1], PinnedBy: 2}, 2)
+		require.NoError(t, err)
+		_, _, err = storage.PinMessage(ctx, domain.PinnedMessage{ChatId: chatID, MessageId: ids[2], PinnedBy: 2}, 2)
+		assert.ErrorIs(t, err, domain.ErrTooManyPinned)
+	})
+
+	t.Run("list newest first", func(t *testing.T) {
+		pins, err := storage.GetPinnedMessages(ctx, chatID, 2)
+		require.NoError(t, err)
+		require.Len(t, pins, 2)
+		assert.Equal(t, ids[1], pins[0].MessageId)
+		assert.Equal(t, ids[0], pins[1].MessageId)
+	})
+
+	t.Run("deleted message is unpinned", func(t *testing.T) {
+		_, err := storage.DeleteMessage(ctx, chatID, ids[1])
+		require.NoError(t, err)
+
+		pins, err := storage.GetPinnedMessages(ctx, chatID, 1)
+		require.NoError(t, err)
+		require.Len(t, pins, 1)
+		assert.Equal(t, ids[0], pins[0].MessageId)
+	})
+
+	t.Run("unpin", func(t *testing.T) {
+		removed, ok, err := storage.UnpinMessage(ctx, chatID, ids[0])
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, ids[0], removed.MessageId)
+
+		_, ok, err = storage.UnpinMessage(ctx, chatID, ids[0])
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		pins, err := storage.GetPinnedMessages(ctx, chatID, 1)
+		require.NoError(t, err)
+		assert.Empty(t, pins)
+	})
+}
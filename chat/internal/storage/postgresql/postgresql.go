@@ -4,7 +4,11 @@ import (
 	"chat/internal/domain"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,11 +21,13 @@ func New(db *pgxpool.Pool) *ChatStorage {
 }
 
 func (s *ChatStorage) GetUserChats(ctx context.Context, userID int) ([]int, error) {
+	// Сначала закрепленные чаты (последний закрепленный выше), архивные - в конце списка
 	query := `
-        SELECT id
-        FROM chats
-        WHERE user_1_id = $1 OR user_2_id = $1
-        ORDER BY id`
+        SELECT c.id
+        FROM chats c
+        LEFT JOIN chat_settings cs ON cs.chat_id = c.id AND cs.user_id = $1
+        WHERE c.user_1_id = $1 OR c.user_2_id = $1
+        ORDER BY COALESCE(cs.archived, FALSE), cs.pinned_at DESC NULLS LAST, c.id`
 	rows, err := s.db.Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chats: %w", err)
@@ -55,41 +61,157 @@ func (s *ChatStorage) CreateChat(ctx context.Context, userid1, userid2 int) (int
         RETURNING id
     `
 
-	err := s.db.QueryRow(ctx, query, userid1, userid2).Scan(&chatID)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, query, userid1, userid2).Scan(&chatID)
 	if err != nil {
 		return 0, err
 	}
 
+	members := domain.ChatMembers{ChatId: chatID, UserIds: []int{userid1, userid2}}
+	if err := recordChange(ctx, tx, chatID, domain.EventChatCreated, members, userid1, userid2); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
 	return chatID, nil
 }
 
-func (s *ChatStorage) SendMessage(ctx context.Context, userId, chatId int, text string) (int, error) {
+// SendMessage сохраняет сообщение и привязывает к нему вложения msg.AttachmentIds.
+// Вложения должны быть загружены отправителем в этот же чат и еще не отправлены.
+// Если у чата задан срок жизни сообщений, сообщение получает expires_at.
+// Упомянутые msg.MentionIds сохраняются в message_mentions.
+// Повторная отправка с тем же ClientMessageId возвращает id уже сохраненного сообщения и created = false
+func (s *ChatStorage) SendMessage(ctx context.Context, msg domain.NewMessage) (int, bool, error) {
 	var messId int
 
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-        INSERT INTO messages (chat_id, sender_id, text) 
-        VALUES ($1, $2, $3)
+        INSERT INTO messages (chat_id, sender_id, text, reply_to_message_id, forwarded_from_sender_id, forwarded_from_message_id, client_message_id, envelope, entities, expires_at,
+                              incoming_webhook_id, webhook_name) 
+        VALUES ($1, $2, $3, NULLIF($4, 0), NULLIF($5, 0), NULLIF($6, 0), NULLIF($7, '')::uuid, $8, $9,
+                (SELECT NOW() + make_interval(secs => message_ttl) FROM chats WHERE id = $1),
+                NULLIF($10, 0), NULLIF($11, ''))
+        ON CONFLICT (chat_id, sender_id, client_message_id) DO NOTHING
         RETURNING id
     `
 
-	err := s.db.QueryRow(ctx, query, chatId, userId, text).Scan(&messId)
+	err = tx.QueryRow(ctx, query,
+		msg.ChatId,
+		msg.SenderId,
+		msg.Text,
+		msg.ReplyToId,
+		msg.ForwardedFromSenderId,
+		msg.ForwardedFromMessageId,
+		msg.ClientMessageId,
+		msg.Envelope,
+		entitiesValue(msg.Entities),
+		msg.IncomingWebhookId,
+		msg.WebhookName,
+	).Scan(&messId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		messId, err = s.GetMessageIdByClientId(ctx, msg.ChatId, msg.SenderId, msg.ClientMessageId)
+		return messId, false, err
+	}
 	if err != nil {
-		return 0, err
+		return 0, false, err
+	}
+
+	if len(msg.AttachmentIds) > 0 {
+		tag, err := tx.Exec(ctx, `
+			UPDATE attachments
+			SET message_id = $1
+			WHERE id = ANY($2) AND chat_id = $3 AND uploader_id = $4 AND message_id IS NULL
+		`, messId, msg.AttachmentIds, msg.ChatId, msg.SenderId)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to link attachments: %w", err)
+		}
+		if tag.RowsAffected() != int64(len(msg.AttachmentIds)) {
+			return 0, false, domain.ErrAttachmentNotFound
+		}
+	}
+
+	if err := saveMentions(ctx, tx, msg.ChatId, messId, msg.MentionIds); err != nil {
+		return 0, false, err
+	}
+
+	created, err := getMessage(ctx, tx, msg.ChatId, messId)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get created message: %w", err)
+	}
+	if err := recordChange(ctx, tx, msg.ChatId, domain.EventMessageCreated, created); err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, false, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return messId, true, nil
+}
+
+// GetMessageIdByClientId возвращает id сообщения senderID, уже отправленного в чат с clientID
+func (s *ChatStorage) GetMessageIdByClientId(ctx context.Context, chatID, senderID int, clientID string) (int, error) {
+	query := `
+		SELECT id FROM messages
+		WHERE chat_id = $1 AND sender_id = $2 AND client_message_id = $3::uuid
+	`
+
+	var messId int
+	err := s.db.QueryRow(ctx, query, chatID, senderID, clientID).Scan(&messId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, domain.ErrMessageNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get message by client id: %w", err)
 	}
 
 	return messId, nil
 }
 
-func (s *ChatStorage) GetMessages(ctx context.Context, chatID int, limit, offset int) ([]domain.Message, error) {
+func (s *ChatStorage) GetMessages(ctx context.Context, chatID, userID int, limit, offset int) ([]domain.Message, error) {
+	// Свои сообщения прочитаны, если их прочитал собеседник, чужие - если их прочитал userID.
+	// mentioned - сообщение упоминает userID. Удаленные у всех сообщения возвращаются без текста, удаленные только у userID и истекшие, но еще
+	// не удаленные очисткой - не возвращаются
 	query := `
-		SELECT sender_id,text, created_at, is_read 
-		FROM messages 
-		WHERE chat_id = $1 
-		ORDER BY created_at ASC 
-		LIMIT $2 OFFSET $3
+		SELECT m.id, m.sender_id, m.text, m.created_at,
+		       m.id <= COALESCE(r.last_read_message_id, 0) AS is_read,
+		       m.edited_at, m.deleted_at, m.expires_at, m.hidden_at,
+		       m.forwarded_from_sender_id, m.forwarded_from_message_id, m.envelope, m.entities,
+		       EXISTS (SELECT 1 FROM message_mentions mm WHERE mm.message_id = m.id AND mm.user_id = $2) AS mentioned,
+		       m.incoming_webhook_id, m.webhook_name,
+		       rm.id, rm.sender_id,
+		       CASE WHEN rm.hidden_at IS NULL OR rm.sender_id = $2 THEN LEFT(rm.text, $5) ELSE '' END,
+		       rm.deleted_at
+		FROM messages m
+		JOIN chats c ON c.id = m.chat_id
+		LEFT JOIN messages rm ON rm.id = m.reply_to_message_id
+		LEFT JOIN chat_reads r ON r.chat_id = m.chat_id
+			AND r.user_id = CASE
+				WHEN m.sender_id <> $2 THEN $2
+				WHEN c.user_1_id = $2 THEN c.user_2_id
+				ELSE c.user_1_id
+			END
+		WHERE m.chat_id = $1
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		  AND NOT EXISTS (SELECT 1 FROM message_deletions d WHERE d.message_id = m.id AND d.user_id = $2)
+		ORDER BY m.created_at ASC, m.id ASC
+		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := s.db.Query(ctx, query, chatID, limit, offset)
+	rows, err := s.db.Query(ctx, query, chatID, userID, limit, offset, previewLength)
 	if err != nil {
 		return nil, err
 	}
@@ -97,8 +219,7 @@ func (s *ChatStorage) GetMessages(ctx context.Context, chatID int, limit, offset
 
 	var messages []domain.Message
 	for rows.Next() {
-		var msg domain.Message
-		err := rows.Scan(&msg.SenderId, &msg.Text, &msg.CreatedAt, &msg.IsRead)
+		msg, err := scanMessage(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -109,5 +230,377 @@ func (s *ChatStorage) GetMessages(ctx context.Context, chatID int, limit, offset
 		return nil, err
 	}
 
+	if err := s.attachReactions(ctx, messages, userID); err != nil {
+		return nil, err
+	}
+	if err := s.attachAttachments(ctx, messages); err != nil {
+		return nil, err
+	}
+	if err := s.attachLinkPreviews(ctx, messages); err != nil {
+		return nil, err
+	}
+
 	return messages, nil
 }
+
+func (s *ChatStorage) GetMessage(ctx context.Context, chatID, messageID int) (domain.Message, error) {
+	msg, err := getMessage(ctx, s.db, chatID, messageID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Message{}, domain.ErrMessageNotFound
+	}
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	return msg, nil
+}
+
+// EditMessage сохраняет текущий текст с разметкой в историю правок и заменяет его новым.
+// Упоминания сообщения заменяются edit.MentionIds
+func (s *ChatStorage) EditMessage(ctx context.Context, chatID, messageID int, edit domain.TextEdit) (domain.Message, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// В историю попадает прежний текст с моментом, когда эта версия появилась
+	var oldText string
+	var oldEntities []domain.MessageEntity
+	var versionAt time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT text, entities, COALESCE(edited_at, created_at) FROM messages
+		WHERE id = $1 AND chat_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, messageID, chatID).Scan(&oldText, &oldEntities, &versionAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Message{}, domain.ErrMessageDeleted
+	}
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to lock message: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO message_edits (message_id, text, entities, edited_at)
+		VALUES ($1, $2, $3, $4)
+	`, messageID, oldText, entitiesValue(oldEntities), versionAt)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to save edit history: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE messages
+		SET text = $2, entities = $3, edited_at = NOW()
+		WHERE id = $1
+	`, messageID, edit.Text, entitiesValue(edit.Entities))
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to edit message: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM message_mentions WHERE message_id = $1`, messageID); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to clear mentions: %w", err)
+	}
+	if err := saveMentions(ctx, tx, chatID, messageID, edit.MentionIds); err != nil {
+		return domain.Message{}, err
+	}
+	// Карточки ссылок прежнего текста больше не актуальны, для нового их заново соберет linkpreview
+	if _, err := tx.Exec(ctx, `DELETE FROM message_link_previews WHERE message_id = $1`, messageID); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to clear link previews: %w", err)
+	}
+
+	msg, err := getMessage(ctx, tx, chatID, messageID)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to get edited message: %w", err)
+	}
+	if err := recordChange(ctx, tx, chatID, domain.EventMessageEdited, msg); err != nil {
+		return domain.Message{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return msg, nil
+}
+
+// DeleteMessage удаляет сообщение у всех: текст, история правок и реакции стираются, остается "надгробие"
+func (s *ChatStorage) DeleteMessage(ctx context.Context, chatID, messageID int) (domain.Message, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE messages
+		SET text = '', envelope = NULL, entities = NULL, deleted_at = NOW()
+		WHERE id = $1 AND chat_id = $2 AND deleted_at IS NULL
+	`, messageID, chatID)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to delete message: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.Message{}, domain.ErrMessageDeleted
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM message_edits WHERE message_id = $1`, messageID); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to delete edit history: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM message_reactions WHERE message_id = $1`, messageID); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to delete reactions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM message_link_previews WHERE message_id = $1`, messageID); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to delete link previews: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM message_mentions WHERE message_id = $1`, messageID); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to delete mentions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM pinned_messages WHERE message_id = $1`, messageID); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to unpin message: %w", err)
+	}
+
+	msg, err := getMessage(ctx, tx, chatID, messageID)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("failed to get deleted message: %w", err)
+	}
+	if err := recordChange(ctx, tx, chatID, domain.EventMessageDeleted, msg); err != nil {
+		return domain.Message{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Message{}, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return msg, nil
+}
+
+// HideMessage удаляет сообщение только у userID
+func (s *ChatStorage) HideMessage(ctx context.Context, chatID, messageID, userID int) error {
+	query := `
+		INSERT INTO message_deletions (message_id, user_id)
+		SELECT id, $3 FROM messages WHERE id = $1 AND chat_id = $2
+		ON CONFLICT (message_id, user_id) DO NOTHING
+	`
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, query, messageID, chatID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to hide message: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		// Сообщение уже скрыто или его нет в чате
+		_, err := s.GetMessage(ctx, chatID, messageID)
+		return err
+	}
+
+	if err := recordChange(ctx, tx, chatID, domain.EventMessageDeleted, domain.Message{Id: messageID}, userID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ChatStorage) GetMessageEdits(ctx context.Context, messageID int) ([]domain.MessageEdit, error) {
+	query := `
+		SELECT text, entities, edited_at
+		FROM message_edits
+		WHERE message_id = $1
+		ORDER BY edited_at ASC, id ASC
+	`
+
+	rows, err := s.db.Query(ctx, query, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message edits: %w", err)
+	}
+	defer rows.Close()
+
+	var edits []domain.MessageEdit
+	for rows.Next() {
+		var edit domain.MessageEdit
+		if err := rows.Scan(&edit.Text, &edit.Entities, &edit.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message edit: %w", err)
+		}
+		edits = append(edits, edit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return edits, nil
+}
+
+func (s *ChatStorage) IsChatMember(ctx context.Context, chatID, userID int) (bool, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM chats WHERE id = $1 AND (user_1_id = $2 OR user_2_id = $2))
+	`
+
+	var isMember bool
+	if err := s.db.QueryRow(ctx, query, chatID, userID).Scan(&isMember); err != nil {
+		return false, fmt.Errorf("failed to check chat member: %w", err)
+	}
+
+	return isMember, nil
+}
+
+func (s *ChatStorage) GetChatMembers(ctx context.Context, chatID int) ([]int, error) {
+	query := `
+		SELECT user_1_id, user_2_id
+		FROM chats
+		WHERE id = $1
+	`
+
+	var user1, user2 int
+	err := s.db.QueryRow(ctx, query, chatID).Scan(&user1, &user2)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotChatMember
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat members: %w", err)
+	}
+
+	return []int{user1, user2}, nil
+}
+
+// MarkRead двигает указатель прочитанного userID вперед. moved - указатель сдвинулся этим вызовом
+func (s *ChatStorage) MarkRead(ctx context.Context, chatID, userID, messageID int) (domain.ReadReceipt, bool, error) {
+	receipt := domain.ReadReceipt{ChatId: chatID, UserId: userID}
+
+	isMember, err := s.IsChatMember(ctx, chatID, userID)
+	if err != nil {
+		return receipt, false, err
+	}
+	if !isMember {
+		return receipt, false, domain.ErrNotChatMember
+	}
+
+	if messageID == 0 {
+		err = s.db.QueryRow(ctx, `
+			SELECT COALESCE(MAX(id), 0) FROM messages WHERE chat_id = $1
+		`, chatID).Scan(&messageID)
+		if err != nil {
+			return receipt, false, fmt.Errorf("failed to get last message: %w", err)
+		}
+	} else {
+		var exists bool
+		err = s.db.QueryRow(ctx, `
+			SELECT EXISTS(SELECT 1 FROM messages WHERE id = $1 AND chat_id = $2)
+		`, messageID, chatID).Scan(&exists)
+		if err != nil {
+			return receipt, false, fmt.Errorf("failed to check message: %w", err)
+		}
+		if !exists {
+			return receipt, false, domain.ErrMessageNotFound
+		}
+	}
+
+	// Указатель только двигается вперед: повторная отметка более старого сообщения ничего не меняет
+	query := `
+		INSERT INTO chat_reads (chat_id, user_id, last_read_message_id, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (chat_id, user_id) DO UPDATE
+		SET last_read_message_id = GREATEST(chat_reads.last_read_message_id, EXCLUDED.last_read_message_id),
+		    updated_at = CASE
+				WHEN EXCLUDED.last_read_message_id > chat_reads.last_read_message_id THEN NOW()
+				ELSE chat_reads.updated_at
+			END
+		RETURNING last_read_message_id, updated_at, updated_at = NOW()
+	`
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return receipt, false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// NOW() постоянен внутри транзакции, поэтому совпадение означает, что указатель сдвинулся этим запросом
+	var moved bool
+	err = tx.QueryRow(ctx, query, chatID, userID, messageID).Scan(&receipt.LastReadMessageId, &receipt.ReadAt, &moved)
+	if err != nil {
+		return receipt, false, fmt.Errorf("failed to mark read: %w", err)
+	}
+
+	if moved {
+		if err := recordChange(ctx, tx, chatID, domain.EventRead, receipt); err != nil {
+			return receipt, false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return receipt, false, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return receipt, moved, nil
+}
+
+// previewLength - сколько символов текста исходного сообщения попадает в превью ответа
+const previewLength = 100
+
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// getMessage читает одно сообщение вне контекста конкретного зрителя, поэтому is_read и mentioned всегда false
+func getMessage(ctx context.Context, q querier, chatID, messageID int) (domain.Message, error) {
+	query := `
+		SELECT m.id, m.sender_id, m.text, m.created_at, FALSE,
+		       m.edited_at, m.deleted_at, m.expires_at, m.hidden_at,
+		       m.forwarded_from_sender_id, m.forwarded_from_message_id, m.envelope, m.entities, FALSE,
+		       m.incoming_webhook_id, m.webhook_name,
+		       rm.id, rm.sender_id, CASE WHEN rm.hidden_at IS NULL THEN LEFT(rm.text, $3) ELSE '' END, rm.deleted_at
+		FROM messages m
+		LEFT JOIN messages rm ON rm.id = m.reply_to_message_id
+		WHERE m.id = $1 AND m.chat_id = $2
+	`
+
+	return scanMessage(q.QueryRow(ctx, query, messageID, chatID, previewLength))
+}
+
+// scanMessage читает колонки id, sender_id, text, created_at, is_read, edited_at, deleted_at, expires_at, hidden_at,
+// forwarded_from_sender_id, forwarded_from_message_id, envelope, entities, mentioned, incoming_webhook_id, webhook_name
+// и id, sender_id, text, deleted_at сообщения-ответа
+func scanMessage(row pgx.Row) (domain.Message, error) {
+	var msg domain.Message
+	var fwdSenderId *string
+	var fwdMessageId *int
+	var webhookId *int
+	var webhookName *string
+	var replyId *int
+	var replySenderId, replyText *string
+	var replyDeletedAt *time.Time
+
+	err := row.Scan(
+		&msg.Id, &msg.SenderId, &msg.Text, &msg.CreatedAt, &msg.IsRead, &msg.EditedAt, &msg.DeletedAt, &msg.ExpiresAt, &msg.HiddenAt,
+		&fwdSenderId, &fwdMessageId, &msg.Envelope, &msg.Entities, &msg.Mentioned,
+		&webhookId, &webhookName,
+		&replyId, &replySenderId, &replyText, &replyDeletedAt,
+	)
+	if err != nil {
+		return msg, err
+	}
+
+	if fwdSenderId != nil {
+		msg.ForwardedFrom = &domain.ForwardInfo{SenderId: *fwdSenderId, MessageId: fwdMessageId}
+	}
+	if webhookName != nil {
+		msg.Webhook = &domain.WebhookSender{Id: webhookId, Name: *webhookName}
+	}
+	if replyId != nil {
+		msg.ReplyTo = &domain.MessagePreview{
+			Id:       *replyId,
+			SenderId: *replySenderId,
+			Text:     *replyText,
+			Deleted:  replyDeletedAt != nil,
+		}
+	}
+
+	return msg, nil
+}
@@ -1,6 +1,7 @@
 package postgresql_test
 
 import (
+	"chat/internal/domain"
 	"chat/internal/storage/postgresql"
 	"context"
 	"database/sql"
@@ -44,17 +45,277 @@ func setupTestDB(t *testing.T) *pgxpool.Pool {
 			user_2_id BIGINT NOT NULL,
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
 			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			message_ttl INTEGER,
+			message_ttl_updated_by BIGINT,
+			message_ttl_updated_at TIMESTAMP WITH TIME ZONE,
 			UNIQUE(user_1_id, user_2_id)
 		);
 		
+		CREATE TABLE IF NOT EXISTS incoming_webhooks (
+			id SERIAL PRIMARY KEY,
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			creator_id BIGINT NOT NULL,
+			name VARCHAR(80) NOT NULL,
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
 		CREATE TABLE IF NOT EXISTS messages (
 			id SERIAL PRIMARY KEY,
 			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
 			sender_id BIGINT NOT NULL,
 			text TEXT NOT NULL,
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			is_read BOOLEAN DEFAULT FALSE
+			edited_at TIMESTAMP WITH TIME ZONE,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			reply_to_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+			forwarded_from_sender_id BIGINT,
+			forwarded_from_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+			search_vector tsvector GENERATED ALWAYS AS (
+				to_tsvector('russian', text) || to_tsvector('english', text)
+			) STORED,
+			client_message_id UUID,
+			envelope JSONB,
+			expires_at TIMESTAMP WITH TIME ZONE,
+			import_id TEXT,
+			hidden_at TIMESTAMP WITH TIME ZONE,
+			entities JSONB,
+			incoming_webhook_id INTEGER REFERENCES incoming_webhooks(id) ON DELETE SET NULL,
+			webhook_name VARCHAR(80),
+			UNIQUE (chat_id, sender_id, client_message_id),
+			UNIQUE (chat_id, import_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS chat_reads (
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			user_id BIGINT NOT NULL,
+			last_read_message_id INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (chat_id, user_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS message_edits (
+			id SERIAL PRIMARY KEY,
+			message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+			text TEXT NOT NULL,
+			edited_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			entities JSONB
+		);
+
+		CREATE TABLE IF NOT EXISTS message_deletions (
+			message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+			user_id BIGINT NOT NULL,
+			deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (message_id, user_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS message_reactions (
+			message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+			user_id BIGINT NOT NULL,
+			emoji VARCHAR(32) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (message_id, user_id, emoji)
+		);
+
+		CREATE TABLE IF NOT EXISTS attachments (
+			id SERIAL PRIMARY KEY,
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			uploader_id BIGINT NOT NULL,
+			message_id INTEGER REFERENCES messages(id) ON DELETE CASCADE,
+			file_name VARCHAR(255) NOT NULL,
+			content_type VARCHAR(127) NOT NULL,
+			size BIGINT NOT NULL,
+			storage_key VARCHAR(255) NOT NULL,
+			thumbnail_key VARCHAR(255),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS user_sync_state (
+			user_id BIGINT PRIMARY KEY,
+			last_seq BIGINT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS user_changes (
+			user_id BIGINT NOT NULL,
+			seq BIGINT NOT NULL,
+			chat_id INTEGER NOT NULL,
+			type VARCHAR(32) NOT NULL,
+			payload JSONB NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (user_id, seq)
+		);
+
+		CREATE TABLE IF NOT EXISTS user_blocks (
+			user_id BIGINT NOT NULL,
+			blocked_user_id BIGINT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (user_id, blocked_user_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS chat_settings (
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			user_id BIGINT NOT NULL,
+			muted BOOLEAN NOT NULL DEFAULT FALSE,
+			muted_until TIMESTAMP WITH TIME ZONE,
+			archived BOOLEAN NOT NULL DEFAULT FALSE,
+			pinned_at TIMESTAMP WITH TIME ZONE,
+			PRIMARY KEY (chat_id, user_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS push_subscriptions (
+			id SERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL,
+			endpoint TEXT NOT NULL UNIQUE,
+			p256dh TEXT NOT NULL,
+			auth TEXT NOT NULL,
+			user_agent TEXT NOT NULL DEFAULT '',
+			expires_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_push_subscriptions_user ON push_subscriptions(user_id);
+
+		CREATE TABLE IF NOT EXISTS exports (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id BIGINT NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			account JSONB NOT NULL,
+			storage_key VARCHAR(255),
+			size BIGINT,
+			error TEXT,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			started_at TIMESTAMP WITH TIME ZONE,
+			finished_at TIMESTAMP WITH TIME ZONE,
+			expires_at TIMESTAMP WITH TIME ZONE
 		);
+		CREATE UNIQUE INDEX IF NOT EXISTS exports_active_user_idx ON exports (user_id) WHERE status IN ('pending', 'running');
+
+		CREATE TABLE IF NOT EXISTS message_reports (
+			id SERIAL PRIMARY KEY,
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+			reporter_id BIGINT NOT NULL,
+			reason VARCHAR(32) NOT NULL,
+			comment TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE (message_id, reporter_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS moderation_queue (
+			message_id INTEGER PRIMARY KEY REFERENCES messages(id) ON DELETE CASCADE,
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			sender_id BIGINT NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			reports INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			hidden_at TIMESTAMP WITH TIME ZONE,
+			resolved_by BIGINT,
+			resolved_at TIMESTAMP WITH TIME ZONE
+		);
+
+		CREATE TABLE IF NOT EXISTS link_previews (
+			url TEXT PRIMARY KEY,
+			title TEXT NOT NULL DEFAULT '',
+			description TEXT NOT NULL DEFAULT '',
+			image_url TEXT NOT NULL DEFAULT '',
+			site_name TEXT NOT NULL DEFAULT '',
+			fetched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS message_link_previews (
+			message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+			url TEXT NOT NULL REFERENCES link_previews(url) ON DELETE CASCADE,
+			position SMALLINT NOT NULL,
+			PRIMARY KEY (message_id, url)
+		);
+
+		CREATE TABLE IF NOT EXISTS message_mentions (
+			message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			user_id BIGINT NOT NULL,
+			PRIMARY KEY (message_id, user_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS scheduled_messages (
+			id SERIAL PRIMARY KEY,
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			sender_id BIGINT NOT NULL,
+			text TEXT NOT NULL,
+			entities JSONB,
+			reply_to_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+			attachment_ids INTEGER[] NOT NULL DEFAULT '{}',
+			client_message_id UUID NOT NULL,
+			send_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			error TEXT,
+			claimed_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE (chat_id, sender_id, client_message_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS pinned_messages (
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+			pinned_by BIGINT NOT NULL,
+			pinned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (chat_id, message_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS drafts (
+			chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+			user_id BIGINT NOT NULL,
+			text TEXT NOT NULL DEFAULT '',
+			entities JSONB,
+			reply_to_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			PRIMARY KEY (user_id, chat_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS bot_webhooks (
+			bot_id BIGINT PRIMARY KEY,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			last_error TEXT,
+			last_error_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id BIGSERIAL PRIMARY KEY,
+			bot_id BIGINT NOT NULL REFERENCES bot_webhooks(bot_id) ON DELETE CASCADE,
+			chat_id INTEGER NOT NULL,
+			event VARCHAR(64) NOT NULL,
+			payload JSONB NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS moderation_log (
+			id BIGSERIAL PRIMARY KEY,
+			admin_id BIGINT NOT NULL,
+			action VARCHAR(32) NOT NULL,
+			user_id BIGINT,
+			chat_id INTEGER,
+			message_id INTEGER,
+			reason TEXT NOT NULL DEFAULT '',
+			details JSONB,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+
+		CREATE OR REPLACE FUNCTION moderation_log_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'moderation_log is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE TRIGGER moderation_log_no_update_delete
+			BEFORE UPDATE OR DELETE ON moderation_log
+			FOR EACH ROW EXECUTE FUNCTION moderation_log_append_only();
+
+		CREATE TRIGGER moderation_log_no_truncate
+			BEFORE TRUNCATE ON moderation_log
+			FOR EACH STATEMENT EXECUTE FUNCTION moderation_log_append_only();
 	`)
 	require.NoError(t, err)
 
@@ -163,12 +424,267 @@ func TestSendMessageWithGetMessages(t *testing.T) {
 	chatID, err := storage.CreateChat(ctx, user1, user2)
 	require.NoError(t, err)
 
-	msgID, err := storage.SendMessage(ctx, user1, chatID, "Test message")
+	msgID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "Test message"})
 	require.NoError(t, err)
 	assert.Greater(t, msgID, 0)
 
-	messages, err := storage.GetMessages(ctx, chatID, 10, 0)
+	messages, err := storage.GetMessages(ctx, chatID, user1, 10, 0)
 	require.NoError(t, err)
 	require.Len(t, messages, 1)
 	assert.Equal(t, "Test message", messages[0].Text)
 }
+
+func TestMarkRead(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+
+	chatID, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+
+	msg1, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "first"})
+	require.NoError(t, err)
+	msg2, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "second"})
+	require.NoError(t, err)
+
+	t.Run("read state per viewer", func(t *testing.T) {
+		receipt, _, err := storage.MarkRead(ctx, chatID, user2, msg1)
+		require.NoError(t, err)
+		assert.Equal(t, msg1, receipt.LastReadMessageId)
+
+		forSender, err := storage.GetMessages(ctx, chatID, user1, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, forSender, 2)
+		assert.True(t, forSender[0].IsRead)
+		assert.False(t, forSender[1].IsRead)
+
+		forReader, err := storage.GetMessages(ctx, chatID, user2, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, forReader, 2)
+		assert.True(t, forReader[0].IsRead)
+		assert.False(t, forReader[1].IsRead)
+	})
+
+	t.Run("mark all read", func(t *testing.T) {
+		receipt, moved, err := storage.MarkRead(ctx, chatID, user2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, msg2, receipt.LastReadMessageId)
+		assert.True(t, moved)
+	})
+
+	t.Run("pointer does not move back", func(t *testing.T) {
+		receipt, moved, err := storage.MarkRead(ctx, chatID, user2, msg1)
+		require.NoError(t, err)
+		assert.Equal(t, msg2, receipt.LastReadMessageId)
+		assert.False(t, moved)
+
+		_, moved, err = storage.MarkRead(ctx, chatID, user2, msg2)
+		require.NoError(t, err)
+		assert.False(t, moved, "repeated mark")
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		_, _, err := storage.MarkRead(ctx, chatID, 3, msg1)
+		require.ErrorIs(t, err, domain.ErrNotChatMember)
+	})
+
+	t.Run("message from other chat", func(t *testing.T) {
+		otherChat, err := storage.CreateChat(ctx, user1, 3)
+		require.NoError(t, err)
+		otherMsg, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: otherChat, SenderId: user1, Text: "other"})
+		require.NoError(t, err)
+
+		_, _, err = storage.MarkRead(ctx, chatID, user2, otherMsg)
+		require.ErrorIs(t, err, domain.ErrMessageNotFound)
+	})
+}
+
+func TestEditAndDeleteMessage(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+
+	chatID, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+
+	msgID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "original"})
+	require.NoError(t, err)
+
+	t.Run("edit keeps history", func(t *testing.T) {
+		msg, err := storage.EditMessage(ctx, chatID, msgID, domain.TextEdit{Text: "edited"})
+		require.NoError(t, err)
+		assert.Equal(t, "edited", msg.Text)
+		assert.NotNil(t, msg.EditedAt)
+
+		edits, err := storage.GetMessageEdits(ctx, msgID)
+		require.NoError(t, err)
+		require.Len(t, edits, 1)
+		assert.Equal(t, "original", edits[0].Text)
+	})
+
+	t.Run("hide for one user", func(t *testing.T) {
+		hiddenID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "hidden"})
+		require.NoError(t, err)
+
+		require.NoError(t, storage.HideMessage(ctx, chatID, hiddenID, user2))
+		require.NoError(t, storage.HideMessage(ctx, chatID, hiddenID, user2))
+
+		forUser2, err := storage.GetMessages(ctx, chatID, user2, 10, 0)
+		require.NoError(t, err)
+		for _, m := range forUser2 {
+			assert.NotEqual(t, hiddenID, m.Id)
+		}
+
+		forUser1, err := storage.GetMessages(ctx, chatID, user1, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, forUser1, 2)
+
+		err = storage.HideMessage(ctx, chatID, 100500, user2)
+		require.ErrorIs(t, err, domain.ErrMessageNotFound)
+	})
+
+	t.Run("delete for everyone leaves tombstone", func(t *testing.T) {
+		msg, err := storage.DeleteMessage(ctx, chatID, msgID)
+		require.NoError(t, err)
+		assert.Empty(t, msg.Text)
+		assert.NotNil(t, msg.DeletedAt)
+
+		edits, err := storage.GetMessageEdits(ctx, msgID)
+		require.NoError(t, err)
+		assert.Empty(t, edits)
+
+		_, err = storage.EditMessage(ctx, chatID, msgID, domain.TextEdit{Text: "again"})
+		require.ErrorIs(t, err, domain.ErrMessageDeleted)
+
+		_, err = storage.DeleteMessage(ctx, chatID, msgID)
+		require.ErrorIs(t, err, domain.ErrMessageDeleted)
+	})
+}
+
+func TestReplyAndForward(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+
+	chatID, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+
+	origID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "question"})
+	require.NoError(t, err)
+
+	t.Run("reply has preview", func(t *testing.T) {
+		replyID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user2, Text: "answer", ReplyToId: origID})
+		require.NoError(t, err)
+
+		msg, err := storage.GetMessage(ctx, chatID, replyID)
+		require.NoError(t, err)
+		require.NotNil(t, msg.ReplyTo)
+		assert.Equal(t, origID, msg.ReplyTo.Id)
+		assert.Equal(t, "question", msg.ReplyTo.Text)
+		assert.Equal(t, "1", msg.ReplyTo.SenderId)
+		assert.Nil(t, msg.ForwardedFrom)
+	})
+
+	t.Run("forward keeps original sender", func(t *testing.T) {
+		otherChat, err := storage.CreateChat(ctx, user2, 3)
+		require.NoError(t, err)
+
+		fwdID, _, err := storage.SendMessage(ctx, domain.NewMessage{
+			ChatId:                 otherChat,
+			SenderId:               user2,
+			Text:                   "question",
+			ForwardedFromSenderId:  user1,
+			ForwardedFromMessageId: origID,
+		})
+		require.NoError(t, err)
+
+		messages, err := storage.GetMessages(ctx, otherChat, 3, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, fwdID, messages[0].Id)
+		assert.Equal(t, "2", messages[0].SenderId)
+		require.NotNil(t, messages[0].ForwardedFrom)
+		assert.Equal(t, "1", messages[0].ForwardedFrom.SenderId)
+		assert.Equal(t, &origID, messages[0].ForwardedFrom.MessageId)
+	})
+}
+
+func TestSendMessageIdempotent(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+
+	chatID, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+
+	msg := domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "hello", ClientMessageId: "6f9619ff-8b86-d011-b42d-00c04fc964ff"}
+
+	first, created, err := storage.SendMessage(ctx, msg)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	retry, created, err := storage.SendMessage(ctx, msg)
+	require.NoError(t, err)
+	assert.Equal(t, first, retry)
+	assert.False(t, created)
+
+	found, err := storage.GetMessageIdByClientId(ctx, chatID, user1, msg.ClientMessageId)
+	require.NoError(t, err)
+	assert.Equal(t, first, found)
+
+	_, err = storage.GetMessageIdByClientId(ctx, chatID, user2, msg.ClientMessageId)
+	assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+
+	other, created, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user2, Text: "hello", ClientMessageId: msg.ClientMessageId})
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+	assert.True(t, created)
+
+	withoutID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "hello"})
+	require.NoError(t, err)
+	assert.NotEqual(t, first, withoutID)
+
+	messages, err := storage.GetMessages(ctx, chatID, user1, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, messages, 3)
+}
+
+func TestEncryptedMessage(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+
+	env := &domain.Envelope{
+		Algorithm:      "x3dh+double-ratchet",
+		SenderDeviceId: "phone",
+		Ciphertext:     "AQIDBA==",
+		Headers:        []domain.KeyHeader{{UserId: 2, DeviceId: "laptop", Header: "BQY="}},
+	}
+	msgID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 1, Envelope: env})
+	require.NoError(t, err)
+
+	messages, err := storage.GetMessages(ctx, chatID, 2, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Empty(t, messages[0].Text)
+	assert.Equal(t, env, messages[0].Envelope)
+
+	deleted, err := storage.DeleteMessage(ctx, chatID, msgID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted.Envelope)
+}
@@ -0,0 +1,102 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"fmt"
+)
+
+// SavePushSubscription сохраняет подписку устройства. Повторная подписка с тем же endpoint обновляет ключи
+// и переходит к sub.UserId, если в браузере залогинился другой пользователь
+func (s *ChatStorage) SavePushSubscription(ctx context.Context, sub domain.PushSubscription) error {
+	query := `
+		INSERT INTO push_subscriptions (user_id, endpoint, p256dh, auth, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (endpoint) DO UPDATE
+		SET user_id = EXCLUDED.user_id,
+		    p256dh = EXCLUDED.p256dh,
+		    auth = EXCLUDED.auth,
+		    user_agent = EXCLUDED.user_agent,
+		    expires_at = EXCLUDED.expires_at
+	`
+
+	_, err := s.db.Exec(ctx, query, sub.UserId, sub.Endpoint, sub.P256dh, sub.Auth, sub.UserAgent, sub.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to save push subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ChatStorage) DeletePushSubscription(ctx context.Context, userID int, endpoint string) error {
+	query := `
+		DELETE FROM push_subscriptions
+		WHERE user_id = $1 AND endpoint = $2
+	`
+
+	if _, err := s.db.Exec(ctx, query, userID, endpoint); err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetPushSubscriptions возвращает действующие подписки пользователей userIDs
+func (s *ChatStorage) GetPushSubscriptions(ctx context.Context, userIDs []int) ([]domain.PushSubscription, error) {
+	query := `
+		SELECT user_id, endpoint, p256dh, auth, user_agent, expires_at
+		FROM push_subscriptions
+		WHERE user_id = ANY($1) AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY user_id, id
+	`
+
+	rows, err := s.db.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []domain.PushSubscription{}
+	for rows.Next() {
+		var sub domain.PushSubscription
+		if err := rows.Scan(&sub.UserId, &sub.Endpoint, &sub.P256dh, &sub.Auth, &sub.UserAgent, &sub.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan push subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return subs, nil
+}
+
+// RemovePushSubscriptions удаляет подписки, которые push-сервис отклонил как несуществующие
+func (s *ChatStorage) RemovePushSubscriptions(ctx context.Context, endpoints []string) error {
+	query := `
+		DELETE FROM push_subscriptions
+		WHERE endpoint = ANY($1)
+	`
+
+	if _, err := s.db.Exec(ctx, query, endpoints); err != nil {
+		return fmt.Errorf("failed to remove push subscriptions: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredPushSubscriptions удаляет подписки с истекшим expires_at и возвращает их число
+func (s *ChatStorage) DeleteExpiredPushSubscriptions(ctx context.Context) (int64, error) {
+	query := `
+		DELETE FROM push_subscriptions
+		WHERE expires_at <= NOW()
+	`
+
+	tag, err := s.db.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired push subscriptions: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,80 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	phone := domain.PushSubscription{UserId: 1, Endpoint: "https://push.example.com/1", P256dh: "key1", Auth: "auth1"}
+	laptop := domain.PushSubscription{UserId: 1, Endpoint: "https://push.example.com/2", P256dh: "key2", Auth: "auth2", ExpiresAt: &future}
+	expired := domain.PushSubscription{UserId: 2, Endpoint: "https://push.example.com/3", P256dh: "key3", Auth: "auth3", ExpiresAt: &past}
+	for _, sub := range []domain.PushSubscription{phone, laptop, expired} {
+		require.NoError(t, storage.SavePushSubscription(ctx, sub))
+	}
+
+	endpoints := func(subs []domain.PushSubscription) []string {
+		var out []string
+		for _, s := range subs {
+			out = append(out, s.Endpoint)
+		}
+		return out
+	}
+
+	t.Run("expired are skipped", func(t *testing.T) {
+		subs, err := storage.GetPushSubscriptions(ctx, []int{1, 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{phone.Endpoint, laptop.Endpoint}, endpoints(subs))
+		assert.Equal(t, "key1", subs[0].P256dh)
+	})
+
+	t.Run("resubscribe moves endpoint to another user", func(t *testing.T) {
+		moved := phone
+		moved.UserId = 2
+		moved.P256dh = "key1-new"
+		require.NoError(t, storage.SavePushSubscription(ctx, moved))
+
+		subs, err := storage.GetPushSubscriptions(ctx, []int{2})
+		require.NoError(t, err)
+		require.Len(t, subs, 1)
+		assert.Equal(t, "key1-new", subs[0].P256dh)
+
+		require.NoError(t, storage.SavePushSubscription(ctx, phone))
+	})
+
+	t.Run("delete only own subscription", func(t *testing.T) {
+		require.NoError(t, storage.DeletePushSubscription(ctx, 2, laptop.Endpoint))
+		subs, err := storage.GetPushSubscriptions(ctx, []int{1})
+		require.NoError(t, err)
+		assert.Len(t, subs, 2)
+
+		require.NoError(t, storage.DeletePushSubscription(ctx, 1, laptop.Endpoint))
+		subs, err = storage.GetPushSubscriptions(ctx, []int{1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{phone.Endpoint}, endpoints(subs))
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		deleted, err := storage.DeleteExpiredPushSubscriptions(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		require.NoError(t, storage.RemovePushSubscriptions(ctx, []string{phone.Endpoint}))
+		subs, err := storage.GetPushSubscriptions(ctx, []int{1, 2})
+		require.NoError(t, err)
+		assert.Empty(t, subs)
+	})
+}
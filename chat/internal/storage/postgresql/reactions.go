@@ -0,0 +1,121 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *ChatStorage) AddReaction(ctx context.Context, reaction domain.Reaction) error {
+	query := `
+		INSERT INTO message_reactions (message_id, user_id, emoji)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (message_id, user_id, emoji) DO NOTHING
+		RETURNING (SELECT chat_id FROM messages WHERE id = message_id)
+	`
+
+	if err := s.changeReaction(ctx, query, reaction, domain.EventReactionAdded); err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ChatStorage) RemoveReaction(ctx context.Context, reaction domain.Reaction) error {
+	query := `
+		DELETE FROM message_reactions
+		WHERE message_id = $1 AND user_id = $2 AND emoji = $3
+		RETURNING (SELECT chat_id FROM messages WHERE id = message_id)
+	`
+
+	if err := s.changeReaction(ctx, query, reaction, domain.EventReactionRemoved); err != nil {
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return nil
+}
+
+// changeReaction выполняет query, возвращающий chat_id измененной реакции, и записывает изменение в журнал.
+// Если реакция уже была поставлена или снята, журнал не меняется
+func (s *ChatStorage) changeReaction(ctx context.Context, query string, reaction domain.Reaction, changeType string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var chatID int
+	err = tx.QueryRow(ctx, query, reaction.MessageId, reaction.UserId, reaction.Emoji).Scan(&chatID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := recordChange(ctx, tx, chatID, changeType, reaction); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetReactions возвращает реакции на сообщения messageIDs, сгруппированные по эмодзи,
+// в порядке появления первой реакции каждым эмодзи
+func (s *ChatStorage) GetReactions(ctx context.Context, messageIDs []int, userID int) (map[int][]domain.ReactionCount, error) {
+	query := `
+		SELECT message_id, emoji, COUNT(*), BOOL_OR(user_id = $2)
+		FROM message_reactions
+		WHERE message_id = ANY($1)
+		GROUP BY message_id, emoji
+		ORDER BY message_id, MIN(created_at), emoji
+	`
+
+	rows, err := s.db.Query(ctx, query, messageIDs, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+	defer rows.Close()
+
+	reactions := make(map[int][]domain.ReactionCount)
+	for rows.Next() {
+		var messageID int
+		var rc domain.ReactionCount
+		if err := rows.Scan(&messageID, &rc.Emoji, &rc.Count, &rc.ReactedByMe); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		reactions[messageID] = append(reactions[messageID], rc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return reactions, nil
+}
+
+// attachReactions дополняет сообщения агрегированными реакциями с точки зрения userID
+func (s *ChatStorage) attachReactions(ctx context.Context, messages []domain.Message, userID int) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(messages))
+	for _, m := range messages {
+		ids = append(ids, m.Id)
+	}
+
+	reactions, err := s.GetReactions(ctx, ids, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		messages[i].Reactions = reactions[messages[i].Id]
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactions(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+
+	chatID, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+
+	msgID, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: user1, Text: "hello"})
+	require.NoError(t, err)
+
+	t.Run("unique per user and emoji", func(t *testing.T) {
+		require.NoError(t, storage.AddReaction(ctx, domain.Reaction{MessageId: msgID, UserId: user1, Emoji: "👍"}))
+		require.NoError(t, storage.AddReaction(ctx, domain.Reaction{MessageId: msgID, UserId: user1, Emoji: "👍"}))
+		require.NoError(t, storage.AddReaction(ctx, domain.Reaction{MessageId: msgID, UserId: user2, Emoji: "👍"}))
+		require.NoError(t, storage.AddReaction(ctx, domain.Reaction{MessageId: msgID, UserId: user2, Emoji: "🔥"}))
+
+		messages, err := storage.GetMessages(ctx, chatID, user1, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, []domain.ReactionCount{
+			{Emoji: "👍", Count: 2, ReactedByMe: true},
+			{Emoji: "🔥", Count: 1, ReactedByMe: false},
+		}, messages[0].Reactions)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, storage.RemoveReaction(ctx, domain.Reaction{MessageId: msgID, UserId: user2, Emoji: "🔥"}))
+
+		reactions, err := storage.GetReactions(ctx, []int{msgID}, user2)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ReactionCount{
+			{Emoji: "👍", Count: 2, ReactedByMe: true},
+		}, reactions[msgID])
+	})
+
+	t.Run("cleared on delete for everyone", func(t *testing.T) {
+		_, err := storage.DeleteMessage(ctx, chatID, msgID)
+		require.NoError(t, err)
+
+		reactions, err := storage.GetReactions(ctx, []int{msgID}, user1)
+		require.NoError(t, err)
+		assert.Empty(t, reactions[msgID])
+	})
+}
@@ -0,0 +1,241 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const reportedMessageColumns = `m.chat_id, m.id, m.sender_id, m.text, m.envelope IS NOT NULL, m.created_at, m.deleted_at,
+		q.status, q.reports,
+		ARRAY(SELECT DISTINCT r.reason FROM message_reports r WHERE r.message_id = q.message_id ORDER BY r.reason),
+		q.updated_at, q.hidden_at, COALESCE(q.resolved_by, 0), q.resolved_at`
+
+// ReportMessage сохраняет жалобу и ставит сообщение в очередь модерации. Повторная жалоба того же
+// пользователя возвращает ErrAlreadyReported, жалоба на отклоненное модератором сообщение снова открывает его.
+// Когда на открытые сообщения отправителя пожаловались hideThreshold разных пользователей, все его сообщения
+// в очереди скрываются до решения модератора; они и возвращаются. hideThreshold 0 - не скрывать
+func (s *ChatStorage) ReportMessage(ctx context.Context, report domain.Report, hideThreshold int) ([]domain.MessageVisibility, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO message_reports (chat_id, message_id, reporter_id, reason, comment)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (message_id, reporter_id) DO NOTHING
+	`, report.ChatId, report.MessageId, report.ReporterId, report.Reason, report.Comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save report: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, domain.ErrAlreadyReported
+	}
+
+	var senderID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO moderation_queue (message_id, chat_id, sender_id, reports)
+		SELECT id, chat_id, sender_id, 1 FROM messages WHERE id = $1 AND chat_id = $2
+		ON CONFLICT (message_id) DO UPDATE
+		SET reports = moderation_queue.reports + 1,
+		    updated_at = NOW(),
+		    status = CASE WHEN moderation_queue.status = 'dismissed' THEN 'pending' ELSE moderation_queue.status END,
+		    resolved_by = CASE WHEN moderation_queue.status = 'dismissed' THEN NULL ELSE moderation_queue.resolved_by END,
+		    resolved_at = CASE WHEN moderation_queue.status = 'dismissed' THEN NULL ELSE moderation_queue.resolved_at END
+		RETURNING sender_id
+	`, report.MessageId, report.ChatId).Scan(&senderID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue report: %w", err)
+	}
+
+	var hidden []domain.MessageVisibility
+	if hideThreshold > 0 {
+		hidden, err = hideReported(ctx, tx, senderID, hideThreshold)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return hidden, nil
+}
+
+// hideReported скрывает открытые сообщения отправителя, если жалоб на него набралось достаточно.
+// Подсчет и скрытие сериализуются по отправителю, иначе параллельные жалобы могут не увидеть друг друга
+func hideReported(ctx context.Context, tx pgx.Tx, senderID, threshold int) ([]domain.MessageVisibility, error) {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('moderation_sender'), $1)`, senderID); err != nil {
+		return nil, fmt.Errorf("failed to lock sender: %w", err)
+	}
+
+	var reporters int
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT r.reporter_id)
+		FROM moderation_queue q
+		JOIN message_reports r ON r.message_id = q.message_id
+		WHERE q.sender_id = $1 AND q.status IN ('pending', 'hidden')
+	`, senderID).Scan(&reporters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reporters: %w", err)
+	}
+	if reporters < threshold {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE moderation_queue
+		SET status = 'hidden', hidden_at = NOW()
+		WHERE sender_id = $1 AND status = 'pending'
+		RETURNING chat_id, message_id, hidden_at
+	`, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hide reported messages: %w", err)
+	}
+	hidden, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.MessageVisibility, error) {
+		var v domain.MessageVisibility
+		err := row.Scan(&v.ChatId, &v.MessageId, &v.HiddenAt)
+		return v, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan hidden message: %w", err)
+	}
+
+	for _, v := range hidden {
+		if _, err := tx.Exec(ctx, `UPDATE messages SET hidden_at = $2 WHERE id = $1`, v.MessageId, v.HiddenAt); err != nil {
+			return nil, fmt.Errorf("failed to hide message: %w", err)
+		}
+		if err := recordChange(ctx, tx, v.ChatId, domain.EventMessageHidden, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return hidden, nil
+}
+
+// GetReportedMessages возвращает очередь модерации со статусом status, пустой status - все нерассмотренные.
+// Сначала сообщения с наибольшим числом жалоб
+func (s *ChatStorage) GetReportedMessages(ctx context.Context, status string, limit, offset int) ([]domain.ReportedMessage, error) {
+	query := `
+		SELECT ` + reportedMessageColumns + `
+		FROM moderation_queue q
+		JOIN messages m ON m.id = q.message_id
+		WHERE CASE WHEN $1 = '' THEN q.status IN ('pending', 'hidden') ELSE q.status = $1 END
+		ORDER BY q.reports DESC, q.updated_at DESC, q.message_id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := s.db.Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reported messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []domain.ReportedMessage{}
+	for rows.Next() {
+		m, err := scanReportedMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reported message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (s *ChatStorage) GetReportedMessage(ctx context.Context, messageID int) (domain.ReportedMessage, error) {
+	query := `
+		SELECT ` + reportedMessageColumns + `
+		FROM moderation_queue q
+		JOIN messages m ON m.id = q.message_id
+		WHERE q.message_id = $1
+	`
+
+	m, err := scanReportedMessage(s.db.QueryRow(ctx, query, messageID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ReportedMessage{}, domain.ErrReportNotFound
+	}
+	if err != nil {
+		return domain.ReportedMessage{}, fmt.Errorf("failed to get reported message: %w", err)
+	}
+
+	return m, nil
+}
+
+// ResolveReport закрывает жалобы на сообщение решением модератора. При status dismissed скрытое сообщение
+// снова показывается, тогда restored = true. Закрытые жалобы возвращают ErrReportResolved
+func (s *ChatStorage) ResolveReport(ctx context.Context, messageID, adminID int, status string) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var chatID int
+	var current string
+	err = tx.QueryRow(ctx, `SELECT chat_id, status FROM moderation_queue WHERE message_id = $1 FOR UPDATE`, messageID).
+		Scan(&chatID, &current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, domain.ErrReportNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get report: %w", err)
+	}
+	if current != domain.ModerationPending && current != domain.ModerationHidden {
+		return false, domain.ErrReportResolved
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE moderation_queue
+		SET status = $2, resolved_by = $3, resolved_at = NOW()
+		WHERE message_id = $1
+	`, messageID, status, adminID)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve report: %w", err)
+	}
+
+	restored := false
+	if status == domain.ModerationDismissed && current == domain.ModerationHidden {
+		if _, err := tx.Exec(ctx, `UPDATE messages SET hidden_at = NULL WHERE id = $1`, messageID); err != nil {
+			return false, fmt.Errorf("failed to restore message: %w", err)
+		}
+		v := domain.MessageVisibility{ChatId: chatID, MessageId: messageID}
+		if err := recordChange(ctx, tx, chatID, domain.EventMessageRestored, v); err != nil {
+			return false, err
+		}
+		restored = true
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return restored, nil
+}
+
+func scanReportedMessage(row pgx.Row) (domain.ReportedMessage, error) {
+	var m domain.ReportedMessage
+	var lastReportedAt *time.Time
+	err := row.Scan(
+		&m.ChatId, &m.MessageId, &m.SenderId, &m.Text, &m.Encrypted, &m.CreatedAt, &m.DeletedAt,
+		&m.Status, &m.Reports, &m.Reasons, &lastReportedAt, &m.HiddenAt, &m.ResolvedBy, &m.ResolvedAt,
+	)
+	if lastReportedAt != nil {
+		m.LastReportedAt = *lastReportedAt
+	}
+	return m, err
+}
@@ -0,0 +1,95 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReports(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	// Пользователь 1 пишет троим собеседникам, двое из них жалуются
+	var chats, messages [3]int
+	for i, peer := range []int{2, 3, 4} {
+		var err error
+		chats[i], err = storage.CreateChat(ctx, 1, peer)
+		require.NoError(t, err)
+		messages[i], _, err = storage.SendMessage(ctx, domain.NewMessage{ChatId: chats[i], SenderId: 1, Text: "buy now"})
+		require.NoError(t, err)
+	}
+	report := func(i, reporter int, reason string) ([]domain.MessageVisibility, error) {
+		return storage.ReportMessage(ctx, domain.Report{
+			ChatId: chats[i], MessageId: messages[i], ReporterId: reporter, Reason: reason,
+		}, 2)
+	}
+
+	hidden, err := report(0, 2, domain.ReportSpam)
+	require.NoError(t, err)
+	assert.Empty(t, hidden)
+
+	t.Run("deduplicated per reporter", func(t *testing.T) {
+		_, err := report(0, 2, domain.ReportAbuse)
+		assert.ErrorIs(t, err, domain.ErrAlreadyReported)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		queue, err := storage.GetReportedMessages(ctx, "", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, queue, 1)
+		assert.Equal(t, messages[0], queue[0].MessageId)
+		assert.Equal(t, domain.ModerationPending, queue[0].Status)
+		assert.Equal(t, 1, queue[0].Reports)
+		assert.Equal(t, []string{domain.ReportSpam}, queue[0].Reasons)
+		assert.Equal(t, "buy now", queue[0].Text)
+	})
+
+	t.Run("threshold hides sender messages", func(t *testing.T) {
+		hidden, err := report(1, 3, domain.ReportSpam)
+		require.NoError(t, err)
+		require.Len(t, hidden, 2)
+
+		msgs, err := storage.GetMessages(ctx, chats[0], 2, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		assert.NotNil(t, msgs[0].HiddenAt)
+
+		queue, err := storage.GetReportedMessages(ctx, domain.ModerationHidden, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, queue, 2)
+
+		// Непожалованное сообщение не скрывается
+		msgs, err = storage.GetMessages(ctx, chats[2], 4, 10, 0)
+		require.NoError(t, err)
+		assert.Nil(t, msgs[0].HiddenAt)
+	})
+
+	t.Run("dismiss restores", func(t *testing.T) {
+		restored, err := storage.ResolveReport(ctx, messages[0], 100, domain.ModerationDismissed)
+		require.NoError(t, err)
+		assert.True(t, restored)
+
+		got, err := storage.GetReportedMessage(ctx, messages[0])
+		require.NoError(t, err)
+		assert.Equal(t, domain.ModerationDismissed, got.Status)
+		assert.Equal(t, 100, got.ResolvedBy)
+
+		msg, err := storage.GetMessage(ctx, chats[0], messages[0])
+		require.NoError(t, err)
+		assert.Nil(t, msg.HiddenAt)
+
+		_, err = storage.ResolveReport(ctx, messages[0], 100, domain.ModerationDismissed)
+		assert.ErrorIs(t, err, domain.ErrReportResolved)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := storage.GetReportedMessage(ctx, messages[2])
+		assert.ErrorIs(t, err, domain.ErrReportNotFound)
+	})
+}
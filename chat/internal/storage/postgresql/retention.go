@@ -0,0 +1,174 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// messageChangeTypes - изменения журнала, в содержимом которых сообщение целиком
+var messageChangeTypes = []string{domain.EventMessageCreated, domain.EventMessageEdited, domain.EventMessageDeleted}
+
+func (s *ChatStorage) GetChatRetention(ctx context.Context, chatID int) (domain.ChatRetention, error) {
+	query := `
+		SELECT COALESCE(message_ttl, 0), COALESCE(message_ttl_updated_by, 0), message_ttl_updated_at
+		FROM chats
+		WHERE id = $1
+	`
+
+	retention := domain.ChatRetention{ChatId: chatID}
+	err := s.db.QueryRow(ctx, query, chatID).Scan(&retention.TTL, &retention.UpdatedBy, &retention.UpdatedAt)
+	if err != nil {
+		return domain.ChatRetention{}, fmt.Errorf("failed to get chat retention: %w", err)
+	}
+
+	return retention, nil
+}
+
+// SetChatRetention задает срок жизни новых сообщений чата в секундах, 0 - сообщения не исчезают
+func (s *ChatStorage) SetChatRetention(ctx context.Context, chatID, userID, ttl int) (domain.ChatRetention, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return domain.ChatRetention{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	retention := domain.ChatRetention{ChatId: chatID, TTL: ttl, UpdatedBy: userID}
+	err = tx.QueryRow(ctx, `
+		UPDATE chats
+		SET message_ttl = NULLIF($2, 0), message_ttl_updated_by = $3, message_ttl_updated_at = NOW()
+		WHERE id = $1
+		RETURNING message_ttl_updated_at
+	`, chatID, ttl, userID).Scan(&retention.UpdatedAt)
+	if err != nil {
+		return domain.ChatRetention{}, fmt.Errorf("failed to set chat retention: %w", err)
+	}
+
+	if err := recordChange(ctx, tx, chatID, domain.EventChatRetentionChanged, retention); err != nil {
+		return domain.ChatRetention{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.ChatRetention{}, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return retention, nil
+}
+
+// DeleteExpiredMessages удаляет до limit сообщений с истекшим expires_at, а при maxAge > 0 - и старше maxAge.
+// Строки, заблокированные другими транзакциями, пропускаются, поэтому очистка не ждет отправку и правку
+// сообщений и может идти с нескольких реплик. Возвращает удаленные сообщения по чатам и их вложения,
+// файлы которых нужно удалить из хранилища. Содержимое удаленных сообщений стирается и из журналов изменений
+func (s *ChatStorage) DeleteExpiredMessages(ctx context.Context, maxAge time.Duration, limit int) ([]domain.ExpiredMessages, []domain.Attachment, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, chat_id
+		FROM messages
+		WHERE expires_at <= NOW()
+		   OR ($1::int > 0 AND created_at < NOW() - make_interval(secs => $1::int))
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, int(maxAge.Seconds()), limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to select expired messages: %w", err)
+	}
+
+	var messageIDs []int
+	byChat := make(map[int][]int)
+	for rows.Next() {
+		var id, chatID int
+		if err := rows.Scan(&id, &chatID); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("failed to scan expired message: %w", err)
+		}
+		messageIDs = append(messageIDs, id)
+		byChat[chatID] = append(byChat[chatID], id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows error: %w", err)
+	}
+	if len(messageIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	attachments, err := deleteAttachments(ctx, tx, messageIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Правки, реакции и скрытия удаляются каскадно, ответы на удаленные сообщения теряют ссылку
+	if _, err := tx.Exec(ctx, `DELETE FROM messages WHERE id = ANY($1)`, messageIDs); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete expired messages: %w", err)
+	}
+
+	chatIDs := make([]int, 0, len(byChat))
+	for chatID := range byChat {
+		chatIDs = append(chatIDs, chatID)
+	}
+	slices.Sort(chatIDs)
+
+	expired := make([]domain.ExpiredMessages, 0, len(chatIDs))
+	for _, chatID := range chatIDs {
+		ids := byChat[chatID]
+		slices.Sort(ids)
+
+		_, err := tx.Exec(ctx, `
+			UPDATE user_changes
+			SET payload = jsonb_build_object('id', payload->'id')
+			WHERE user_id IN (SELECT user_1_id FROM chats WHERE id = $1 UNION SELECT user_2_id FROM chats WHERE id = $1)
+			  AND chat_id = $1 AND type = ANY($2) AND (payload->>'id')::int = ANY($3)
+		`, chatID, messageChangeTypes, ids)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scrub expired changes: %w", err)
+		}
+
+		e := domain.ExpiredMessages{ChatId: chatID, MessageIds: ids}
+		if err := recordChange(ctx, tx, chatID, domain.EventMessagesExpired, e); err != nil {
+			return nil, nil, err
+		}
+		expired = append(expired, e)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return expired, attachments, nil
+}
+
+// deleteAttachments удаляет вложения сообщений messageIDs и возвращает их
+func deleteAttachments(ctx context.Context, tx pgx.Tx, messageIDs []int) ([]domain.Attachment, error) {
+	rows, err := tx.Query(ctx, `
+		DELETE FROM attachments
+		WHERE message_id = ANY($1)
+		RETURNING `+attachmentColumns, messageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return attachments, nil
+}
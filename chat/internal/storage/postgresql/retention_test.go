@@ -0,0 +1,102 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageRetention(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+	otherChat, err := storage.CreateChat(ctx, 1, 3)
+	require.NoError(t, err)
+
+	t.Run("defaults", func(t *testing.T) {
+		retention, err := storage.GetChatRetention(ctx, chatID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ChatRetention{ChatId: chatID}, retention)
+	})
+
+	t.Run("set ttl", func(t *testing.T) {
+		retention, err := storage.SetChatRetention(ctx, chatID, 2, 60)
+		require.NoError(t, err)
+		assert.Equal(t, 60, retention.TTL)
+		assert.Equal(t, 2, retention.UpdatedBy)
+		require.NotNil(t, retention.UpdatedAt)
+
+		got, err := storage.GetChatRetention(ctx, chatID)
+		require.NoError(t, err)
+		assert.Equal(t, 60, got.TTL)
+	})
+
+	t.Run("expire messages", func(t *testing.T) {
+		attachmentID, err := storage.CreateAttachment(ctx, domain.Attachment{
+			ChatId:      chatID,
+			UploaderId:  1,
+			FileName:    "photo.png",
+			ContentType: "image/png",
+			Size:        10,
+			StorageKey:  "chats/1/a",
+		})
+		require.NoError(t, err)
+
+		disappearing, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: 1, Text: "secret", AttachmentIds: []int{attachmentID}})
+		require.NoError(t, err)
+		permanent, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: otherChat, SenderId: 1, Text: "hello"})
+		require.NoError(t, err)
+
+		messages, err := storage.GetMessages(ctx, chatID, 2, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		require.NotNil(t, messages[0].ExpiresAt)
+
+		// Сообщение истекло, но очистка еще не прошла: в выдаче его уже нет
+		_, err = pool.Exec(ctx, `UPDATE messages SET expires_at = NOW() - INTERVAL '1 second' WHERE id = $1`, disappearing)
+		require.NoError(t, err)
+		messages, err = storage.GetMessages(ctx, chatID, 2, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+
+		expired, attachments, err := storage.DeleteExpiredMessages(ctx, 0, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ExpiredMessages{{ChatId: chatID, MessageIds: []int{disappearing}}}, expired)
+		require.Len(t, attachments, 1)
+		assert.Equal(t, "chats/1/a", attachments[0].StorageKey)
+
+		batch, err := storage.GetChanges(ctx, 2, 0, 100)
+		require.NoError(t, err)
+		last := batch.Changes[len(batch.Changes)-1]
+		assert.Equal(t, domain.EventMessagesExpired, last.Type)
+		for _, c := range batch.Changes {
+			assert.NotContains(t, string(c.Payload), "secret")
+		}
+
+		_, err = storage.GetMessage(ctx, otherChat, permanent)
+		require.NoError(t, err)
+	})
+
+	t.Run("global retention", func(t *testing.T) {
+		old, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: otherChat, SenderId: 3, Text: "old"})
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, `UPDATE messages SET created_at = NOW() - INTERVAL '2 hours' WHERE id = $1`, old)
+		require.NoError(t, err)
+
+		expired, _, err := storage.DeleteExpiredMessages(ctx, time.Hour, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ExpiredMessages{{ChatId: otherChat, MessageIds: []int{old}}}, expired)
+
+		expired, _, err = storage.DeleteExpiredMessages(ctx, time.Hour, 100)
+		require.NoError(t, err)
+		assert.Empty(t, expired)
+	})
+}
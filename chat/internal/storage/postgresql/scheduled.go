@@ -0,0 +1,215 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const scheduledColumns = `id, chat_id, sender_id, text, entities, COALESCE(reply_to_message_id, 0), attachment_ids,
+		client_message_id::text, send_at, status, COALESCE(error, ''), created_at`
+
+// CreateScheduledMessage откладывает сообщение до m.SendAt. Если у отправителя уже limit отложенных сообщений
+// (limit 0 - без ограничения), возвращается ErrTooManyScheduled. Повтор с тем же ClientMessageId
+// возвращает уже отложенное сообщение
+func (s *ChatStorage) CreateScheduledMessage(ctx context.Context, m domain.ScheduledMessage, limit int) (domain.ScheduledMessage, error) {
+	query := `
+		INSERT INTO scheduled_messages (chat_id, sender_id, text, entities, reply_to_message_id, attachment_ids, client_message_id, send_at)
+		SELECT $1, $2, $3, $4, NULLIF($5, 0), COALESCE($6::int[], '{}'), $7::uuid, $8
+		WHERE $9::int <= 0 OR (SELECT COUNT(*) FROM scheduled_messages WHERE sender_id = $2) < $9
+		ON CONFLICT (chat_id, sender_id, client_message_id) DO NOTHING
+		RETURNING ` + scheduledColumns
+
+	created, err := scanScheduled(s.db.QueryRow(ctx, query,
+		m.ChatId, m.SenderId, m.Text, entitiesValue(m.Entities), m.ReplyToId, m.AttachmentIds, m.ClientMessageId, m.SendAt, limit,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.scheduledByClientID(ctx, m)
+	}
+	if err != nil {
+		return domain.ScheduledMessage{}, fmt.Errorf("failed to create scheduled message: %w", err)
+	}
+
+	return created, nil
+}
+
+// scheduledByClientID возвращает сообщение, уже отложенное с m.ClientMessageId. Если его нет,
+// вставка не прошла из-за лимита
+func (s *ChatStorage) scheduledByClientID(ctx context.Context, m domain.ScheduledMessage) (domain.ScheduledMessage, error) {
+	query := `
+		SELECT ` + scheduledColumns + `
+		FROM scheduled_messages
+		WHERE chat_id = $1 AND sender_id = $2 AND client_message_id = $3::uuid
+	`
+
+	existing, err := scanScheduled(s.db.QueryRow(ctx, query, m.ChatId, m.SenderId, m.ClientMessageId))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ScheduledMessage{}, domain.ErrTooManyScheduled
+	}
+	if err != nil {
+		return domain.ScheduledMessage{}, fmt.Errorf("failed to get scheduled message by client id: %w", err)
+	}
+
+	return existing, nil
+}
+
+// ListScheduledMessages возвращает неотправленные сообщения пользователя в порядке отправки.
+// chatID 0 - по всем чатам
+func (s *ChatStorage) ListScheduledMessages(ctx context.Context, userID, chatID int) ([]domain.ScheduledMessage, error) {
+	query := `
+		SELECT ` + scheduledColumns + `
+		FROM scheduled_messages
+		WHERE sender_id = $1 AND ($2 = 0 OR chat_id = $2)
+		ORDER BY send_at, id
+	`
+
+	rows, err := s.db.Query(ctx, query, userID, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+	defer rows.Close()
+
+	scheduled := []domain.ScheduledMessage{}
+	for rows.Next() {
+		m, err := scanScheduled(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled message: %w", err)
+		}
+		scheduled = append(scheduled, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return scheduled, nil
+}
+
+// UpdateScheduledMessage меняет текст или время отправки ожидающего сообщения. Сообщение, отправка
+// которого не удалась, снова становится ожидающим только с новым временем: старое уже в прошлом,
+// и без него сообщение ушло бы сразу
+func (s *ChatStorage) UpdateScheduledMessage(ctx context.Context, userID, id int, edit domain.ScheduledEdit) (domain.ScheduledMessage, error) {
+	query := `
+		UPDATE scheduled_messages
+		SET text = CASE WHEN $3::boolean THEN $4::text ELSE text END,
+		    entities = CASE WHEN $3::boolean THEN $5::jsonb ELSE entities END,
+		    send_at = COALESCE($6::timestamptz, send_at),
+		    status = CASE WHEN $6::timestamptz IS NULL THEN status ELSE 'pending' END,
+		    error = CASE WHEN $6::timestamptz IS NULL THEN error END
+		WHERE id = $1 AND sender_id = $2 AND status IN ('pending', 'failed')
+		RETURNING ` + scheduledColumns
+
+	var text string
+	var entities any
+	if edit.Text != nil {
+		text, entities = edit.Text.Text, entitiesValue(edit.Text.Entities)
+	}
+
+	m, err := scanScheduled(s.db.QueryRow(ctx, query, id, userID, edit.Text != nil, text, entities, edit.SendAt))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ScheduledMessage{}, s.scheduledUnavailable(ctx, userID, id)
+	}
+	if err != nil {
+		return domain.ScheduledMessage{}, fmt.Errorf("failed to update scheduled message: %w", err)
+	}
+
+	return m, nil
+}
+
+// DeleteScheduledMessage отменяет отправку отложенного сообщения
+func (s *ChatStorage) DeleteScheduledMessage(ctx context.Context, userID, id int) error {
+	query := `
+		DELETE FROM scheduled_messages
+		WHERE id = $1 AND sender_id = $2 AND status IN ('pending', 'failed')
+	`
+
+	tag, err := s.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete scheduled message: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return s.scheduledUnavailable(ctx, userID, id)
+	}
+
+	return nil
+}
+
+// scheduledUnavailable объясняет, почему сообщение нельзя изменить: оно уже отправляется или его нет
+func (s *ChatStorage) scheduledUnavailable(ctx context.Context, userID, id int) error {
+	var exists bool
+	err := s.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM scheduled_messages WHERE id = $1 AND sender_id = $2)
+	`, id, userID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check scheduled message: %w", err)
+	}
+	if exists {
+		return domain.ErrScheduledSending
+	}
+	return domain.ErrScheduledNotFound
+}
+
+// ClaimScheduledMessage берет в отправку самое раннее сообщение, время которого подошло. Строки,
+// заблокированные другими репликами, пропускаются. Отправка, не завершенная за timeout, считается
+// брошенной упавшей репликой и берется заново. Без подходящих сообщений возвращает ErrScheduledNotFound
+func (s *ChatStorage) ClaimScheduledMessage(ctx context.Context, timeout time.Duration) (domain.ScheduledMessage, error) {
+	query := `
+		UPDATE scheduled_messages
+		SET status = 'sending', claimed_at = NOW()
+		WHERE id = (
+			SELECT id FROM scheduled_messages
+			WHERE (status = 'pending' AND send_at <= NOW())
+			   OR (status = 'sending' AND claimed_at < NOW() - make_interval(secs => $1::int))
+			ORDER BY send_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + scheduledColumns
+
+	m, err := scanScheduled(s.db.QueryRow(ctx, query, int(timeout.Seconds())))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ScheduledMessage{}, domain.ErrScheduledNotFound
+	}
+	if err != nil {
+		return domain.ScheduledMessage{}, fmt.Errorf("failed to claim scheduled message: %w", err)
+	}
+
+	return m, nil
+}
+
+// CompleteScheduledMessage убирает отправленное сообщение из отложенных
+func (s *ChatStorage) CompleteScheduledMessage(ctx context.Context, id int) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM scheduled_messages WHERE id = $1 AND status = 'sending'`, id); err != nil {
+		return fmt.Errorf("failed to complete scheduled message: %w", err)
+	}
+	return nil
+}
+
+// FailScheduledMessage отмечает, что сообщение отправить нельзя. Отправитель может исправить его и запланировать снова
+func (s *ChatStorage) FailScheduledMessage(ctx context.Context, id int, reason string) error {
+	query := `
+		UPDATE scheduled_messages
+		SET status = 'failed', error = $2, claimed_at = NULL
+		WHERE id = $1 AND status = 'sending'
+	`
+
+	if _, err := s.db.Exec(ctx, query, id, reason); err != nil {
+		return fmt.Errorf("failed to fail scheduled message: %w", err)
+	}
+
+	return nil
+}
+
+func scanScheduled(row pgx.Row) (domain.ScheduledMessage, error) {
+	var m domain.ScheduledMessage
+	err := row.Scan(&m.Id, &m.ChatId, &m.SenderId, &m.Text, &m.Entities, &m.ReplyToId, &m.AttachmentIds,
+		&m.ClientMessageId, &m.SendAt, &m.Status, &m.Error, &m.CreatedAt)
+	if len(m.AttachmentIds) == 0 {
+		m.AttachmentIds = nil
+	}
+	return m, err
+}
@@ -0,0 +1,127 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledMessages(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	chatID, err := storage.CreateChat(ctx, 1, 2)
+	require.NoError(t, err)
+
+	schedule := func(sendAt time.Time) domain.ScheduledMessage {
+		m, err := storage.CreateScheduledMessage(ctx, domain.ScheduledMessage{
+			ChatId:          chatID,
+			SenderId:        1,
+			Text:            "позже",
+			ClientMessageId: uuid.NewString(),
+			SendAt:          sendAt,
+		}, 3)
+		require.NoError(t, err)
+		return m
+	}
+
+	due := schedule(time.Now().Add(-time.Second))
+	later := schedule(time.Now().Add(time.Hour))
+
+	t.Run("create is idempotent and limited", func(t *testing.T) {
+		again, err := storage.CreateScheduledMessage(ctx, domain.ScheduledMessage{
+			ChatId: chatID, SenderId: 1, Text: "позже", ClientMessageId: due.ClientMessageId, SendAt: due.SendAt,
+		}, 3)
+		require.NoError(t, err)
+		assert.Equal(t, due.Id, again.Id)
+
+		third := schedule(time.Now().Add(2 * time.Hour))
+		_, err = storage.CreateScheduledMessage(ctx, domain.ScheduledMessage{
+			ChatId: chatID, SenderId: 1, Text: "лишнее", ClientMessageId: uuid.NewString(), SendAt: time.Now().Add(time.Hour),
+		}, 3)
+		assert.ErrorIs(t, err, domain.ErrTooManyScheduled)
+		require.NoError(t, storage.DeleteScheduledMessage(ctx, 1, third.Id))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		list, err := storage.ListScheduledMessages(ctx, 1, chatID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, due.Id, list[0].Id)
+		assert.Equal(t, domain.ScheduledPending, list[0].Status)
+
+		list, err = storage.ListScheduledMessages(ctx, 2, 0)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("claim once", func(t *testing.T) {
+		claimed, err := storage.ClaimScheduledMessage(ctx, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, due.Id, claimed.Id)
+		assert.Equal(t, domain.ScheduledSending, claimed.Status)
+
+		// Второй раз сообщение не выдается, пока не истек таймаут отправки
+		_, err = storage.ClaimScheduledMessage(ctx, time.Minute)
+		assert.ErrorIs(t, err, domain.ErrScheduledNotFound)
+
+		_, err = storage.UpdateScheduledMessage(ctx, 1, due.Id, domain.ScheduledEdit{Text: &domain.TextEdit{Text: "нет"}})
+		assert.ErrorIs(t, err, domain.ErrScheduledSending)
+
+		// Брошенная отправка берется заново
+		reclaimed, err := storage.ClaimScheduledMessage(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, due.Id, reclaimed.Id)
+
+		require.NoError(t, storage.CompleteScheduledMessage(ctx, due.Id))
+		list, err := storage.ListScheduledMessages(ctx, 1, 0)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("failed message can be rescheduled", func(t *testing.T) {
+		sendAt := time.Now().Add(-time.Second)
+		_, err := storage.UpdateScheduledMessage(ctx, 1, later.Id, domain.ScheduledEdit{SendAt: &sendAt})
+		require.NoError(t, err)
+		claimed, err := storage.ClaimScheduledMessage(ctx, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, storage.FailScheduledMessage(ctx, claimed.Id, "not a chat member"))
+
+		list, err := storage.ListScheduledMessages(ctx, 1, chatID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, domain.ScheduledFailed, list[0].Status)
+		assert.Equal(t, "not a chat member", list[0].Error)
+
+		// Без нового времени сообщение не ставится в очередь: старое время уже прошло
+		updated, err := storage.UpdateScheduledMessage(ctx, 1, later.Id, domain.ScheduledEdit{Text: &domain.TextEdit{Text: "снова"}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.ScheduledFailed, updated.Status)
+		assert.Equal(t, "снова", updated.Text)
+		assert.Equal(t, "not a chat member", updated.Error)
+		_, err = storage.ClaimScheduledMessage(ctx, time.Minute)
+		assert.ErrorIs(t, err, domain.ErrScheduledNotFound)
+
+		sendAt = time.Now().Add(time.Hour)
+		updated, err = storage.UpdateScheduledMessage(ctx, 1, later.Id, domain.ScheduledEdit{SendAt: &sendAt})
+		require.NoError(t, err)
+		assert.Equal(t, domain.ScheduledPending, updated.Status)
+		assert.Equal(t, "снова", updated.Text)
+		assert.Empty(t, updated.Error)
+		_, err = storage.ClaimScheduledMessage(ctx, time.Minute)
+		assert.ErrorIs(t, err, domain.ErrScheduledNotFound)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		assert.ErrorIs(t, storage.DeleteScheduledMessage(ctx, 2, later.Id), domain.ErrScheduledNotFound)
+		require.NoError(t, storage.DeleteScheduledMessage(ctx, 1, later.Id))
+		assert.ErrorIs(t, storage.DeleteScheduledMessage(ctx, 1, later.Id), domain.ErrScheduledNotFound)
+	})
+}
@@ -0,0 +1,67 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+// searchHeadlineOptions - параметры фрагментов ts_headline. Текст экранируется до подсветки,
+// поэтому в сниппете безопасны только теги <mark>
+const searchHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=" ... "`
+
+// SearchMessages ищет неудаленные сообщения в чатах пользователя от новых к старым.
+// Запрос разбирается в обеих конфигурациях, сообщение находится, если совпала любая из них
+func (s *ChatStorage) SearchMessages(ctx context.Context, q domain.SearchQuery) ([]domain.SearchResult, error) {
+	query := `
+		WITH q AS (
+			SELECT websearch_to_tsquery('russian', $2) || websearch_to_tsquery('english', $2) AS query
+		)
+		SELECT m.chat_id, m.id, m.sender_id, m.created_at,
+		       ts_headline('russian',
+		                   replace(replace(replace(m.text, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+		                   q.query, $7)
+		FROM messages m
+		JOIN chats c ON c.id = m.chat_id
+		CROSS JOIN q
+		WHERE m.search_vector @@ q.query
+		  AND (c.user_1_id = $1 OR c.user_2_id = $1)
+		  AND ($3 = 0 OR m.chat_id = $3)
+		  AND m.deleted_at IS NULL
+		  AND (m.hidden_at IS NULL OR m.sender_id = $1)
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		  AND NOT EXISTS (SELECT 1 FROM message_deletions d WHERE d.message_id = m.id AND d.user_id = $1)
+		  AND ($4::timestamptz IS NULL OR (m.created_at, m.id) < ($4::timestamptz, $5))
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $6
+	`
+
+	var afterTime *time.Time
+	var afterID int
+	if q.After != nil {
+		afterTime = &q.After.CreatedAt
+		afterID = q.After.MessageId
+	}
+
+	rows, err := s.db.Query(ctx, query, q.UserId, q.Text, q.ChatId, afterTime, afterID, q.Limit, searchHeadlineOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]domain.SearchResult, 0, q.Limit)
+	for rows.Next() {
+		var r domain.SearchResult
+		if err := rows.Scan(&r.ChatId, &r.MessageId, &r.SenderId, &r.CreatedAt, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return results, nil
+}
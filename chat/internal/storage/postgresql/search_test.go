@@ -0,0 +1,81 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchMessages(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	user1 := 1
+	user2 := 2
+	user3 := 3
+
+	chat12, err := storage.CreateChat(ctx, user1, user2)
+	require.NoError(t, err)
+	chat23, err := storage.CreateChat(ctx, user2, user3)
+	require.NoError(t, err)
+
+	send := func(chatID, sender int, text string) int {
+		id, _, err := storage.SendMessage(ctx, domain.NewMessage{ChatId: chatID, SenderId: sender, Text: text})
+		require.NoError(t, err)
+		return id
+	}
+
+	m1 := send(chat12, user1, "Купил новые книги <b>по Go</b>")
+	m2 := send(chat12, user2, "Какую книгу читаешь?")
+	m3 := send(chat23, user3, "Книга про running и programming")
+	deleted := send(chat12, user1, "Удаленная книга")
+	hidden := send(chat12, user2, "Скрытая книга")
+
+	_, err = storage.DeleteMessage(ctx, chat12, deleted)
+	require.NoError(t, err)
+	require.NoError(t, storage.HideMessage(ctx, chat12, hidden, user1))
+
+	ids := func(results []domain.SearchResult) []int {
+		var out []int
+		for _, r := range results {
+			out = append(out, r.MessageId)
+		}
+		return out
+	}
+
+	t.Run("russian stemming in user chats", func(t *testing.T) {
+		results, err := storage.SearchMessages(ctx, domain.SearchQuery{UserId: user1, Text: "книга", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int{m2, m1}, ids(results))
+		assert.Contains(t, results[1].Snippet, "<mark>книги</mark>")
+		assert.Contains(t, results[1].Snippet, "&lt;b&gt;")
+	})
+
+	t.Run("english stemming", func(t *testing.T) {
+		results, err := storage.SearchMessages(ctx, domain.SearchQuery{UserId: user2, Text: "run", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int{m3}, ids(results))
+	})
+
+	t.Run("one chat with cursor", func(t *testing.T) {
+		first, err := storage.SearchMessages(ctx, domain.SearchQuery{UserId: user2, ChatId: chat12, Text: "книга", Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+		assert.Equal(t, hidden, first[0].MessageId)
+
+		next, err := storage.SearchMessages(ctx, domain.SearchQuery{
+			UserId: user2,
+			ChatId: chat12,
+			Text:   "книга",
+			Limit:  10,
+			After:  &domain.SearchCursor{CreatedAt: first[0].CreatedAt, MessageId: first[0].MessageId},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{m2, m1}, ids(next))
+	})
+}
@@ -0,0 +1,169 @@
+package postgresql
+
+import (
+	"chat/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const webhookColumns = `bot_id, url, last_error, last_error_at, updated_at,
+		(SELECT COUNT(*) FROM webhook_deliveries d WHERE d.bot_id = bot_webhooks.bot_id)`
+
+// SetWebhook устанавливает вебхук бота или заменяет адрес и ключ уже установленного.
+// Недоставленные события уйдут на новый адрес с новой подписью
+func (s *ChatStorage) SetWebhook(ctx context.Context, w domain.Webhook) (domain.Webhook, error) {
+	query := `
+		INSERT INTO bot_webhooks (bot_id, url, secret)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (bot_id) DO UPDATE
+		SET url = EXCLUDED.url, secret = EXCLUDED.secret, last_error = NULL, last_error_at = NULL, updated_at = NOW()
+		RETURNING ` + webhookColumns
+
+	saved, err := scanWebhook(s.db.QueryRow(ctx, query, w.BotId, w.URL, w.Secret))
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("failed to set webhook: %w", err)
+	}
+	saved.Secret = w.Secret
+
+	return saved, nil
+}
+
+// GetWebhook возвращает вебхук бота без ключа подписи
+func (s *ChatStorage) GetWebhook(ctx context.Context, botID int) (domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM bot_webhooks WHERE bot_id = $1`
+
+	w, err := scanWebhook(s.db.QueryRow(ctx, query, botID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Webhook{}, domain.ErrWebhookNotFound
+	}
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return w, nil
+}
+
+// DeleteWebhook удаляет вебхук бота вместе с недоставленными событиями
+func (s *ChatStorage) DeleteWebhook(ctx context.Context, botID int) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM bot_webhooks WHERE bot_id = $1`, botID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueWebhookDeliveries ставит событие в очередь тем из userIDs, у кого установлен вебхук.
+// Пользователи без вебхука, в том числе люди, просто не попадают в выборку
+func (s *ChatStorage) EnqueueWebhookDeliveries(ctx context.Context, userIDs []int, chatID int, event string, payload []byte) error {
+	query := `
+		INSERT INTO webhook_deliveries (bot_id, chat_id, event, payload)
+		SELECT bot_id, $2, $3, $4
+		FROM bot_webhooks
+		WHERE bot_id = ANY($1)
+	`
+
+	if _, err := s.db.Exec(ctx, query, userIDs, chatID, event, payload); err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries берет в работу до limit доставок, время которых подошло, и сдвигает их следующую
+// попытку на lease: если реплика не завершит доставку, ее возьмет другая. Строки, заблокированные
+// другими репликами, пропускаются
+func (s *ChatStorage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2::int)
+		FROM bot_webhooks w
+		WHERE w.bot_id = d.bot_id AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.id, d.bot_id, d.chat_id, d.event, d.payload, d.attempts, d.created_at, w.url, w.secret
+	`
+
+	rows, err := s.db.Query(ctx, query, limit, int(lease.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := rows.Scan(&d.Id, &d.BotId, &d.ChatId, &d.Event, &d.Payload, &d.Attempts, &d.CreatedAt, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// CompleteWebhookDelivery убирает доставленное событие из очереди
+func (s *ChatStorage) CompleteWebhookDelivery(ctx context.Context, id int64) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM webhook_deliveries WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to complete webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// RetryWebhookDelivery откладывает следующую попытку доставки до retryAt и запоминает ошибку в вебхуке
+func (s *ChatStorage) RetryWebhookDelivery(ctx context.Context, id int64, retryAt time.Time, reason string) error {
+	query := `
+		WITH d AS (
+			UPDATE webhook_deliveries SET next_attempt_at = $2 WHERE id = $1 RETURNING bot_id
+		)
+		UPDATE bot_webhooks SET last_error = $3, last_error_at = NOW()
+		WHERE bot_id IN (SELECT bot_id FROM d)
+	`
+
+	if _, err := s.db.Exec(ctx, query, id, retryAt, reason); err != nil {
+		return fmt.Errorf("failed to retry webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// DropWebhookDelivery убирает из очереди событие, исчерпавшее попытки, и запоминает ошибку в вебхуке
+func (s *ChatStorage) DropWebhookDelivery(ctx context.Context, id int64, reason string) error {
+	query := `
+		WITH d AS (
+			DELETE FROM webhook_deliveries WHERE id = $1 RETURNING bot_id
+		)
+		UPDATE bot_webhooks SET last_error = $2, last_error_at = NOW()
+		WHERE bot_id IN (SELECT bot_id FROM d)
+	`
+
+	if _, err := s.db.Exec(ctx, query, id, reason); err != nil {
+		return fmt.Errorf("failed to drop webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func scanWebhook(row pgx.Row) (domain.Webhook, error) {
+	var w domain.Webhook
+	var lastError *string
+	err := row.Scan(&w.BotId, &w.URL, &lastError, &w.LastErrorAt, &w.UpdatedAt, &w.PendingCount)
+	if lastError != nil {
+		w.LastError = *lastError
+	}
+	return w, err
+}
@@ -0,0 +1,95 @@
+package postgresql_test
+
+import (
+	"chat/internal/domain"
+	"chat/internal/storage/postgresql"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	ctx := context.Background()
+	pool := setupTestDB(t)
+	storage := postgresql.New(pool)
+
+	const botID = 10
+
+	_, err := storage.GetWebhook(ctx, botID)
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+
+	w, err := storage.SetWebhook(ctx, domain.Webhook{BotId: botID, URL: "https://example.com/old", Secret: "old"})
+	require.NoError(t, err)
+	assert.Equal(t, "old", w.Secret)
+
+	w, err = storage.SetWebhook(ctx, domain.Webhook{BotId: botID, URL: "https://example.com/hook", Secret: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", w.URL)
+
+	t.Run("enqueue only for bots with webhook", func(t *testing.T) {
+		require.NoError(t, storage.EnqueueWebhookDeliveries(ctx, []int{1, botID}, 5, domain.EventMessageCreated, []byte(`{"id":1}`)))
+
+		w, err := storage.GetWebhook(ctx, botID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, w.PendingCount)
+		assert.Empty(t, w.Secret)
+	})
+
+	t.Run("claim leases deliveries", func(t *testing.T) {
+		claimed, err := storage.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		d := claimed[0]
+		assert.Equal(t, botID, d.BotId)
+		assert.Equal(t, 5, d.ChatId)
+		assert.Equal(t, 1, d.Attempts)
+		assert.Equal(t, "secret", d.Secret)
+		assert.JSONEq(t, `{"id":1}`, string(d.Payload))
+
+		again, err := storage.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, again)
+
+		require.NoError(t, storage.RetryWebhookDelivery(ctx, d.Id, time.Now().Add(-time.Second), "unexpected status 500"))
+		claimed, err = storage.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, 2, claimed[0].Attempts)
+
+		w, err := storage.GetWebhook(ctx, botID)
+		require.NoError(t, err)
+		assert.Equal(t, "unexpected status 500", w.LastError)
+		assert.NotNil(t, w.LastErrorAt)
+
+		require.NoError(t, storage.CompleteWebhookDelivery(ctx, d.Id))
+		w, err = storage.GetWebhook(ctx, botID)
+		require.NoError(t, err)
+		assert.Zero(t, w.PendingCount)
+	})
+
+	t.Run("drop records error", func(t *testing.T) {
+		require.NoError(t, storage.EnqueueWebhookDeliveries(ctx, []int{botID}, 5, domain.EventMessageEdited, []byte(`{}`)))
+		claimed, err := storage.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		require.NoError(t, storage.DropWebhookDelivery(ctx, claimed[0].Id, "connection refused"))
+		w, err := storage.GetWebhook(ctx, botID)
+		require.NoError(t, err)
+		assert.Zero(t, w.PendingCount)
+		assert.Equal(t, "connection refused", w.LastError)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, storage.EnqueueWebhookDeliveries(ctx, []int{botID}, 5, domain.EventMessageDeleted, []byte(`{}`)))
+		require.NoError(t, storage.DeleteWebhook(ctx, botID))
+		assert.ErrorIs(t, storage.DeleteWebhook(ctx, botID), domain.ErrWebhookNotFound)
+
+		claimed, err := storage.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+	})
+}
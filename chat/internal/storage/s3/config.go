@@ -0,0 +1,10 @@
+package s3
+
+type Config struct {
+	Endpoint  string `env:"S3_ENDPOINT"`
+	AccessKey string `env:"S3_ACCESS_KEY"`
+	SecretKey string `env:"S3_SECRET_KEY"`
+	Bucket    string `env:"S3_BUCKET" envDefault:"attachments"`
+	Region    string `env:"S3_REGION"`
+	UseSSL    bool   `env:"S3_USE_SSL" envDefault:"false"`
+}
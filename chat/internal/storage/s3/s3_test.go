@@ -0,0 +1,82 @@
+package s3_test
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/storage/s3"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func setupTestStore(t *testing.T) *s3.BlobStore {
+	ctx := context.Background()
+
+	minioContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "minio/minio:latest",
+			ExposedPorts: []string{"9000/tcp"},
+			Env: map[string]string{
+				"MINIO_ROOT_USER":     "minioadmin",
+				"MINIO_ROOT_PASSWORD": "minioadmin",
+			},
+			Cmd: []string{"server", "/data"},
+			WaitingFor: wait.ForHTTP("/minio/health/live").
+				WithPort("9000/tcp").
+				WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if err := minioContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	endpoint, err := minioContainer.PortEndpoint(ctx, "9000/tcp", "")
+	require.NoError(t, err)
+
+	store, err := s3.New(ctx, s3.Config{
+		Endpoint:  endpoint,
+		AccessKey: "minioadmin",
+		SecretKey: "minioadmin",
+		Bucket:    "attachments",
+	})
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+
+	t.Run("put and get", func(t *testing.T) {
+		data := []byte("hello")
+		require.NoError(t, store.Put(ctx, "chats/1/file", bytes.NewReader(data), int64(len(data)), "text/plain"))
+
+		r, err := store.Get(ctx, "chats/1/file")
+		require.NoError(t, err)
+		defer r.Close()
+
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "chats/1/deleted", bytes.NewReader([]byte("x")), 1, "text/plain"))
+		require.NoError(t, store.Delete(ctx, "chats/1/deleted"))
+
+		_, err := store.Get(ctx, "chats/1/deleted")
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+	})
+}
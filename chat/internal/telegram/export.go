@@ -0,0 +1,155 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export - result.json из экспорта Telegram Desktop. Полный экспорт аккаунта содержит список чатов
+// и владельца, экспорт одного чата - только сам чат
+type Export struct {
+	OwnerId int64
+	Chats   []Chat
+}
+
+type Chat struct {
+	Id       int64     `json:"id"`
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Messages []Message `json:"messages"`
+}
+
+// ChatPersonal - тип личного чата в экспорте. Переносятся только такие чаты: групп у нас нет
+const ChatPersonal = "personal_chat"
+
+type Message struct {
+	Id         int64  `json:"id"`
+	Type       string `json:"type"`
+	Date       string `json:"date"`
+	DateUnix   string `json:"date_unixtime"`
+	EditedUnix string `json:"edited_unixtime"`
+	FromId     string `json:"from_id"`
+	Text       Text   `json:"text"`
+	ReplyToId  int64  `json:"reply_to_message_id"`
+	Photo      string `json:"photo"`
+	File       string `json:"file"`
+	MediaType  string `json:"media_type"`
+}
+
+// HasMedia - у сообщения есть фото, файл или стикер. Сами файлы не переносятся
+func (m Message) HasMedia() bool {
+	return m.Photo != "" || m.File != "" || m.MediaType != ""
+}
+
+// Time - время отправки. Telegram пишет date в локальном времени экспортировавшего, поэтому
+// предпочтительнее date_unixtime, который есть в экспортах с конца 2021 года
+func (m Message) Time() (time.Time, error) {
+	if m.DateUnix != "" {
+		return parseUnix(m.DateUnix)
+	}
+	t, err := time.Parse("2006-01-02T15:04:05", m.Date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", m.Date)
+	}
+	return t, nil
+}
+
+// EditedAt - время последней правки, nil, если сообщение не правилось
+func (m Message) EditedAt() *time.Time {
+	if m.EditedUnix == "" {
+		return nil
+	}
+	t, err := parseUnix(m.EditedUnix)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func parseUnix(s string) (time.Time, error) {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid unix time %q", s)
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
+
+// Text - текст сообщения. В экспорте это строка, а при наличии разметки - массив из строк
+// и объектов {"type": "bold", "text": "..."}. Разметка отбрасывается, адрес text_link дописывается в скобках
+type Text string
+
+func (t *Text) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = Text(s)
+		return nil
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return fmt.Errorf("text must be a string or an array: %w", err)
+	}
+
+	var b strings.Builder
+	for _, raw := range parts {
+		if err := json.Unmarshal(raw, &s); err == nil {
+			b.WriteString(s)
+			continue
+		}
+		var entity struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+			Href string `json:"href"`
+		}
+		if err := json.Unmarshal(raw, &entity); err != nil {
+			return fmt.Errorf("invalid text entity: %w", err)
+		}
+		b.WriteString(entity.Text)
+		if entity.Type == "text_link" && entity.Href != "" && entity.Href != entity.Text {
+			b.WriteString(" (" + entity.Href + ")")
+		}
+	}
+	*t = Text(b.String())
+	return nil
+}
+
+// Parse читает result.json в любом из двух форматов экспорта
+func Parse(r io.Reader) (Export, error) {
+	var raw struct {
+		PersonalInformation *struct {
+			UserId int64 `json:"user_id"`
+		} `json:"personal_information"`
+		Chats *struct {
+			List []Chat `json:"list"`
+		} `json:"chats"`
+		Chat
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return Export{}, fmt.Errorf("failed to decode export: %w", err)
+	}
+
+	var export Export
+	if raw.PersonalInformation != nil {
+		export.OwnerId = raw.PersonalInformation.UserId
+	}
+	switch {
+	case raw.Chats != nil:
+		export.Chats = raw.Chats.List
+	case raw.Type != "":
+		export.Chats = []Chat{raw.Chat}
+	default:
+		return Export{}, errors.New("no chats in export: expected result.json of Telegram Desktop in JSON format")
+	}
+
+	return export, nil
+}
+
+// userKey - идентификатор пользователя в формате from_id
+func userKey(id int64) string {
+	return "user" + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const fullExport = `{
+  "about": "Here is the data you requested.",
+  "personal_information": {"user_id": 100, "first_name": "Alice"},
+  "chats": {
+    "about": "This page lists all chats from this export.",
+    "list": [
+      {
+        "name": "Bob",
+        "type": "personal_chat",
+        "id": 200,
+        "messages": [
+          {"id": 1, "type": "message", "date": "2023-01-15T10:20:30", "date_unixtime": "1673778030",
+           "from": "Alice", "from_id": "user100", "text": "Привет!"},
+          {"id": 2, "type": "message", "date": "2023-01-15T10:21:00", "date_unixtime": "1673778060",
+           "edited": "2023-01-15T10:22:00", "edited_unixtime": "1673778120",
+           "from": "Bob", "from_id": "user200", "reply_to_message_id": 1,
+           "text": ["Смотри ", {"type": "bold", "text": "тут"}, ": ", {"type": "text_link", "text": "ссылка", "href": "https://example.com"}]},
+          {"id": 3, "type": "service", "date": "2023-01-15T10:23:00", "actor_id": "user100", "action": "phone_call", "text": ""}
+        ]
+      },
+      {"name": "Team", "type": "private_group", "id": 300, "messages": []}
+    ]
+  }
+}`
+
+const chatExport = `{
+  "name": "Bob",
+  "type": "personal_chat",
+  "id": 200,
+  "messages": [
+    {"id": 7, "type": "message", "date": "2020-03-01T09:00:00", "from": "Bob", "from_id": "user200",
+     "photo": "photos/photo_1.jpg", "width": 800, "height": 600, "text": ""}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	t.Run("full export", func(t *testing.T) {
+		export, err := Parse(strings.NewReader(fullExport))
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if export.OwnerId != 100 || len(export.Chats) != 2 {
+			t.Fatalf("Parse() = %+v", export)
+		}
+
+		msgs := export.Chats[0].Messages
+		if got := string(msgs[1].Text); got != "Смотри тут: ссылка (https://example.com)" {
+			t.Errorf("text = %q", got)
+		}
+		created, err := msgs[1].Time()
+		if err != nil || !created.Equal(time.Unix(1673778060, 0)) {
+			t.Errorf("Time() = %v, %v", created, err)
+		}
+		if edited := msgs[1].EditedAt(); edited == nil || !edited.Equal(time.Unix(1673778120, 0)) {
+			t.Errorf("EditedAt() = %v", edited)
+		}
+		if msgs[0].EditedAt() != nil {
+			t.Error("EditedAt() of not edited message is not nil")
+		}
+	})
+
+	t.Run("single chat export", func(t *testing.T) {
+		export, err := Parse(strings.NewReader(chatExport))
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if export.OwnerId != 0 || len(export.Chats) != 1 || export.Chats[0].Id != 200 {
+			t.Fatalf("Parse() = %+v", export)
+		}
+
+		m := export.Chats[0].Messages[0]
+		if !m.HasMedia() {
+			t.Error("HasMedia() = false for photo")
+		}
+		created, err := m.Time()
+		if err != nil || !created.Equal(time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC)) {
+			t.Errorf("Time() without date_unixtime = %v, %v", created, err)
+		}
+	})
+
+	t.Run("not an export", func(t *testing.T) {
+		if _, err := Parse(strings.NewReader(`{"foo": 1}`)); err == nil {
+			t.Error("Parse() error = nil for unknown format")
+		}
+	})
+
+	t.Run("invalid text", func(t *testing.T) {
+		if _, err := Parse(strings.NewReader(`{"type": "personal_chat", "messages": [{"text": 5}]}`)); err == nil {
+			t.Error("Parse() error = nil for numeric text")
+		}
+	})
+}
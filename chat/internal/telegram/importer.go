@@ -0,0 +1,215 @@
+package telegram
+
+import (
+	"chat/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+//go:generate mockgen -destination=./mock/mock.go -package=mock -source=importer.go
+
+// Store сохраняет подготовленную историю чата
+type Store interface {
+	ImportChat(ctx context.Context, chat domain.ImportedChat) (domain.ImportResult, error)
+}
+
+// Mapping сопоставляет пользователей Telegram (from_id вида "user123456") с id пользователей мессенджера
+type Mapping map[string]int
+
+// LoadMapping читает сопоставление из JSON-объекта {"user123456": 1, ...}
+func LoadMapping(r io.Reader) (Mapping, error) {
+	var m Mapping
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode mapping: %w", err)
+	}
+	for key, id := range m {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid user id %d for %q", id, key)
+		}
+	}
+	return m, nil
+}
+
+type Options struct {
+	// DryRun - только разобрать экспорт и составить отчет, ничего не записывая
+	DryRun bool
+	// MaxMessageLength - сообщения длиннее пропускаются, как их не принял бы сервис; 0 - без ограничения
+	MaxMessageLength int
+}
+
+// Report - отчет об импорте: перенесенные чаты и все пропущенные чаты и сообщения с причиной
+type Report struct {
+	DryRun  bool         `json:"dry_run"`
+	Chats   []ChatReport `json:"chats"`
+	Skipped []Skipped    `json:"skipped"`
+}
+
+type ChatReport struct {
+	TelegramId int64  `json:"telegram_id"`
+	Name       string `json:"name"`
+	UserIds    [2]int `json:"user_ids"`
+	Messages   int    `json:"messages"`
+
+	// Заполняются только при реальном импорте
+	ChatId     int  `json:"chat_id,omitempty"`
+	Created    bool `json:"created,omitempty"`
+	Imported   int  `json:"imported,omitempty"`
+	Duplicates int  `json:"duplicates,omitempty"`
+}
+
+// Skipped - пропущенный чат (MessageId пустой) или сообщение
+type Skipped struct {
+	TelegramChatId int64  `json:"telegram_chat_id"`
+	MessageId      int64  `json:"message_id,omitempty"`
+	Reason         string `json:"reason"`
+}
+
+type Importer struct {
+	store   Store
+	mapping Mapping
+	opts    Options
+}
+
+func NewImporter(store Store, mapping Mapping, opts Options) *Importer {
+	return &Importer{store: store, mapping: mapping, opts: opts}
+}
+
+// Import переносит личные чаты экспорта. Ошибка хранилища прерывает импорт, отчет при этом
+// содержит уже перенесенные чаты: каждый чат импортируется своей транзакцией
+func (im *Importer) Import(ctx context.Context, export Export) (Report, error) {
+	report := Report{DryRun: im.opts.DryRun, Chats: []ChatReport{}, Skipped: []Skipped{}}
+
+	for _, tgChat := range export.Chats {
+		chat, skipped, err := im.convert(export.OwnerId, tgChat)
+		report.Skipped = append(report.Skipped, skipped...)
+		if err != nil {
+			report.Skipped = append(report.Skipped, Skipped{TelegramChatId: tgChat.Id, Reason: err.Error()})
+			continue
+		}
+
+		cr := ChatReport{
+			TelegramId: tgChat.Id,
+			Name:       tgChat.Name,
+			UserIds:    [2]int{chat.User1Id, chat.User2Id},
+			Messages:   len(chat.Messages),
+		}
+		if !im.opts.DryRun {
+			res, err := im.store.ImportChat(ctx, chat)
+			if err != nil {
+				return report, fmt.Errorf("failed to import chat %d: %w", tgChat.Id, err)
+			}
+			cr.ChatId, cr.Created, cr.Imported, cr.Duplicates = res.ChatId, res.Created, res.Imported, res.Duplicates
+		}
+		report.Chats = append(report.Chats, cr)
+	}
+
+	return report, nil
+}
+
+// convert готовит чат к импорту. Ошибка означает, что чат пропускается целиком,
+// skipped - отдельные сообщения, которые не переносятся
+func (im *Importer) convert(ownerID int64, tgChat Chat) (domain.ImportedChat, []Skipped, error) {
+	if tgChat.Type != ChatPersonal {
+		return domain.ImportedChat{}, nil, fmt.Errorf("chat type %q is not supported, only personal chats are imported", tgChat.Type)
+	}
+
+	users, err := im.participants(ownerID, tgChat)
+	if err != nil {
+		return domain.ImportedChat{}, nil, err
+	}
+
+	chat := domain.ImportedChat{User1Id: users[0], User2Id: users[1]}
+	var skipped []Skipped
+	skip := func(m Message, reason string) {
+		skipped = append(skipped, Skipped{TelegramChatId: tgChat.Id, MessageId: m.Id, Reason: reason})
+	}
+	for _, m := range tgChat.Messages {
+		if m.Type != "message" {
+			skip(m, "service message")
+			continue
+		}
+		createdAt, err := m.Time()
+		if err != nil {
+			skip(m, err.Error())
+			continue
+		}
+		text := string(m.Text)
+		if text == "" {
+			skip(m, "media without text is not imported")
+			continue
+		}
+		if im.opts.MaxMessageLength > 0 && utf8.RuneCountInString(text) > im.opts.MaxMessageLength {
+			skip(m, "message is too long")
+			continue
+		}
+		if m.HasMedia() {
+			skip(m, "attachment is not imported, text is kept")
+		}
+
+		msg := domain.ImportedMessage{
+			ImportId:  importID(tgChat.Id, m.Id),
+			SenderId:  im.mapping[m.FromId],
+			Text:      text,
+			CreatedAt: createdAt,
+			EditedAt:  m.EditedAt(),
+		}
+		if m.ReplyToId != 0 {
+			msg.ReplyToImportId = importID(tgChat.Id, m.ReplyToId)
+		}
+		chat.Messages = append(chat.Messages, msg)
+	}
+
+	if len(chat.Messages) == 0 {
+		return domain.ImportedChat{}, skipped, errors.New("no messages to import")
+	}
+	return chat, skipped, nil
+}
+
+// participants находит двух участников личного чата: собеседника (id чата), владельца экспорта
+// и авторов сообщений. Все они должны быть в сопоставлении
+func (im *Importer) participants(ownerID int64, tgChat Chat) ([2]int, error) {
+	keys := map[string]struct{}{userKey(tgChat.Id): {}}
+	if ownerID != 0 {
+		keys[userKey(ownerID)] = struct{}{}
+	}
+	for _, m := range tgChat.Messages {
+		if m.Type == "message" && m.FromId != "" {
+			keys[m.FromId] = struct{}{}
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	ids := map[int]struct{}{}
+	for _, key := range sorted {
+		id, ok := im.mapping[key]
+		if !ok {
+			return [2]int{}, fmt.Errorf("participant %s is not in mapping", key)
+		}
+		ids[id] = struct{}{}
+	}
+	if len(ids) != 2 {
+		return [2]int{}, fmt.Errorf("expected 2 participants, got %d", len(ids))
+	}
+
+	users := make([]int, 0, 2)
+	for id := range ids {
+		users = append(users, id)
+	}
+	sort.Ints(users)
+	return [2]int{users[0], users[1]}, nil
+}
+
+func importID(chatID, messageID int64) string {
+	return "telegram:" + strconv.FormatInt(chatID, 10) + ":" + strconv.FormatInt(messageID, 10)
+}
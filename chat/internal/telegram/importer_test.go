@@ -0,0 +1,117 @@
+package telegram
+
+import (
+	"chat/internal/domain"
+	"chat/internal/telegram/mock"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestLoadMapping(t *testing.T) {
+	m, err := LoadMapping(strings.NewReader(`{"user100": 1, "user200": 2}`))
+	if err != nil || m["user200"] != 2 {
+		t.Errorf("LoadMapping() = %v, %v", m, err)
+	}
+
+	if _, err := LoadMapping(strings.NewReader(`{"user100": 0}`)); err == nil {
+		t.Error("LoadMapping() error = nil for zero user id")
+	}
+}
+
+func TestImporter_Import(t *testing.T) {
+	export, err := Parse(strings.NewReader(fullExport))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	mapping := Mapping{"user100": 2, "user200": 1}
+	ctx := context.Background()
+
+	edited := time.Unix(1673778120, 0).UTC()
+	want := domain.ImportedChat{
+		User1Id: 1,
+		User2Id: 2,
+		Messages: []domain.ImportedMessage{
+			{ImportId: "telegram:200:1", SenderId: 2, Text: "Привет!", CreatedAt: time.Unix(1673778030, 0).UTC()},
+			{ImportId: "telegram:200:2", SenderId: 1, Text: "Смотри тут: ссылка (https://example.com)",
+				CreatedAt: time.Unix(1673778060, 0).UTC(), EditedAt: &edited, ReplyToImportId: "telegram:200:1"},
+		},
+	}
+
+	t.Run("import", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+		store.EXPECT().ImportChat(gomock.Any(), want).Return(domain.ImportResult{ChatId: 9, Created: true, Imported: 2}, nil)
+
+		report, err := NewImporter(store, mapping, Options{}).Import(ctx, export)
+		if err != nil {
+			t.Fatalf("Importer.Import() error = %v", err)
+		}
+		if len(report.Chats) != 1 || report.Chats[0].ChatId != 9 || report.Chats[0].Imported != 2 {
+			t.Errorf("report chats = %+v", report.Chats)
+		}
+		// служебное сообщение и групповой чат
+		if len(report.Skipped) != 2 || report.Skipped[0].MessageId != 3 || report.Skipped[1].TelegramChatId != 300 {
+			t.Errorf("report skipped = %+v", report.Skipped)
+		}
+	})
+
+	t.Run("dry run does not touch store", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+
+		report, err := NewImporter(store, mapping, Options{DryRun: true}).Import(ctx, export)
+		if err != nil {
+			t.Fatalf("Importer.Import() error = %v", err)
+		}
+		if !report.DryRun || len(report.Chats) != 1 || report.Chats[0].Messages != 2 || report.Chats[0].ChatId != 0 {
+			t.Errorf("report = %+v", report)
+		}
+	})
+
+	t.Run("unmapped participant", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+
+		report, err := NewImporter(store, Mapping{"user100": 2}, Options{}).Import(ctx, export)
+		if err != nil {
+			t.Fatalf("Importer.Import() error = %v", err)
+		}
+		if len(report.Chats) != 0 || report.Skipped[0].Reason != "participant user200 is not in mapping" {
+			t.Errorf("report = %+v", report)
+		}
+	})
+
+	t.Run("too long and media messages", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+		chat := Chat{Id: 200, Type: ChatPersonal, Messages: []Message{
+			{Id: 1, Type: "message", DateUnix: "1", FromId: "user100", Text: "длинный текст"},
+			{Id: 2, Type: "message", DateUnix: "2", FromId: "user200", Text: "фото", Photo: "photos/1.jpg"},
+			{Id: 3, Type: "message", DateUnix: "3", FromId: "user200", File: "files/a.pdf"},
+		}}
+		store.EXPECT().ImportChat(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c domain.ImportedChat) (domain.ImportResult, error) {
+			if len(c.Messages) != 1 || c.Messages[0].Text != "фото" {
+				t.Errorf("imported messages = %+v", c.Messages)
+			}
+			return domain.ImportResult{ChatId: 1, Imported: 1}, nil
+		})
+
+		report, err := NewImporter(store, mapping, Options{MaxMessageLength: 5}).Import(ctx, Export{Chats: []Chat{chat}})
+		if err != nil {
+			t.Fatalf("Importer.Import() error = %v", err)
+		}
+		if len(report.Skipped) != 3 {
+			t.Errorf("report skipped = %+v", report.Skipped)
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+		store.EXPECT().ImportChat(gomock.Any(), gomock.Any()).Return(domain.ImportResult{}, errors.New("db is down"))
+
+		if _, err := NewImporter(store, mapping, Options{}).Import(ctx, export); err == nil {
+			t.Error("Importer.Import() error = nil")
+		}
+	})
+}
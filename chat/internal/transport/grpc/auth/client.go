@@ -1,14 +1,18 @@
 package auth
 
 import (
+	"chat/internal/domain"
 	"chat/pkg/logger"
 	"context"
+	"fmt"
 	"time"
 
 	authpb "chat/pkg/api/auth_pb"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 type AuthClient struct {
@@ -53,16 +57,168 @@ func (ac *AuthClient) Close() error {
 }
 
 func (ac *AuthClient) GetId(ctx context.Context, token string) (int, error) {
+	identity, err := ac.Authenticate(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return identity.UserId, nil
+}
+
+// Authenticate проверяет токен и возвращает пользователя с его ролью. Заблокированному пользователю
+// auth-сервис отвечает PermissionDenied, это превращается в ErrUserSuspended
+func (ac *AuthClient) Authenticate(ctx context.Context, token string) (domain.Identity, error) {
 	if ac.conn == nil {
 		if err := ac.connect(ctx); err != nil {
-			return -1, err
+			return domain.Identity{}, err
 		}
 	}
 
 	resp, err := ac.client.Validate(ctx, &authpb.Token{Token: token})
+	if status.Code(err) == codes.PermissionDenied {
+		return domain.Identity{}, domain.ErrUserSuspended
+	}
 	if err != nil{
 		logger.GetFromCtx(ctx).ErrorContext(ctx, "error in grpc service", err)
-		return 0, err
+		return domain.Identity{}, err
+	}
+
+	role := resp.GetRole()
+	if role == "" {
+		role = domain.RoleUser
+	}
+	return domain.Identity{UserId: int(resp.GetUserId()), Role: role, Token: token}, nil
+}
+
+// GetAccount собирает профиль, сессии и устройства пользователя для выгрузки данных
+func (ac *AuthClient) GetAccount(ctx context.Context, token string) (domain.Account, error) {
+	if ac.conn == nil {
+		if err := ac.connect(ctx); err != nil {
+			return domain.Account{}, err
+		}
+	}
+
+	user, err := ac.client.GetUser(ctx, &authpb.Token{Token: token})
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "error in grpc service", err)
+		return domain.Account{}, err
+	}
+	sessions, err := ac.client.GetSessions(ctx, &authpb.Token{Token: token})
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "error in grpc service", err)
+		return domain.Account{}, err
+	}
+
+	account := domain.Account{
+		Login:    user.GetLogin(),
+		Email:    user.GetEmail(),
+		Name:     user.GetName(),
+		Sessions: make([]domain.Session, 0, len(sessions.GetSessions())),
+		Devices:  make([]domain.Device, 0, len(sessions.GetDevices())),
+	}
+	for _, s := range sessions.GetSessions() {
+		account.Sessions = append(account.Sessions, domain.Session{
+			IssuedAt:  unixTime(s.GetIssuedAt()),
+			ExpiresAt: unixTime(s.GetExpiresAt()),
+		})
+	}
+	for _, d := range sessions.GetDevices() {
+		account.Devices = append(account.Devices, domain.Device{
+			DeviceId:  d.GetDeviceId(),
+			UpdatedAt: time.Unix(d.GetUpdatedAt(), 0).UTC(),
+		})
+	}
+	return account, nil
+}
+
+// unixTime - нулевая метка означает, что auth-сервис время не знает
+func unixTime(sec int64) *time.Time {
+	if sec == 0 {
+		return nil
+	}
+	t := time.Unix(sec, 0).UTC()
+	return &t
+}
+
+// ListUsers ищет пользователей от имени администратора admin
+func (ac *AuthClient) ListUsers(ctx context.Context, admin domain.Identity, q domain.UserQuery) ([]domain.User, error) {
+	if ac.conn == nil {
+		if err := ac.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := ac.client.ListUsers(ctx, &authpb.ListUsersRequest{
+		Token:  admin.Token,
+		Query:  q.Query,
+		Limit:  int32(q.Limit),
+		Offset: int32(q.Offset),
+	})
+	if err != nil {
+		return nil, adminError(ctx, err)
+	}
+
+	users := make([]domain.User, 0, len(resp.GetUsers()))
+	for _, u := range resp.GetUsers() {
+		users = append(users, userFromProto(u))
+	}
+	return users, nil
+}
+
+func (ac *AuthClient) SuspendUser(ctx context.Context, admin domain.Identity, s domain.Suspension) (domain.User, error) {
+	if ac.conn == nil {
+		if err := ac.connect(ctx); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	req := &authpb.SuspendUserRequest{Token: admin.Token, UserId: int32(s.UserId), Reason: s.Reason}
+	if s.Until != nil {
+		req.Until = s.Until.Unix()
+	}
+	user, err := ac.client.SuspendUser(ctx, req)
+	if err != nil {
+		return domain.User{}, adminError(ctx, err)
 	}
-	return int(resp.GetUserId()), nil
-}
\ No newline at end of file
+	return userFromProto(user), nil
+}
+
+func (ac *AuthClient) UnsuspendUser(ctx context.Context, admin domain.Identity, userID int) (domain.User, error) {
+	if ac.conn == nil {
+		if err := ac.connect(ctx); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	user, err := ac.client.UnsuspendUser(ctx, &authpb.UnsuspendUserRequest{Token: admin.Token, UserId: int32(userID)})
+	if err != nil {
+		return domain.User{}, adminError(ctx, err)
+	}
+	return userFromProto(user), nil
+}
+
+// adminError переводит коды ответа административных методов auth-сервиса в ошибки домена
+func adminError(ctx context.Context, err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return domain.ErrUserNotFound
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return domain.ErrAdminRequired
+	case codes.InvalidArgument:
+		return fmt.Errorf("%w: %s", domain.ErrInvalidSuspension, status.Convert(err).Message())
+	}
+	logger.GetFromCtx(ctx).ErrorContext(ctx, "error in grpc service", err)
+	return err
+}
+
+func userFromProto(u *authpb.User) domain.User {
+	return domain.User{
+		Id:             int(u.GetId()),
+		Login:          u.GetLogin(),
+		Email:          u.GetEmail(),
+		Name:           u.GetName(),
+		Role:           u.GetRole(),
+		Suspended:      u.GetSuspended(),
+		SuspendedUntil: unixTime(u.GetSuspendedUntil()),
+		SuspendReason:  u.GetSuspendReason(),
+	}
+}
@@ -0,0 +1,9 @@
+package grpcserver
+
+type Config struct {
+	Port string `env:"GRPC_PORT" envDefault:"50052"`
+}
+
+func (c *Config) Address() string {
+	return "0.0.0.0:" + c.Port
+}
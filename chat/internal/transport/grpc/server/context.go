@@ -0,0 +1,6 @@
+package grpcserver
+
+const (
+	UserIdKey   = "user_id_key"
+	IdentityKey = "identity_key"
+)
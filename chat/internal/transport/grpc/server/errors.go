@@ -0,0 +1,52 @@
+package grpcserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// serviceError переводит доменные ошибки сервиса в gRPC-статусы так же, как writeServiceError HTTP-сервера
+func serviceError(ctx context.Context, prefix string, err error) error {
+	var rateErr *domain.RateLimitError
+	switch {
+	case errors.As(err, &rateErr):
+		st, detailsErr := status.New(codes.ResourceExhausted, err.Error()).
+			WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(rateErr.RetryAfter)})
+		if detailsErr != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return st.Err()
+	case errors.Is(err, domain.ErrNotChatMember),
+		errors.Is(err, domain.ErrNotMessageSender),
+		errors.Is(err, domain.ErrEditWindowExpired),
+		errors.Is(err, domain.ErrUserBlocked):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrMessageNotFound),
+		errors.Is(err, domain.ErrAttachmentNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrMessageDeleted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidEmoji),
+		errors.Is(err, domain.ErrInvalidClientId),
+		errors.Is(err, domain.ErrTooManyAttachments),
+		errors.Is(err, domain.ErrEmptySearchQuery),
+		errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrInvalidBlock),
+		errors.Is(err, domain.ErrInvalidMuteTimeout),
+		errors.Is(err, domain.ErrMessageTooLong),
+		errors.Is(err, domain.ErrInvalidEnvelope),
+		errors.Is(err, domain.ErrMessageEncrypted),
+		errors.Is(err, domain.ErrInvalidEntities):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		logger.GetFromCtx(ctx).ErrorContext(ctx, prefix, err)
+		return status.Error(codes.Internal, prefix+": "+err.Error())
+	}
+}
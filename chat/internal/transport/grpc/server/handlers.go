@@ -0,0 +1,293 @@
+package grpcserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/api/chat_pb"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//go:generate mockgen -destination=./mock/mock_handlers.go -package=mock -source=handlers.go
+
+type ChatService interface {
+	StartChat(ctx context.Context, userID1, userID2 int) (int, error)
+	PostMessage(ctx context.Context, msg domain.NewMessage) (int, error)
+	GetMessages(ctx context.Context, chatID, userID int, limit, offset int) ([]domain.Message, error)
+	GetUserChats(ctx context.Context, userID int) ([]int, error)
+	CheckMembership(ctx context.Context, chatID, userID int) (bool, error)
+	SubscribeChat(ctx context.Context, chatID, userID int) (<-chan domain.Event, error)
+}
+
+type Handler struct {
+	chat_pb.UnimplementedChatServiceServer
+
+	srv ChatService
+	// closing закрывается при остановке сервера, чтобы завершить открытые подписки
+	closing chan struct{}
+}
+
+func NewHandler(srv ChatService) *Handler {
+	return &Handler{srv: srv, closing: make(chan struct{})}
+}
+
+func (h *Handler) CreateChat(ctx context.Context, req *chat_pb.CreateChatRequest) (*chat_pb.CreateChatResponse, error) {
+	userID, err := userIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chatID, err := h.srv.StartChat(ctx, userID, int(req.GetUserId()))
+	if err != nil {
+		return nil, serviceError(ctx, "failed to create chat", err)
+	}
+
+	return &chat_pb.CreateChatResponse{ChatId: int64(chatID)}, nil
+}
+
+func (h *Handler) SendMessage(ctx context.Context, req *chat_pb.SendMessageRequest) (*chat_pb.SendMessageResponse, error) {
+	userID, err := userIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetChatId() <= 0 || (req.GetText() == "" && len(req.GetAttachmentIds()) == 0 && req.GetEnvelope() == nil) {
+		return nil, status.Error(codes.InvalidArgument, "chat_id and text, attachment_ids or envelope are required")
+	}
+
+	attachmentIDs := make([]int, 0, len(req.GetAttachmentIds()))
+	for _, id := range req.GetAttachmentIds() {
+		attachmentIDs = append(attachmentIDs, int(id))
+	}
+
+	messageID, err := h.srv.PostMessage(ctx, domain.NewMessage{
+		ChatId:          int(req.GetChatId()),
+		SenderId:        userID,
+		Text:            req.GetText(),
+		Format:          req.GetFormat(),
+		Entities:        entitiesFromProto(req.GetEntities()),
+		ReplyToId:       int(req.GetReplyToMessageId()),
+		AttachmentIds:   attachmentIDs,
+		ClientMessageId: req.GetClientMessageId(),
+		Envelope:        envelopeFromProto(req.GetEnvelope()),
+	})
+	if err != nil {
+		return nil, serviceError(ctx, "failed to send message", err)
+	}
+
+	return &chat_pb.SendMessageResponse{MessageId: int64(messageID)}, nil
+}
+
+// ListMessages отдает сообщения только участнику чата
+func (h *Handler) ListMessages(ctx context.Context, req *chat_pb.ListMessagesRequest) (*chat_pb.ListMessagesResponse, error) {
+	userID, err := userIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chatID := int(req.GetChatId())
+
+	// Участие в чате проверяет сервис, как и для HTTP
+	messages, err := h.srv.GetMessages(ctx, chatID, userID, int(req.GetLimit()), int(req.GetOffset()))
+	if err != nil {
+		return nil, serviceError(ctx, "failed to get messages", err)
+	}
+
+	resp := &chat_pb.ListMessagesResponse{Messages: make([]*chat_pb.Message, 0, len(messages))}
+	for _, msg := range messages {
+		resp.Messages = append(resp.Messages, messageToProto(msg))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) ListChats(ctx context.Context, _ *chat_pb.ListChatsRequest) (*chat_pb.ListChatsResponse, error) {
+	userID, err := userIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chats, err := h.srv.GetUserChats(ctx, userID)
+	if err != nil {
+		return nil, serviceError(ctx, "failed to get chats", err)
+	}
+
+	resp := &chat_pb.ListChatsResponse{ChatIds: make([]int64, 0, len(chats))}
+	for _, id := range chats {
+		resp.ChatIds = append(resp.ChatIds, int64(id))
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) CheckMembership(ctx context.Context, req *chat_pb.CheckMembershipRequest) (*chat_pb.CheckMembershipResponse, error) {
+	userID, err := userIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := h.srv.CheckMembership(ctx, int(req.GetChatId()), userID)
+	if err != nil {
+		return nil, serviceError(ctx, "failed to check membership", err)
+	}
+
+	return &chat_pb.CheckMembershipResponse{IsMember: isMember}, nil
+}
+
+// SubscribeChat передает события чата, пока клиент не отключится или сервер не начнет остановку.
+// Если подписчик не успевает читать события, стрим завершается с codes.Aborted
+func (h *Handler) SubscribeChat(req *chat_pb.SubscribeChatRequest, stream chat_pb.ChatService_SubscribeChatServer) error {
+	ctx := stream.Context()
+	userID, err := userIDFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	events, err := h.srv.SubscribeChat(ctx, int(req.GetChatId()), userID)
+	if err != nil {
+		return serviceError(ctx, "failed to subscribe", err)
+	}
+
+	for {
+		select {
+		case <-h.closing:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return status.FromContextError(ctx.Err()).Err()
+				}
+				return status.Error(codes.Aborted, "subscription is too slow, resync required")
+			}
+
+			payload, err := json.Marshal(event.Payload)
+			if err != nil {
+				logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to marshal event payload", err)
+				continue
+			}
+			if err := stream.Send(&chat_pb.ChatEvent{
+				Type:    event.Type,
+				ChatId:  int64(event.ChatId),
+				Payload: payload,
+				Muted:   slices.Contains(event.MutedUserIds, userID),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// close завершает открытые подписки перед остановкой сервера
+func (h *Handler) close() {
+	close(h.closing)
+}
+
+func userIDFromCtx(ctx context.Context) (int, error) {
+	id, err := strconv.Atoi(fmt.Sprintf("%v", ctx.Value(UserIdKey)))
+	if err != nil {
+		return 0, status.Error(codes.Unauthenticated, "invalid user ID")
+	}
+	return id, nil
+}
+
+func messageToProto(msg domain.Message) *chat_pb.Message {
+	senderID, _ := strconv.ParseInt(msg.SenderId, 10, 64)
+	pb := &chat_pb.Message{
+		Id:        int64(msg.Id),
+		SenderId:  senderID,
+		Text:      msg.Text,
+		CreatedAt: timestamppb.New(msg.CreatedAt),
+		IsRead:    msg.IsRead,
+		Mentioned: msg.Mentioned,
+	}
+	if msg.EditedAt != nil {
+		pb.EditedAt = timestamppb.New(*msg.EditedAt)
+	}
+	if msg.DeletedAt != nil {
+		pb.DeletedAt = timestamppb.New(*msg.DeletedAt)
+	}
+	if msg.ReplyTo != nil {
+		pb.ReplyToMessageId = int64(msg.ReplyTo.Id)
+	}
+	if msg.ForwardedFrom != nil {
+		pb.ForwardedFromSenderId, _ = strconv.ParseInt(msg.ForwardedFrom.SenderId, 10, 64)
+	}
+	for _, r := range msg.Reactions {
+		pb.Reactions = append(pb.Reactions, &chat_pb.Reaction{
+			Emoji:       r.Emoji,
+			Count:       int32(r.Count),
+			ReactedByMe: r.ReactedByMe,
+		})
+	}
+	for _, a := range msg.Attachments {
+		pb.Attachments = append(pb.Attachments, &chat_pb.Attachment{
+			Id:          int64(a.Id),
+			FileName:    a.FileName,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+		})
+	}
+	for _, e := range msg.Entities {
+		pb.Entities = append(pb.Entities, &chat_pb.MessageEntity{
+			Type:   e.Type,
+			Offset: int32(e.Offset),
+			Length: int32(e.Length),
+			Url:    e.URL,
+			UserId: int64(e.UserId),
+		})
+	}
+	if env := msg.Envelope; env != nil {
+		pb.Envelope = &chat_pb.Envelope{
+			Algorithm:      env.Algorithm,
+			SenderDeviceId: env.SenderDeviceId,
+			Ciphertext:     env.Ciphertext,
+		}
+		for _, h := range env.Headers {
+			pb.Envelope.Headers = append(pb.Envelope.Headers, &chat_pb.KeyHeader{
+				UserId:   int64(h.UserId),
+				DeviceId: h.DeviceId,
+				Header:   h.Header,
+			})
+		}
+	}
+	return pb
+}
+
+func envelopeFromProto(pb *chat_pb.Envelope) *domain.Envelope {
+	if pb == nil {
+		return nil
+	}
+	env := &domain.Envelope{
+		Algorithm:      pb.GetAlgorithm(),
+		SenderDeviceId: pb.GetSenderDeviceId(),
+		Ciphertext:     pb.GetCiphertext(),
+	}
+	for _, h := range pb.GetHeaders() {
+		env.Headers = append(env.Headers, domain.KeyHeader{
+			UserId:   int(h.GetUserId()),
+			DeviceId: h.GetDeviceId(),
+			Header:   h.GetHeader(),
+		})
+	}
+	return env
+}
+
+func entitiesFromProto(pbs []*chat_pb.MessageEntity) []domain.MessageEntity {
+	var entities []domain.MessageEntity
+	for _, e := range pbs {
+		entities = append(entities, domain.MessageEntity{
+			Type:   e.GetType(),
+			Offset: int(e.GetOffset()),
+			Length: int(e.GetLength()),
+			URL:    e.GetUrl(),
+			UserId: int(e.GetUserId()),
+		})
+	}
+	return entities
+}
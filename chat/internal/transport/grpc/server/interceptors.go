@@ -0,0 +1,79 @@
+package grpcserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate mockgen -destination=./mock/mock_interceptors.go -package=mock -source=interceptors.go
+
+type Auther interface {
+	Authenticate(ctx context.Context, token string) (domain.Identity, error)
+}
+
+// authorizationKey - метаданные с JWT пользователя в виде "Bearer <token>"
+const authorizationKey = "authorization"
+
+func UnaryInterceptor(ctx context.Context, auther Auther) grpc.UnaryServerInterceptor {
+	return func(reqCtx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		reqCtx, err := authenticate(logger.InitFromCtx(reqCtx, logger.GetFromCtx(ctx)), auther)
+		if err != nil {
+			return nil, err
+		}
+		return handler(reqCtx, req)
+	}
+}
+
+func StreamInterceptor(ctx context.Context, auther Auther) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		streamCtx, err := authenticate(logger.InitFromCtx(ss.Context(), logger.GetFromCtx(ctx)), auther)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: streamCtx})
+	}
+}
+
+// authenticate проверяет токен из метаданных и кладет в контекст пользователя с его ролью, как AuthMiddleware
+// HTTP-сервера. Заблокированный пользователь получает PermissionDenied
+func authenticate(ctx context.Context, auther Auther) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationKey)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "not found token")
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata")
+	}
+
+	identity, err := auther.Authenticate(ctx, token)
+	if errors.Is(err, domain.ErrUserSuspended) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "failed to get id from auth")
+	}
+
+	ctx = logger.AppendCtx(ctx, UserIdKey, identity.UserId)
+	ctx = context.WithValue(ctx, UserIdKey, identity.UserId)
+	return context.WithValue(ctx, IdentityKey, identity), nil
+}
+
+// authStream подменяет контекст стрима на контекст с пользователем
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
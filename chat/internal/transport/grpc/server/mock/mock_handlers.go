@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handlers.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "chat/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChatService is a mock of ChatService interface.
+type MockChatService struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatServiceMockRecorder
+}
+
+// MockChatServiceMockRecorder is the mock recorder for MockChatService.
+type MockChatServiceMockRecorder struct {
+	mock *MockChatService
+}
+
+// NewMockChatService creates a new mock instance.
+func NewMockChatService(ctrl *gomock.Controller) *MockChatService {
+	mock := &MockChatService{ctrl: ctrl}
+	mock.recorder = &MockChatServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatService) EXPECT() *MockChatServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckMembership mocks base method.
+func (m *MockChatService) CheckMembership(ctx context.Context, chatID, userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckMembership", ctx, chatID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckMembership indicates an expected call of CheckMembership.
+func (mr *MockChatServiceMockRecorder) CheckMembership(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckMembership", reflect.TypeOf((*MockChatService)(nil).CheckMembership), ctx, chatID, userID)
+}
+
+// GetMessages mocks base method.
+func (m *MockChatService) GetMessages(ctx context.Context, chatID, userID, limit, offset int) ([]domain.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessages", ctx, chatID, userID, limit, offset)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessages indicates an expected call of GetMessages.
+func (mr *MockChatServiceMockRecorder) GetMessages(ctx, chatID, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockChatService)(nil).GetMessages), ctx, chatID, userID, limit, offset)
+}
+
+// GetUserChats mocks base method.
+func (m *MockChatService) GetUserChats(ctx context.Context, userID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserChats", ctx, userID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserChats indicates an expected call of GetUserChats.
+func (mr *MockChatServiceMockRecorder) GetUserChats(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserChats", reflect.TypeOf((*MockChatService)(nil).GetUserChats), ctx, userID)
+}
+
+// PostMessage mocks base method.
+func (m *MockChatService) PostMessage(ctx context.Context, msg domain.NewMessage) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostMessage", ctx, msg)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostMessage indicates an expected call of PostMessage.
+func (mr *MockChatServiceMockRecorder) PostMessage(ctx, msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockChatService)(nil).PostMessage), ctx, msg)
+}
+
+// StartChat mocks base method.
+func (m *MockChatService) StartChat(ctx context.Context, userID1, userID2 int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartChat", ctx, userID1, userID2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartChat indicates an expected call of StartChat.
+func (mr *MockChatServiceMockRecorder) StartChat(ctx, userID1, userID2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartChat", reflect.TypeOf((*MockChatService)(nil).StartChat), ctx, userID1, userID2)
+}
+
+// SubscribeChat mocks base method.
+func (m *MockChatService) SubscribeChat(ctx context.Context, chatID, userID int) (<-chan domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeChat", ctx, chatID, userID)
+	ret0, _ := ret[0].(<-chan domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeChat indicates an expected call of SubscribeChat.
+func (mr *MockChatServiceMockRecorder) SubscribeChat(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeChat", reflect.TypeOf((*MockChatService)(nil).SubscribeChat), ctx, chatID, userID)
+}
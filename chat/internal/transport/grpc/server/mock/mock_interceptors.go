@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interceptors.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "chat/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuther is a mock of Auther interface.
+type MockAuther struct {
+	ctrl     *gomock.Controller
+	recorder *MockAutherMockRecorder
+}
+
+// MockAutherMockRecorder is the mock recorder for MockAuther.
+type MockAutherMockRecorder struct {
+	mock *MockAuther
+}
+
+// NewMockAuther creates a new mock instance.
+func NewMockAuther(ctrl *gomock.Controller) *MockAuther {
+	mock := &MockAuther{ctrl: ctrl}
+	mock.recorder = &MockAutherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuther) EXPECT() *MockAutherMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuther) Authenticate(ctx context.Context, token string) (domain.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(domain.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAutherMockRecorder) Authenticate(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuther)(nil).Authenticate), ctx, token)
+}
@@ -0,0 +1,66 @@
+package grpcserver
+
+import (
+	"chat/pkg/api/chat_pb"
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	cfg        Config
+	grpcServer *grpc.Server
+	Handler    *Handler
+}
+
+func New(ctx context.Context, cfg Config, service ChatService, auther Auther) *Server {
+	s := &Server{
+		cfg:     cfg,
+		Handler: NewHandler(service),
+		grpcServer: grpc.NewServer(
+			grpc.UnaryInterceptor(UnaryInterceptor(ctx, auther)),
+			grpc.StreamInterceptor(StreamInterceptor(ctx, auther)),
+		),
+	}
+	chat_pb.RegisterChatServiceServer(s.grpcServer, s.Handler)
+	return s
+}
+
+func (s *Server) Run() error {
+	lis, err := net.Listen("tcp", s.cfg.Address())
+	if err != nil {
+		return err
+	}
+	return s.Serve(lis)
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpcServer.Serve(lis)
+}
+
+func (s *Server) MustRun() {
+	if err := s.Run(); err != nil {
+		panic(err)
+	}
+}
+
+// Shutdown завершает подписки и ждет окончания текущих вызовов. Если ctx истекает раньше,
+// оставшиеся соединения закрываются принудительно
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.Handler.close()
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,252 @@
+package grpcserver
+
+import (
+	"chat/internal/domain"
+	"chat/internal/transport/grpc/server/mock"
+	"chat/pkg/api/chat_pb"
+	"chat/pkg/logger"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, cs ChatService, auther Auther) chat_pb.ChatServiceClient {
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+	srv := New(ctx, Config{}, cs, auther)
+
+	lis := bufconn.Listen(1024 * 1024)
+	go srv.Serve(lis)
+	t.Cleanup(func() { srv.grpcServer.Stop() })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return chat_pb.NewChatServiceClient(conn)
+}
+
+var testUser = domain.Identity{UserId: 1, Role: domain.RoleUser, Token: "token"}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer_Unary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cs := mock.NewMockChatService(ctrl)
+	auther := mock.NewMockAuther(ctrl)
+	client := newTestClient(t, cs, auther)
+
+	type mockBehavior func()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		call         func(ctx context.Context) error
+		mockBehavior mockBehavior
+		wantCode     codes.Code
+	}{
+		{
+			name: "no token",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := client.ListChats(ctx, &chat_pb.ListChatsRequest{})
+				return err
+			},
+			mockBehavior: func() {},
+			wantCode:     codes.Unauthenticated,
+		},
+		{
+			name: "invalid token",
+			ctx:  withToken("bad"),
+			call: func(ctx context.Context) error {
+				_, err := client.ListChats(ctx, &chat_pb.ListChatsRequest{})
+				return err
+			},
+			mockBehavior: func() {
+				auther.EXPECT().Authenticate(gomock.Any(), "bad").Return(domain.Identity{}, errors.New("invalid token"))
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "suspended user",
+			ctx:  withToken("suspended"),
+			call: func(ctx context.Context) error {
+				_, err := client.ListChats(ctx, &chat_pb.ListChatsRequest{})
+				return err
+			},
+			mockBehavior: func() {
+				auther.EXPECT().Authenticate(gomock.Any(), "suspended").Return(domain.Identity{}, domain.ErrUserSuspended)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "create chat",
+			ctx:  withToken("token"),
+			call: func(ctx context.Context) error {
+				resp, err := client.CreateChat(ctx, &chat_pb.CreateChatRequest{UserId: 2})
+				if err == nil {
+					assert.Equal(t, int64(10), resp.GetChatId())
+				}
+				return err
+			},
+			mockBehavior: func() {
+				auther.EXPECT().Authenticate(gomock.Any(), "token").Return(testUser, nil)
+				cs.EXPECT().StartChat(gomock.Any(), 1, 2).Return(10, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "list messages of foreign chat",
+			ctx:  withToken("token"),
+			call: func(ctx context.Context) error {
+				_, err := client.ListMessages(ctx, &chat_pb.ListMessagesRequest{ChatId: 5, Limit: 10})
+				return err
+			},
+			mockBehavior: func() {
+				auther.EXPECT().Authenticate(gomock.Any(), "token").Return(testUser, nil)
+				cs.EXPECT().GetMessages(gomock.Any(), 5, 1, 10, 0).Return(nil, domain.ErrNotChatMember)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "list messages",
+			ctx:  withToken("token"),
+			call: func(ctx context.Context) error {
+				resp, err := client.ListMessages(ctx, &chat_pb.ListMessagesRequest{ChatId: 5, Limit: 10})
+				if err == nil {
+					require.Len(t, resp.GetMessages(), 1)
+					assert.Equal(t, int64(2), resp.GetMessages()[0].GetSenderId())
+					assert.Equal(t, "hi", resp.GetMessages()[0].GetText())
+				}
+				return err
+			},
+			mockBehavior: func() {
+				auther.EXPECT().Authenticate(gomock.Any(), "token").Return(testUser, nil)
+				cs.EXPECT().GetMessages(gomock.Any(), 5, 1, 10, 0).Return([]domain.Message{
+					{Id: 7, SenderId: "2", Text: "hi", CreatedAt: time.Now()},
+				}, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "send message without text",
+			ctx:  withToken("token"),
+			call: func(ctx context.Context) error {
+				_, err := client.SendMessage(ctx, &chat_pb.SendMessageRequest{ChatId: 5})
+				return err
+			},
+			mockBehavior: func() {
+				auther.EXPECT().Authenticate(gomock.Any(), "token").Return(testUser, nil)
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "send message rate limited",
+			ctx:  withToken("token"),
+			call: func(ctx context.Context) error {
+				_, err := client.SendMessage(ctx, &chat_pb.SendMessageRequest{ChatId: 5, Text: "hi"})
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.RetryInfo)
+				require.True(t, ok)
+				assert.Equal(t, 3*time.Second, info.GetRetryDelay().AsDuration())
+				return err
+			},
+			mockBehavior: func() {
+				auther.EXPECT().Authenticate(gomock.Any(), "token").Return(testUser, nil)
+				cs.EXPECT().PostMessage(gomock.Any(), domain.NewMessage{ChatId: 5, SenderId: 1, Text: "hi", AttachmentIds: []int{}}).
+					Return(0, &domain.RateLimitError{RetryAfter: 3 * time.Second})
+			},
+			wantCode: codes.ResourceExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			err := tt.call(tt.ctx)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestServer_SubscribeChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cs := mock.NewMockChatService(ctrl)
+	auther := mock.NewMockAuther(ctrl)
+	client := newTestClient(t, cs, auther)
+
+	events := make(chan domain.Event, 2)
+	events <- domain.Event{Type: "message_created", ChatId: 5, Payload: map[string]int{"message_id": 7}}
+	events <- domain.Event{Type: "message_created", ChatId: 5, MutedUserIds: []int{1}, Payload: map[string]int{"message_id": 8}}
+	close(events)
+
+	auther.EXPECT().Authenticate(gomock.Any(), "token").Return(testUser, nil)
+	cs.EXPECT().SubscribeChat(gomock.Any(), 5, 1).Return((<-chan domain.Event)(events), nil)
+
+	stream, err := client.SubscribeChat(withToken("token"), &chat_pb.SubscribeChatRequest{ChatId: 5})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "message_created", event.GetType())
+	assert.JSONEq(t, `{"message_id": 7}`, string(event.GetPayload()))
+	assert.False(t, event.GetMuted())
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	assert.True(t, event.GetMuted())
+
+	// Канал закрыт хабом - подписчик отстал и должен пересинхронизироваться
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Aborted, status.Code(err))
+}
+
+func TestServer_SubscribeChatNotMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cs := mock.NewMockChatService(ctrl)
+	auther := mock.NewMockAuther(ctrl)
+	client := newTestClient(t, cs, auther)
+
+	auther.EXPECT().Authenticate(gomock.Any(), "token").Return(testUser, nil)
+	cs.EXPECT().SubscribeChat(gomock.Any(), 5, 1).Return(nil, domain.ErrNotChatMember)
+
+	stream, err := client.SubscribeChat(withToken("token"), &chat_pb.SubscribeChatRequest{ChatId: 5})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAuthenticate_Identity(t *testing.T) {
+	auther := mock.NewMockAuther(gomock.NewController(t))
+	admin := domain.Identity{UserId: 3, Role: domain.RoleAdmin, Token: "admin"}
+	auther.EXPECT().Authenticate(gomock.Any(), "admin").Return(admin, nil)
+
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationKey, "Bearer admin"))
+
+	ctx, err := authenticate(ctx, auther)
+	require.NoError(t, err)
+	assert.Equal(t, admin, ctx.Value(IdentityKey))
+	assert.Equal(t, 3, ctx.Value(UserIdKey))
+}
@@ -0,0 +1,213 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Административные маршруты /chat/admin/... закрыты RequireAdmin, сервис дополнительно проверяет роль
+
+func (h *Handler) AdminListUsersHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, offset := pageParams(r)
+		q := domain.UserQuery{Query: r.URL.Query().Get("q"), Limit: limit, Offset: offset}
+
+		users, err := h.srv.ListUsers(r.Context(), identityFromCtx(r), q)
+		if err != nil {
+			writeServiceError(w, "Failed to list users: ", err)
+			return
+		}
+
+		writeAdminJSON(w, r, users)
+	})
+}
+
+func (h *Handler) AdminSuspendUserHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req SuspendUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		suspension := domain.Suspension{UserId: userID, Until: req.Until, Reason: req.Reason}
+		user, err := h.srv.SuspendUser(r.Context(), identityFromCtx(r), suspension)
+		if err != nil {
+			writeServiceError(w, "Failed to suspend user: ", err)
+			return
+		}
+
+		writeAdminJSON(w, r, user)
+	})
+}
+
+func (h *Handler) AdminUnsuspendUserHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		user, err := h.srv.UnsuspendUser(r.Context(), identityFromCtx(r), userID)
+		if err != nil {
+			writeServiceError(w, "Failed to unsuspend user: ", err)
+			return
+		}
+
+		writeAdminJSON(w, r, user)
+	})
+}
+
+func (h *Handler) AdminListChatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := domain.ChatQuery{}
+		if s := r.URL.Query().Get("user_id"); s != "" {
+			userID, err := strconv.Atoi(s)
+			if err != nil {
+				http.Error(w, "Invalid user ID", http.StatusBadRequest)
+				return
+			}
+			q.UserId = userID
+		}
+		q.Limit, q.Offset = pageParams(r)
+
+		chats, err := h.srv.ListChats(r.Context(), identityFromCtx(r), q)
+		if err != nil {
+			writeServiceError(w, "Failed to list chats: ", err)
+			return
+		}
+
+		writeAdminJSON(w, r, chats)
+	})
+}
+
+// AdminDeleteChatHandler удаляет чат целиком. Причина передается параметром reason и попадает в журнал
+func (h *Handler) AdminDeleteChatHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		err = h.srv.AdminDeleteChat(r.Context(), identityFromCtx(r), chatID, r.URL.Query().Get("reason"))
+		if err != nil {
+			writeServiceError(w, "Failed to delete chat: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (h *Handler) AdminDeleteMessageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		chatID, err := strconv.Atoi(vars["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+		messageID, err := strconv.Atoi(vars["message_id"])
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		err = h.srv.AdminDeleteMessage(r.Context(), identityFromCtx(r), chatID, messageID, r.URL.Query().Get("reason"))
+		if err != nil {
+			writeServiceError(w, "Failed to delete message: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (h *Handler) AdminReportsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, offset := pageParams(r)
+		status := r.URL.Query().Get("status")
+
+		reports, err := h.srv.GetReportedMessages(r.Context(), identityFromCtx(r), status, limit, offset)
+		if err != nil {
+			writeServiceError(w, "Failed to get reports: ", err)
+			return
+		}
+
+		writeAdminJSON(w, r, reports)
+	})
+}
+
+func (h *Handler) AdminResolveReportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		var req ResolveReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		report, err := h.srv.ResolveReport(r.Context(), identityFromCtx(r), messageID, req.Action, req.Reason)
+		if err != nil {
+			writeServiceError(w, "Failed to resolve report: ", err)
+			return
+		}
+
+		writeAdminJSON(w, r, report)
+	})
+}
+
+func (h *Handler) AdminAuditLogHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, offset := pageParams(r)
+
+		entries, err := h.srv.GetAuditLog(r.Context(), identityFromCtx(r), limit, offset)
+		if err != nil {
+			writeServiceError(w, "Failed to get audit log: ", err)
+			return
+		}
+
+		writeAdminJSON(w, r, entries)
+	})
+}
+
+func identityFromCtx(r *http.Request) domain.Identity {
+	identity, _ := r.Context().Value(IdentityKey).(domain.Identity)
+	return identity
+}
+
+// pageParams - limit и offset из query; некорректные значения дают 0, границы задает сервис
+func pageParams(r *http.Request) (int, int) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	return limit, offset
+}
+
+func writeAdminJSON(w http.ResponseWriter, r *http.Request, v any) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,117 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+var testAdmin = domain.Identity{UserId: 100, Role: domain.RoleAdmin, Token: "token"}
+
+func newAdminRouter(h *Handler) *mux.Router {
+	router := mux.NewRouter()
+	admin := router.PathPrefix("/chat/admin").Subrouter()
+	admin.Use(RequireAdmin)
+	admin.Handle("/users", h.AdminListUsersHandler()).Methods("GET")
+	admin.Handle("/users/{user_id:[0-9]+}/suspension", h.AdminSuspendUserHandler()).Methods("POST")
+	admin.Handle("/chats/{chat_id:[0-9]+}", h.AdminDeleteChatHandler()).Methods("DELETE")
+	admin.Handle("/chats/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}", h.AdminDeleteMessageHandler()).Methods("DELETE")
+	return router
+}
+
+func serveAdmin(router *mux.Router, identity domain.Identity, method, target string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	ctx := context.WithValue(req.Context(), UserIdKey, identity.UserId)
+	ctx = context.WithValue(ctx, IdentityKey, identity)
+	ctx = logger.InitFromCtx(ctx, logger.New())
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRequireAdmin(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newAdminRouter(NewHandler(cs))
+
+	rr := serveAdmin(router, domain.Identity{UserId: 1, Role: domain.RoleUser}, "GET", "/chat/admin/users", nil)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestHandler_AdminListUsersHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newAdminRouter(NewHandler(cs))
+
+	cs.EXPECT().ListUsers(gomock.Any(), testAdmin, domain.UserQuery{Query: "ali", Limit: 10, Offset: 20}).
+		Return([]domain.User{{Id: 2, Login: "alice"}}, nil)
+
+	rr := serveAdmin(router, testAdmin, "GET", "/chat/admin/users?q=ali&limit=10&offset=20", nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var users []domain.User
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&users))
+	assert.Equal(t, "alice", users[0].Login)
+}
+
+func TestHandler_AdminSuspendUserHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newAdminRouter(NewHandler(cs))
+
+	t.Run("ok", func(t *testing.T) {
+		cs.EXPECT().SuspendUser(gomock.Any(), testAdmin, domain.Suspension{UserId: 2, Reason: "spam"}).
+			Return(domain.User{Id: 2, Suspended: true}, nil)
+
+		rr := serveAdmin(router, testAdmin, "POST", "/chat/admin/users/2/suspension", []byte(`{"reason":"spam"}`))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		cs.EXPECT().SuspendUser(gomock.Any(), testAdmin, gomock.Any()).Return(domain.User{}, domain.ErrUserNotFound)
+
+		rr := serveAdmin(router, testAdmin, "POST", "/chat/admin/users/3/suspension", []byte(`{"reason":"spam"}`))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cs.EXPECT().SuspendUser(gomock.Any(), testAdmin, gomock.Any()).Return(domain.User{}, domain.ErrInvalidSuspension)
+
+		rr := serveAdmin(router, testAdmin, "POST", "/chat/admin/users/2/suspension", []byte(`{}`))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestHandler_AdminDeleteHandlers(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newAdminRouter(NewHandler(cs))
+
+	t.Run("message", func(t *testing.T) {
+		cs.EXPECT().AdminDeleteMessage(gomock.Any(), testAdmin, 1, 10, "spam").Return(nil)
+
+		rr := serveAdmin(router, testAdmin, "DELETE", "/chat/admin/chats/1/messages/10?reason=spam", nil)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("chat not found", func(t *testing.T) {
+		cs.EXPECT().AdminDeleteChat(gomock.Any(), testAdmin, 5, "").Return(domain.ErrChatNotFound)
+
+		rr := serveAdmin(router, testAdmin, "DELETE", "/chat/admin/chats/5", nil)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,140 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// UploadAttachmentHandler принимает файл в поле file multipart-формы и читает его потоком,
+// ограничение размера проверяет сервис
+func (h *Handler) UploadAttachmentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+			return
+		}
+
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				http.Error(w, "File is required", http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in multipart reader", err)
+				http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+				return
+			}
+			if part.FormName() != AttachmentFormField {
+				part.Close()
+				continue
+			}
+
+			attachment, err := h.srv.UploadAttachment(r.Context(), chatID, userId, part.FileName(), part)
+			part.Close()
+			if err != nil {
+				writeServiceError(w, "Failed to upload attachment: ", err)
+				return
+			}
+
+			setAttachmentURLs(&attachment)
+			w.WriteHeader(http.StatusCreated)
+			if err := json.NewEncoder(w).Encode(attachment); err != nil {
+				logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+				http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			}
+			return
+		}
+	})
+}
+
+func (h *Handler) DownloadAttachmentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.downloadAttachment(w, r, false)
+	})
+}
+
+func (h *Handler) DownloadThumbnailHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.downloadAttachment(w, r, true)
+	})
+}
+
+func (h *Handler) downloadAttachment(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.Atoi(vars["chat_id"])
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+	attachmentID, err := strconv.Atoi(vars["attachment_id"])
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	sUserId := r.Context().Value(UserIdKey)
+	userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	content, err := h.srv.GetAttachment(r.Context(), chatID, userId, attachmentID, thumbnail)
+	if err != nil {
+		writeServiceError(w, "Failed to get attachment: ", err)
+		return
+	}
+	defer content.Body.Close()
+
+	// Изображения показываются в браузере, остальные файлы только скачиваются
+	disposition := "attachment"
+	if strings.HasPrefix(content.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", content.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": content.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	if content.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(content.Size, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content.Body); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error while sending attachment", err)
+	}
+}
+
+// setAttachmentURLs заполняет ссылки на скачивание, доступные только участникам чата
+func setAttachmentURLs(a *domain.Attachment) {
+	a.URL = fmt.Sprintf("/chat/%d/attachments/%d", a.ChatId, a.Id)
+	if a.ThumbnailKey != "" {
+		a.ThumbnailURL = a.URL + "/thumbnail"
+	}
+}
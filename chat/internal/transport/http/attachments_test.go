@@ -0,0 +1,240 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_UploadAttachmentHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(chatId, userId int)
+
+	tests := []struct {
+		name         string
+		field        string
+		mockBehavior mockBehavior
+		resp         domain.Attachment
+		wantStatus   int
+	}{
+		{
+			name:  "ok",
+			field: AttachmentFormField,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					UploadAttachment(gomock.Any(), chatId, userId, "photo.png", gomock.Any()).
+					DoAndReturn(func(_ context.Context, chatID, userID int, fileName string, r io.Reader) (domain.Attachment, error) {
+						data, err := io.ReadAll(r)
+						require.NoError(t, err)
+						assert.Equal(t, "content", string(data))
+						return domain.Attachment{Id: 5, ChatId: chatID, UploaderId: userID, FileName: fileName, ThumbnailKey: "key"}, nil
+					})
+			},
+			resp: domain.Attachment{
+				Id:           5,
+				ChatId:       1,
+				UploaderId:   1,
+				FileName:     "photo.png",
+				URL:          "/chat/1/attachments/5",
+				ThumbnailURL: "/chat/1/attachments/5/thumbnail",
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:  "too large",
+			field: AttachmentFormField,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					UploadAttachment(gomock.Any(), chatId, userId, "photo.png", gomock.Any()).
+					Return(domain.Attachment{}, domain.ErrAttachmentTooLarge)
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:  "type not allowed",
+			field: AttachmentFormField,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					UploadAttachment(gomock.Any(), chatId, userId, "photo.png", gomock.Any()).
+					Return(domain.Attachment{}, domain.ErrAttachmentType)
+			},
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:         "without file field",
+			field:        "other",
+			mockBehavior: func(chatId, userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/attachments", h.UploadAttachmentHandler()).Methods("POST")
+
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			fw, err := mw.CreateFormFile(tt.field, "photo.png")
+			require.NoError(t, err)
+			_, err = fw.Write([]byte("content"))
+			require.NoError(t, err)
+			require.NoError(t, mw.Close())
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("POST", "/chat/1/attachments", &body)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("UploadAttachmentHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+
+			var resp domain.Attachment
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("UploadAttachmentHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
+
+func TestHandler_DownloadAttachmentHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(chatId, userId, attachmentId int)
+
+	tests := []struct {
+		name         string
+		url          string
+		mockBehavior mockBehavior
+		wantStatus   int
+		wantBody     string
+		wantHeaders  map[string]string
+	}{
+		{
+			name: "file",
+			url:  "/chat/1/attachments/5",
+			mockBehavior: func(chatId, userId, attachmentId int) {
+				cs.EXPECT().
+					GetAttachment(gomock.Any(), chatId, userId, attachmentId, false).
+					Return(domain.AttachmentContent{
+						FileName:    "отчет.pdf",
+						ContentType: "application/pdf",
+						Size:        4,
+						Body:        io.NopCloser(strings.NewReader("%PDF")),
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "%PDF",
+			wantHeaders: map[string]string{
+				"Content-Type":           "application/pdf",
+				"Content-Length":         "4",
+				"Content-Disposition":    "attachment; filename*=utf-8''%D0%BE%D1%82%D1%87%D0%B5%D1%82.pdf",
+				"X-Content-Type-Options": "nosniff",
+			},
+		},
+		{
+			name: "thumbnail",
+			url:  "/chat/1/attachments/5/thumbnail",
+			mockBehavior: func(chatId, userId, attachmentId int) {
+				cs.EXPECT().
+					GetAttachment(gomock.Any(), chatId, userId, attachmentId, true).
+					Return(domain.AttachmentContent{
+						FileName:    "photo.png",
+						ContentType: "image/jpeg",
+						Size:        -1,
+						Body:        io.NopCloser(strings.NewReader("jpeg")),
+					}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "jpeg",
+			wantHeaders: map[string]string{
+				"Content-Type":        "image/jpeg",
+				"Content-Length":      "",
+				"Content-Disposition": "inline; filename=photo.png",
+			},
+		},
+		{
+			name: "not member",
+			url:  "/chat/1/attachments/5",
+			mockBehavior: func(chatId, userId, attachmentId int) {
+				cs.EXPECT().
+					GetAttachment(gomock.Any(), chatId, userId, attachmentId, false).
+					Return(domain.AttachmentContent{}, domain.ErrNotChatMember)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "not found",
+			url:  "/chat/1/attachments/5",
+			mockBehavior: func(chatId, userId, attachmentId int) {
+				cs.EXPECT().
+					GetAttachment(gomock.Any(), chatId, userId, attachmentId, false).
+					Return(domain.AttachmentContent{}, domain.ErrAttachmentNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 1, 5)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/attachments/{attachment_id:[0-9]+}", h.DownloadAttachmentHandler()).Methods("GET")
+			router.Handle("/chat/{chat_id:[0-9]+}/attachments/{attachment_id:[0-9]+}/thumbnail", h.DownloadThumbnailHandler()).Methods("GET")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("DownloadAttachmentHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tt.wantBody, rr.Body.String())
+			for header, value := range tt.wantHeaders {
+				assert.Equal(t, value, rr.Header().Get(header), header)
+			}
+		})
+	}
+}
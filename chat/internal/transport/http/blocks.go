@@ -0,0 +1,84 @@
+package httpserver
+
+import (
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) GetBlockedUsersHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		blocked, err := h.srv.GetBlockedUsers(r.Context(), userId)
+		if err != nil {
+			writeServiceError(w, "Failed to get blocked users: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(BlockedUsersResponse{UserIDs: blocked}); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+func (h *Handler) BlockUserHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req BlockUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.srv.BlockUser(r.Context(), userId, req.UserID); err != nil {
+			writeServiceError(w, "Failed to block user: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (h *Handler) UnblockUserHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blockedId, err := strconv.Atoi(mux.Vars(r)["user_id"])
+		if err != nil {
+			http.Error(w, "Invalid blocked user ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.srv.UnblockUser(r.Context(), userId, blockedId); err != nil {
+			writeServiceError(w, "Failed to unblock user: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,117 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_BlockHandlers(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(userId int)
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         string
+		mockBehavior mockBehavior
+		resp         *BlockedUsersResponse
+		wantStatus   int
+	}{
+		{
+			name:   "block",
+			method: "POST",
+			url:    "/chat/blocks",
+			body:   `{"user_id": 2}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().BlockUser(gomock.Any(), userId, 2).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "block yourself",
+			method: "POST",
+			url:    "/chat/blocks",
+			body:   `{"user_id": 1}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().BlockUser(gomock.Any(), userId, 1).Return(domain.ErrInvalidBlock)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "block invalid body",
+			method:       "POST",
+			url:          "/chat/blocks",
+			body:         `{"user_id": "two"}`,
+			mockBehavior: func(userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:   "unblock",
+			method: "DELETE",
+			url:    "/chat/blocks/2",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().UnblockUser(gomock.Any(), userId, 2).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "list",
+			method: "GET",
+			url:    "/chat/blocks",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().GetBlockedUsers(gomock.Any(), userId).Return([]int{2, 3}, nil)
+			},
+			resp:       &BlockedUsersResponse{UserIDs: []int{2, 3}},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/blocks", h.GetBlockedUsersHandler()).Methods("GET")
+			router.Handle("/chat/blocks", h.BlockUserHandler()).Methods("POST")
+			router.Handle("/chat/blocks/{user_id:[0-9]+}", h.UnblockUserHandler()).Methods("DELETE")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("BlockHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.resp == nil {
+				return
+			}
+
+			var resp BlockedUsersResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("BlockHandler response got error %v", err)
+			}
+
+			assert.Equal(t, *tt.resp, resp)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxUpdatesTimeout - дольше getUpdates не держит запрос, даже если бот просит больше
+	maxUpdatesTimeout = 50 * time.Second
+	// updatesPollInterval - как часто ожидающий getUpdates перечитывает журнал, чтобы заметить
+	// изменения, сделанные через другие реплики chat-сервиса
+	updatesPollInterval = 2 * time.Second
+)
+
+// BotSendMessageHandler отправляет сообщение от имени бота в чат из тела запроса
+func (h *Handler) BotSendMessageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BotSendMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		h.sendMessage(w, r, req.ChatID, identityFromCtx(r).UserId, req.SendMessageRequest)
+	})
+}
+
+// GetUpdatesHandler отдает изменения в чатах бота после курсора offset. Если изменений нет, запрос ждет
+// их до timeout секунд (long polling), поэтому бот без вебхука получает события без частых запросов
+func (h *Handler) GetUpdatesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userId := identityFromCtx(r).UserId
+
+		query := r.URL.Query()
+		var offset int64
+		if s := query.Get("offset"); s != "" {
+			var err error
+			offset, err = strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid offset", http.StatusBadRequest)
+				return
+			}
+		}
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		var timeout time.Duration
+		if s := query.Get("timeout"); s != "" {
+			seconds, err := strconv.Atoi(s)
+			if err != nil || seconds < 0 {
+				http.Error(w, "Invalid timeout", http.StatusBadRequest)
+				return
+			}
+			timeout = min(time.Duration(seconds)*time.Second, maxUpdatesTimeout)
+		}
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		// Подписка оформляется до чтения журнала, чтобы не пропустить изменения между ними
+		events := h.srv.SubscribeUser(ctx, userId)
+
+		if timeout > 0 {
+			// Таймауты сервера короче ожидания: продлеваем их на время этого запроса
+			rc := http.NewResponseController(w)
+			if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to reset read deadline", err)
+				return
+			}
+			if err := rc.SetWriteDeadline(time.Now().Add(timeout + sseWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to extend write deadline", err)
+				return
+			}
+		}
+
+		deadline := time.NewTimer(timeout)
+		defer deadline.Stop()
+		poll := time.NewTicker(updatesPollInterval)
+		defer poll.Stop()
+
+		for {
+			batch, err := h.srv.SyncChanges(ctx, userId, offset, limit)
+			if err != nil {
+				writeServiceError(w, "Failed to get updates: ", err)
+				return
+			}
+			if len(batch.Changes) > 0 || batch.ResyncRequired || timeout == 0 {
+				writeUpdates(w, r, batch)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.closing:
+				writeUpdates(w, r, batch)
+				return
+			case <-deadline.C:
+				writeUpdates(w, r, batch)
+				return
+			case _, ok := <-events:
+				if !ok {
+					// Хаб отключил отставшую подписку: дальше ждем только по опросу журнала
+					events = nil
+				}
+			case <-poll.C:
+			}
+		}
+	})
+}
+
+func writeUpdates(w http.ResponseWriter, r *http.Request, batch domain.SyncBatch) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(batch); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+	}
+}
+
+// SetWebhookHandler устанавливает вебхук бота. Ключ подписи есть только в этом ответе
+func (h *Handler) SetWebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SetWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		webhook, err := h.srv.SetWebhook(r.Context(), identityFromCtx(r), req.URL)
+		if err != nil {
+			writeServiceError(w, "Failed to set webhook: ", err)
+			return
+		}
+
+		writeWebhook(w, r, webhook)
+	})
+}
+
+func (h *Handler) GetWebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhook, err := h.srv.GetWebhook(r.Context(), identityFromCtx(r))
+		if err != nil {
+			writeServiceError(w, "Failed to get webhook: ", err)
+			return
+		}
+
+		writeWebhook(w, r, webhook)
+	})
+}
+
+func (h *Handler) DeleteWebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h.srv.DeleteWebhook(r.Context(), identityFromCtx(r)); err != nil {
+			writeServiceError(w, "Failed to delete webhook: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func writeWebhook(w http.ResponseWriter, r *http.Request, webhook domain.Webhook) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(webhook); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
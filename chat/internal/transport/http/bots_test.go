@@ -0,0 +1,162 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBot = domain.Identity{UserId: 7, Role: domain.RoleBot, Token: "bot_token"}
+
+func newBotRouter(h *Handler) *mux.Router {
+	router := mux.NewRouter()
+	bot := router.PathPrefix("/bot").Subrouter()
+	bot.Use(RequireBot)
+	bot.Handle("/sendMessage", h.BotSendMessageHandler()).Methods("POST")
+	bot.Handle("/getUpdates", h.GetUpdatesHandler()).Methods("GET")
+	bot.Handle("/webhook", h.GetWebhookHandler()).Methods("GET")
+	bot.Handle("/webhook", h.SetWebhookHandler()).Methods("PUT")
+	bot.Handle("/webhook", h.DeleteWebhookHandler()).Methods("DELETE")
+	return router
+}
+
+func TestAuthMiddleware_BearerToken(t *testing.T) {
+	auther := mock.NewMockAuther(gomock.NewController(t))
+	var got domain.Identity
+	handler := AuthMiddleware(auther)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = identityFromCtx(r)
+	}))
+
+	auther.EXPECT().Authenticate(gomock.Any(), "bot_token").Return(testBot, nil)
+	req := httptest.NewRequest("GET", "/bot/getUpdates", nil)
+	req.Header.Set("Authorization", "Bearer bot_token")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, testBot, got)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/bot/getUpdates", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestRequireBot(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newBotRouter(NewHandler(cs))
+
+	rr := serveAdmin(router, domain.Identity{UserId: 1, Role: domain.RoleUser}, "GET", "/bot/getUpdates", nil)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestHandler_BotSendMessageHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newBotRouter(NewHandler(cs))
+
+	cs.EXPECT().PostMessage(gomock.Any(), domain.NewMessage{ChatId: 3, SenderId: 7, Text: "build #12 failed", Format: "markdown"}).Return(40, nil)
+
+	rr := serveAdmin(router, testBot, "POST", "/bot/sendMessage", []byte(`{"chat_id":3,"text":"build #12 failed","format":"markdown"}`))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"message_id":40}`, rr.Body.String())
+
+	rr = serveAdmin(router, testBot, "POST", "/bot/sendMessage", []byte(`{"text":"no chat"}`))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestHandler_GetUpdatesHandler(t *testing.T) {
+	created := domain.Change{Seq: 4, ChatId: 3, Type: domain.EventMessageCreated, Payload: json.RawMessage(`{"id":40}`)}
+
+	t.Run("returns available changes at once", func(t *testing.T) {
+		cs := mock.NewMockChatService(gomock.NewController(t))
+		router := newBotRouter(NewHandler(cs))
+
+		cs.EXPECT().SubscribeUser(gomock.Any(), 7).Return(make(<-chan domain.Event))
+		cs.EXPECT().SyncChanges(gomock.Any(), 7, int64(3), 10).Return(domain.SyncBatch{Changes: []domain.Change{created}, Cursor: 4}, nil)
+
+		rr := serveAdmin(router, testBot, "GET", "/bot/getUpdates?offset=3&limit=10&timeout=30", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var batch domain.SyncBatch
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&batch))
+		assert.Equal(t, int64(4), batch.Cursor)
+		assert.Len(t, batch.Changes, 1)
+	})
+
+	t.Run("waits for an event", func(t *testing.T) {
+		cs := mock.NewMockChatService(gomock.NewController(t))
+		router := newBotRouter(NewHandler(cs))
+
+		events := make(chan domain.Event, 1)
+		cs.EXPECT().SubscribeUser(gomock.Any(), 7).Return((<-chan domain.Event)(events))
+		gomock.InOrder(
+			cs.EXPECT().SyncChanges(gomock.Any(), 7, int64(3), 0).DoAndReturn(func(context.Context, int, int64, int) (domain.SyncBatch, error) {
+				events <- domain.Event{Type: domain.EventMessageCreated}
+				return domain.SyncBatch{Changes: []domain.Change{}, Cursor: 3}, nil
+			}),
+			cs.EXPECT().SyncChanges(gomock.Any(), 7, int64(3), 0).Return(domain.SyncBatch{Changes: []domain.Change{created}, Cursor: 4}, nil),
+		)
+
+		rr := serveAdmin(router, testBot, "GET", "/bot/getUpdates?offset=3&timeout=30", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"cursor":4`)
+	})
+
+	t.Run("empty batch after timeout", func(t *testing.T) {
+		cs := mock.NewMockChatService(gomock.NewController(t))
+		router := newBotRouter(NewHandler(cs))
+
+		cs.EXPECT().SubscribeUser(gomock.Any(), 7).Return(make(<-chan domain.Event))
+		cs.EXPECT().SyncChanges(gomock.Any(), 7, int64(3), 0).Return(domain.SyncBatch{Changes: []domain.Change{}, Cursor: 3}, nil)
+
+		start := time.Now()
+		rr := serveAdmin(router, testBot, "GET", "/bot/getUpdates?offset=3&timeout=1", nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.JSONEq(t, `{"changes":[],"cursor":3,"has_more":false}`, rr.Body.String())
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		cs := mock.NewMockChatService(gomock.NewController(t))
+		router := newBotRouter(NewHandler(cs))
+
+		rr := serveAdmin(router, testBot, "GET", "/bot/getUpdates?timeout=-1", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestHandler_WebhookHandlers(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newBotRouter(NewHandler(cs))
+
+	cs.EXPECT().SetWebhook(gomock.Any(), testBot, "https://ci.example.com/hook").
+		Return(domain.Webhook{BotId: 7, URL: "https://ci.example.com/hook", Secret: "s3cr3t"}, nil)
+	rr := serveAdmin(router, testBot, "PUT", "/bot/webhook", []byte(`{"url":"https://ci.example.com/hook"}`))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"secret":"s3cr3t"`)
+
+	cs.EXPECT().SetWebhook(gomock.Any(), testBot, "ftp://example.com").Return(domain.Webhook{}, domain.ErrInvalidWebhookURL)
+	rr = serveAdmin(router, testBot, "PUT", "/bot/webhook", []byte(`{"url":"ftp://example.com"}`))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	cs.EXPECT().GetWebhook(gomock.Any(), testBot).Return(domain.Webhook{}, domain.ErrWebhookNotFound)
+	rr = serveAdmin(router, testBot, "GET", "/bot/webhook", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	cs.EXPECT().DeleteWebhook(gomock.Any(), testBot).Return(nil)
+	rr = serveAdmin(router, testBot, "DELETE", "/bot/webhook", nil)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
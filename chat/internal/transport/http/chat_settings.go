@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) GetChatSettingsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		settings, err := h.srv.GetChatSettings(r.Context(), chatID, userId)
+		if err != nil {
+			writeServiceError(w, "Failed to get chat settings: ", err)
+			return
+		}
+
+		writeChatSettings(w, r, settings)
+	})
+}
+
+func (h *Handler) UpdateChatSettingsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req ChatSettingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.MuteFor < 0 {
+			http.Error(w, "Invalid mute_for", http.StatusBadRequest)
+			return
+		}
+
+		upd := domain.ChatSettingsUpdate{
+			Muted:    req.Muted,
+			Archived: req.Archived,
+			Pinned:   req.Pinned,
+		}
+		if req.MuteFor > 0 {
+			until := time.Now().Add(time.Duration(req.MuteFor) * time.Second)
+			upd.MutedUntil = &until
+		}
+
+		settings, err := h.srv.UpdateChatSettings(r.Context(), chatID, userId, upd)
+		if err != nil {
+			writeServiceError(w, "Failed to update chat settings: ", err)
+			return
+		}
+
+		writeChatSettings(w, r, settings)
+	})
+}
+
+func writeChatSettings(w http.ResponseWriter, r *http.Request, settings domain.ChatSettings) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(settings); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
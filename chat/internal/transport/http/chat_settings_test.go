@@ -0,0 +1,116 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_UpdateChatSettingsHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(chatId, userId int)
+
+	yes := true
+
+	tests := []struct {
+		name         string
+		body         string
+		mockBehavior mockBehavior
+		resp         domain.ChatSettings
+		wantStatus   int
+	}{
+		{
+			name: "mute for an hour",
+			body: `{"muted": true, "mute_for": 3600}`,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					UpdateChatSettings(gomock.Any(), chatId, userId, gomock.Any()).
+					DoAndReturn(func(_ context.Context, chatId, _ int, upd domain.ChatSettingsUpdate) (domain.ChatSettings, error) {
+						if upd.Muted == nil || !*upd.Muted || upd.MutedUntil == nil {
+							t.Errorf("unexpected update %+v", upd)
+							return domain.ChatSettings{}, nil
+						}
+						if d := time.Until(*upd.MutedUntil); d < 59*time.Minute || d > time.Hour {
+							t.Errorf("muted_until in %v, want an hour", d)
+						}
+						return domain.ChatSettings{ChatId: chatId, Muted: true}, nil
+					})
+			},
+			resp:       domain.ChatSettings{ChatId: 1, Muted: true},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "pin",
+			body: `{"pinned": true}`,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					UpdateChatSettings(gomock.Any(), chatId, userId, domain.ChatSettingsUpdate{Pinned: &yes}).
+					Return(domain.ChatSettings{ChatId: chatId, Pinned: true}, nil)
+			},
+			resp:       domain.ChatSettings{ChatId: 1, Pinned: true},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "negative mute_for",
+			body:         `{"muted": true, "mute_for": -1}`,
+			mockBehavior: func(chatId, userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "not member",
+			body: `{"pinned": true}`,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					UpdateChatSettings(gomock.Any(), chatId, userId, gomock.Any()).
+					Return(domain.ChatSettings{}, domain.ErrNotChatMember)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/settings", h.UpdateChatSettingsHandler()).Methods("PATCH")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("PATCH", "/chat/1/settings", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("UpdateChatSettingsHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp domain.ChatSettings
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("UpdateChatSettingsHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
@@ -1,5 +1,6 @@
 package httpserver
 
 const (
-	UserIdKey = "user_id_key"
+	UserIdKey   = "user_id_key"
+	IdentityKey = "identity_key"
 )
\ No newline at end of file
@@ -0,0 +1,57 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// SaveDraftHandler сохраняет черновик чата и отдает актуальную версию. Если на другом устройстве черновик
+// изменили позже, в ответе будет та версия, и клиенту стоит показать ее
+func (h *Handler) SaveDraftHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req SaveDraftRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		draft, err := h.srv.SaveDraft(r.Context(), userId, domain.Draft{
+			ChatId:    chatID,
+			Text:      req.Text,
+			Entities:  req.Entities,
+			ReplyToId: req.ReplyToMessageID,
+			UpdatedAt: req.UpdatedAt,
+		}, req.Format)
+		if err != nil {
+			writeServiceError(w, "Failed to save draft: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(draft); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
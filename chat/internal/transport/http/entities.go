@@ -1,10 +1,15 @@
 package httpserver
 
-import "chat/internal/domain"
+import (
+	"chat/internal/domain"
+	"time"
+)
 
 type GetChatsResponse struct {
 	UserId int  `json:"user_id"`
 	Chats []int `json:"chats"`
+	// Drafts - непустые черновики пользователя по чатам
+	Drafts []domain.Draft `json:"drafts"`
 }
 
 type NewChatRequest struct {
@@ -16,7 +21,37 @@ type NewChatResponse struct {
 }
 
 type SendMessageRequest struct {
-	Text string `json:"text"`
+	Text             string                 `json:"text"`
+	Format           string                 `json:"format,omitempty"`
+	Entities         []domain.MessageEntity `json:"entities,omitempty"`
+	ReplyToMessageID int                    `json:"reply_to_message_id,omitempty"`
+	AttachmentIDs    []int                  `json:"attachment_ids,omitempty"`
+	ClientMessageID  string                 `json:"client_message_id,omitempty"`
+	Envelope         *domain.Envelope       `json:"envelope,omitempty"`
+	// SendAt откладывает отправку до указанного времени
+	SendAt *time.Time `json:"send_at,omitempty"`
+}
+
+// BotSendMessageRequest - сообщение бота: чат указывается в теле, остальные поля как у SendMessageRequest
+type BotSendMessageRequest struct {
+	ChatID int `json:"chat_id"`
+	SendMessageRequest
+}
+
+type SetWebhookRequest struct {
+	URL string `json:"url"`
+}
+
+type CreateIncomingWebhookRequest struct {
+	Name string `json:"name"`
+}
+
+// IncomingWebhookPayload - тело запроса на адрес входящего вебхука в формате Slack: text и username.
+// Остальные поля Slack игнорируются, format - разметка текста, как в SendMessageRequest
+type IncomingWebhookPayload struct {
+	Text     string `json:"text"`
+	Username string `json:"username,omitempty"`
+	Format   string `json:"format,omitempty"`
 }
 
 type SendMessageResponse struct {
@@ -24,3 +59,124 @@ type SendMessageResponse struct {
 }
 
 type GetMessagesResponse []domain.Message
+
+type UnreadCountsResponse []domain.UnreadCount
+
+type MarkReadRequest struct {
+	MessageID int `json:"message_id"`
+}
+
+type MarkReadResponse struct {
+	ChatID            int `json:"chat_id"`
+	LastReadMessageID int `json:"last_read_message_id"`
+}
+
+const (
+	DeleteForMe       = "me"
+	DeleteForEveryone = "everyone"
+)
+
+type EditMessageRequest struct {
+	Text     string                 `json:"text"`
+	Format   string                 `json:"format,omitempty"`
+	Entities []domain.MessageEntity `json:"entities,omitempty"`
+}
+
+// EditScheduledMessageRequest меняет только переданные поля: текст вместе с форматом и разметкой, время отправки
+type EditScheduledMessageRequest struct {
+	Text     *string                `json:"text,omitempty"`
+	Format   string                 `json:"format,omitempty"`
+	Entities []domain.MessageEntity `json:"entities,omitempty"`
+	SendAt   *time.Time             `json:"send_at,omitempty"`
+}
+
+type ScheduledMessagesResponse []domain.ScheduledMessage
+
+type PinnedMessagesResponse []domain.PinnedMessage
+
+// SaveDraftRequest - новая версия черновика. UpdatedAt - время правки на устройстве, пустое - время запроса.
+// Пустые text и reply_to_message_id удаляют черновик
+type SaveDraftRequest struct {
+	Text             string                 `json:"text"`
+	Format           string                 `json:"format,omitempty"`
+	Entities         []domain.MessageEntity `json:"entities,omitempty"`
+	ReplyToMessageID int                    `json:"reply_to_message_id,omitempty"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+type GetMessageHistoryResponse []domain.MessageEdit
+
+type ForwardMessageRequest struct {
+	ToChatID int `json:"to_chat_id"`
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+type ReactionsResponse struct {
+	MessageID int                    `json:"message_id"`
+	Reactions []domain.ReactionCount `json:"reactions"`
+}
+
+// AttachmentFormField - поле multipart-формы с загружаемым файлом
+const AttachmentFormField = "file"
+
+type BlockUserRequest struct {
+	UserID int `json:"user_id"`
+}
+
+type BlockedUsersResponse struct {
+	UserIDs []int `json:"user_ids"`
+}
+
+// ChatSettingsRequest - изменение личных настроек чата, отсутствующие поля не меняются.
+// MuteFor - срок отключения уведомлений в секундах, 0 - без срока
+type ChatSettingsRequest struct {
+	Muted    *bool `json:"muted,omitempty"`
+	MuteFor  int   `json:"mute_for,omitempty"`
+	Archived *bool `json:"archived,omitempty"`
+	Pinned   *bool `json:"pinned,omitempty"`
+}
+
+// ChatRetentionRequest - срок жизни новых сообщений чата в секундах, 0 - выключить исчезающие сообщения
+type ChatRetentionRequest struct {
+	TTL *int `json:"ttl"`
+}
+
+type VAPIDKeyResponse struct {
+	PublicKey string `json:"public_key"`
+}
+
+// PushSubscriptionRequest - результат PushSubscription.toJSON() в браузере.
+// ExpirationTime - срок подписки в миллисекундах Unix, null - бессрочная
+type PushSubscriptionRequest struct {
+	Endpoint       string `json:"endpoint"`
+	ExpirationTime *int64 `json:"expirationTime"`
+	Keys           struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+type PushUnsubscribeRequest struct {
+	Endpoint string `json:"endpoint"`
+}
+
+// SuspendUserRequest - блокировка пользователя администратором. Until пустой - бессрочно
+type SuspendUserRequest struct {
+	Until  *time.Time `json:"until,omitempty"`
+	Reason string     `json:"reason"`
+}
+
+// ReportMessageRequest - жалоба на сообщение: reason - одна из причин domain.ReportReasons
+type ReportMessageRequest struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// ResolveReportRequest - решение модератора: action dismiss или remove
+type ResolveReportRequest struct {
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,173 @@
+package httpserver
+
+import (
+	"chat/pkg/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// sseHeartbeatInterval - период комментариев-пингов. По нему же поток перечитывает журнал изменений,
+	// чтобы доставить изменения, сделанные через другие реплики chat-сервиса
+	sseHeartbeatInterval = 15 * time.Second
+	// sseWriteTimeout - сколько ждать записи в поток, прежде чем считать клиента отключившимся.
+	// Заменяет WriteTimeout сервера, который оборвал бы долгий запрос
+	sseWriteTimeout = 10 * time.Second
+	// sseRetry - через сколько миллисекунд EventSource переподключается после обрыва
+	sseRetry = 3000
+	// sseBatchLimit - сколько изменений читать из журнала за раз
+	sseBatchLimit = 100
+)
+
+// resyncEvent - событие потока, после которого клиент должен заново загрузить чаты
+const resyncEvent = "resync"
+
+// EventsHandler отдает изменения в чатах пользователя как Server-Sent Events. id события - номер изменения
+// в журнале, поэтому после обрыва EventSource продолжает с заголовком Last-Event-ID без потерь
+func (h *Handler) EventsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sUserId := ctx.Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		// Подписка оформляется до чтения журнала, чтобы не пропустить изменения между ними
+		events := h.srv.SubscribeUser(ctx, userId)
+
+		cursor, err := lastEventID(r)
+		if err != nil {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		if cursor < 0 {
+			cursor, err = h.srv.GetSyncCursor(ctx, userId)
+			if err != nil {
+				writeServiceError(w, "Failed to get sync cursor: ", err)
+				return
+			}
+		}
+
+		rc := http.NewResponseController(w)
+		// ReadTimeout сервера оборвал бы поток: по его истечении net/http отменяет контекст запроса
+		if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to reset read deadline", err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		stream := &sseStream{w: w, rc: rc}
+		if err := stream.write(fmt.Sprintf("retry: %d\n\n", sseRetry)); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(sseHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			cursor, err = h.sendChanges(r, stream, userId, cursor)
+			if err != nil {
+				logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to stream changes", err)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.closing:
+				return
+			case _, ok := <-events:
+				if !ok {
+					// Подписчик отстал и отключен хабом: клиент переподключится и продолжит с Last-Event-ID
+					return
+				}
+			case <-heartbeat.C:
+				if err := stream.write(": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// sendChanges отправляет все изменения после cursor и возвращает новый курсор
+func (h *Handler) sendChanges(r *http.Request, stream *sseStream, userId int, cursor int64) (int64, error) {
+	for {
+		batch, err := h.srv.SyncChanges(r.Context(), userId, cursor, sseBatchLimit)
+		if err != nil {
+			return cursor, err
+		}
+
+		if batch.ResyncRequired {
+			data, _ := json.Marshal(map[string]int64{"cursor": batch.Cursor})
+			if err := stream.event(batch.Cursor, resyncEvent, data); err != nil {
+				return cursor, err
+			}
+			return batch.Cursor, nil
+		}
+
+		for _, change := range batch.Changes {
+			data, err := json.Marshal(change)
+			if err != nil {
+				return cursor, err
+			}
+			if err := stream.event(change.Seq, change.Type, data); err != nil {
+				return cursor, err
+			}
+			cursor = change.Seq
+		}
+
+		if !batch.HasMore {
+			return cursor, nil
+		}
+	}
+}
+
+// lastEventID читает курсор из Last-Event-ID или параметра last_event_id для клиентов,
+// которые не умеют выставлять заголовки. -1, если курсор не передан
+func lastEventID(r *http.Request) (int64, error) {
+	s := r.Header.Get("Last-Event-ID")
+	if s == "" {
+		s = r.URL.Query().Get("last_event_id")
+	}
+	if s == "" {
+		return -1, nil
+	}
+
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("invalid event id")
+	}
+	return id, nil
+}
+
+// sseStream пишет события в ответ и сразу отправляет их клиенту
+type sseStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (s *sseStream) event(id int64, event string, data []byte) error {
+	return s.write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", id, event, data))
+}
+
+func (s *sseStream) write(msg string) error {
+	// Дедлайн продлевается на каждую запись: WriteTimeout сервера отсчитывается от начала запроса
+	if err := s.rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if _, err := fmt.Fprint(s.w, msg); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
@@ -0,0 +1,177 @@
+package httpserver
+
+import (
+	"bufio"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_EventsHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	created := domain.Change{Seq: 4, ChatId: 1, Type: domain.EventMessageCreated, Payload: json.RawMessage(`{"id":7}`)}
+	read := domain.Change{Seq: 5, ChatId: 1, Type: domain.EventRead, Payload: json.RawMessage(`{"chat_id":1}`)}
+
+	type mockBehavior func(userId int)
+
+	tests := []struct {
+		name         string
+		lastEventID  string
+		url          string
+		mockBehavior mockBehavior
+		wantStatus   int
+		wantIDs      []string
+		wantEvents   []string
+	}{
+		{
+			name:        "resume from Last-Event-ID",
+			lastEventID: "3",
+			url:         "/chat/events",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().SyncChanges(gomock.Any(), userId, int64(3), sseBatchLimit).
+					Return(domain.SyncBatch{Changes: []domain.Change{created}, Cursor: 4, HasMore: true}, nil)
+				cs.EXPECT().SyncChanges(gomock.Any(), userId, int64(4), sseBatchLimit).
+					Return(domain.SyncBatch{Changes: []domain.Change{read}, Cursor: 5}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"4", "5"},
+			wantEvents: []string{domain.EventMessageCreated, domain.EventRead},
+		},
+		{
+			name: "resume from query parameter",
+			url:  "/chat/events?last_event_id=4",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().SyncChanges(gomock.Any(), userId, int64(4), sseBatchLimit).
+					Return(domain.SyncBatch{Changes: []domain.Change{read}, Cursor: 5}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"5"},
+			wantEvents: []string{domain.EventRead},
+		},
+		{
+			name: "without cursor only new changes",
+			url:  "/chat/events",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().GetSyncCursor(gomock.Any(), userId).Return(int64(5), nil)
+				cs.EXPECT().SyncChanges(gomock.Any(), userId, int64(5), sseBatchLimit).
+					Return(domain.SyncBatch{Changes: []domain.Change{}, Cursor: 5}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "log is truncated",
+			lastEventID: "1",
+			url:         "/chat/events",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().SyncChanges(gomock.Any(), userId, int64(1), sseBatchLimit).
+					Return(domain.SyncBatch{Changes: []domain.Change{}, Cursor: 1500, ResyncRequired: true}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"1500"},
+			wantEvents: []string{resyncEvent},
+		},
+		{
+			name:         "invalid Last-Event-ID",
+			lastEventID:  "abc",
+			url:          "/chat/events",
+			mockBehavior: func(userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Канал уже закрыт: после отправки журнала поток завершается, как при отключении отставшего подписчика
+			events := make(chan domain.Event)
+			close(events)
+			cs.EXPECT().SubscribeUser(gomock.Any(), 1).Return((<-chan domain.Event)(events))
+			tt.mockBehavior(1)
+
+			h := NewHandler(cs)
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			ctx = logger.InitFromCtx(ctx, logger.New())
+			req = req.WithContext(ctx)
+
+			h.EventsHandler().ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("EventsHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+
+			var ids, names []string
+			for _, line := range strings.Split(rr.Body.String(), "\n") {
+				if id, ok := strings.CutPrefix(line, "id: "); ok {
+					ids = append(ids, id)
+				}
+				if name, ok := strings.CutPrefix(line, "event: "); ok {
+					names = append(names, name)
+				}
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantEvents, names)
+		})
+	}
+}
+
+func TestHandler_EventsHandlerOutlivesServerTimeouts(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	events := make(chan domain.Event, 1)
+	cs.EXPECT().SubscribeUser(gomock.Any(), 1).Return((<-chan domain.Event)(events))
+	cs.EXPECT().GetSyncCursor(gomock.Any(), 1).Return(int64(0), nil)
+	cs.EXPECT().SyncChanges(gomock.Any(), 1, int64(0), sseBatchLimit).Return(domain.SyncBatch{Changes: []domain.Change{}}, nil)
+	cs.EXPECT().SyncChanges(gomock.Any(), 1, int64(0), sseBatchLimit).Return(domain.SyncBatch{
+		Changes: []domain.Change{{Seq: 1, ChatId: 1, Type: domain.EventMessageCreated, Payload: json.RawMessage(`{}`)}},
+		Cursor:  1,
+	}, nil)
+	cs.EXPECT().SyncChanges(gomock.Any(), 1, int64(1), sseBatchLimit).Return(domain.SyncBatch{Changes: []domain.Change{}, Cursor: 1}, nil).AnyTimes()
+
+	h := NewHandler(cs)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), UserIdKey, 1)
+		ctx = logger.InitFromCtx(ctx, logger.New())
+		h.EventsHandler().ServeHTTP(w, r.WithContext(ctx))
+	}))
+	srv.Config.ReadTimeout = 100 * time.Millisecond
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+	defer h.CloseStreams()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	time.AfterFunc(300*time.Millisecond, func() {
+		events <- domain.Event{Type: domain.EventMessageCreated, ChatId: 1, UserIds: []int{1}}
+	})
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if scanner.Text() == "id: 1" {
+			return
+		}
+	}
+	t.Fatalf("stream closed before event: %v", scanner.Err())
+}
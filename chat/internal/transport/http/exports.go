@@ -0,0 +1,137 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// RequestExportHandler ставит выгрузку в очередь и сразу отвечает 202: архив собирается в фоне.
+// Токен нужен сервису, чтобы забрать из auth-сервиса профиль и сессии пользователя
+func (h *Handler) RequestExportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		cookie, err := r.Cookie("user_jwt")
+		if err != nil {
+			http.Error(w, "not found cookie", http.StatusUnauthorized)
+			return
+		}
+
+		exp, err := h.srv.RequestExport(r.Context(), userId, cookie.Value)
+		if err != nil {
+			writeServiceError(w, "Failed to request export: ", err)
+			return
+		}
+
+		w.Header().Set("Location", "/chat/exports/"+exp.Id)
+		writeExport(w, r, http.StatusAccepted, exp)
+	})
+}
+
+func (h *Handler) ListExportsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		exports, err := h.srv.ListExports(r.Context(), userId)
+		if err != nil {
+			writeServiceError(w, "Failed to list exports: ", err)
+			return
+		}
+		for i := range exports {
+			setExportURL(&exports[i])
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(exports); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+func (h *Handler) GetExportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		exp, err := h.srv.GetExport(r.Context(), userId, mux.Vars(r)["export_id"])
+		if err != nil {
+			writeServiceError(w, "Failed to get export: ", err)
+			return
+		}
+
+		writeExport(w, r, http.StatusOK, exp)
+	})
+}
+
+// DownloadExportHandler отдает архив только его владельцу и только до истечения срока ссылки
+func (h *Handler) DownloadExportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		content, err := h.srv.GetExportArchive(r.Context(), userId, mux.Vars(r)["export_id"])
+		if err != nil {
+			writeServiceError(w, "Failed to get export archive: ", err)
+			return
+		}
+		defer content.Body.Close()
+
+		w.Header().Set("Content-Type", content.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": content.FileName}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "private, no-store")
+		if content.Size >= 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(content.Size, 10))
+		}
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := io.Copy(w, content.Body); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error while sending export", err)
+		}
+	})
+}
+
+func writeExport(w http.ResponseWriter, r *http.Request, status int, exp domain.Export) {
+	setExportURL(&exp)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(exp); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// setExportURL дает ссылку на скачивание, как только архив готов
+func setExportURL(exp *domain.Export) {
+	if exp.Status == domain.ExportReady {
+		exp.DownloadURL = fmt.Sprintf("/chat/exports/%s/download", exp.Id)
+	}
+}
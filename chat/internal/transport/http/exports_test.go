@@ -0,0 +1,143 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportsRouter(h *Handler) *mux.Router {
+	router := mux.NewRouter()
+	router.Handle("/chat/exports", h.RequestExportHandler()).Methods("POST")
+	router.Handle("/chat/exports/{export_id}", h.GetExportHandler()).Methods("GET")
+	router.Handle("/chat/exports/{export_id}/download", h.DownloadExportHandler()).Methods("GET")
+	return router
+}
+
+func serveExports(router *mux.Router, method, target string, cookie bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if cookie {
+		req.AddCookie(&http.Cookie{Name: "user_jwt", Value: "token"})
+	}
+	ctx := context.WithValue(req.Context(), UserIdKey, 1)
+	ctx = logger.InitFromCtx(ctx, logger.New())
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestHandler_RequestExportHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newExportsRouter(NewHandler(cs))
+
+	t.Run("accepted", func(t *testing.T) {
+		cs.EXPECT().RequestExport(gomock.Any(), 1, "token").Return(domain.Export{Id: "e1", Status: domain.ExportPending}, nil)
+
+		rr := serveExports(router, "POST", "/chat/exports", true)
+
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Equal(t, "/chat/exports/e1", rr.Header().Get("Location"))
+	})
+
+	t.Run("already running", func(t *testing.T) {
+		cs.EXPECT().RequestExport(gomock.Any(), 1, "token").Return(domain.Export{}, domain.ErrExportInProgress)
+
+		rr := serveExports(router, "POST", "/chat/exports", true)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("no cookie", func(t *testing.T) {
+		rr := serveExports(router, "POST", "/chat/exports", false)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestHandler_GetExportHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newExportsRouter(NewHandler(cs))
+
+	t.Run("ready has download url", func(t *testing.T) {
+		cs.EXPECT().GetExport(gomock.Any(), 1, "e1").Return(domain.Export{Id: "e1", Status: domain.ExportReady}, nil)
+
+		rr := serveExports(router, "GET", "/chat/exports/e1", false)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var resp domain.Export
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("GetExportHandler response got error %v", err)
+		}
+		assert.Equal(t, "/chat/exports/e1/download", resp.DownloadURL)
+	})
+
+	t.Run("pending has no download url", func(t *testing.T) {
+		cs.EXPECT().GetExport(gomock.Any(), 1, "e1").Return(domain.Export{Id: "e1", Status: domain.ExportPending}, nil)
+
+		rr := serveExports(router, "GET", "/chat/exports/e1", false)
+
+		var resp domain.Export
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("GetExportHandler response got error %v", err)
+		}
+		assert.Empty(t, resp.DownloadURL)
+	})
+
+	t.Run("someone else's export", func(t *testing.T) {
+		cs.EXPECT().GetExport(gomock.Any(), 1, "e2").Return(domain.Export{}, domain.ErrExportNotFound)
+
+		rr := serveExports(router, "GET", "/chat/exports/e2", false)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestHandler_DownloadExportHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newExportsRouter(NewHandler(cs))
+
+	t.Run("ok", func(t *testing.T) {
+		cs.EXPECT().GetExportArchive(gomock.Any(), 1, "e1").Return(domain.AttachmentContent{
+			FileName:    "export-2024-05-01.zip",
+			ContentType: "application/zip",
+			Size:        3,
+			Body:        io.NopCloser(bytes.NewReader([]byte("zip"))),
+		}, nil)
+
+		rr := serveExports(router, "GET", "/chat/exports/e1/download", false)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "attachment; filename=export-2024-05-01.zip", rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, "3", rr.Header().Get("Content-Length"))
+		assert.Equal(t, "zip", rr.Body.String())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		cs.EXPECT().GetExportArchive(gomock.Any(), 1, "e1").Return(domain.AttachmentContent{}, domain.ErrExportExpired)
+
+		rr := serveExports(router, "GET", "/chat/exports/e1/download", false)
+
+		assert.Equal(t, http.StatusGone, rr.Code)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		cs.EXPECT().GetExportArchive(gomock.Any(), 1, "e1").Return(domain.AttachmentContent{}, domain.ErrExportNotReady)
+
+		rr := serveExports(router, "GET", "/chat/exports/e1/download", false)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
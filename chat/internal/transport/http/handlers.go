@@ -5,9 +5,14 @@ import (
 	"chat/pkg/logger"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -16,17 +21,79 @@ import (
 
 type ChatService interface {
 	StartChat(ctx context.Context, userID1, userID2 int) (int, error)
-	PostMessage(ctx context.Context, chatID, userID int, text string) (int, error)
-	GetMessages(ctx context.Context, chatID int, limit, offset int) ([]domain.Message, error)
+	PostMessage(ctx context.Context, msg domain.NewMessage) (int, error)
+	ForwardMessage(ctx context.Context, fromChatID, messageID, toChatID, userID int) (int, error)
+	GetMessages(ctx context.Context, chatID, userID int, limit, offset int) ([]domain.Message, error)
 	GetUserChats(ctx context.Context, userID int) ([]int, error)
+	MarkRead(ctx context.Context, chatID, userID, messageID int) (domain.ReadReceipt, error)
+	EditMessage(ctx context.Context, chatID, userID, messageID int, edit domain.TextEdit) (domain.Message, error)
+	DeleteMessage(ctx context.Context, chatID, userID, messageID int, forEveryone bool) error
+	GetMessageHistory(ctx context.Context, chatID, userID, messageID int) ([]domain.MessageEdit, error)
+	GetUnreadCounts(ctx context.Context, userID int) ([]domain.UnreadCount, error)
+	ScheduleMessage(ctx context.Context, msg domain.NewMessage, sendAt time.Time) (domain.ScheduledMessage, error)
+	ListScheduledMessages(ctx context.Context, userID, chatID int) ([]domain.ScheduledMessage, error)
+	EditScheduledMessage(ctx context.Context, userID, id int, edit domain.ScheduledEdit) (domain.ScheduledMessage, error)
+	CancelScheduledMessage(ctx context.Context, userID, id int) error
+	PinMessage(ctx context.Context, chatID, userID, messageID int) (domain.PinnedMessage, error)
+	UnpinMessage(ctx context.Context, chatID, userID, messageID int) error
+	GetPinnedMessages(ctx context.Context, chatID, userID int) ([]domain.PinnedMessage, error)
+	SaveDraft(ctx context.Context, userID int, draft domain.Draft, format string) (domain.Draft, error)
+	GetDrafts(ctx context.Context, userID int) ([]domain.Draft, error)
+	AddReaction(ctx context.Context, chatID, userID, messageID int, emoji string) ([]domain.ReactionCount, error)
+	RemoveReaction(ctx context.Context, chatID, userID, messageID int, emoji string) ([]domain.ReactionCount, error)
+	UploadAttachment(ctx context.Context, chatID, userID int, fileName string, r io.Reader) (domain.Attachment, error)
+	GetAttachment(ctx context.Context, chatID, userID, attachmentID int, thumbnail bool) (domain.AttachmentContent, error)
+	SearchMessages(ctx context.Context, userID, chatID int, text, cursor string, limit int) (domain.SearchPage, error)
+	SyncChanges(ctx context.Context, userID int, since int64, limit int) (domain.SyncBatch, error)
+	GetSyncCursor(ctx context.Context, userID int) (int64, error)
+	SubscribeUser(ctx context.Context, userID int) <-chan domain.Event
+	BlockUser(ctx context.Context, userID, blockedUserID int) error
+	UnblockUser(ctx context.Context, userID, blockedUserID int) error
+	GetBlockedUsers(ctx context.Context, userID int) ([]int, error)
+	GetChatSettings(ctx context.Context, chatID, userID int) (domain.ChatSettings, error)
+	UpdateChatSettings(ctx context.Context, chatID, userID int, upd domain.ChatSettingsUpdate) (domain.ChatSettings, error)
+	GetVAPIDPublicKey() (string, error)
+	SubscribePush(ctx context.Context, sub domain.PushSubscription) error
+	UnsubscribePush(ctx context.Context, userID int, endpoint string) error
+	GetChatRetention(ctx context.Context, chatID, userID int) (domain.ChatRetention, error)
+	SetChatRetention(ctx context.Context, chatID, userID int, ttl time.Duration) (domain.ChatRetention, error)
+	RequestExport(ctx context.Context, userID int, token string) (domain.Export, error)
+	ListExports(ctx context.Context, userID int) ([]domain.Export, error)
+	GetExport(ctx context.Context, userID int, exportID string) (domain.Export, error)
+	GetExportArchive(ctx context.Context, userID int, exportID string) (domain.AttachmentContent, error)
+	ListUsers(ctx context.Context, admin domain.Identity, q domain.UserQuery) ([]domain.User, error)
+	SuspendUser(ctx context.Context, admin domain.Identity, suspension domain.Suspension) (domain.User, error)
+	UnsuspendUser(ctx context.Context, admin domain.Identity, userID int) (domain.User, error)
+	ListChats(ctx context.Context, admin domain.Identity, q domain.ChatQuery) ([]domain.ChatInfo, error)
+	ReportMessage(ctx context.Context, report domain.Report) error
+	GetReportedMessages(ctx context.Context, admin domain.Identity, status string, limit, offset int) ([]domain.ReportedMessage, error)
+	ResolveReport(ctx context.Context, admin domain.Identity, messageID int, action, reason string) (domain.ReportedMessage, error)
+	AdminDeleteMessage(ctx context.Context, admin domain.Identity, chatID, messageID int, reason string) error
+	AdminDeleteChat(ctx context.Context, admin domain.Identity, chatID int, reason string) error
+	GetAuditLog(ctx context.Context, admin domain.Identity, limit, offset int) ([]domain.AuditEntry, error)
+	SetWebhook(ctx context.Context, bot domain.Identity, rawURL string) (domain.Webhook, error)
+	GetWebhook(ctx context.Context, bot domain.Identity) (domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, bot domain.Identity) error
+	CreateIncomingWebhook(ctx context.Context, chatID, userID int, name string) (domain.IncomingWebhook, error)
+	ListIncomingWebhooks(ctx context.Context, chatID, userID int) ([]domain.IncomingWebhook, error)
+	DeleteIncomingWebhook(ctx context.Context, chatID, userID, webhookID int) error
+	PostIncomingWebhook(ctx context.Context, token string, post domain.IncomingWebhookPost) (int, error)
 }
 
 type Handler struct {
 	srv ChatService
+	// closing закрывается при остановке сервера, чтобы завершить открытые SSE-потоки
+	closing   chan struct{}
+	closeOnce sync.Once
 }
 
 func NewHandler(srv ChatService) *Handler {
-	return &Handler{srv: srv}
+	return &Handler{srv: srv, closing: make(chan struct{})}
+}
+
+// CloseStreams завершает открытые SSE-потоки: http.Server.Shutdown не прерывает активные запросы
+func (h *Handler) CloseStreams() {
+	h.closeOnce.Do(func() { close(h.closing) })
 }
 
 func (h *Handler) GetChatsHandler() http.Handler {
@@ -43,9 +110,17 @@ func (h *Handler) GetChatsHandler() http.Handler {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		drafts, err := h.srv.GetDrafts(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if drafts == nil {
+			drafts = []domain.Draft{}
+		}
 
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(GetChatsResponse{id, chats}); err != nil {
+		if err := json.NewEncoder(w).Encode(GetChatsResponse{UserId: id, Chats: chats, Drafts: drafts}); err != nil {
 			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			return
@@ -72,7 +147,7 @@ func (h *Handler) NewChatHandler() http.Handler {
 
 		chatID, err := h.srv.StartChat(r.Context(), userId1, req.UserID2)
 		if err != nil {
-			http.Error(w, "Failed to create chat: "+err.Error(), http.StatusInternalServerError)
+			writeServiceError(w, "Failed to create chat: ", err)
 			return
 		}
 
@@ -107,25 +182,58 @@ func (h *Handler) SendMessageHandler() http.Handler {
 			return
 		}
 
-		if chatId == 0 || userId == 0 || req.Text == "" {
-			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "Chat ID or User ID or text are required", nil)
-			http.Error(w, "Chat ID or User ID or text are required", http.StatusBadRequest)
-			return
-		}
+		h.sendMessage(w, r, chatId, userId, req)
+	})
+}
+
+// sendMessage отправляет или откладывает сообщение из запроса и пишет ответ. Общая часть
+// SendMessageHandler и BotSendMessageHandler
+func (h *Handler) sendMessage(w http.ResponseWriter, r *http.Request, chatId, userId int, req SendMessageRequest) {
+	if chatId == 0 || userId == 0 || (req.Text == "" && len(req.AttachmentIDs) == 0 && req.Envelope == nil) {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "Chat ID or User ID or text are required", nil)
+		http.Error(w, "Chat ID or User ID or text are required", http.StatusBadRequest)
+		return
+	}
+
+	msg := domain.NewMessage{
+		ChatId:          chatId,
+		SenderId:        userId,
+		Text:            req.Text,
+		Format:          req.Format,
+		Entities:        req.Entities,
+		ReplyToId:       req.ReplyToMessageID,
+		AttachmentIds:   req.AttachmentIDs,
+		ClientMessageId: req.ClientMessageID,
+		Envelope:        req.Envelope,
+	}
 
-		messageID, err := h.srv.PostMessage(r.Context(), chatId, userId, req.Text)
+	if req.SendAt != nil {
+		scheduled, err := h.srv.ScheduleMessage(r.Context(), msg, *req.SendAt)
 		if err != nil {
-			http.Error(w, "Failed to send message: "+err.Error(), http.StatusInternalServerError)
+			writeServiceError(w, "Failed to schedule message: ", err)
 			return
 		}
 
-		w.WriteHeader(http.StatusCreated)
-		if err := json.NewEncoder(w).Encode(SendMessageResponse{MessageID: messageID}); err != nil {
+		w.WriteHeader(http.StatusAccepted)
+		if err := json.NewEncoder(w).Encode(scheduled); err != nil {
 			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-			return
 		}
-	})
+		return
+	}
+
+	messageID, err := h.srv.PostMessage(r.Context(), msg)
+	if err != nil {
+		writeServiceError(w, "Failed to send message: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(SendMessageResponse{MessageID: messageID}); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
 }
 
 func (h *Handler) GetMessagesHandler() http.Handler {
@@ -136,13 +244,26 @@ func (h *Handler) GetMessagesHandler() http.Handler {
 			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
 			return
 		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
 		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-		messages, err := h.srv.GetMessages(r.Context(), chatID, limit, offset)
+		messages, err := h.srv.GetMessages(r.Context(), chatID, userId, limit, offset)
 		if err != nil {
-			http.Error(w, "Failed to get messages: "+err.Error(), http.StatusInternalServerError)
+			writeServiceError(w, "Failed to get messages: ", err)
 			return
 		}
+		for i := range messages {
+			for j := range messages[i].Attachments {
+				setAttachmentURLs(&messages[i].Attachments[j])
+			}
+		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(messages); err != nil {
@@ -152,3 +273,289 @@ func (h *Handler) GetMessagesHandler() http.Handler {
 		}
 	})
 }
+
+func (h *Handler) MarkReadHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		chatID, err := strconv.Atoi(vars["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		// Пустое тело - отметить прочитанными все сообщения чата
+		var req MarkReadRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		receipt, err := h.srv.MarkRead(r.Context(), chatID, userId, req.MessageID)
+		if err != nil {
+			writeServiceError(w, "Failed to mark read: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(MarkReadResponse{
+			ChatID:            receipt.ChatId,
+			LastReadMessageID: receipt.LastReadMessageId,
+		}); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+func (h *Handler) EditMessageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		chatID, err := strconv.Atoi(vars["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+		messageID, err := strconv.Atoi(vars["message_id"])
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req EditMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Text == "" {
+			http.Error(w, "Text is required", http.StatusBadRequest)
+			return
+		}
+
+		msg, err := h.srv.EditMessage(r.Context(), chatID, userId, messageID, domain.TextEdit{
+			Text:     req.Text,
+			Format:   req.Format,
+			Entities: req.Entities,
+		})
+		if err != nil {
+			writeServiceError(w, "Failed to edit message: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(msg); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+func (h *Handler) DeleteMessageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		chatID, err := strconv.Atoi(vars["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+		messageID, err := strconv.Atoi(vars["message_id"])
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var forEveryone bool
+		switch r.URL.Query().Get("for") {
+		case "", DeleteForMe:
+			forEveryone = false
+		case DeleteForEveryone:
+			forEveryone = true
+		default:
+			http.Error(w, "Invalid delete mode", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.srv.DeleteMessage(r.Context(), chatID, userId, messageID, forEveryone); err != nil {
+			writeServiceError(w, "Failed to delete message: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (h *Handler) GetMessageHistoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		chatID, err := strconv.Atoi(vars["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+		messageID, err := strconv.Atoi(vars["message_id"])
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		edits, err := h.srv.GetMessageHistory(r.Context(), chatID, userId, messageID)
+		if err != nil {
+			writeServiceError(w, "Failed to get message history: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(GetMessageHistoryResponse(edits)); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+func (h *Handler) ForwardMessageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		chatID, err := strconv.Atoi(vars["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+		messageID, err := strconv.Atoi(vars["message_id"])
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req ForwardMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.ToChatID <= 0 {
+			http.Error(w, "Target chat ID is required", http.StatusBadRequest)
+			return
+		}
+
+		newMessageID, err := h.srv.ForwardMessage(r.Context(), chatID, messageID, req.ToChatID, userId)
+		if err != nil {
+			writeServiceError(w, "Failed to forward message: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(SendMessageResponse{MessageID: newMessageID}); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+// writeServiceError переводит доменные ошибки сервиса в HTTP-статусы
+func writeServiceError(w http.ResponseWriter, prefix string, err error) {
+	var rateErr *domain.RateLimitError
+	switch {
+	case errors.As(err, &rateErr):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rateErr.RetryAfter.Seconds()))))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case errors.Is(err, domain.ErrNotChatMember),
+		errors.Is(err, domain.ErrNotMessageSender),
+		errors.Is(err, domain.ErrEditWindowExpired),
+		errors.Is(err, domain.ErrUserBlocked),
+		errors.Is(err, domain.ErrAdminRequired),
+		errors.Is(err, domain.ErrBotRequired),
+		errors.Is(err, domain.ErrUserSuspended):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrMessageNotFound),
+		errors.Is(err, domain.ErrAttachmentNotFound),
+		errors.Is(err, domain.ErrPushDisabled),
+		errors.Is(err, domain.ErrExportNotFound),
+		errors.Is(err, domain.ErrReportNotFound),
+		errors.Is(err, domain.ErrScheduledNotFound),
+		errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrWebhookNotFound),
+		errors.Is(err, domain.ErrIncomingWebhookNotFound),
+		errors.Is(err, domain.ErrChatNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrMessageDeleted),
+		errors.Is(err, domain.ErrExportInProgress),
+		errors.Is(err, domain.ErrExportNotReady),
+		errors.Is(err, domain.ErrAlreadyReported),
+		errors.Is(err, domain.ErrReportResolved),
+		errors.Is(err, domain.ErrMessageHidden),
+		errors.Is(err, domain.ErrScheduledSending):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrExportExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+	case errors.Is(err, domain.ErrInvalidEmoji),
+		errors.Is(err, domain.ErrInvalidClientId),
+		errors.Is(err, domain.ErrTooManyAttachments),
+		errors.Is(err, domain.ErrEmptySearchQuery),
+		errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrInvalidBlock),
+		errors.Is(err, domain.ErrInvalidMuteTimeout),
+		errors.Is(err, domain.ErrMessageTooLong),
+		errors.Is(err, domain.ErrInvalidPushSubscription),
+		errors.Is(err, domain.ErrInvalidEnvelope),
+		errors.Is(err, domain.ErrMessageEncrypted),
+		errors.Is(err, domain.ErrInvalidRetention),
+		errors.Is(err, domain.ErrInvalidSuspension),
+		errors.Is(err, domain.ErrInvalidReport),
+		errors.Is(err, domain.ErrInvalidEntities),
+		errors.Is(err, domain.ErrInvalidSendAt),
+		errors.Is(err, domain.ErrTooManyScheduled),
+		errors.Is(err, domain.ErrTooManyPinned),
+		errors.Is(err, domain.ErrInvalidWebhookURL),
+		errors.Is(err, domain.ErrInvalidIncomingWebhook),
+		errors.Is(err, domain.ErrTooManyIncomingWebhooks):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrAttachmentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, domain.ErrContentRejected):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, domain.ErrAttachmentType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
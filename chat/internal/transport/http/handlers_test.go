@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"bytes"
+	"chat/internal/domain"
 	"chat/internal/transport/http/mock"
 	"chat/pkg/logger"
 	"context"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -35,9 +37,14 @@ func TestGetChatsHandler(t *testing.T) {
 				cs.EXPECT().
 					GetUserChats(gomock.Any(), userID).
 					Return([]int{1, 2}, nil)
+				cs.EXPECT().
+					GetDrafts(gomock.Any(), userID).
+					Return([]domain.Draft{{ChatId: 2, Text: "черновик", UpdatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}}, nil)
 			},
 			want: GetChatsResponse{
-				Chats: []int{1, 2},
+				UserId: 1,
+				Chats:  []int{1, 2},
+				Drafts: []domain.Draft{{ChatId: 2, Text: "черновик", UpdatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}},
 			},
 			wantStatus: http.StatusOK,
 		},
@@ -147,6 +154,22 @@ func TestHandler_NewChatHandler(t *testing.T) {
 			},
 			wantStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "blocked",
+			host: 1,
+			req: NewChatRequest{
+				UserID2: 2,
+			},
+			mockBehavior: func(user1, user2 int) {
+				cs.EXPECT().
+					StartChat(gomock.Any(), user1, user2).
+					Return(-1, domain.ErrUserBlocked)
+			},
+			want: NewChatResponse{
+				ChatID: 0,
+			},
+			wantStatus: http.StatusForbidden,
+		},
 		{
 			name: "error user id",
 			host: 1,
@@ -214,6 +237,7 @@ func TestHandler_SendMessageHandler(t *testing.T) {
 		req 	SendMessageRequest
 		resp 	SendMessageResponse
 		wantStatus int
+		retryAfter string
 	}{
 		{
 			name: "ok",
@@ -222,7 +246,28 @@ func TestHandler_SendMessageHandler(t *testing.T) {
 			},
 			mockBehavior: func(chatId, userId int, text string) {
 				cs.EXPECT().
-					PostMessage(gomock.Any(), chatId, userId, text).
+					PostMessage(gomock.Any(), domain.NewMessage{ChatId: chatId, SenderId: userId, Text: text}).
+					Return(8, nil)
+			},
+			resp: SendMessageResponse{
+				MessageID: 8,
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "retry with client message id",
+			req: SendMessageRequest{
+				Text:            "Hello",
+				ClientMessageID: "6f9619ff-8b86-d011-b42d-00c04fc964ff",
+			},
+			mockBehavior: func(chatId, userId int, text string) {
+				cs.EXPECT().
+					PostMessage(gomock.Any(), domain.NewMessage{
+						ChatId:          chatId,
+						SenderId:        userId,
+						Text:            text,
+						ClientMessageId: "6f9619ff-8b86-d011-b42d-00c04fc964ff",
+					}).
 					Return(8, nil)
 			},
 			resp: SendMessageResponse{
@@ -230,6 +275,80 @@ func TestHandler_SendMessageHandler(t *testing.T) {
 			},
 			wantStatus: http.StatusCreated,
 		},
+		{
+			name: "invalid client message id",
+			req: SendMessageRequest{
+				Text:            "Hello",
+				ClientMessageID: "retry-1",
+			},
+			mockBehavior: func(chatId, userId int, text string) {
+				cs.EXPECT().
+					PostMessage(gomock.Any(), domain.NewMessage{
+						ChatId:          chatId,
+						SenderId:        userId,
+						Text:            text,
+						ClientMessageId: "retry-1",
+					}).
+					Return(-1, domain.ErrInvalidClientId)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "encrypted",
+			req: SendMessageRequest{
+				Envelope: &domain.Envelope{Algorithm: "x3dh", SenderDeviceId: "phone", Ciphertext: "AQID"},
+			},
+			mockBehavior: func(chatId, userId int, text string) {
+				cs.EXPECT().
+					PostMessage(gomock.Any(), domain.NewMessage{
+						ChatId:   chatId,
+						SenderId: userId,
+						Envelope: &domain.Envelope{Algorithm: "x3dh", SenderDeviceId: "phone", Ciphertext: "AQID"},
+					}).
+					Return(9, nil)
+			},
+			resp: SendMessageResponse{
+				MessageID: 9,
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "invalid envelope",
+			req: SendMessageRequest{
+				Envelope: &domain.Envelope{Algorithm: "x3dh"},
+			},
+			mockBehavior: func(chatId, userId int, text string) {
+				cs.EXPECT().
+					PostMessage(gomock.Any(), gomock.Any()).
+					Return(-1, domain.ErrInvalidEnvelope)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rate limited",
+			req: SendMessageRequest{
+				Text: "Hello",
+			},
+			mockBehavior: func(chatId, userId int, text string) {
+				cs.EXPECT().
+					PostMessage(gomock.Any(), domain.NewMessage{ChatId: chatId, SenderId: userId, Text: text}).
+					Return(-1, &domain.RateLimitError{RetryAfter: 1500 * time.Millisecond})
+			},
+			wantStatus: http.StatusTooManyRequests,
+			retryAfter: "2",
+		},
+		{
+			name: "too long",
+			req: SendMessageRequest{
+				Text: "Hello",
+			},
+			mockBehavior: func(chatId, userId int, text string) {
+				cs.EXPECT().
+					PostMessage(gomock.Any(), domain.NewMessage{ChatId: chatId, SenderId: userId, Text: text}).
+					Return(-1, domain.ErrMessageTooLong)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -257,6 +376,9 @@ func TestHandler_SendMessageHandler(t *testing.T) {
 			if tt.wantStatus != rr.Code {
 				t.Errorf("GetChatsHandler status got %v, want %v", rr.Code, tt.wantStatus)
 			}
+			if got := rr.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("SendMessageHandler Retry-After got %q, want %q", got, tt.retryAfter)
+			}
 
 			if tt.wantStatus != 200 && tt.wantStatus != 201 {
 				return
@@ -271,3 +393,354 @@ func TestHandler_SendMessageHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_MarkReadHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(chatId, userId, messageId int)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		body         string
+		messageId    int
+		resp         MarkReadResponse
+		wantStatus   int
+	}{
+		{
+			name:      "ok",
+			body:      `{"message_id": 5}`,
+			messageId: 5,
+			mockBehavior: func(chatId, userId, messageId int) {
+				cs.EXPECT().
+					MarkRead(gomock.Any(), chatId, userId, messageId).
+					Return(domain.ReadReceipt{ChatId: chatId, UserId: userId, LastReadMessageId: 5}, nil)
+			},
+			resp: MarkReadResponse{
+				ChatID:            1,
+				LastReadMessageID: 5,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:      "empty body",
+			body:      "",
+			messageId: 0,
+			mockBehavior: func(chatId, userId, messageId int) {
+				cs.EXPECT().
+					MarkRead(gomock.Any(), chatId, userId, messageId).
+					Return(domain.ReadReceipt{ChatId: chatId, UserId: userId, LastReadMessageId: 9}, nil)
+			},
+			resp: MarkReadResponse{
+				ChatID:            1,
+				LastReadMessageID: 9,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:      "not member",
+			body:      `{"message_id": 5}`,
+			messageId: 5,
+			mockBehavior: func(chatId, userId, messageId int) {
+				cs.EXPECT().
+					MarkRead(gomock.Any(), chatId, userId, messageId).
+					Return(domain.ReadReceipt{}, domain.ErrNotChatMember)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:      "message not found",
+			body:      `{"message_id": 100}`,
+			messageId: 100,
+			mockBehavior: func(chatId, userId, messageId int) {
+				cs.EXPECT().
+					MarkRead(gomock.Any(), chatId, userId, messageId).
+					Return(domain.ReadReceipt{}, domain.ErrMessageNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:         "invalid body",
+			body:         `{"message_id": "abc"}`,
+			mockBehavior: func(chatId, userId, messageId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 1, tt.messageId)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/read", h.MarkReadHandler())
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("POST", "/chat/1/read", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("MarkReadHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp MarkReadResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("MarkReadHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
+
+func TestHandler_EditMessageHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(chatId, userId, messageId int, text string)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		req          EditMessageRequest
+		wantStatus   int
+	}{
+		{
+			name: "ok",
+			req:  EditMessageRequest{Text: "edited"},
+			mockBehavior: func(chatId, userId, messageId int, text string) {
+				cs.EXPECT().
+					EditMessage(gomock.Any(), chatId, userId, messageId, domain.TextEdit{Text: text}).
+					Return(domain.Message{Id: messageId, Text: text}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "empty text",
+			req:          EditMessageRequest{Text: ""},
+			mockBehavior: func(chatId, userId, messageId int, text string) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "not sender",
+			req:  EditMessageRequest{Text: "edited"},
+			mockBehavior: func(chatId, userId, messageId int, text string) {
+				cs.EXPECT().
+					EditMessage(gomock.Any(), chatId, userId, messageId, domain.TextEdit{Text: text}).
+					Return(domain.Message{}, domain.ErrNotMessageSender)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "deleted",
+			req:  EditMessageRequest{Text: "edited"},
+			mockBehavior: func(chatId, userId, messageId int, text string) {
+				cs.EXPECT().
+					EditMessage(gomock.Any(), chatId, userId, messageId, domain.TextEdit{Text: text}).
+					Return(domain.Message{}, domain.ErrMessageDeleted)
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 1, 2, tt.req.Text)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}", h.EditMessageHandler())
+
+			rr := httptest.NewRecorder()
+
+			requestBody, err := json.Marshal(tt.req)
+			if err != nil {
+				t.Fatalf("Failed to marshal request: %v", err)
+			}
+
+			req := httptest.NewRequest("PATCH", "/chat/1/messages/2", bytes.NewBuffer(requestBody))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("EditMessageHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp domain.Message
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("EditMessageHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.req.Text, resp.Text)
+		})
+	}
+}
+
+func TestHandler_DeleteMessageHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(chatId, userId, messageId int)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		query        string
+		wantStatus   int
+	}{
+		{
+			name:  "for me by default",
+			query: "",
+			mockBehavior: func(chatId, userId, messageId int) {
+				cs.EXPECT().
+					DeleteMessage(gomock.Any(), chatId, userId, messageId, false).
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:  "for everyone",
+			query: "?for=everyone",
+			mockBehavior: func(chatId, userId, messageId int) {
+				cs.EXPECT().
+					DeleteMessage(gomock.Any(), chatId, userId, messageId, true).
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:         "invalid mode",
+			query:        "?for=all",
+			mockBehavior: func(chatId, userId, messageId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:  "window expired",
+			query: "?for=everyone",
+			mockBehavior: func(chatId, userId, messageId int) {
+				cs.EXPECT().
+					DeleteMessage(gomock.Any(), chatId, userId, messageId, true).
+					Return(domain.ErrEditWindowExpired)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 1, 2)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}", h.DeleteMessageHandler())
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("DELETE", "/chat/1/messages/2"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("DeleteMessageHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_ForwardMessageHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(fromChatId, messageId, toChatId, userId int)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		req          ForwardMessageRequest
+		resp         SendMessageResponse
+		wantStatus   int
+	}{
+		{
+			name: "ok",
+			req:  ForwardMessageRequest{ToChatID: 3},
+			mockBehavior: func(fromChatId, messageId, toChatId, userId int) {
+				cs.EXPECT().
+					ForwardMessage(gomock.Any(), fromChatId, messageId, toChatId, userId).
+					Return(10, nil)
+			},
+			resp:       SendMessageResponse{MessageID: 10},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:         "no target chat",
+			req:          ForwardMessageRequest{},
+			mockBehavior: func(fromChatId, messageId, toChatId, userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "not member",
+			req:  ForwardMessageRequest{ToChatID: 3},
+			mockBehavior: func(fromChatId, messageId, toChatId, userId int) {
+				cs.EXPECT().
+					ForwardMessage(gomock.Any(), fromChatId, messageId, toChatId, userId).
+					Return(-1, domain.ErrNotChatMember)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 2, tt.req.ToChatID, 1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/forward", h.ForwardMessageHandler())
+
+			rr := httptest.NewRecorder()
+
+			requestBody, err := json.Marshal(tt.req)
+			if err != nil {
+				t.Fatalf("Failed to marshal request: %v", err)
+			}
+
+			req := httptest.NewRequest("POST", "/chat/1/messages/2/forward", bytes.NewBuffer(requestBody))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("ForwardMessageHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+
+			var resp SendMessageResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("ForwardMessageHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// maxIncomingWebhookBody - больший запрос на адрес вебхука отклоняется, не дочитываясь
+const maxIncomingWebhookBody = 64 << 10
+
+func (h *Handler) CreateIncomingWebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req CreateIncomingWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		webhook, err := h.srv.CreateIncomingWebhook(r.Context(), chatID, userId, req.Name)
+		if err != nil {
+			writeServiceError(w, "Failed to create incoming webhook: ", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(webhook); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		}
+	})
+}
+
+func (h *Handler) ListIncomingWebhooksHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		webhooks, err := h.srv.ListIncomingWebhooks(r.Context(), chatID, userId)
+		if err != nil {
+			writeServiceError(w, "Failed to list incoming webhooks: ", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(webhooks); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		}
+	})
+}
+
+func (h *Handler) DeleteIncomingWebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		chatID, err := strconv.Atoi(vars["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+		webhookID, err := strconv.Atoi(vars["webhook_id"])
+		if err != nil {
+			http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.srv.DeleteIncomingWebhook(r.Context(), chatID, userId, webhookID); err != nil {
+			writeServiceError(w, "Failed to delete incoming webhook: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// PostIncomingWebhookHandler принимает сообщение на адрес входящего вебхука. Запрос авторизуется только
+// секретом из адреса. Как и Slack, принимает JSON или форму с JSON в поле payload и отвечает "ok"
+func (h *Handler) PostIncomingWebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxIncomingWebhookBody)
+
+		var payload IncomingWebhookPayload
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		var err error
+		if mediaType == "application/x-www-form-urlencoded" {
+			err = json.Unmarshal([]byte(r.PostFormValue("payload")), &payload)
+		} else {
+			err = json.NewDecoder(r.Body).Decode(&payload)
+		}
+		if err != nil {
+			http.Error(w, "Invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		_, err = h.srv.PostIncomingWebhook(r.Context(), mux.Vars(r)["token"], domain.IncomingWebhookPost{
+			Text:     payload.Text,
+			Username: payload.Username,
+			Format:   payload.Format,
+		})
+		if err != nil {
+			writeServiceError(w, "Failed to post message: ", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+}
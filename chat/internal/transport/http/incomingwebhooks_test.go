@@ -0,0 +1,126 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// newHooksRouter собирает маршруты сервера целиком: адрес вебхука должен работать без токена пользователя
+func newHooksRouter(t *testing.T, cs *mock.MockChatService) *mux.Router {
+	router := mux.NewRouter()
+	auther := mock.NewMockAuther(gomock.NewController(t))
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+	setupRouter(router, &Server{ctx: ctx, Handler: NewHandler(cs), Auther: auther})
+	return router
+}
+
+func TestHandler_PostIncomingWebhookHandler(t *testing.T) {
+	t.Run("slack json without user token", func(t *testing.T) {
+		cs := mock.NewMockChatService(gomock.NewController(t))
+		router := newHooksRouter(t, cs)
+
+		cs.EXPECT().
+			PostIncomingWebhook(gomock.Any(), "s3cr3t", domain.IncomingWebhookPost{Text: "Deploy finished", Username: "deploy"}).
+			Return(12, nil)
+
+		req := httptest.NewRequest("POST", "/hooks/s3cr3t", strings.NewReader(`{"text":"Deploy finished","username":"deploy","icon_emoji":":rocket:"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "ok", rr.Body.String())
+	})
+
+	t.Run("form with payload field", func(t *testing.T) {
+		cs := mock.NewMockChatService(gomock.NewController(t))
+		router := newHooksRouter(t, cs)
+
+		cs.EXPECT().PostIncomingWebhook(gomock.Any(), "s3cr3t", domain.IncomingWebhookPost{Text: "hello"}).Return(13, nil)
+
+		form := url.Values{"payload": {`{"text":"hello"}`}}
+		req := httptest.NewRequest("POST", "/hooks/s3cr3t", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cs := mock.NewMockChatService(gomock.NewController(t))
+		router := newHooksRouter(t, cs)
+
+		cs.EXPECT().PostIncomingWebhook(gomock.Any(), "revoked", gomock.Any()).Return(-1, domain.ErrIncomingWebhookNotFound)
+		cs.EXPECT().PostIncomingWebhook(gomock.Any(), "busy", gomock.Any()).Return(-1, &domain.RateLimitError{RetryAfter: 1500 * time.Millisecond})
+
+		tests := []struct {
+			target, body string
+			want         int
+		}{
+			{"/hooks/revoked", `{"text":"hi"}`, http.StatusNotFound},
+			{"/hooks/busy", `{"text":"hi"}`, http.StatusTooManyRequests},
+			{"/hooks/any", `not json`, http.StatusBadRequest},
+			{"/hooks/any", `{"text":"` + strings.Repeat("a", maxIncomingWebhookBody) + `"}`, http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.want, rr.Code, tt.target)
+			if tt.want == http.StatusTooManyRequests {
+				assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+			}
+		}
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/chat/", nil))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, "other routes still require a token")
+	})
+}
+
+func TestHandler_IncomingWebhookManagement(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	h := NewHandler(cs)
+	router := mux.NewRouter()
+	router.Handle("/chat/{chat_id:[0-9]+}/webhooks", h.ListIncomingWebhooksHandler()).Methods("GET")
+	router.Handle("/chat/{chat_id:[0-9]+}/webhooks", h.CreateIncomingWebhookHandler()).Methods("POST")
+	router.Handle("/chat/{chat_id:[0-9]+}/webhooks/{webhook_id:[0-9]+}", h.DeleteIncomingWebhookHandler()).Methods("DELETE")
+	user := domain.Identity{UserId: 2, Role: domain.RoleUser}
+	created := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	cs.EXPECT().CreateIncomingWebhook(gomock.Any(), 3, 2, "CI").
+		Return(domain.IncomingWebhook{Id: 5, ChatId: 3, CreatorId: 2, Name: "CI", Token: "tok", CreatedAt: created}, nil)
+	rr := serveAdmin(router, user, "POST", "/chat/3/webhooks", []byte(`{"name":"CI"}`))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"id":5,"chat_id":3,"creator_id":2,"name":"CI","token":"tok","created_at":"2030-05-01T09:00:00Z"}`, rr.Body.String())
+
+	cs.EXPECT().CreateIncomingWebhook(gomock.Any(), 3, 2, "").Return(domain.IncomingWebhook{}, domain.ErrInvalidIncomingWebhook)
+	rr = serveAdmin(router, user, "POST", "/chat/3/webhooks", []byte(`{}`))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	cs.EXPECT().ListIncomingWebhooks(gomock.Any(), 3, 2).
+		Return([]domain.IncomingWebhook{{Id: 5, ChatId: 3, CreatorId: 2, Name: "CI", CreatedAt: created}}, nil)
+	rr = serveAdmin(router, user, "GET", "/chat/3/webhooks", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "token")
+
+	cs.EXPECT().DeleteIncomingWebhook(gomock.Any(), 3, 2, 5).Return(nil)
+	rr = serveAdmin(router, user, "DELETE", "/chat/3/webhooks/5", nil)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	cs.EXPECT().DeleteIncomingWebhook(gomock.Any(), 3, 2, 6).Return(domain.ErrNotChatMember)
+	rr = serveAdmin(router, user, "DELETE", "/chat/3/webhooks/6", nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
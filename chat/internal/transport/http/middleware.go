@@ -1,15 +1,18 @@
 package httpserver
 
 import (
+	"chat/internal/domain"
 	"chat/pkg/logger"
 	"context"
+	"errors"
 	"net/http"
+	"strings"
 )
 
 //go:generate mockgen -destination=./mock/mock_middleware.go -package=mock -source=middleware.go
 
 type Auther interface {
-	GetId(ctx context.Context, token string) (int, error)
+	Authenticate(ctx context.Context, token string) (domain.Identity, error)
 }
 
 func InitLoggerCtxMiddleware(ctx context.Context) func(next http.Handler) http.Handler {
@@ -25,23 +28,60 @@ func InitLoggerCtxMiddleware(ctx context.Context) func(next http.Handler) http.H
 func AuthMiddleware(auther Auther) func(next http.Handler) http.Handler{
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			cookie, err := r.Cookie("user_jwt")
-			if err != nil {
-				http.Error(w, "not found cookie", http.StatusUnauthorized)
+			token, ok := requestToken(r)
+			if !ok {
+				http.Error(w, "not found cookie or bearer token", http.StatusUnauthorized)
 				return
 			}
 
-			id, err := auther.GetId(r.Context(), cookie.Value)
+			identity, err := auther.Authenticate(r.Context(), token)
+			if errors.Is(err, domain.ErrUserSuspended) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
 			if err != nil {
 				http.Error(w, "failed to get id from auth", http.StatusUnauthorized)
 				return
 			}
 
-			ctx := logger.AppendCtx(r.Context(), UserIdKey, id)
-			ctx = context.WithValue(ctx, UserIdKey, id)
+			ctx := logger.AppendCtx(r.Context(), UserIdKey, identity.UserId)
+			ctx = context.WithValue(ctx, UserIdKey, identity.UserId)
+			ctx = context.WithValue(ctx, IdentityKey, identity)
 			r = r.WithContext(ctx)
 			
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// requestToken берет токен из куки user_jwt, а без нее - из заголовка Authorization: Bearer,
+// как его передают боты
+func requestToken(r *http.Request) (string, bool) {
+	if cookie, err := r.Cookie("user_jwt"); err == nil {
+		return cookie.Value, true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token, ok && token != ""
+}
+
+// RequireAdmin пропускает к административным маршрутам только пользователей с ролью admin
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !identityFromCtx(r).IsAdmin() {
+			http.Error(w, domain.ErrAdminRequired.Error(), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireBot пропускает к API ботов только запросы с токеном бота
+func RequireBot(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !identityFromCtx(r).IsBot() {
+			http.Error(w, domain.ErrBotRequired.Error(), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -7,7 +7,9 @@ package mock
 import (
 	domain "chat/internal/domain"
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,19 +37,387 @@ func (m *MockChatService) EXPECT() *MockChatServiceMockRecorder {
 	return m.recorder
 }
 
+// AddReaction mocks base method.
+func (m *MockChatService) AddReaction(ctx context.Context, chatID, userID, messageID int, emoji string) ([]domain.ReactionCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, chatID, userID, messageID, emoji)
+	ret0, _ := ret[0].([]domain.ReactionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockChatServiceMockRecorder) AddReaction(ctx, chatID, userID, messageID, emoji interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockChatService)(nil).AddReaction), ctx, chatID, userID, messageID, emoji)
+}
+
+// AdminDeleteChat mocks base method.
+func (m *MockChatService) AdminDeleteChat(ctx context.Context, admin domain.Identity, chatID int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDeleteChat", ctx, admin, chatID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminDeleteChat indicates an expected call of AdminDeleteChat.
+func (mr *MockChatServiceMockRecorder) AdminDeleteChat(ctx, admin, chatID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDeleteChat", reflect.TypeOf((*MockChatService)(nil).AdminDeleteChat), ctx, admin, chatID, reason)
+}
+
+// AdminDeleteMessage mocks base method.
+func (m *MockChatService) AdminDeleteMessage(ctx context.Context, admin domain.Identity, chatID, messageID int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDeleteMessage", ctx, admin, chatID, messageID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminDeleteMessage indicates an expected call of AdminDeleteMessage.
+func (mr *MockChatServiceMockRecorder) AdminDeleteMessage(ctx, admin, chatID, messageID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDeleteMessage", reflect.TypeOf((*MockChatService)(nil).AdminDeleteMessage), ctx, admin, chatID, messageID, reason)
+}
+
+// BlockUser mocks base method.
+func (m *MockChatService) BlockUser(ctx context.Context, userID, blockedUserID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", ctx, userID, blockedUserID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockChatServiceMockRecorder) BlockUser(ctx, userID, blockedUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockChatService)(nil).BlockUser), ctx, userID, blockedUserID)
+}
+
+// CancelScheduledMessage mocks base method.
+func (m *MockChatService) CancelScheduledMessage(ctx context.Context, userID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledMessage", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelScheduledMessage indicates an expected call of CancelScheduledMessage.
+func (mr *MockChatServiceMockRecorder) CancelScheduledMessage(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledMessage", reflect.TypeOf((*MockChatService)(nil).CancelScheduledMessage), ctx, userID, id)
+}
+
+// CreateIncomingWebhook mocks base method.
+func (m *MockChatService) CreateIncomingWebhook(ctx context.Context, chatID, userID int, name string) (domain.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncomingWebhook", ctx, chatID, userID, name)
+	ret0, _ := ret[0].(domain.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIncomingWebhook indicates an expected call of CreateIncomingWebhook.
+func (mr *MockChatServiceMockRecorder) CreateIncomingWebhook(ctx, chatID, userID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncomingWebhook", reflect.TypeOf((*MockChatService)(nil).CreateIncomingWebhook), ctx, chatID, userID, name)
+}
+
+// DeleteIncomingWebhook mocks base method.
+func (m *MockChatService) DeleteIncomingWebhook(ctx context.Context, chatID, userID, webhookID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIncomingWebhook", ctx, chatID, userID, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIncomingWebhook indicates an expected call of DeleteIncomingWebhook.
+func (mr *MockChatServiceMockRecorder) DeleteIncomingWebhook(ctx, chatID, userID, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncomingWebhook", reflect.TypeOf((*MockChatService)(nil).DeleteIncomingWebhook), ctx, chatID, userID, webhookID)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockChatService) DeleteMessage(ctx context.Context, chatID, userID, messageID int, forEveryone bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, chatID, userID, messageID, forEveryone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockChatServiceMockRecorder) DeleteMessage(ctx, chatID, userID, messageID, forEveryone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockChatService)(nil).DeleteMessage), ctx, chatID, userID, messageID, forEveryone)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockChatService) DeleteWebhook(ctx context.Context, bot domain.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, bot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockChatServiceMockRecorder) DeleteWebhook(ctx, bot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockChatService)(nil).DeleteWebhook), ctx, bot)
+}
+
+// EditMessage mocks base method.
+func (m *MockChatService) EditMessage(ctx context.Context, chatID, userID, messageID int, edit domain.TextEdit) (domain.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditMessage", ctx, chatID, userID, messageID, edit)
+	ret0, _ := ret[0].(domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditMessage indicates an expected call of EditMessage.
+func (mr *MockChatServiceMockRecorder) EditMessage(ctx, chatID, userID, messageID, edit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditMessage", reflect.TypeOf((*MockChatService)(nil).EditMessage), ctx, chatID, userID, messageID, edit)
+}
+
+// EditScheduledMessage mocks base method.
+func (m *MockChatService) EditScheduledMessage(ctx context.Context, userID, id int, edit domain.ScheduledEdit) (domain.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditScheduledMessage", ctx, userID, id, edit)
+	ret0, _ := ret[0].(domain.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditScheduledMessage indicates an expected call of EditScheduledMessage.
+func (mr *MockChatServiceMockRecorder) EditScheduledMessage(ctx, userID, id, edit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditScheduledMessage", reflect.TypeOf((*MockChatService)(nil).EditScheduledMessage), ctx, userID, id, edit)
+}
+
+// ForwardMessage mocks base method.
+func (m *MockChatService) ForwardMessage(ctx context.Context, fromChatID, messageID, toChatID, userID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForwardMessage", ctx, fromChatID, messageID, toChatID, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForwardMessage indicates an expected call of ForwardMessage.
+func (mr *MockChatServiceMockRecorder) ForwardMessage(ctx, fromChatID, messageID, toChatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForwardMessage", reflect.TypeOf((*MockChatService)(nil).ForwardMessage), ctx, fromChatID, messageID, toChatID, userID)
+}
+
+// GetAttachment mocks base method.
+func (m *MockChatService) GetAttachment(ctx context.Context, chatID, userID, attachmentID int, thumbnail bool) (domain.AttachmentContent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, chatID, userID, attachmentID, thumbnail)
+	ret0, _ := ret[0].(domain.AttachmentContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockChatServiceMockRecorder) GetAttachment(ctx, chatID, userID, attachmentID, thumbnail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockChatService)(nil).GetAttachment), ctx, chatID, userID, attachmentID, thumbnail)
+}
+
+// GetAuditLog mocks base method.
+func (m *MockChatService) GetAuditLog(ctx context.Context, admin domain.Identity, limit, offset int) ([]domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", ctx, admin, limit, offset)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockChatServiceMockRecorder) GetAuditLog(ctx, admin, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockChatService)(nil).GetAuditLog), ctx, admin, limit, offset)
+}
+
+// GetBlockedUsers mocks base method.
+func (m *MockChatService) GetBlockedUsers(ctx context.Context, userID int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockedUsers", ctx, userID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockedUsers indicates an expected call of GetBlockedUsers.
+func (mr *MockChatServiceMockRecorder) GetBlockedUsers(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockedUsers", reflect.TypeOf((*MockChatService)(nil).GetBlockedUsers), ctx, userID)
+}
+
+// GetChatRetention mocks base method.
+func (m *MockChatService) GetChatRetention(ctx context.Context, chatID, userID int) (domain.ChatRetention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatRetention", ctx, chatID, userID)
+	ret0, _ := ret[0].(domain.ChatRetention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatRetention indicates an expected call of GetChatRetention.
+func (mr *MockChatServiceMockRecorder) GetChatRetention(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatRetention", reflect.TypeOf((*MockChatService)(nil).GetChatRetention), ctx, chatID, userID)
+}
+
+// GetChatSettings mocks base method.
+func (m *MockChatService) GetChatSettings(ctx context.Context, chatID, userID int) (domain.ChatSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatSettings", ctx, chatID, userID)
+	ret0, _ := ret[0].(domain.ChatSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatSettings indicates an expected call of GetChatSettings.
+func (mr *MockChatServiceMockRecorder) GetChatSettings(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatSettings", reflect.TypeOf((*MockChatService)(nil).GetChatSettings), ctx, chatID, userID)
+}
+
+// GetDrafts mocks base method.
+func (m *MockChatService) GetDrafts(ctx context.Context, userID int) ([]domain.Draft, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDrafts", ctx, userID)
+	ret0, _ := ret[0].([]domain.Draft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDrafts indicates an expected call of GetDrafts.
+func (mr *MockChatServiceMockRecorder) GetDrafts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDrafts", reflect.TypeOf((*MockChatService)(nil).GetDrafts), ctx, userID)
+}
+
+// GetExport mocks base method.
+func (m *MockChatService) GetExport(ctx context.Context, userID int, exportID string) (domain.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExport", ctx, userID, exportID)
+	ret0, _ := ret[0].(domain.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExport indicates an expected call of GetExport.
+func (mr *MockChatServiceMockRecorder) GetExport(ctx, userID, exportID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExport", reflect.TypeOf((*MockChatService)(nil).GetExport), ctx, userID, exportID)
+}
+
+// GetExportArchive mocks base method.
+func (m *MockChatService) GetExportArchive(ctx context.Context, userID int, exportID string) (domain.AttachmentContent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportArchive", ctx, userID, exportID)
+	ret0, _ := ret[0].(domain.AttachmentContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportArchive indicates an expected call of GetExportArchive.
+func (mr *MockChatServiceMockRecorder) GetExportArchive(ctx, userID, exportID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportArchive", reflect.TypeOf((*MockChatService)(nil).GetExportArchive), ctx, userID, exportID)
+}
+
+// GetMessageHistory mocks base method.
+func (m *MockChatService) GetMessageHistory(ctx context.Context, chatID, userID, messageID int) ([]domain.MessageEdit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessageHistory", ctx, chatID, userID, messageID)
+	ret0, _ := ret[0].([]domain.MessageEdit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessageHistory indicates an expected call of GetMessageHistory.
+func (mr *MockChatServiceMockRecorder) GetMessageHistory(ctx, chatID, userID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageHistory", reflect.TypeOf((*MockChatService)(nil).GetMessageHistory), ctx, chatID, userID, messageID)
+}
+
 // GetMessages mocks base method.
-func (m *MockChatService) GetMessages(ctx context.Context, chatID, limit, offset int) ([]domain.Message, error) {
+func (m *MockChatService) GetMessages(ctx context.Context, chatID, userID, limit, offset int) ([]domain.Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMessages", ctx, chatID, limit, offset)
+	ret := m.ctrl.Call(m, "GetMessages", ctx, chatID, userID, limit, offset)
 	ret0, _ := ret[0].([]domain.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMessages indicates an expected call of GetMessages.
-func (mr *MockChatServiceMockRecorder) GetMessages(ctx, chatID, limit, offset interface{}) *gomock.Call {
+func (mr *MockChatServiceMockRecorder) GetMessages(ctx, chatID, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockChatService)(nil).GetMessages), ctx, chatID, userID, limit, offset)
+}
+
+// GetPinnedMessages mocks base method.
+func (m *MockChatService) GetPinnedMessages(ctx context.Context, chatID, userID int) ([]domain.PinnedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPinnedMessages", ctx, chatID, userID)
+	ret0, _ := ret[0].([]domain.PinnedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPinnedMessages indicates an expected call of GetPinnedMessages.
+func (mr *MockChatServiceMockRecorder) GetPinnedMessages(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPinnedMessages", reflect.TypeOf((*MockChatService)(nil).GetPinnedMessages), ctx, chatID, userID)
+}
+
+// GetReportedMessages mocks base method.
+func (m *MockChatService) GetReportedMessages(ctx context.Context, admin domain.Identity, status string, limit, offset int) ([]domain.ReportedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportedMessages", ctx, admin, status, limit, offset)
+	ret0, _ := ret[0].([]domain.ReportedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportedMessages indicates an expected call of GetReportedMessages.
+func (mr *MockChatServiceMockRecorder) GetReportedMessages(ctx, admin, status, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportedMessages", reflect.TypeOf((*MockChatService)(nil).GetReportedMessages), ctx, admin, status, limit, offset)
+}
+
+// GetSyncCursor mocks base method.
+func (m *MockChatService) GetSyncCursor(ctx context.Context, userID int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncCursor", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncCursor indicates an expected call of GetSyncCursor.
+func (mr *MockChatServiceMockRecorder) GetSyncCursor(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockChatService)(nil).GetMessages), ctx, chatID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncCursor", reflect.TypeOf((*MockChatService)(nil).GetSyncCursor), ctx, userID)
+}
+
+// GetUnreadCounts mocks base method.
+func (m *MockChatService) GetUnreadCounts(ctx context.Context, userID int) ([]domain.UnreadCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadCounts", ctx, userID)
+	ret0, _ := ret[0].([]domain.UnreadCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadCounts indicates an expected call of GetUnreadCounts.
+func (mr *MockChatServiceMockRecorder) GetUnreadCounts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadCounts", reflect.TypeOf((*MockChatService)(nil).GetUnreadCounts), ctx, userID)
 }
 
 // GetUserChats mocks base method.
@@ -65,19 +435,303 @@ func (mr *MockChatServiceMockRecorder) GetUserChats(ctx, userID interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserChats", reflect.TypeOf((*MockChatService)(nil).GetUserChats), ctx, userID)
 }
 
+// GetVAPIDPublicKey mocks base method.
+func (m *MockChatService) GetVAPIDPublicKey() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVAPIDPublicKey")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVAPIDPublicKey indicates an expected call of GetVAPIDPublicKey.
+func (mr *MockChatServiceMockRecorder) GetVAPIDPublicKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVAPIDPublicKey", reflect.TypeOf((*MockChatService)(nil).GetVAPIDPublicKey))
+}
+
+// GetWebhook mocks base method.
+func (m *MockChatService) GetWebhook(ctx context.Context, bot domain.Identity) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, bot)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockChatServiceMockRecorder) GetWebhook(ctx, bot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockChatService)(nil).GetWebhook), ctx, bot)
+}
+
+// ListChats mocks base method.
+func (m *MockChatService) ListChats(ctx context.Context, admin domain.Identity, q domain.ChatQuery) ([]domain.ChatInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", ctx, admin, q)
+	ret0, _ := ret[0].([]domain.ChatInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockChatServiceMockRecorder) ListChats(ctx, admin, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockChatService)(nil).ListChats), ctx, admin, q)
+}
+
+// ListExports mocks base method.
+func (m *MockChatService) ListExports(ctx context.Context, userID int) ([]domain.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExports", ctx, userID)
+	ret0, _ := ret[0].([]domain.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExports indicates an expected call of ListExports.
+func (mr *MockChatServiceMockRecorder) ListExports(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExports", reflect.TypeOf((*MockChatService)(nil).ListExports), ctx, userID)
+}
+
+// ListIncomingWebhooks mocks base method.
+func (m *MockChatService) ListIncomingWebhooks(ctx context.Context, chatID, userID int) ([]domain.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomingWebhooks", ctx, chatID, userID)
+	ret0, _ := ret[0].([]domain.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomingWebhooks indicates an expected call of ListIncomingWebhooks.
+func (mr *MockChatServiceMockRecorder) ListIncomingWebhooks(ctx, chatID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomingWebhooks", reflect.TypeOf((*MockChatService)(nil).ListIncomingWebhooks), ctx, chatID, userID)
+}
+
+// ListScheduledMessages mocks base method.
+func (m *MockChatService) ListScheduledMessages(ctx context.Context, userID, chatID int) ([]domain.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledMessages", ctx, userID, chatID)
+	ret0, _ := ret[0].([]domain.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledMessages indicates an expected call of ListScheduledMessages.
+func (mr *MockChatServiceMockRecorder) ListScheduledMessages(ctx, userID, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledMessages", reflect.TypeOf((*MockChatService)(nil).ListScheduledMessages), ctx, userID, chatID)
+}
+
+// ListUsers mocks base method.
+func (m *MockChatService) ListUsers(ctx context.Context, admin domain.Identity, q domain.UserQuery) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, admin, q)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockChatServiceMockRecorder) ListUsers(ctx, admin, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockChatService)(nil).ListUsers), ctx, admin, q)
+}
+
+// MarkRead mocks base method.
+func (m *MockChatService) MarkRead(ctx context.Context, chatID, userID, messageID int) (domain.ReadReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, chatID, userID, messageID)
+	ret0, _ := ret[0].(domain.ReadReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockChatServiceMockRecorder) MarkRead(ctx, chatID, userID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockChatService)(nil).MarkRead), ctx, chatID, userID, messageID)
+}
+
+// PinMessage mocks base method.
+func (m *MockChatService) PinMessage(ctx context.Context, chatID, userID, messageID int) (domain.PinnedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinMessage", ctx, chatID, userID, messageID)
+	ret0, _ := ret[0].(domain.PinnedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PinMessage indicates an expected call of PinMessage.
+func (mr *MockChatServiceMockRecorder) PinMessage(ctx, chatID, userID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinMessage", reflect.TypeOf((*MockChatService)(nil).PinMessage), ctx, chatID, userID, messageID)
+}
+
+// PostIncomingWebhook mocks base method.
+func (m *MockChatService) PostIncomingWebhook(ctx context.Context, token string, post domain.IncomingWebhookPost) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostIncomingWebhook", ctx, token, post)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostIncomingWebhook indicates an expected call of PostIncomingWebhook.
+func (mr *MockChatServiceMockRecorder) PostIncomingWebhook(ctx, token, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostIncomingWebhook", reflect.TypeOf((*MockChatService)(nil).PostIncomingWebhook), ctx, token, post)
+}
+
 // PostMessage mocks base method.
-func (m *MockChatService) PostMessage(ctx context.Context, chatID, userID int, text string) (int, error) {
+func (m *MockChatService) PostMessage(ctx context.Context, msg domain.NewMessage) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PostMessage", ctx, chatID, userID, text)
+	ret := m.ctrl.Call(m, "PostMessage", ctx, msg)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // PostMessage indicates an expected call of PostMessage.
-func (mr *MockChatServiceMockRecorder) PostMessage(ctx, chatID, userID, text interface{}) *gomock.Call {
+func (mr *MockChatServiceMockRecorder) PostMessage(ctx, msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockChatService)(nil).PostMessage), ctx, msg)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockChatService) RemoveReaction(ctx context.Context, chatID, userID, messageID int, emoji string) ([]domain.ReactionCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, chatID, userID, messageID, emoji)
+	ret0, _ := ret[0].([]domain.ReactionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockChatServiceMockRecorder) RemoveReaction(ctx, chatID, userID, messageID, emoji interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockChatService)(nil).RemoveReaction), ctx, chatID, userID, messageID, emoji)
+}
+
+// ReportMessage mocks base method.
+func (m *MockChatService) ReportMessage(ctx context.Context, report domain.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportMessage", ctx, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportMessage indicates an expected call of ReportMessage.
+func (mr *MockChatServiceMockRecorder) ReportMessage(ctx, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportMessage", reflect.TypeOf((*MockChatService)(nil).ReportMessage), ctx, report)
+}
+
+// RequestExport mocks base method.
+func (m *MockChatService) RequestExport(ctx context.Context, userID int, token string) (domain.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestExport", ctx, userID, token)
+	ret0, _ := ret[0].(domain.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestExport indicates an expected call of RequestExport.
+func (mr *MockChatServiceMockRecorder) RequestExport(ctx, userID, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestExport", reflect.TypeOf((*MockChatService)(nil).RequestExport), ctx, userID, token)
+}
+
+// ResolveReport mocks base method.
+func (m *MockChatService) ResolveReport(ctx context.Context, admin domain.Identity, messageID int, action, reason string) (domain.ReportedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReport", ctx, admin, messageID, action, reason)
+	ret0, _ := ret[0].(domain.ReportedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReport indicates an expected call of ResolveReport.
+func (mr *MockChatServiceMockRecorder) ResolveReport(ctx, admin, messageID, action, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReport", reflect.TypeOf((*MockChatService)(nil).ResolveReport), ctx, admin, messageID, action, reason)
+}
+
+// SaveDraft mocks base method.
+func (m *MockChatService) SaveDraft(ctx context.Context, userID int, draft domain.Draft, format string) (domain.Draft, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDraft", ctx, userID, draft, format)
+	ret0, _ := ret[0].(domain.Draft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDraft indicates an expected call of SaveDraft.
+func (mr *MockChatServiceMockRecorder) SaveDraft(ctx, userID, draft, format interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockChatService)(nil).PostMessage), ctx, chatID, userID, text)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDraft", reflect.TypeOf((*MockChatService)(nil).SaveDraft), ctx, userID, draft, format)
+}
+
+// ScheduleMessage mocks base method.
+func (m *MockChatService) ScheduleMessage(ctx context.Context, msg domain.NewMessage, sendAt time.Time) (domain.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleMessage", ctx, msg, sendAt)
+	ret0, _ := ret[0].(domain.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleMessage indicates an expected call of ScheduleMessage.
+func (mr *MockChatServiceMockRecorder) ScheduleMessage(ctx, msg, sendAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleMessage", reflect.TypeOf((*MockChatService)(nil).ScheduleMessage), ctx, msg, sendAt)
+}
+
+// SearchMessages mocks base method.
+func (m *MockChatService) SearchMessages(ctx context.Context, userID, chatID int, text, cursor string, limit int) (domain.SearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchMessages", ctx, userID, chatID, text, cursor, limit)
+	ret0, _ := ret[0].(domain.SearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchMessages indicates an expected call of SearchMessages.
+func (mr *MockChatServiceMockRecorder) SearchMessages(ctx, userID, chatID, text, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMessages", reflect.TypeOf((*MockChatService)(nil).SearchMessages), ctx, userID, chatID, text, cursor, limit)
+}
+
+// SetChatRetention mocks base method.
+func (m *MockChatService) SetChatRetention(ctx context.Context, chatID, userID int, ttl time.Duration) (domain.ChatRetention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChatRetention", ctx, chatID, userID, ttl)
+	ret0, _ := ret[0].(domain.ChatRetention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetChatRetention indicates an expected call of SetChatRetention.
+func (mr *MockChatServiceMockRecorder) SetChatRetention(ctx, chatID, userID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChatRetention", reflect.TypeOf((*MockChatService)(nil).SetChatRetention), ctx, chatID, userID, ttl)
+}
+
+// SetWebhook mocks base method.
+func (m *MockChatService) SetWebhook(ctx context.Context, bot domain.Identity, rawURL string) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWebhook", ctx, bot, rawURL)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWebhook indicates an expected call of SetWebhook.
+func (mr *MockChatServiceMockRecorder) SetWebhook(ctx, bot, rawURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWebhook", reflect.TypeOf((*MockChatService)(nil).SetWebhook), ctx, bot, rawURL)
 }
 
 // StartChat mocks base method.
@@ -94,3 +748,148 @@ func (mr *MockChatServiceMockRecorder) StartChat(ctx, userID1, userID2 interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartChat", reflect.TypeOf((*MockChatService)(nil).StartChat), ctx, userID1, userID2)
 }
+
+// SubscribePush mocks base method.
+func (m *MockChatService) SubscribePush(ctx context.Context, sub domain.PushSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribePush", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribePush indicates an expected call of SubscribePush.
+func (mr *MockChatServiceMockRecorder) SubscribePush(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribePush", reflect.TypeOf((*MockChatService)(nil).SubscribePush), ctx, sub)
+}
+
+// SubscribeUser mocks base method.
+func (m *MockChatService) SubscribeUser(ctx context.Context, userID int) <-chan domain.Event {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeUser", ctx, userID)
+	ret0, _ := ret[0].(<-chan domain.Event)
+	return ret0
+}
+
+// SubscribeUser indicates an expected call of SubscribeUser.
+func (mr *MockChatServiceMockRecorder) SubscribeUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeUser", reflect.TypeOf((*MockChatService)(nil).SubscribeUser), ctx, userID)
+}
+
+// SuspendUser mocks base method.
+func (m *MockChatService) SuspendUser(ctx context.Context, admin domain.Identity, suspension domain.Suspension) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuspendUser", ctx, admin, suspension)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuspendUser indicates an expected call of SuspendUser.
+func (mr *MockChatServiceMockRecorder) SuspendUser(ctx, admin, suspension interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuspendUser", reflect.TypeOf((*MockChatService)(nil).SuspendUser), ctx, admin, suspension)
+}
+
+// SyncChanges mocks base method.
+func (m *MockChatService) SyncChanges(ctx context.Context, userID int, since int64, limit int) (domain.SyncBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncChanges", ctx, userID, since, limit)
+	ret0, _ := ret[0].(domain.SyncBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncChanges indicates an expected call of SyncChanges.
+func (mr *MockChatServiceMockRecorder) SyncChanges(ctx, userID, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncChanges", reflect.TypeOf((*MockChatService)(nil).SyncChanges), ctx, userID, since, limit)
+}
+
+// UnblockUser mocks base method.
+func (m *MockChatService) UnblockUser(ctx context.Context, userID, blockedUserID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockUser", ctx, userID, blockedUserID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockChatServiceMockRecorder) UnblockUser(ctx, userID, blockedUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockChatService)(nil).UnblockUser), ctx, userID, blockedUserID)
+}
+
+// UnpinMessage mocks base method.
+func (m *MockChatService) UnpinMessage(ctx context.Context, chatID, userID, messageID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinMessage", ctx, chatID, userID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinMessage indicates an expected call of UnpinMessage.
+func (mr *MockChatServiceMockRecorder) UnpinMessage(ctx, chatID, userID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinMessage", reflect.TypeOf((*MockChatService)(nil).UnpinMessage), ctx, chatID, userID, messageID)
+}
+
+// UnsubscribePush mocks base method.
+func (m *MockChatService) UnsubscribePush(ctx context.Context, userID int, endpoint string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribePush", ctx, userID, endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribePush indicates an expected call of UnsubscribePush.
+func (mr *MockChatServiceMockRecorder) UnsubscribePush(ctx, userID, endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribePush", reflect.TypeOf((*MockChatService)(nil).UnsubscribePush), ctx, userID, endpoint)
+}
+
+// UnsuspendUser mocks base method.
+func (m *MockChatService) UnsuspendUser(ctx context.Context, admin domain.Identity, userID int) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsuspendUser", ctx, admin, userID)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnsuspendUser indicates an expected call of UnsuspendUser.
+func (mr *MockChatServiceMockRecorder) UnsuspendUser(ctx, admin, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsuspendUser", reflect.TypeOf((*MockChatService)(nil).UnsuspendUser), ctx, admin, userID)
+}
+
+// UpdateChatSettings mocks base method.
+func (m *MockChatService) UpdateChatSettings(ctx context.Context, chatID, userID int, upd domain.ChatSettingsUpdate) (domain.ChatSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChatSettings", ctx, chatID, userID, upd)
+	ret0, _ := ret[0].(domain.ChatSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChatSettings indicates an expected call of UpdateChatSettings.
+func (mr *MockChatServiceMockRecorder) UpdateChatSettings(ctx, chatID, userID, upd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChatSettings", reflect.TypeOf((*MockChatService)(nil).UpdateChatSettings), ctx, chatID, userID, upd)
+}
+
+// UploadAttachment mocks base method.
+func (m *MockChatService) UploadAttachment(ctx context.Context, chatID, userID int, fileName string, r io.Reader) (domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadAttachment", ctx, chatID, userID, fileName, r)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadAttachment indicates an expected call of UploadAttachment.
+func (mr *MockChatServiceMockRecorder) UploadAttachment(ctx, chatID, userID, fileName, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadAttachment", reflect.TypeOf((*MockChatService)(nil).UploadAttachment), ctx, chatID, userID, fileName, r)
+}
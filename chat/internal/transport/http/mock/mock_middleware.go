@@ -5,6 +5,7 @@
 package mock
 
 import (
+	domain "chat/internal/domain"
 	context "context"
 	reflect "reflect"
 
@@ -34,17 +35,17 @@ func (m *MockAuther) EXPECT() *MockAutherMockRecorder {
 	return m.recorder
 }
 
-// GetId mocks base method.
-func (m *MockAuther) GetId(ctx context.Context, token string) (int, error) {
+// Authenticate mocks base method.
+func (m *MockAuther) Authenticate(ctx context.Context, token string) (domain.Identity, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetId", ctx, token)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(domain.Identity)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetId indicates an expected call of GetId.
-func (mr *MockAutherMockRecorder) GetId(ctx, token interface{}) *gomock.Call {
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAutherMockRecorder) Authenticate(ctx, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetId", reflect.TypeOf((*MockAuther)(nil).GetId), ctx, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuther)(nil).Authenticate), ctx, token)
 }
@@ -0,0 +1,123 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func TestHandler_PinsAndDrafts(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	updatedAt := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	type mockBehavior func(userId int)
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         string
+		mockBehavior mockBehavior
+		wantStatus   int
+	}{
+		{
+			name:   "pin",
+			method: "POST",
+			url:    "/chat/5/messages/10/pin",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().PinMessage(gomock.Any(), 5, userId, 10).Return(domain.PinnedMessage{ChatId: 5, MessageId: 10, PinnedBy: userId}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "pin over limit",
+			method: "POST",
+			url:    "/chat/5/messages/11/pin",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().PinMessage(gomock.Any(), 5, userId, 11).Return(domain.PinnedMessage{}, domain.ErrTooManyPinned)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "pin deleted",
+			method: "POST",
+			url:    "/chat/5/messages/12/pin",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().PinMessage(gomock.Any(), 5, userId, 12).Return(domain.PinnedMessage{}, domain.ErrMessageDeleted)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "unpin",
+			method: "DELETE",
+			url:    "/chat/5/messages/10/pin",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().UnpinMessage(gomock.Any(), 5, userId, 10).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "list pinned not member",
+			method: "GET",
+			url:    "/chat/6/pinned",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().GetPinnedMessages(gomock.Any(), 6, userId).Return(nil, domain.ErrNotChatMember)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "save draft",
+			method: "PUT",
+			url:    "/chat/5/draft",
+			body:   `{"text": "**не** забыть", "format": "markdown", "updated_at": "2030-01-02T10:00:00Z"}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().SaveDraft(gomock.Any(), userId, domain.Draft{ChatId: 5, Text: "**не** забыть", UpdatedAt: updatedAt}, domain.FormatMarkdown).
+					Return(domain.Draft{ChatId: 5, Text: "не забыть", UpdatedAt: updatedAt}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "save draft invalid body",
+			method:       "PUT",
+			url:          "/chat/5/draft",
+			body:         `{"text": 1}`,
+			mockBehavior: func(userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/pinned", h.GetPinnedMessagesHandler()).Methods("GET")
+			router.Handle("/chat/{chat_id:[0-9]+}/draft", h.SaveDraftHandler()).Methods("PUT")
+			router.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/pin", h.PinMessageHandler()).Methods("POST")
+			router.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/pin", h.UnpinMessageHandler()).Methods("DELETE")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("PinsHandler status got %v, want %v: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GetVAPIDKeyHandler отдает applicationServerKey для подписки браузера на push-уведомления
+func (h *Handler) GetVAPIDKeyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, err := h.srv.GetVAPIDPublicKey()
+		if err != nil {
+			writeServiceError(w, "Failed to get VAPID key: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(VAPIDKeyResponse{PublicKey: key}); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+func (h *Handler) SubscribePushHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req PushSubscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		sub := domain.PushSubscription{
+			UserId:    userId,
+			Endpoint:  req.Endpoint,
+			P256dh:    req.Keys.P256dh,
+			Auth:      req.Keys.Auth,
+			UserAgent: r.UserAgent(),
+		}
+		if req.ExpirationTime != nil {
+			expiresAt := time.UnixMilli(*req.ExpirationTime)
+			sub.ExpiresAt = &expiresAt
+		}
+
+		if err := h.srv.SubscribePush(r.Context(), sub); err != nil {
+			writeServiceError(w, "Failed to subscribe: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (h *Handler) UnsubscribePushHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req PushUnsubscribeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.srv.UnsubscribePush(r.Context(), userId, req.Endpoint); err != nil {
+			writeServiceError(w, "Failed to unsubscribe: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
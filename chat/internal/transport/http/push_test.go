@@ -0,0 +1,138 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_PushHandlers(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	expiresAt := time.UnixMilli(1767225600000)
+
+	type mockBehavior func(userId int)
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         string
+		mockBehavior mockBehavior
+		resp         *VAPIDKeyResponse
+		wantStatus   int
+	}{
+		{
+			name:   "vapid key",
+			method: "GET",
+			url:    "/chat/push/vapid-key",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().GetVAPIDPublicKey().Return("BPublicKey", nil)
+			},
+			resp:       &VAPIDKeyResponse{PublicKey: "BPublicKey"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "push disabled",
+			method: "GET",
+			url:    "/chat/push/vapid-key",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().GetVAPIDPublicKey().Return("", domain.ErrPushDisabled)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "subscribe",
+			method: "POST",
+			url:    "/chat/push/subscriptions",
+			body:   `{"endpoint": "https://push.example.com/1", "expirationTime": 1767225600000, "keys": {"p256dh": "key", "auth": "secret"}}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().SubscribePush(gomock.Any(), domain.PushSubscription{
+					UserId:    userId,
+					Endpoint:  "https://push.example.com/1",
+					P256dh:    "key",
+					Auth:      "secret",
+					UserAgent: "test-browser",
+					ExpiresAt: &expiresAt,
+				}).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "invalid subscription",
+			method: "POST",
+			url:    "/chat/push/subscriptions",
+			body:   `{"endpoint": "http://push.example.com/1", "expirationTime": null, "keys": {"p256dh": "key", "auth": "secret"}}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().SubscribePush(gomock.Any(), gomock.Any()).Return(domain.ErrInvalidPushSubscription)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "subscribe invalid body",
+			method:       "POST",
+			url:          "/chat/push/subscriptions",
+			body:         `{"endpoint": 1}`,
+			mockBehavior: func(userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:   "unsubscribe",
+			method: "DELETE",
+			url:    "/chat/push/subscriptions",
+			body:   `{"endpoint": "https://push.example.com/1"}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().UnsubscribePush(gomock.Any(), userId, "https://push.example.com/1").Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/push/vapid-key", h.GetVAPIDKeyHandler()).Methods("GET")
+			router.Handle("/chat/push/subscriptions", h.SubscribePushHandler()).Methods("POST")
+			router.Handle("/chat/push/subscriptions", h.UnsubscribePushHandler()).Methods("DELETE")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			req.Header.Set("User-Agent", "test-browser")
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("PushHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.resp == nil {
+				return
+			}
+
+			var resp VAPIDKeyResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("PushHandler response got error %v", err)
+			}
+
+			assert.Equal(t, *tt.resp, resp)
+		})
+	}
+}
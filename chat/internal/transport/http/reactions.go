@@ -0,0 +1,69 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) AddReactionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ReactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		h.changeReaction(w, r, req.Emoji, h.srv.AddReaction)
+	})
+}
+
+// RemoveReactionHandler снимает реакцию, эмодзи передается в query-параметре emoji
+func (h *Handler) RemoveReactionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.changeReaction(w, r, r.URL.Query().Get("emoji"), h.srv.RemoveReaction)
+	})
+}
+
+type reactionFunc func(ctx context.Context, chatID, userID, messageID int, emoji string) ([]domain.ReactionCount, error)
+
+func (h *Handler) changeReaction(w http.ResponseWriter, r *http.Request, emoji string, change reactionFunc) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.Atoi(vars["chat_id"])
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+	messageID, err := strconv.Atoi(vars["message_id"])
+	if err != nil {
+		http.Error(w, "Invalid message ID", http.StatusBadRequest)
+		return
+	}
+
+	sUserId := r.Context().Value(UserIdKey)
+	userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	reactions, err := change(r.Context(), chatID, userId, messageID, emoji)
+	if err != nil {
+		writeServiceError(w, "Failed to change reaction: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(ReactionsResponse{MessageID: messageID, Reactions: reactions}); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
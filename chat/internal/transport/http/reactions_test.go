@@ -0,0 +1,127 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ReactionHandlers(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(chatId, userId, messageId int, emoji string)
+
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		query        string
+		emoji        string
+		mockBehavior mockBehavior
+		resp         ReactionsResponse
+		wantStatus   int
+	}{
+		{
+			name:   "add",
+			method: "POST",
+			body:   `{"emoji": "👍"}`,
+			emoji:  "👍",
+			mockBehavior: func(chatId, userId, messageId int, emoji string) {
+				cs.EXPECT().
+					AddReaction(gomock.Any(), chatId, userId, messageId, emoji).
+					Return([]domain.ReactionCount{{Emoji: emoji, Count: 1, ReactedByMe: true}}, nil)
+			},
+			resp: ReactionsResponse{
+				MessageID: 2,
+				Reactions: []domain.ReactionCount{{Emoji: "👍", Count: 1, ReactedByMe: true}},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "add invalid emoji",
+			method: "POST",
+			body:   `{"emoji": "like"}`,
+			emoji:  "like",
+			mockBehavior: func(chatId, userId, messageId int, emoji string) {
+				cs.EXPECT().
+					AddReaction(gomock.Any(), chatId, userId, messageId, emoji).
+					Return(nil, domain.ErrInvalidEmoji)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "remove",
+			method: "DELETE",
+			query:  "?emoji=" + url.QueryEscape("👍"),
+			emoji:  "👍",
+			mockBehavior: func(chatId, userId, messageId int, emoji string) {
+				cs.EXPECT().
+					RemoveReaction(gomock.Any(), chatId, userId, messageId, emoji).
+					Return([]domain.ReactionCount{}, nil)
+			},
+			resp: ReactionsResponse{
+				MessageID: 2,
+				Reactions: []domain.ReactionCount{},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "remove not member",
+			method: "DELETE",
+			query:  "?emoji=" + url.QueryEscape("👍"),
+			emoji:  "👍",
+			mockBehavior: func(chatId, userId, messageId int, emoji string) {
+				cs.EXPECT().
+					RemoveReaction(gomock.Any(), chatId, userId, messageId, emoji).
+					Return(nil, domain.ErrNotChatMember)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 1, 2, tt.emoji)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/reactions", h.AddReactionHandler()).Methods("POST")
+			router.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/reactions", h.RemoveReactionHandler()).Methods("DELETE")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest(tt.method, "/chat/1/messages/2/reactions"+tt.query, bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("ReactionHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp ReactionsResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("ReactionHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
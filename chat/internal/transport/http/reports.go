@@ -0,0 +1,56 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) ReportMessageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		chatID, err := strconv.Atoi(vars["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+		messageID, err := strconv.Atoi(vars["message_id"])
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req ReportMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		report := domain.Report{
+			ChatId:     chatID,
+			MessageId:  messageID,
+			ReporterId: userId,
+			Reason:     req.Reason,
+			Comment:    req.Comment,
+		}
+		if err := h.srv.ReportMessage(r.Context(), report); err != nil {
+			writeServiceError(w, "Failed to report message: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,88 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ReportMessageHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := mux.NewRouter()
+	router.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/report", NewHandler(cs).ReportMessageHandler()).Methods("POST")
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/chat/1/messages/10/report", bytes.NewBufferString(body))
+		ctx := context.WithValue(req.Context(), UserIdKey, 2)
+		ctx = logger.InitFromCtx(ctx, logger.New())
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{"ok", `{"reason":"spam","comment":"реклама"}`, nil, http.StatusNoContent},
+		{"duplicate", `{"reason":"spam"}`, domain.ErrAlreadyReported, http.StatusConflict},
+		{"invalid reason", `{"reason":"boring"}`, domain.ErrInvalidReport, http.StatusBadRequest},
+		{"not a member", `{"reason":"spam"}`, domain.ErrNotChatMember, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs.EXPECT().ReportMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r domain.Report) error {
+				assert.Equal(t, 1, r.ChatId)
+				assert.Equal(t, 10, r.MessageId)
+				assert.Equal(t, 2, r.ReporterId)
+				return tt.err
+			})
+
+			rr := serve(tt.body)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+
+	t.Run("invalid body", func(t *testing.T) {
+		rr := serve(`{`)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestHandler_AdminResolveReportHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	router := newAdminRouter(NewHandler(cs))
+	router.Handle("/chat/admin/reports/{message_id:[0-9]+}/resolve", NewHandler(cs).AdminResolveReportHandler()).Methods("POST")
+
+	t.Run("ok", func(t *testing.T) {
+		cs.EXPECT().ResolveReport(gomock.Any(), testAdmin, 10, domain.ResolveRemove, "spam").
+			Return(domain.ReportedMessage{MessageId: 10, Status: domain.ModerationRemoved}, nil)
+
+		rr := serveAdmin(router, testAdmin, "POST", "/chat/admin/reports/10/resolve", []byte(`{"action":"remove","reason":"spam"}`))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"status":"removed"`)
+	})
+
+	t.Run("resolved", func(t *testing.T) {
+		cs.EXPECT().ResolveReport(gomock.Any(), testAdmin, 10, domain.ResolveDismiss, "").
+			Return(domain.ReportedMessage{}, domain.ErrReportResolved)
+
+		rr := serveAdmin(router, testAdmin, "POST", "/chat/admin/reports/10/resolve", []byte(`{"action":"dismiss"}`))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
@@ -0,0 +1,83 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) GetChatRetentionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		retention, err := h.srv.GetChatRetention(r.Context(), chatID, userId)
+		if err != nil {
+			writeServiceError(w, "Failed to get chat retention: ", err)
+			return
+		}
+
+		writeChatRetention(w, r, retention)
+	})
+}
+
+func (h *Handler) SetChatRetentionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.Atoi(mux.Vars(r)["chat_id"])
+		if err != nil {
+			http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req ChatRetentionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.TTL == nil || *req.TTL < 0 {
+			http.Error(w, "Invalid ttl", http.StatusBadRequest)
+			return
+		}
+
+		retention, err := h.srv.SetChatRetention(r.Context(), chatID, userId, time.Duration(*req.TTL)*time.Second)
+		if err != nil {
+			writeServiceError(w, "Failed to set chat retention: ", err)
+			return
+		}
+
+		writeChatRetention(w, r, retention)
+	})
+}
+
+func writeChatRetention(w http.ResponseWriter, r *http.Request, retention domain.ChatRetention) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(retention); err != nil {
+		logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
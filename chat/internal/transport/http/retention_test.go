@@ -0,0 +1,115 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_SetChatRetentionHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(chatId, userId int)
+
+	tests := []struct {
+		name         string
+		body         string
+		mockBehavior mockBehavior
+		resp         domain.ChatRetention
+		wantStatus   int
+	}{
+		{
+			name: "a week",
+			body: `{"ttl": 604800}`,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					SetChatRetention(gomock.Any(), chatId, userId, 7*24*time.Hour).
+					Return(domain.ChatRetention{ChatId: chatId, TTL: 604800, UpdatedBy: userId}, nil)
+			},
+			resp:       domain.ChatRetention{ChatId: 1, TTL: 604800, UpdatedBy: 1},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "turn off",
+			body: `{"ttl": 0}`,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					SetChatRetention(gomock.Any(), chatId, userId, time.Duration(0)).
+					Return(domain.ChatRetention{ChatId: chatId, UpdatedBy: userId}, nil)
+			},
+			resp:       domain.ChatRetention{ChatId: 1, UpdatedBy: 1},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "no ttl",
+			body:         `{}`,
+			mockBehavior: func(chatId, userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "invalid ttl",
+			body: `{"ttl": 5}`,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					SetChatRetention(gomock.Any(), chatId, userId, 5*time.Second).
+					Return(domain.ChatRetention{}, domain.ErrInvalidRetention)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not member",
+			body: `{"ttl": 3600}`,
+			mockBehavior: func(chatId, userId int) {
+				cs.EXPECT().
+					SetChatRetention(gomock.Any(), chatId, userId, time.Hour).
+					Return(domain.ChatRetention{}, domain.ErrNotChatMember)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}/retention", h.SetChatRetentionHandler()).Methods("PUT")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("PUT", "/chat/1/retention", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("SetChatRetentionHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp domain.ChatRetention
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("SetChatRetentionHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
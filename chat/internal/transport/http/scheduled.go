@@ -0,0 +1,123 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ListScheduledMessagesHandler отдает неотправленные отложенные сообщения пользователя,
+// параметр chat_id ограничивает список одним чатом
+func (h *Handler) ListScheduledMessagesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var chatID int
+		if s := r.URL.Query().Get("chat_id"); s != "" {
+			chatID, err = strconv.Atoi(s)
+			if err != nil || chatID <= 0 {
+				http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+				return
+			}
+		}
+
+		scheduled, err := h.srv.ListScheduledMessages(r.Context(), userId, chatID)
+		if err != nil {
+			writeServiceError(w, "Failed to list scheduled messages: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ScheduledMessagesResponse(scheduled)); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+// EditScheduledMessageHandler меняет текст или время отправки. Сообщение, не отправленное из-за ошибки,
+// после правки снова ждет отправки
+func (h *Handler) EditScheduledMessageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheduledID, err := strconv.Atoi(mux.Vars(r)["scheduled_id"])
+		if err != nil {
+			http.Error(w, "Invalid scheduled message ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var req EditScheduledMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json decoder", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.Text == nil && req.SendAt == nil {
+			http.Error(w, "Text or send_at is required", http.StatusBadRequest)
+			return
+		}
+		edit := domain.ScheduledEdit{SendAt: req.SendAt}
+		if req.Text != nil {
+			if *req.Text == "" {
+				http.Error(w, "Text is required", http.StatusBadRequest)
+				return
+			}
+			edit.Text = &domain.TextEdit{Text: *req.Text, Format: req.Format, Entities: req.Entities}
+		}
+
+		scheduled, err := h.srv.EditScheduledMessage(r.Context(), userId, scheduledID, edit)
+		if err != nil {
+			writeServiceError(w, "Failed to edit scheduled message: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(scheduled); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+func (h *Handler) CancelScheduledMessageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheduledID, err := strconv.Atoi(mux.Vars(r)["scheduled_id"])
+		if err != nil {
+			http.Error(w, "Invalid scheduled message ID", http.StatusBadRequest)
+			return
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.srv.CancelScheduledMessage(r.Context(), userId, scheduledID); err != nil {
+			writeServiceError(w, "Failed to cancel scheduled message: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,144 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func TestHandler_ScheduledHandlers(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+	sendAt := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	type mockBehavior func(userId int)
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		body         string
+		mockBehavior mockBehavior
+		wantStatus   int
+	}{
+		{
+			name:   "schedule",
+			method: "POST",
+			url:    "/chat/5",
+			body:   `{"text": "утром", "send_at": "2030-01-02T10:00:00Z"}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().ScheduleMessage(gomock.Any(), domain.NewMessage{ChatId: 5, SenderId: userId, Text: "утром"}, sendAt).
+					Return(domain.ScheduledMessage{Id: 1, ChatId: 5, SenderId: userId, Text: "утром", SendAt: sendAt}, nil)
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:   "schedule in the past",
+			method: "POST",
+			url:    "/chat/5",
+			body:   `{"text": "вчера", "send_at": "2030-01-02T10:00:00Z"}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().ScheduleMessage(gomock.Any(), gomock.Any(), sendAt).Return(domain.ScheduledMessage{}, domain.ErrInvalidSendAt)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "list by chat",
+			method: "GET",
+			url:    "/chat/scheduled?chat_id=5",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().ListScheduledMessages(gomock.Any(), userId, 5).Return([]domain.ScheduledMessage{{Id: 1}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "list invalid chat",
+			method:       "GET",
+			url:          "/chat/scheduled?chat_id=x",
+			mockBehavior: func(userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:   "edit send_at",
+			method: "PATCH",
+			url:    "/chat/scheduled/1",
+			body:   `{"send_at": "2030-01-02T10:00:00Z"}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().EditScheduledMessage(gomock.Any(), userId, 1, domain.ScheduledEdit{SendAt: &sendAt}).
+					Return(domain.ScheduledMessage{Id: 1, SendAt: sendAt}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "edit while sending",
+			method: "PATCH",
+			url:    "/chat/scheduled/1",
+			body:   `{"text": "новый"}`,
+			mockBehavior: func(userId int) {
+				cs.EXPECT().EditScheduledMessage(gomock.Any(), userId, 1, domain.ScheduledEdit{Text: &domain.TextEdit{Text: "новый"}}).
+					Return(domain.ScheduledMessage{}, domain.ErrScheduledSending)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:         "edit nothing",
+			method:       "PATCH",
+			url:          "/chat/scheduled/1",
+			body:         `{}`,
+			mockBehavior: func(userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:   "cancel",
+			method: "DELETE",
+			url:    "/chat/scheduled/1",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().CancelScheduledMessage(gomock.Any(), userId, 1).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "cancel unknown",
+			method: "DELETE",
+			url:    "/chat/scheduled/2",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().CancelScheduledMessage(gomock.Any(), userId, 2).Return(domain.ErrScheduledNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/scheduled", h.ListScheduledMessagesHandler()).Methods("GET")
+			router.Handle("/chat/scheduled/{scheduled_id:[0-9]+}", h.EditScheduledMessageHandler()).Methods("PATCH")
+			router.Handle("/chat/scheduled/{scheduled_id:[0-9]+}", h.CancelScheduledMessageHandler()).Methods("DELETE")
+			router.Handle("/chat/{chat_id:[0-9]+}", h.SendMessageHandler()).Methods("POST")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("ScheduledHandler status got %v, want %v: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package httpserver
+
+import (
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// SearchHandler ищет по всем чатам пользователя, а на маршруте с chat_id - только по этому чату
+func (h *Handler) SearchHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chatID := 0
+		if sChatId, ok := mux.Vars(r)["chat_id"]; ok {
+			var err error
+			chatID, err = strconv.Atoi(sChatId)
+			if err != nil {
+				http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+				return
+			}
+		}
+
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		page, err := h.srv.SearchMessages(r.Context(), userId, chatID, query.Get("q"), query.Get("cursor"), limit)
+		if err != nil {
+			writeServiceError(w, "Failed to search messages: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_SearchHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(userId int)
+
+	page := domain.SearchPage{
+		Results:    []domain.SearchResult{{ChatId: 3, MessageId: 4, SenderId: "2", Snippet: "<mark>привет</mark>"}},
+		NextCursor: "abc",
+	}
+
+	tests := []struct {
+		name         string
+		url          string
+		mockBehavior mockBehavior
+		resp         domain.SearchPage
+		wantStatus   int
+	}{
+		{
+			name: "all chats",
+			url:  "/chat/search?q=%D0%BF%D1%80%D0%B8%D0%B2%D0%B5%D1%82&limit=10&cursor=xyz",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().
+					SearchMessages(gomock.Any(), userId, 0, "привет", "xyz", 10).
+					Return(page, nil)
+			},
+			resp:       page,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "one chat",
+			url:  "/chat/3/search?q=hello",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().
+					SearchMessages(gomock.Any(), userId, 3, "hello", "", 0).
+					Return(page, nil)
+			},
+			resp:       page,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "not member",
+			url:  "/chat/3/search?q=hello",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().
+					SearchMessages(gomock.Any(), userId, 3, "hello", "", 0).
+					Return(domain.SearchPage{}, domain.ErrNotChatMember)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "empty query",
+			url:  "/chat/search",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().
+					SearchMessages(gomock.Any(), userId, 0, "", "", 0).
+					Return(domain.SearchPage{}, domain.ErrEmptySearchQuery)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/search", h.SearchHandler()).Methods("GET")
+			router.Handle("/chat/{chat_id:[0-9]+}/search", h.SearchHandler()).Methods("GET")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("SearchHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp domain.SearchPage
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("SearchHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
@@ -34,6 +34,7 @@ func New(ctx context.Context, cfg Config, service Service, auther Auther) *Serve
 		WriteTimeout: cfg.Timeout,
 		IdleTimeout:  cfg.IdleTimeout,
 	}
+	s.httpServer.RegisterOnShutdown(chatHandler.CloseStreams)
 	return s
 }
 
@@ -50,11 +51,76 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
 
-func setupRouter(r *mux.Router, s *Server) {
-	r.Use(InitLoggerCtxMiddleware(s.ctx))
+func setupRouter(root *mux.Router, s *Server) {
+	root.Use(InitLoggerCtxMiddleware(s.ctx))
+	// Адрес входящего вебхука сам содержит секрет, токен пользователя ему не нужен
+	root.Handle("/hooks/{token}", s.Handler.PostIncomingWebhookHandler()).Methods("POST")
+
+	r := root.NewRoute().Subrouter()
 	r.Use(AuthMiddleware(s.Auther))
 	r.Handle("/chat/", s.Handler.GetChatsHandler()).Methods("GET")
 	r.Handle("/chat/create", s.Handler.NewChatHandler()).Methods("POST")
+	r.Handle("/chat/search", s.Handler.SearchHandler()).Methods("GET")
+	r.Handle("/chat/sync", s.Handler.SyncHandler()).Methods("GET")
+	r.Handle("/chat/events", s.Handler.EventsHandler()).Methods("GET")
+	r.Handle("/chat/unread", s.Handler.UnreadCountsHandler()).Methods("GET")
+	r.Handle("/chat/scheduled", s.Handler.ListScheduledMessagesHandler()).Methods("GET")
+	r.Handle("/chat/scheduled/{scheduled_id:[0-9]+}", s.Handler.EditScheduledMessageHandler()).Methods("PATCH")
+	r.Handle("/chat/scheduled/{scheduled_id:[0-9]+}", s.Handler.CancelScheduledMessageHandler()).Methods("DELETE")
+	r.Handle("/chat/blocks", s.Handler.GetBlockedUsersHandler()).Methods("GET")
+	r.Handle("/chat/blocks", s.Handler.BlockUserHandler()).Methods("POST")
+	r.Handle("/chat/blocks/{user_id:[0-9]+}", s.Handler.UnblockUserHandler()).Methods("DELETE")
+	r.Handle("/chat/push/vapid-key", s.Handler.GetVAPIDKeyHandler()).Methods("GET")
+	r.Handle("/chat/push/subscriptions", s.Handler.SubscribePushHandler()).Methods("POST")
+	r.Handle("/chat/push/subscriptions", s.Handler.UnsubscribePushHandler()).Methods("DELETE")
+	r.Handle("/chat/exports", s.Handler.ListExportsHandler()).Methods("GET")
+	r.Handle("/chat/exports", s.Handler.RequestExportHandler()).Methods("POST")
+	r.Handle("/chat/exports/{export_id}", s.Handler.GetExportHandler()).Methods("GET")
+	r.Handle("/chat/exports/{export_id}/download", s.Handler.DownloadExportHandler()).Methods("GET")
 	r.Handle("/chat/{chat_id:[0-9]+}", s.Handler.SendMessageHandler()).Methods("POST")
 	r.Handle("/chat/{chat_id:[0-9]+}/messages", s.Handler.GetMessagesHandler()).Methods("GET")
+	r.Handle("/chat/{chat_id:[0-9]+}/search", s.Handler.SearchHandler()).Methods("GET")
+	r.Handle("/chat/{chat_id:[0-9]+}/read", s.Handler.MarkReadHandler()).Methods("POST")
+	r.Handle("/chat/{chat_id:[0-9]+}/pinned", s.Handler.GetPinnedMessagesHandler()).Methods("GET")
+	r.Handle("/chat/{chat_id:[0-9]+}/draft", s.Handler.SaveDraftHandler()).Methods("PUT")
+	r.Handle("/chat/{chat_id:[0-9]+}/settings", s.Handler.GetChatSettingsHandler()).Methods("GET")
+	r.Handle("/chat/{chat_id:[0-9]+}/settings", s.Handler.UpdateChatSettingsHandler()).Methods("PATCH")
+	r.Handle("/chat/{chat_id:[0-9]+}/retention", s.Handler.GetChatRetentionHandler()).Methods("GET")
+	r.Handle("/chat/{chat_id:[0-9]+}/retention", s.Handler.SetChatRetentionHandler()).Methods("PUT")
+	r.Handle("/chat/{chat_id:[0-9]+}/webhooks", s.Handler.ListIncomingWebhooksHandler()).Methods("GET")
+	r.Handle("/chat/{chat_id:[0-9]+}/webhooks", s.Handler.CreateIncomingWebhookHandler()).Methods("POST")
+	r.Handle("/chat/{chat_id:[0-9]+}/webhooks/{webhook_id:[0-9]+}", s.Handler.DeleteIncomingWebhookHandler()).Methods("DELETE")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}", s.Handler.EditMessageHandler()).Methods("PATCH")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}", s.Handler.DeleteMessageHandler()).Methods("DELETE")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/history", s.Handler.GetMessageHistoryHandler()).Methods("GET")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/forward", s.Handler.ForwardMessageHandler()).Methods("POST")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/report", s.Handler.ReportMessageHandler()).Methods("POST")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/pin", s.Handler.PinMessageHandler()).Methods("POST")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/pin", s.Handler.UnpinMessageHandler()).Methods("DELETE")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/reactions", s.Handler.AddReactionHandler()).Methods("POST")
+	r.Handle("/chat/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}/reactions", s.Handler.RemoveReactionHandler()).Methods("DELETE")
+	r.Handle("/chat/{chat_id:[0-9]+}/attachments", s.Handler.UploadAttachmentHandler()).Methods("POST")
+	r.Handle("/chat/{chat_id:[0-9]+}/attachments/{attachment_id:[0-9]+}", s.Handler.DownloadAttachmentHandler()).Methods("GET")
+	r.Handle("/chat/{chat_id:[0-9]+}/attachments/{attachment_id:[0-9]+}/thumbnail", s.Handler.DownloadThumbnailHandler()).Methods("GET")
+
+	admin := r.PathPrefix("/chat/admin").Subrouter()
+	admin.Use(RequireAdmin)
+	admin.Handle("/users", s.Handler.AdminListUsersHandler()).Methods("GET")
+	admin.Handle("/users/{user_id:[0-9]+}/suspension", s.Handler.AdminSuspendUserHandler()).Methods("POST")
+	admin.Handle("/users/{user_id:[0-9]+}/suspension", s.Handler.AdminUnsuspendUserHandler()).Methods("DELETE")
+	admin.Handle("/chats", s.Handler.AdminListChatsHandler()).Methods("GET")
+	admin.Handle("/chats/{chat_id:[0-9]+}", s.Handler.AdminDeleteChatHandler()).Methods("DELETE")
+	admin.Handle("/chats/{chat_id:[0-9]+}/messages/{message_id:[0-9]+}", s.Handler.AdminDeleteMessageHandler()).Methods("DELETE")
+	admin.Handle("/reports", s.Handler.AdminReportsHandler()).Methods("GET")
+	admin.Handle("/reports/{message_id:[0-9]+}/resolve", s.Handler.AdminResolveReportHandler()).Methods("POST")
+	admin.Handle("/audit", s.Handler.AdminAuditLogHandler()).Methods("GET")
+
+	// API ботов: токен бота передается в заголовке Authorization: Bearer
+	bot := r.PathPrefix("/bot").Subrouter()
+	bot.Use(RequireBot)
+	bot.Handle("/sendMessage", s.Handler.BotSendMessageHandler()).Methods("POST")
+	bot.Handle("/getUpdates", s.Handler.GetUpdatesHandler()).Methods("GET")
+	bot.Handle("/webhook", s.Handler.GetWebhookHandler()).Methods("GET")
+	bot.Handle("/webhook", s.Handler.SetWebhookHandler()).Methods("PUT")
+	bot.Handle("/webhook", s.Handler.DeleteWebhookHandler()).Methods("DELETE")
 }
@@ -0,0 +1,45 @@
+package httpserver
+
+import (
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// SyncHandler отдает изменения после курсора since для клиента, который переподключился к websocket
+func (h *Handler) SyncHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		query := r.URL.Query()
+		var since int64
+		if s := query.Get("since"); s != "" {
+			since, err = strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid since", http.StatusBadRequest)
+				return
+			}
+		}
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		batch, err := h.srv.SyncChanges(r.Context(), userId, since, limit)
+		if err != nil {
+			writeServiceError(w, "Failed to sync: ", err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(batch); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
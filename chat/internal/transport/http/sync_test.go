@@ -0,0 +1,105 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_SyncHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	type mockBehavior func(userId int)
+
+	batch := domain.SyncBatch{
+		Changes: []domain.Change{{Seq: 6, ChatId: 1, Type: domain.EventRead, Payload: json.RawMessage(`{"chat_id":1}`)}},
+		Cursor:  6,
+		HasMore: true,
+	}
+
+	tests := []struct {
+		name         string
+		url          string
+		mockBehavior mockBehavior
+		resp         domain.SyncBatch
+		wantStatus   int
+	}{
+		{
+			name: "ok",
+			url:  "/chat/sync?since=5&limit=1",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().SyncChanges(gomock.Any(), userId, int64(5), 1).Return(batch, nil)
+			},
+			resp:       batch,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "resync required",
+			url:  "/chat/sync",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().
+					SyncChanges(gomock.Any(), userId, int64(0), 0).
+					Return(domain.SyncBatch{Changes: []domain.Change{}, Cursor: 1500, ResyncRequired: true}, nil)
+			},
+			resp:       domain.SyncBatch{Changes: []domain.Change{}, Cursor: 1500, ResyncRequired: true},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "invalid since",
+			url:          "/chat/sync?since=abc",
+			mockBehavior: func(userId int) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "negative since",
+			url:  "/chat/sync?since=-1",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().SyncChanges(gomock.Any(), userId, int64(-1), 0).Return(domain.SyncBatch{}, domain.ErrInvalidCursor)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/sync", h.SyncHandler()).Methods("GET")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			l := logger.New()
+			ctx = logger.InitFromCtx(ctx, l)
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("SyncHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp domain.SyncBatch
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("SyncHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
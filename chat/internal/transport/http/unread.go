@@ -0,0 +1,38 @@
+package httpserver
+
+import (
+	"chat/internal/domain"
+	"chat/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// UnreadCountsHandler отдает число непрочитанных сообщений и упоминаний по чатам пользователя
+func (h *Handler) UnreadCountsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sUserId := r.Context().Value(UserIdKey)
+		userId, err := strconv.Atoi(fmt.Sprintf("%v", sUserId))
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		counts, err := h.srv.GetUnreadCounts(r.Context(), userId)
+		if err != nil {
+			writeServiceError(w, "Failed to get unread counts: ", err)
+			return
+		}
+		if counts == nil {
+			counts = []domain.UnreadCount{}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(UnreadCountsResponse(counts)); err != nil {
+			logger.GetFromCtx(r.Context()).ErrorContext(r.Context(), "error in json encoder", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	})
+}
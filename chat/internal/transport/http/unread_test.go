@@ -0,0 +1,142 @@
+package httpserver
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/transport/http/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_UnreadCountsHandler(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	tests := []struct {
+		name         string
+		mockBehavior func(userId int)
+		resp         UnreadCountsResponse
+		wantStatus   int
+	}{
+		{
+			name: "ok",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().GetUnreadCounts(gomock.Any(), userId).
+					Return([]domain.UnreadCount{{ChatId: 3, Unread: 5, Mentions: 1}}, nil)
+			},
+			resp:       UnreadCountsResponse{{ChatId: 3, Unread: 5, Mentions: 1}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "nothing unread",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().GetUnreadCounts(gomock.Any(), userId).Return(nil, nil)
+			},
+			resp:       UnreadCountsResponse{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "service error",
+			mockBehavior: func(userId int) {
+				cs.EXPECT().GetUnreadCounts(gomock.Any(), userId).Return(nil, errors.New("db is down"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1)
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/unread", h.UnreadCountsHandler()).Methods("GET")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("GET", "/chat/unread", nil)
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			ctx = logger.InitFromCtx(ctx, logger.New())
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("UnreadCountsHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+
+			if tt.resp == nil {
+				return
+			}
+
+			var resp UnreadCountsResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Errorf("UnreadCountsHandler response got error %v", err)
+			}
+
+			assert.Equal(t, tt.resp, resp)
+		})
+	}
+}
+
+func TestHandler_SendMessageHandler_Entities(t *testing.T) {
+	cs := mock.NewMockChatService(gomock.NewController(t))
+
+	entities := []domain.MessageEntity{{Type: domain.EntityBold, Offset: 0, Length: 2}}
+
+	tests := []struct {
+		name         string
+		body         string
+		mockBehavior func()
+		wantStatus   int
+	}{
+		{
+			name: "markdown",
+			body: `{"text": "**hi**", "format": "markdown"}`,
+			mockBehavior: func() {
+				cs.EXPECT().PostMessage(gomock.Any(), domain.NewMessage{ChatId: 1, SenderId: 1, Text: "**hi**", Format: domain.FormatMarkdown}).
+					Return(10, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "invalid entities",
+			body: `{"text": "hi", "entities": [{"type": "bold", "offset": 0, "length": 2}]}`,
+			mockBehavior: func() {
+				cs.EXPECT().PostMessage(gomock.Any(), domain.NewMessage{ChatId: 1, SenderId: 1, Text: "hi", Entities: entities}).
+					Return(-1, fmt.Errorf("%w: bold at 0 overlaps", domain.ErrInvalidEntities))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			h := NewHandler(cs)
+			router := mux.NewRouter()
+			router.Handle("/chat/{chat_id:[0-9]+}", h.SendMessageHandler()).Methods("POST")
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest("POST", "/chat/1", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), UserIdKey, 1)
+			ctx = logger.InitFromCtx(ctx, logger.New())
+			req = req.WithContext(ctx)
+
+			router.ServeHTTP(rr, req)
+
+			if tt.wantStatus != rr.Code {
+				t.Errorf("SendMessageHandler status got %v, want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package realtime
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Client отправляет события чата во внутренний endpoint websocket-сервиса,
+// который доставляет их подключенным клиентам
+type Client struct {
+	cfg    Config
+	client *http.Client
+}
+
+func New(cfg Config) *Client {
+	return &Client{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (c *Client) Notify(ctx context.Context, event domain.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.EventsURL(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("websocket service responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// OnlineUsers возвращает тех из userIDs, у кого есть открытое websocket-соединение
+func (c *Client) OnlineUsers(ctx context.Context, userIDs []int) ([]int, error) {
+	body, err := json.Marshal(map[string][]int{"user_ids": userIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal presence request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.PresenceURL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presence: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("websocket service responded with status %d", resp.StatusCode)
+	}
+
+	var presence struct {
+		OnlineUserIds []int `json:"online_user_ids"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&presence); err != nil {
+		return nil, fmt.Errorf("failed to decode presence: %w", err)
+	}
+
+	return presence.OnlineUserIds, nil
+}
@@ -0,0 +1,20 @@
+package realtime
+
+import (
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	Host    string        `env:"WS_HOST"`
+	Port    string        `env:"WS_PORT"`
+	Timeout time.Duration `env:"WS_TIMEOUT" envDefault:"2s"`
+}
+
+func (c *Config) EventsURL() string {
+	return fmt.Sprintf("http://%s:%s/events", c.Host, c.Port)
+}
+
+func (c *Config) PresenceURL() string {
+	return fmt.Sprintf("http://%s:%s/presence", c.Host, c.Port)
+}
@@ -0,0 +1,168 @@
+package webpush
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/netguard"
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// vapidTokenTTL - срок действия подписи VAPID, push-сервисы принимают не больше 24 часов
+const vapidTokenTTL = 12 * time.Hour
+
+// ErrSubscriptionGone - push-сервис больше не принимает уведомления для подписки, ее нужно удалить
+var ErrSubscriptionGone = errors.New("push subscription is gone")
+
+// Client отправляет зашифрованные уведомления в push-сервисы браузеров с подписью VAPID (RFC 8292)
+type Client struct {
+	cfg       Config
+	key       *ecdsa.PrivateKey
+	publicKey string
+	client    *http.Client
+}
+
+func New(cfg Config) (*Client, error) {
+	return NewWithAddressCheck(cfg, netguard.PublicAddress)
+}
+
+// NewWithAddressCheck создает клиент, который соединяется только с адресами, разрешенными allow.
+// Адреса push-сервисов присылают пользователи, поэтому New пускает только в публичную сеть
+func NewWithAddressCheck(cfg Config, allow func(netip.AddrPort) bool) (*Client, error) {
+	raw, err := decodeKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	private, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	public := private.PublicKey().Bytes()
+
+	if cfg.PublicKey != "" {
+		configured, err := decodeKey(cfg.PublicKey)
+		if err != nil || !bytes.Equal(configured, public) {
+			return nil, errors.New("VAPID public key does not match private key")
+		}
+	}
+
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+
+	return &Client{
+		cfg:       cfg,
+		key:       key,
+		publicKey: base64.RawURLEncoding.EncodeToString(public),
+		client:    newHTTPClient(cfg.Timeout, allow),
+	}, nil
+}
+
+func newHTTPClient(timeout time.Duration, allow func(netip.AddrPort) bool) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           netguard.Dialer(timeout, allow).DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		Timeout: timeout,
+		// Редирект считается ошибкой: зашифрованное уведомление не должно уходить на другой адрес
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// PublicKey - applicationServerKey для PushManager.subscribe() в браузере
+func (c *Client) PublicKey() string {
+	return c.publicKey
+}
+
+// Send шифрует payload ключами подписки и передает его push-сервису.
+// ErrSubscriptionGone означает, что подписка истекла или отозвана
+func (c *Client) Send(ctx context.Context, sub domain.PushSubscription, payload []byte) error {
+	body, err := encrypt(sub.P256dh, sub.Auth, payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+	token, err := c.vapidToken(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", token, c.publicKey))
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(c.cfg.TTL.Seconds())))
+	req.Header.Set("Urgency", "high")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		return fmt.Errorf("push service responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// vapidToken подписывает JWT (ES256) для origin push-сервиса
+func (c *Client) vapidToken(audience string) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]any{
+		"aud": audience,
+		"exp": time.Now().Add(vapidTokenTTL).Unix(),
+		"sub": c.cfg.Subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, c.key, hash[:])
+	if err != nil {
+		return "", err
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,131 @@
+package webpush_test
+
+import (
+	"chat/internal/netguard"
+	"chat/internal/transport/webpush"
+	"chat/internal/transport/webpush/webpushtest"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowAll пускает в локальную сеть, где работает httptest
+func allowAll(netip.AddrPort) bool { return true }
+
+func newClient(t *testing.T) *webpush.Client {
+	return newGuardedClient(t, allowAll)
+}
+
+func newGuardedClient(t *testing.T, allow func(netip.AddrPort) bool) *webpush.Client {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	client, err := webpush.NewWithAddressCheck(webpush.Config{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		Subject:    "mailto:admin@example.com",
+		TTL:        time.Hour,
+		Timeout:    time.Second,
+	}, allow)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_Send(t *testing.T) {
+	ctx := context.Background()
+	pushService := webpushtest.NewServer()
+	defer pushService.Close()
+
+	client := newClient(t)
+
+	t.Run("encrypted and signed", func(t *testing.T) {
+		sub := pushService.NewSubscription(1)
+
+		require.NoError(t, client.Send(ctx, sub, []byte(`{"text":"Привет"}`)))
+
+		pushes := pushService.Pushes()
+		require.Len(t, pushes, 1)
+		assert.Equal(t, sub.Endpoint, pushes[0].Endpoint)
+		assert.JSONEq(t, `{"text":"Привет"}`, string(pushes[0].Payload))
+		assert.Equal(t, 3600, pushes[0].TTL)
+		assert.Equal(t, "high", pushes[0].Urgency)
+	})
+
+	t.Run("padded keys", func(t *testing.T) {
+		sub := pushService.NewSubscription(1)
+		sub.P256dh += "="
+		sub.Auth += "=="
+
+		assert.NoError(t, client.Send(ctx, sub, []byte("{}")))
+	})
+
+	t.Run("expired subscription", func(t *testing.T) {
+		sub := pushService.NewSubscription(1)
+		pushService.Expire(sub.Endpoint)
+
+		assert.ErrorIs(t, client.Send(ctx, sub, []byte("{}")), webpush.ErrSubscriptionGone)
+	})
+
+	t.Run("payload too large", func(t *testing.T) {
+		sub := pushService.NewSubscription(1)
+
+		err := client.Send(ctx, sub, []byte(strings.Repeat("a", webpush.MaxPayload+1)))
+		assert.ErrorIs(t, err, webpush.ErrPayloadTooLarge)
+
+		assert.NoError(t, client.Send(ctx, sub, []byte(strings.Repeat("a", webpush.MaxPayload))))
+	})
+}
+
+func TestClient_SendInternalAddress(t *testing.T) {
+	ctx := context.Background()
+	pushService := webpushtest.NewServer()
+	defer pushService.Close()
+
+	t.Run("loopback is refused", func(t *testing.T) {
+		sub := pushService.NewSubscription(1)
+
+		err := newGuardedClient(t, netguard.PublicAddress).Send(ctx, sub, []byte("{}"))
+		assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+		assert.Empty(t, pushService.Pushes())
+	})
+
+	t.Run("redirect is not followed", func(t *testing.T) {
+		redirect := httptest.NewServer(http.RedirectHandler(pushService.URL, http.StatusTemporaryRedirect))
+		defer redirect.Close()
+		sub := pushService.NewSubscription(1)
+		sub.Endpoint = redirect.URL
+
+		assert.Error(t, newClient(t).Send(ctx, sub, []byte("{}")))
+		assert.Empty(t, pushService.Pushes())
+	})
+}
+
+func TestNew(t *testing.T) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, err = webpush.New(webpush.Config{PrivateKey: "not a key"})
+	assert.Error(t, err)
+
+	_, err = webpush.New(webpush.Config{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(other.PublicKey().Bytes()),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(key.Bytes()),
+	})
+	assert.Error(t, err)
+
+	client, err := webpush.New(webpush.Config{PrivateKey: base64.RawURLEncoding.EncodeToString(key.Bytes())})
+	require.NoError(t, err)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()), client.PublicKey())
+}
@@ -0,0 +1,17 @@
+package webpush
+
+import "time"
+
+// Config - VAPID-ключи сервера в base64url: PublicKey - несжатая точка P-256 (65 байт),
+// PrivateKey - скаляр (32 байта). Пустой PrivateKey отключает push-уведомления
+type Config struct {
+	PublicKey  string        `env:"VAPID_PUBLIC_KEY"`
+	PrivateKey string        `env:"VAPID_PRIVATE_KEY"`
+	Subject    string        `env:"VAPID_SUBJECT" envDefault:"mailto:admin@localhost"`
+	TTL        time.Duration `env:"PUSH_TTL" envDefault:"24h"`
+	Timeout    time.Duration `env:"PUSH_TIMEOUT" envDefault:"5s"`
+}
+
+func (c *Config) Enabled() bool {
+	return c.PrivateKey != ""
+}
@@ -0,0 +1,147 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// recordSize - размер записи aes128gcm. Уведомление помещается в одну запись
+	recordSize = 4096
+	saltLen    = 16
+	keyLen     = 65
+	headerLen  = saltLen + 4 + 1 + keyLen
+	// MaxPayload - наибольший размер уведомления до шифрования: запись минус тег GCM и разделитель
+	MaxPayload = recordSize - headerLen - 16 - 1
+)
+
+var ErrPayloadTooLarge = errors.New("push payload is too large")
+
+// encrypt шифрует payload для подписки по RFC 8291 (Content-Encoding: aes128gcm)
+func encrypt(p256dh, authSecret string, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+
+	uaPublicBytes, err := decodeKey(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh: %w", err)
+	}
+	auth, err := decodeKey(authSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth secret: %w", err)
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	secret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	gcm, nonce, err := contentCipher(secret, auth, salt, uaPublicBytes, asPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, headerLen)
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// 0x02 - разделитель последней записи, дополнение не используется
+	plaintext := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// contentCipher выводит ключ и nonce записи из общего секрета ECDH и auth-секрета подписки
+func contentCipher(secret, auth, salt, uaPublic, asPublic []byte) (cipher.AEAD, []byte, error) {
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, secret, auth, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gcm, nonce, nil
+}
+
+// Decrypt расшифровывает тело уведомления ключами получателя. Нужен поддельному push-сервису в тестах
+func Decrypt(uaPrivate *ecdh.PrivateKey, auth, body []byte) ([]byte, error) {
+	if len(body) < headerLen {
+		return nil, errors.New("body is too short")
+	}
+	salt := body[:saltLen]
+	idLen := int(body[saltLen+4])
+	if idLen != keyLen {
+		return nil, errors.New("invalid key id length")
+	}
+	asPublicBytes := body[saltLen+5 : headerLen]
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := uaPrivate.ECDH(asPublic)
+	if err != nil {
+		return nil, err
+	}
+	gcm, nonce, err := contentCipher(secret, auth, salt, uaPrivate.PublicKey().Bytes(), asPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, body[headerLen:], nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(plaintext) == 0 || plaintext[len(plaintext)-1] != 0x02 {
+		return nil, errors.New("invalid record delimiter")
+	}
+
+	return plaintext[:len(plaintext)-1], nil
+}
+
+// decodeKey декодирует base64url с дополнением и без: браузеры отдают ключи по-разному
+func decodeKey(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,28 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Пример из приложения A RFC 8291
+func TestDecrypt_RFC8291(t *testing.T) {
+	b64 := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		require.NoError(t, err)
+		return b
+	}
+
+	uaPrivate, err := ecdh.P256().NewPrivateKey(b64("q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94"))
+	require.NoError(t, err)
+	auth := b64("BTBZMqHH6r4Tts7J_aSIgg")
+	body := b64("DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN")
+
+	plaintext, err := Decrypt(uaPrivate, auth, body)
+	require.NoError(t, err)
+	assert.Equal(t, "When I grow up, I want to be a watermelon", string(plaintext))
+}
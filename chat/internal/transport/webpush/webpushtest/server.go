@@ -0,0 +1,204 @@
+// Package webpushtest - поддельный push-сервис для тестов отправки Web Push уведомлений
+package webpushtest
+
+import (
+	"chat/internal/domain"
+	"chat/internal/transport/webpush"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Push - уведомление, принятое поддельным push-сервисом, уже расшифрованное
+type Push struct {
+	Endpoint string
+	Payload  []byte
+	TTL      int
+	Urgency  string
+}
+
+type subscription struct {
+	key  *ecdh.PrivateKey
+	auth []byte
+	gone bool
+}
+
+// Server проверяет подпись VAPID и расшифровывает уведомления ключами выданных им подписок.
+// Уведомления для неизвестных и истекших подписок получают 410 Gone, как в настоящих push-сервисах
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	subs   map[string]*subscription
+	pushes []Push
+	nextID int
+}
+
+func NewServer() *Server {
+	s := &Server{subs: make(map[string]*subscription)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// NewSubscription создает подписку браузера пользователя userID на этот сервис
+func (s *Server) NewSubscription(userID int) domain.PushSubscription {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	endpoint := fmt.Sprintf("%s/push/%d", s.URL, s.nextID)
+	s.subs[endpoint] = &subscription{key: key, auth: auth}
+
+	return domain.PushSubscription{
+		UserId:   userID,
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}
+}
+
+// Expire отзывает подписку: следующие уведомления для нее получат 410 Gone
+func (s *Server) Expire(endpoint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sub, ok := s.subs[endpoint]; ok {
+		sub.gone = true
+	}
+}
+
+// Pushes возвращает принятые уведомления в порядке получения
+func (s *Server) Pushes() []Push {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Push(nil), s.pushes...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	endpoint := s.URL + r.URL.Path
+
+	s.mu.Lock()
+	sub, ok := s.subs[endpoint]
+	s.mu.Unlock()
+	if !ok || sub.gone {
+		http.Error(w, "subscription is gone", http.StatusGone)
+		return
+	}
+
+	if err := s.verifyVAPID(r.Header.Get("Authorization")); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("Content-Encoding") != "aes128gcm" {
+		http.Error(w, "unsupported content encoding", http.StatusUnsupportedMediaType)
+		return
+	}
+	ttl, err := strconv.Atoi(r.Header.Get("TTL"))
+	if err != nil {
+		http.Error(w, "TTL is required", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	payload, err := webpush.Decrypt(sub.key, sub.auth, body)
+	if err != nil {
+		http.Error(w, "failed to decrypt payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.pushes = append(s.pushes, Push{Endpoint: endpoint, Payload: payload, TTL: ttl, Urgency: r.Header.Get("Urgency")})
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// verifyVAPID проверяет заголовок "vapid t=<jwt>, k=<public key>": подпись ES256, аудиторию и срок
+func (s *Server) verifyVAPID(header string) error {
+	params, ok := strings.CutPrefix(header, "vapid ")
+	if !ok {
+		return errors.New("missing vapid authorization")
+	}
+	var token, key string
+	for _, p := range strings.Split(params, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(p), "=")
+		switch name {
+		case "t":
+			token = value
+		case "k":
+			key = value
+		}
+	}
+
+	rawKey, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil || len(rawKey) != 65 {
+		return errors.New("invalid vapid key")
+	}
+	public := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(rawKey[1:33]),
+		Y:     new(big.Int).SetBytes(rawKey[33:]),
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("invalid vapid token")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 64 {
+		return errors.New("invalid vapid signature")
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !ecdsa.Verify(public, hash[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+		return errors.New("invalid vapid signature")
+	}
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errors.New("invalid vapid claims")
+	}
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	if err := json.Unmarshal(rawClaims, &claims); err != nil {
+		return errors.New("invalid vapid claims")
+	}
+	if claims.Aud != s.URL {
+		return fmt.Errorf("invalid vapid audience %q", claims.Aud)
+	}
+	if time.Unix(claims.Exp, 0).Before(time.Now()) || time.Unix(claims.Exp, 0).After(time.Now().Add(24*time.Hour)) {
+		return errors.New("invalid vapid expiration")
+	}
+	if claims.Sub == "" {
+		return errors.New("vapid subject is required")
+	}
+
+	return nil
+}
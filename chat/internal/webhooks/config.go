@@ -0,0 +1,20 @@
+package webhooks
+
+import "time"
+
+type Config struct {
+	// Interval - как часто проверять очередь доставки. За один проход отправляются все готовые события
+	// порциями по BatchSize, до Workers запросов одновременно
+	Interval  time.Duration `env:"WEBHOOK_INTERVAL" envDefault:"1s"`
+	BatchSize int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"100"`
+	Workers   int           `env:"WEBHOOK_WORKERS" envDefault:"8"`
+
+	// Timeout ограничивает один запрос к вебхуку. Бот должен ответить 2xx, иначе доставка повторяется
+	Timeout time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"5s"`
+
+	// MaxAttempts - сколько раз пытаться доставить событие. Пауза перед повтором растет вдвое
+	// от BackoffBase после каждой неудачи, но не больше BackoffMax
+	MaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	BackoffBase time.Duration `env:"WEBHOOK_BACKOFF_BASE" envDefault:"5s"`
+	BackoffMax  time.Duration `env:"WEBHOOK_BACKOFF_MAX" envDefault:"1h"`
+}
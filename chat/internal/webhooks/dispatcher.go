@@ -0,0 +1,239 @@
+package webhooks
+
+import (
+	"bytes"
+	"chat/internal/domain"
+	"chat/internal/netguard"
+	"chat/pkg/logger"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -destination=./mock/mock.go -package=mock -source=dispatcher.go
+
+const userAgent = "ChatWebhooks/1.0"
+
+// Заголовки запроса вебхука
+const (
+	HeaderId        = "X-Webhook-Id"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Store - очередь доставки событий вебхукам ботов
+type Store interface {
+	EnqueueWebhookDeliveries(ctx context.Context, userIDs []int, chatID int, event string, payload []byte) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	CompleteWebhookDelivery(ctx context.Context, id int64) error
+	RetryWebhookDelivery(ctx context.Context, id int64, retryAt time.Time, reason string) error
+	DropWebhookDelivery(ctx context.Context, id int64, reason string) error
+}
+
+// Update - тело запроса вебхука. Повторы одной доставки приходят с тем же Id, по нему бот отбрасывает
+// дубликаты. Порядок доставки не гарантирован: событие, ожидающее повтора, не задерживает следующие
+type Update struct {
+	Id        int64           `json:"id"`
+	Event     string          `json:"event"`
+	ChatId    int             `json:"chat_id"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// forwardedEvents - события, которые получают вебхуки ботов
+var forwardedEvents = map[string]bool{
+	domain.EventMessageCreated: true,
+	domain.EventMessageEdited:  true,
+	domain.EventMessageDeleted: true,
+}
+
+// Dispatcher отправляет события о сообщениях в чатах ботов на их вебхуки. События сохраняются в очередь
+// в базе, поэтому переживают перезапуск и доставляются одной из реплик
+type Dispatcher struct {
+	cfg    Config
+	store  Store
+	client *http.Client
+}
+
+func New(cfg Config, store Store) *Dispatcher {
+	return newDispatcher(cfg, store, netguard.PublicAddress)
+}
+
+func newDispatcher(cfg Config, store Store, allow func(netip.AddrPort) bool) *Dispatcher {
+	dialer := netguard.Dialer(cfg.Timeout, allow)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   cfg.Timeout,
+			ResponseHeaderTimeout: cfg.Timeout,
+			MaxIdleConnsPerHost:   max(cfg.Workers, 2),
+			IdleConnTimeout:       30 * time.Second,
+		},
+		Timeout: cfg.Timeout,
+		// Редирект считается ошибкой доставки: подписанное тело не должно уходить на другой адрес
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &Dispatcher{cfg: cfg, store: store, client: client}
+}
+
+// Notify ставит событие о сообщении в очередь тем получателям, у которых установлен вебхук
+func (d *Dispatcher) Notify(ctx context.Context, event domain.Event) error {
+	if !forwardedEvents[event.Type] || len(event.UserIds) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	return d.store.EnqueueWebhookDeliveries(ctx, event.UserIds, event.ChatId, event.Type, payload)
+}
+
+// Run проверяет очередь раз в Interval, пока не отменен ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	if d.cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.drain(ctx)
+		}
+	}
+}
+
+// drain отправляет готовые события порциями, пока порция заполняется целиком
+func (d *Dispatcher) drain(ctx context.Context) {
+	batchSize := max(d.cfg.BatchSize, 1)
+	for ctx.Err() == nil {
+		deliveries, err := d.store.ClaimWebhookDeliveries(ctx, batchSize, d.lease())
+		if err != nil {
+			logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to claim webhook deliveries", err)
+			return
+		}
+
+		d.deliverAll(ctx, deliveries)
+		if len(deliveries) < batchSize {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliverAll(ctx context.Context, deliveries []domain.WebhookDelivery) {
+	sem := make(chan struct{}, max(d.cfg.Workers, 1))
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver отправляет событие и записывает результат. Если реплика останавливается, результат не записывается:
+// по истечении аренды доставку повторит другая реплика
+func (d *Dispatcher) deliver(ctx context.Context, delivery domain.WebhookDelivery) {
+	sendErr := d.send(ctx, delivery)
+	if ctx.Err() != nil {
+		return
+	}
+
+	var err error
+	switch {
+	case sendErr == nil:
+		err = d.store.CompleteWebhookDelivery(ctx, delivery.Id)
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		err = d.store.DropWebhookDelivery(ctx, delivery.Id, fmt.Sprintf("dropped after %d attempts: %v", delivery.Attempts, sendErr))
+	default:
+		err = d.store.RetryWebhookDelivery(ctx, delivery.Id, time.Now().Add(d.backoff(delivery.Attempts)), sendErr.Error())
+	}
+	if err != nil {
+		logger.GetFromCtx(ctx).ErrorContext(ctx, "failed to save webhook delivery result", err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery domain.WebhookDelivery) error {
+	body, err := json.Marshal(Update{
+		Id:        delivery.Id,
+		Event:     delivery.Event,
+		ChatId:    delivery.ChatId,
+		Payload:   delivery.Payload,
+		CreatedAt: delivery.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode update: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderId, strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send update: %w", err)
+	}
+	defer resp.Body.Close()
+	// Остаток ответа дочитывается, чтобы соединение вернулось в пул
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// backoff - пауза перед попыткой attempt+1: BackoffBase, затем вдвое больше после каждой неудачи
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.BackoffBase
+	for i := 1; i < attempt && delay < d.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, d.cfg.BackoffMax)
+}
+
+// lease - на сколько доставка закрепляется за репликой. С запасом больше Timeout, чтобы отправку,
+// которая еще идет, не взяла другая реплика
+func (d *Dispatcher) lease() time.Duration {
+	return max(2*d.cfg.Timeout, time.Second)
+}
+
+// Sign возвращает подпись запроса вебхука: HMAC-SHA256 ключом вебхука от "<timestamp>.<тело запроса>".
+// Бот сравнивает ее с заголовком X-Webhook-Signature и отбрасывает запросы со старым X-Webhook-Timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,148 @@
+package webhooks
+
+import (
+	"chat/internal/domain"
+	"chat/internal/webhooks/mock"
+	"chat/pkg/logger"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = Config{BatchSize: 10, Workers: 2, Timeout: time.Second, MaxAttempts: 3, BackoffBase: time.Second, BackoffMax: 5 * time.Second}
+
+// allowAll пускает в локальную сеть, где работает httptest
+func allowAll(netip.AddrPort) bool { return true }
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11", Sign("secret", 1700000000, []byte(`{"id":1}`)))
+	assert.NotEqual(t, Sign("secret", 1700000000, []byte(`{"id":1}`)), Sign("secret", 1700000001, []byte(`{"id":1}`)))
+}
+
+func TestDispatcher_Notify(t *testing.T) {
+	store := mock.NewMockStore(gomock.NewController(t))
+	d := newDispatcher(testConfig, store, allowAll)
+	ctx := context.Background()
+
+	msg := domain.Message{Id: 5, Text: "сборка упала"}
+	store.EXPECT().EnqueueWebhookDeliveries(ctx, []int{2, 7}, 1, domain.EventMessageCreated, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []int, _ int, _ string, payload []byte) error {
+			var got domain.Message
+			require.NoError(t, json.Unmarshal(payload, &got))
+			assert.Equal(t, msg.Text, got.Text)
+			return nil
+		})
+	require.NoError(t, d.Notify(ctx, domain.Event{Type: domain.EventMessageCreated, ChatId: 1, UserIds: []int{2, 7}, Payload: msg}))
+
+	// Остальные события вебхукам не передаются
+	require.NoError(t, d.Notify(ctx, domain.Event{Type: domain.EventRead, ChatId: 1, UserIds: []int{2}}))
+	require.NoError(t, d.Notify(ctx, domain.Event{Type: domain.EventDraftUpdated, UserIds: []int{7}}))
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+
+	var status int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		assert.Equal(t, Sign("secret", timestamp, body), r.Header.Get(HeaderSignature))
+		assert.Equal(t, "42", r.Header.Get(HeaderId))
+
+		var update Update
+		assert.NoError(t, json.Unmarshal(body, &update))
+		assert.Equal(t, Update{Id: 42, Event: domain.EventMessageCreated, ChatId: 1, Payload: json.RawMessage(`{"id":5}`), CreatedAt: update.CreatedAt}, update)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	delivery := func(attempts int) domain.WebhookDelivery {
+		return domain.WebhookDelivery{
+			Id: 42, BotId: 7, ChatId: 1, Event: domain.EventMessageCreated, Payload: json.RawMessage(`{"id":5}`),
+			Attempts: attempts, URL: srv.URL + "/hook", Secret: "secret",
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+		status = http.StatusOK
+		store.EXPECT().CompleteWebhookDelivery(gomock.Any(), int64(42)).Return(nil)
+
+		newDispatcher(testConfig, store, allowAll).deliver(ctx, delivery(1))
+	})
+
+	t.Run("failure is retried with backoff", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+		status = http.StatusInternalServerError
+		store.EXPECT().RetryWebhookDelivery(gomock.Any(), int64(42), gomock.Any(), "unexpected status 500").
+			DoAndReturn(func(_ context.Context, _ int64, retryAt time.Time, _ string) error {
+				assert.WithinDuration(t, time.Now().Add(2*time.Second), retryAt, 500*time.Millisecond)
+				return nil
+			})
+
+		newDispatcher(testConfig, store, allowAll).deliver(ctx, delivery(2))
+	})
+
+	t.Run("redirect is a failure", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+		status = http.StatusFound
+		store.EXPECT().RetryWebhookDelivery(gomock.Any(), int64(42), gomock.Any(), "unexpected status 302").Return(nil)
+
+		newDispatcher(testConfig, store, allowAll).deliver(ctx, delivery(1))
+	})
+
+	t.Run("last attempt drops delivery", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+		status = http.StatusServiceUnavailable
+		store.EXPECT().DropWebhookDelivery(gomock.Any(), int64(42), "dropped after 3 attempts: unexpected status 503").Return(nil)
+
+		newDispatcher(testConfig, store, allowAll).deliver(ctx, delivery(3))
+	})
+
+	t.Run("private address is refused", func(t *testing.T) {
+		store := mock.NewMockStore(gomock.NewController(t))
+		store.EXPECT().RetryWebhookDelivery(gomock.Any(), int64(42), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int64, _ time.Time, reason string) error {
+				assert.Contains(t, reason, "address is not allowed")
+				return nil
+			})
+
+		New(testConfig, store).deliver(ctx, delivery(1))
+	})
+}
+
+func TestDispatcher_Drain(t *testing.T) {
+	ctx := logger.InitFromCtx(context.Background(), logger.New())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	store := mock.NewMockStore(gomock.NewController(t))
+	d := newDispatcher(Config{BatchSize: 2, Workers: 2, Timeout: time.Second}, store, allowAll)
+
+	full := []domain.WebhookDelivery{{Id: 1, URL: srv.URL}, {Id: 2, URL: srv.URL}}
+	gomock.InOrder(
+		store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), 2, 2*time.Second).Return(full, nil),
+		store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), 2, 2*time.Second).Return(nil, nil),
+	)
+	store.EXPECT().CompleteWebhookDelivery(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+
+	d.drain(ctx)
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := newDispatcher(testConfig, nil, allowAll)
+
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 30: 5 * time.Second} {
+		assert.Equal(t, want, d.backoff(attempt), "attempt %d", attempt)
+	}
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dispatcher.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	domain "chat/internal/domain"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimWebhookDeliveries(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDeliveries), ctx, limit, lease)
+}
+
+// CompleteWebhookDelivery mocks base method.
+func (m *MockStore) CompleteWebhookDelivery(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteWebhookDelivery", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteWebhookDelivery indicates an expected call of CompleteWebhookDelivery.
+func (mr *MockStoreMockRecorder) CompleteWebhookDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CompleteWebhookDelivery), ctx, id)
+}
+
+// DropWebhookDelivery mocks base method.
+func (m *MockStore) DropWebhookDelivery(ctx context.Context, id int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropWebhookDelivery", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropWebhookDelivery indicates an expected call of DropWebhookDelivery.
+func (mr *MockStoreMockRecorder) DropWebhookDelivery(ctx, id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropWebhookDelivery", reflect.TypeOf((*MockStore)(nil).DropWebhookDelivery), ctx, id, reason)
+}
+
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockStore) EnqueueWebhookDeliveries(ctx context.Context, userIDs []int, chatID int, event string, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", ctx, userIDs, chatID, event, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockStoreMockRecorder) EnqueueWebhookDeliveries(ctx, userIDs, chatID, event, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).EnqueueWebhookDeliveries), ctx, userIDs, chatID, event, payload)
+}
+
+// RetryWebhookDelivery mocks base method.
+func (m *MockStore) RetryWebhookDelivery(ctx context.Context, id int64, retryAt time.Time, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryWebhookDelivery", ctx, id, retryAt, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryWebhookDelivery indicates an expected call of RetryWebhookDelivery.
+func (mr *MockStoreMockRecorder) RetryWebhookDelivery(ctx, id, retryAt, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryWebhookDelivery", reflect.TypeOf((*MockStore)(nil).RetryWebhookDelivery), ctx, id, retryAt, reason)
+}
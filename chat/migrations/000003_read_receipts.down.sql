@@ -0,0 +1,10 @@
+ALTER TABLE messages ADD COLUMN is_read BOOLEAN DEFAULT FALSE;
+
+UPDATE messages m
+SET is_read = TRUE
+FROM chat_reads r
+WHERE r.chat_id = m.chat_id
+  AND r.user_id <> m.sender_id
+  AND m.id <= r.last_read_message_id;
+
+DROP TABLE chat_reads;
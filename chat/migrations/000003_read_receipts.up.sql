@@ -0,0 +1,20 @@
+-- Указатель на последнее прочитанное сообщение для каждого участника чата
+CREATE TABLE chat_reads (
+                            chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                            user_id BIGINT NOT NULL,
+                            last_read_message_id INTEGER NOT NULL DEFAULT 0,
+                            updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                            PRIMARY KEY (chat_id, user_id)
+);
+
+-- Переносим прочитанность из messages.is_read: участник прочитал все входящие сообщения с is_read = TRUE
+INSERT INTO chat_reads (chat_id, user_id, last_read_message_id)
+SELECT m.chat_id,
+       CASE WHEN m.sender_id = c.user_1_id THEN c.user_2_id ELSE c.user_1_id END,
+       MAX(m.id)
+FROM messages m
+         JOIN chats c ON c.id = m.chat_id
+WHERE m.is_read
+GROUP BY m.chat_id, CASE WHEN m.sender_id = c.user_1_id THEN c.user_2_id ELSE c.user_1_id END;
+
+ALTER TABLE messages DROP COLUMN is_read;
@@ -0,0 +1,6 @@
+DROP TABLE message_deletions;
+DROP TABLE message_edits;
+
+ALTER TABLE messages
+    DROP COLUMN edited_at,
+    DROP COLUMN deleted_at;
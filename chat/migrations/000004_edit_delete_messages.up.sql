@@ -0,0 +1,21 @@
+ALTER TABLE messages
+    ADD COLUMN edited_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Предыдущие версии текста сообщения
+CREATE TABLE message_edits (
+                               id SERIAL PRIMARY KEY,
+                               message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+                               text TEXT NOT NULL,
+                               edited_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX message_edits_message_id_idx ON message_edits (message_id);
+
+-- Сообщения, удаленные пользователем только у себя
+CREATE TABLE message_deletions (
+                                   message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+                                   user_id BIGINT NOT NULL,
+                                   deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                                   PRIMARY KEY (message_id, user_id)
+);
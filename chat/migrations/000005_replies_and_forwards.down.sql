@@ -0,0 +1,4 @@
+ALTER TABLE messages
+    DROP COLUMN reply_to_message_id,
+    DROP COLUMN forwarded_from_sender_id,
+    DROP COLUMN forwarded_from_message_id;
@@ -0,0 +1,5 @@
+ALTER TABLE messages
+    ADD COLUMN reply_to_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+    -- Автор и id исходного сообщения при пересылке; при пересылке пересланного сохраняется первоисточник
+    ADD COLUMN forwarded_from_sender_id BIGINT,
+    ADD COLUMN forwarded_from_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL;
@@ -0,0 +1,7 @@
+CREATE TABLE message_reactions (
+                                   message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+                                   user_id BIGINT NOT NULL,
+                                   emoji VARCHAR(32) NOT NULL,
+                                   created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                                   PRIMARY KEY (message_id, user_id, emoji)
+);
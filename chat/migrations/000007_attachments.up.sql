@@ -0,0 +1,15 @@
+-- Загруженные в чат файлы. message_id пустой, пока вложение не отправлено в сообщении
+CREATE TABLE attachments (
+                             id SERIAL PRIMARY KEY,
+                             chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                             uploader_id BIGINT NOT NULL,
+                             message_id INTEGER REFERENCES messages(id) ON DELETE CASCADE,
+                             file_name VARCHAR(255) NOT NULL,
+                             content_type VARCHAR(127) NOT NULL,
+                             size BIGINT NOT NULL,
+                             storage_key VARCHAR(255) NOT NULL,
+                             thumbnail_key VARCHAR(255),
+                             created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX attachments_message_id_idx ON attachments (message_id);
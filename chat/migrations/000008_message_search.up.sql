@@ -0,0 +1,8 @@
+-- Поисковый вектор по тексту сообщения. Русская конфигурация дает стемминг основного контента,
+-- английская - латинских слов, поэтому используются обе
+ALTER TABLE messages
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('russian', text) || to_tsvector('english', text)
+    ) STORED;
+
+CREATE INDEX messages_search_vector_idx ON messages USING GIN (search_vector);
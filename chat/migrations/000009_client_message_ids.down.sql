@@ -0,0 +1,3 @@
+ALTER TABLE messages
+    DROP CONSTRAINT messages_client_message_id_key,
+    DROP COLUMN client_message_id;
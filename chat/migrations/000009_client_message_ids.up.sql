@@ -0,0 +1,4 @@
+-- Идентификатор, который клиент генерирует для сообщения, чтобы повторная отправка не создавала дубликат
+ALTER TABLE messages
+    ADD COLUMN client_message_id UUID,
+    ADD CONSTRAINT messages_client_message_id_key UNIQUE (chat_id, sender_id, client_message_id);
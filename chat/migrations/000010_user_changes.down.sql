@@ -0,0 +1,2 @@
+DROP TABLE user_changes;
+DROP TABLE user_sync_state;
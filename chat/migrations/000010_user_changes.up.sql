@@ -0,0 +1,17 @@
+-- Последний выданный номер изменения для каждого пользователя. Строка блокируется при записи,
+-- поэтому номера одного пользователя идут строго по порядку фиксации транзакций
+CREATE TABLE user_sync_state (
+                                 user_id BIGINT PRIMARY KEY,
+                                 last_seq BIGINT NOT NULL
+);
+
+-- Журнал изменений, которые пользователь пропустил бы без подключения к websocket
+CREATE TABLE user_changes (
+                              user_id BIGINT NOT NULL,
+                              seq BIGINT NOT NULL,
+                              chat_id INTEGER NOT NULL,
+                              type VARCHAR(32) NOT NULL,
+                              payload JSONB NOT NULL,
+                              created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                              PRIMARY KEY (user_id, seq)
+);
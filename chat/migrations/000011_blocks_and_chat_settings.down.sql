@@ -0,0 +1,2 @@
+DROP TABLE chat_settings;
+DROP TABLE user_blocks;
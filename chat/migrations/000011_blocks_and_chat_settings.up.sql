@@ -0,0 +1,20 @@
+-- Черный список пользователя. Блокировка действует в обе стороны:
+-- пока она есть, пользователи не могут создать общий чат и писать в уже созданный
+CREATE TABLE user_blocks (
+                             user_id BIGINT NOT NULL,
+                             blocked_user_id BIGINT NOT NULL,
+                             created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                             PRIMARY KEY (user_id, blocked_user_id)
+);
+
+-- Личные настройки чата. muted без muted_until - уведомления отключены без срока,
+-- закрепленные чаты идут в списке по убыванию pinned_at, архивные - в конце
+CREATE TABLE chat_settings (
+                               chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                               user_id BIGINT NOT NULL,
+                               muted BOOLEAN NOT NULL DEFAULT FALSE,
+                               muted_until TIMESTAMP WITH TIME ZONE,
+                               archived BOOLEAN NOT NULL DEFAULT FALSE,
+                               pinned_at TIMESTAMP WITH TIME ZONE,
+                               PRIMARY KEY (chat_id, user_id)
+);
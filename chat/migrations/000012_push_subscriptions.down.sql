@@ -0,0 +1 @@
+DROP TABLE push_subscriptions;
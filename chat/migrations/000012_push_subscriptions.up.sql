@@ -0,0 +1,14 @@
+-- Web Push подписки устройств пользователя. endpoint уникален: если в браузере сменился пользователь,
+-- подписка переходит к новому. expires_at - срок, который браузер указал в expirationTime
+CREATE TABLE push_subscriptions (
+                                    id SERIAL PRIMARY KEY,
+                                    user_id BIGINT NOT NULL,
+                                    endpoint TEXT NOT NULL UNIQUE,
+                                    p256dh TEXT NOT NULL,
+                                    auth TEXT NOT NULL,
+                                    user_agent TEXT NOT NULL DEFAULT '',
+                                    expires_at TIMESTAMP WITH TIME ZONE,
+                                    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_push_subscriptions_user ON push_subscriptions(user_id);
@@ -0,0 +1,2 @@
+ALTER TABLE messages
+    DROP COLUMN envelope;
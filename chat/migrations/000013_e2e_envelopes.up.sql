@@ -0,0 +1,3 @@
+-- Конверт сквозного шифрования: шифротекст и ключи для устройств. У зашифрованных сообщений text пустой
+ALTER TABLE messages
+    ADD COLUMN envelope JSONB;
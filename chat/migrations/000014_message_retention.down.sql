@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS messages_created_at_idx;
+DROP INDEX IF EXISTS messages_expires_at_idx;
+
+ALTER TABLE messages
+    DROP COLUMN expires_at;
+
+ALTER TABLE chats
+    DROP COLUMN message_ttl,
+    DROP COLUMN message_ttl_updated_by,
+    DROP COLUMN message_ttl_updated_at;
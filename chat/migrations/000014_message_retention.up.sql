@@ -0,0 +1,13 @@
+-- Срок жизни новых сообщений чата в секундах, общий для обоих участников. NULL - сообщения не исчезают
+ALTER TABLE chats
+    ADD COLUMN message_ttl INTEGER,
+    ADD COLUMN message_ttl_updated_by BIGINT,
+    ADD COLUMN message_ttl_updated_at TIMESTAMP WITH TIME ZONE;
+
+-- Время, после которого сообщение удаляется фоновой очисткой. Задается при отправке по message_ttl чата
+ALTER TABLE messages
+    ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX messages_expires_at_idx ON messages (expires_at) WHERE expires_at IS NOT NULL;
+-- Для глобального срока хранения MESSAGE_RETENTION
+CREATE INDEX messages_created_at_idx ON messages (created_at);
@@ -0,0 +1,20 @@
+-- Задачи выгрузки данных пользователя. account - профиль и сессии из auth-сервиса на момент запроса,
+-- storage_key - готовый ZIP-архив в хранилище вложений
+CREATE TABLE exports (
+                         id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                         user_id BIGINT NOT NULL,
+                         status VARCHAR(16) NOT NULL DEFAULT 'pending',
+                         account JSONB NOT NULL,
+                         storage_key VARCHAR(255),
+                         size BIGINT,
+                         error TEXT,
+                         created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                         started_at TIMESTAMP WITH TIME ZONE,
+                         finished_at TIMESTAMP WITH TIME ZONE,
+                         expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX exports_user_id_idx ON exports (user_id, created_at);
+CREATE INDEX exports_pending_idx ON exports (created_at) WHERE status IN ('pending', 'running');
+-- У пользователя не больше одной незавершенной выгрузки
+CREATE UNIQUE INDEX exports_active_user_idx ON exports (user_id) WHERE status IN ('pending', 'running');
@@ -0,0 +1,3 @@
+ALTER TABLE messages
+    DROP CONSTRAINT messages_import_id_key,
+    DROP COLUMN import_id;
@@ -0,0 +1,5 @@
+-- Идентификатор сообщения в источнике импорта (например, telegram:<chat>:<message>).
+-- Уникален в чате, чтобы повторный импорт пропускал уже перенесенные сообщения
+ALTER TABLE messages
+    ADD COLUMN import_id TEXT,
+    ADD CONSTRAINT messages_import_id_key UNIQUE (chat_id, import_id);
@@ -0,0 +1,3 @@
+DROP TABLE moderation_log;
+DROP FUNCTION moderation_log_append_only();
+DROP TABLE message_reports;
@@ -0,0 +1,42 @@
+-- Жалобы пользователей на сообщения. Удаляются вместе с сообщением или чатом
+CREATE TABLE message_reports (
+                                 id SERIAL PRIMARY KEY,
+                                 chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                                 message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+                                 reporter_id BIGINT NOT NULL,
+                                 reason VARCHAR(32) NOT NULL,
+                                 created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX message_reports_message_idx ON message_reports (message_id);
+
+-- Журнал действий администраторов. chat_id и message_id без внешних ключей:
+-- записи об удалении должны пережить удаленные объекты
+CREATE TABLE moderation_log (
+                                id BIGSERIAL PRIMARY KEY,
+                                admin_id BIGINT NOT NULL,
+                                action VARCHAR(32) NOT NULL,
+                                user_id BIGINT,
+                                chat_id INTEGER,
+                                message_id INTEGER,
+                                reason TEXT NOT NULL DEFAULT '',
+                                details JSONB,
+                                created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX moderation_log_created_idx ON moderation_log (created_at);
+
+-- Журнал только дополняется: изменение, удаление и очистка записей запрещены на уровне БД
+CREATE FUNCTION moderation_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'moderation_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER moderation_log_no_update_delete
+    BEFORE UPDATE OR DELETE ON moderation_log
+    FOR EACH ROW EXECUTE FUNCTION moderation_log_append_only();
+
+CREATE TRIGGER moderation_log_no_truncate
+    BEFORE TRUNCATE ON moderation_log
+    FOR EACH STATEMENT EXECUTE FUNCTION moderation_log_append_only();
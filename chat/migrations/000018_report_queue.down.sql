@@ -0,0 +1,9 @@
+DROP TABLE moderation_queue;
+
+CREATE INDEX message_reports_message_idx ON message_reports (message_id);
+
+ALTER TABLE message_reports
+    DROP CONSTRAINT message_reports_reporter_key,
+    DROP COLUMN comment;
+
+ALTER TABLE messages DROP COLUMN hidden_at;
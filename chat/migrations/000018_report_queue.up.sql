@@ -0,0 +1,34 @@
+-- Сообщение, скрытое до решения модератора после жалоб
+ALTER TABLE messages ADD COLUMN hidden_at TIMESTAMP WITH TIME ZONE;
+
+-- Один пользователь жалуется на сообщение один раз
+ALTER TABLE message_reports
+    ADD COLUMN comment TEXT NOT NULL DEFAULT '',
+    ADD CONSTRAINT message_reports_reporter_key UNIQUE (message_id, reporter_id);
+
+DROP INDEX message_reports_message_idx;
+
+-- Очередь модерации: одна строка на сообщение с жалобами. sender_id хранится здесь,
+-- чтобы считать жалобы на отправителя без соединения с messages
+CREATE TABLE moderation_queue (
+                                  message_id INTEGER PRIMARY KEY REFERENCES messages(id) ON DELETE CASCADE,
+                                  chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                                  sender_id BIGINT NOT NULL,
+                                  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+                                  reports INTEGER NOT NULL DEFAULT 0,
+                                  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                                  updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                                  hidden_at TIMESTAMP WITH TIME ZONE,
+                                  resolved_by BIGINT,
+                                  resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX moderation_queue_status_idx ON moderation_queue (status, updated_at);
+CREATE INDEX moderation_queue_open_sender_idx ON moderation_queue (sender_id) WHERE status IN ('pending', 'hidden');
+
+-- Жалобы, поданные до появления очереди, попадают в нее на рассмотрение
+INSERT INTO moderation_queue (message_id, chat_id, sender_id, reports, created_at, updated_at)
+SELECT m.id, m.chat_id, m.sender_id, COUNT(*), MIN(r.created_at), MAX(r.created_at)
+FROM message_reports r
+JOIN messages m ON m.id = r.message_id
+GROUP BY m.id;
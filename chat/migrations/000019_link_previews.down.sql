@@ -0,0 +1,2 @@
+DROP TABLE message_link_previews;
+DROP TABLE link_previews;
@@ -0,0 +1,18 @@
+-- Кэш карточек ссылок по URL. Страница, с которой не удалось получить заголовок, тоже кэшируется
+-- с пустым title, чтобы не запрашивать ее при каждом сообщении
+CREATE TABLE link_previews (
+                               url TEXT PRIMARY KEY,
+                               title TEXT NOT NULL DEFAULT '',
+                               description TEXT NOT NULL DEFAULT '',
+                               image_url TEXT NOT NULL DEFAULT '',
+                               site_name TEXT NOT NULL DEFAULT '',
+                               fetched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Карточки ссылок сообщения в порядке появления ссылок в тексте
+CREATE TABLE message_link_previews (
+                                       message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+                                       url TEXT NOT NULL REFERENCES link_previews(url) ON DELETE CASCADE,
+                                       position SMALLINT NOT NULL,
+                                       PRIMARY KEY (message_id, url)
+);
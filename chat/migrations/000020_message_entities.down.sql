@@ -0,0 +1,7 @@
+DROP TABLE message_mentions;
+
+ALTER TABLE message_edits
+    DROP COLUMN entities;
+
+ALTER TABLE messages
+    DROP COLUMN entities;
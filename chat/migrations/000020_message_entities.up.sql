@@ -0,0 +1,16 @@
+-- Разметка текста: массив участков {type, offset, length, url, user_id} со смещениями в UTF-16
+ALTER TABLE messages
+    ADD COLUMN entities JSONB;
+
+ALTER TABLE message_edits
+    ADD COLUMN entities JSONB;
+
+-- Упомянутые в сообщении участники чата, кроме отправителя. По ним считаются непрочитанные упоминания
+CREATE TABLE message_mentions (
+                                  message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+                                  chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                                  user_id BIGINT NOT NULL,
+                                  PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX message_mentions_user_chat_idx ON message_mentions (user_id, chat_id, message_id);
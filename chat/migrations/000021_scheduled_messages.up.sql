@@ -0,0 +1,21 @@
+-- Отложенные сообщения. Отправленное сообщение удаляется из таблицы, status = 'sending' - отправка идет сейчас,
+-- claimed_at - когда ее начали, 'failed' - отправка невозможна, причина в error
+CREATE TABLE scheduled_messages (
+                                    id SERIAL PRIMARY KEY,
+                                    chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                                    sender_id BIGINT NOT NULL,
+                                    text TEXT NOT NULL,
+                                    entities JSONB,
+                                    reply_to_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+                                    attachment_ids INTEGER[] NOT NULL DEFAULT '{}',
+                                    client_message_id UUID NOT NULL,
+                                    send_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+                                    error TEXT,
+                                    claimed_at TIMESTAMP WITH TIME ZONE,
+                                    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+                                    UNIQUE (chat_id, sender_id, client_message_id)
+);
+
+CREATE INDEX scheduled_messages_sender_idx ON scheduled_messages (sender_id, send_at);
+CREATE INDEX scheduled_messages_due_idx ON scheduled_messages (send_at) WHERE status IN ('pending', 'sending');
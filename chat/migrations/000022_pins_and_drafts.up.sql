@@ -0,0 +1,20 @@
+-- Закрепленные сообщения чата, общие для обоих участников
+CREATE TABLE pinned_messages (
+                                 chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                                 message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+                                 pinned_by BIGINT NOT NULL,
+                                 pinned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                 PRIMARY KEY (chat_id, message_id)
+);
+
+-- Черновики пользователя по чатам. Удаленный черновик остается строкой с пустым текстом, чтобы
+-- запоздавшая правка с другого устройства не восстановила его: сравнивается updated_at
+CREATE TABLE drafts (
+                        chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                        user_id BIGINT NOT NULL,
+                        text TEXT NOT NULL DEFAULT '',
+                        entities JSONB,
+                        reply_to_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+                        updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                        PRIMARY KEY (user_id, chat_id)
+);
@@ -0,0 +1,27 @@
+-- Вебхуки ботов: по одному адресу на бота, secret подписывает тело запроса (HMAC-SHA256).
+-- last_error - последняя неудачная доставка, чтобы владелец бота видел, почему события не доходят.
+-- updated_at - когда вебхук установлен, повторная установка меняет адрес и ключ
+CREATE TABLE bot_webhooks (
+                              bot_id BIGINT PRIMARY KEY,
+                              url TEXT NOT NULL,
+                              secret TEXT NOT NULL,
+                              last_error TEXT,
+                              last_error_at TIMESTAMP WITH TIME ZONE,
+                              updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Очередь доставки событий вебхукам. Взятая в работу доставка сдвигает next_attempt_at на время аренды:
+-- если реплика упадет, доставку возьмет другая. Доставленные и исчерпавшие попытки строки удаляются
+CREATE TABLE webhook_deliveries (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    bot_id BIGINT NOT NULL REFERENCES bot_webhooks(bot_id) ON DELETE CASCADE,
+                                    chat_id INTEGER NOT NULL,
+                                    event VARCHAR(64) NOT NULL,
+                                    payload JSONB NOT NULL,
+                                    attempts INTEGER NOT NULL DEFAULT 0,
+                                    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                                    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at);
+CREATE INDEX webhook_deliveries_bot_idx ON webhook_deliveries (bot_id);
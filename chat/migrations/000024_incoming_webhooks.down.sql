@@ -0,0 +1,4 @@
+ALTER TABLE messages
+    DROP COLUMN webhook_name,
+    DROP COLUMN incoming_webhook_id;
+DROP TABLE incoming_webhooks;
@@ -0,0 +1,18 @@
+-- Входящие вебхуки: адрес с секретом, по которому внешние системы пишут в чат.
+-- Хранится только SHA-256 секрета, сам секрет показывается один раз при создании
+CREATE TABLE incoming_webhooks (
+                                   id SERIAL PRIMARY KEY,
+                                   chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+                                   creator_id BIGINT NOT NULL,
+                                   name VARCHAR(80) NOT NULL,
+                                   token_hash CHAR(64) NOT NULL UNIQUE,
+                                   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX incoming_webhooks_chat_idx ON incoming_webhooks (chat_id);
+
+-- Сообщения вебхука сохраняются от имени его создателя, webhook_name - имя, под которым сообщение показывается.
+-- После отзыва вебхука имя остается
+ALTER TABLE messages
+    ADD COLUMN incoming_webhook_id INTEGER REFERENCES incoming_webhooks(id) ON DELETE SET NULL,
+    ADD COLUMN webhook_name VARCHAR(80);
@@ -43,4 +43,26 @@ async function networkAndCache(req) {
     const cached = await cache.match(req);
     return cached;
   }
-}
\ No newline at end of file
+}
+// Push-уведомление о новом сообщении от chat-сервиса
+self.addEventListener('push', e => {
+  const data = e.data ? e.data.json() : {};
+  e.waitUntil(
+    self.registration.showNotification(`Диалог #${data.chat_id}`, {
+      body: data.text,
+      icon: './icons/icon-192x192.png',
+      tag: `chat-${data.chat_id}`,
+      data: {chatId: data.chat_id}
+    })
+  );
+});
+
+self.addEventListener('notificationclick', e => {
+  e.notification.close();
+  e.waitUntil(
+    self.clients.matchAll({type: 'window', includeUncontrolled: true}).then(windows => {
+      const chat = windows.find(w => w.url.includes('chat.html'));
+      return chat ? chat.focus() : self.clients.openWindow('./chat.html');
+    })
+  );
+});
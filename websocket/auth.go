@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	authpb "websocket/pkg/api/auth_pb"
+
+	socketio "github.com/googollee/go-socket.io"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const jwtCookieName = "user_jwt"
+
+type authClient struct {
+	conn   *grpc.ClientConn
+	client authpb.AuthServiceClient
+}
+
+func newAuthClient() (*authClient, error) {
+	address := fmt.Sprintf("%s:%s", os.Getenv("AUTH_GRPC_HOST"), os.Getenv("AUTH_GRPC_PORT"))
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &authClient{conn: conn, client: authpb.NewAuthServiceClient(conn)}, nil
+}
+
+func (a *authClient) Close() error {
+	return a.conn.Close()
+}
+
+// handshakeToken достает токен из куки рукопожатия или query-параметра token
+func handshakeToken(s socketio.Conn) (string, error) {
+	u := s.URL()
+	if token := u.Query().Get("token"); token != "" {
+		return token, nil
+	}
+
+	req := http.Request{Header: s.RemoteHeader()}
+	cookie, err := req.Cookie(jwtCookieName)
+	if err != nil {
+		return "", fmt.Errorf("no token in handshake: %w", err)
+	}
+	return cookie.Value, nil
+}
+
+// userID проверяет токен в auth-сервисе
+func (a *authClient) userID(token string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	resp, err := a.client.Validate(ctx, &authpb.Token{Token: token})
+	if err != nil {
+		return 0, err
+	}
+	if resp.GetUserId() <= 0 {
+		return 0, fmt.Errorf("invalid user id %d", resp.GetUserId())
+	}
+	return int(resp.GetUserId()), nil
+}
+
+func userRoom(id int) string {
+	return fmt.Sprintf("user:%d", id)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// session - данные аутентифицированного соединения, хранятся в контексте socketio.Conn
+type session struct {
+	userID int
+	token  string
+}
+
+// sendMessageRequest - тело события send_message, совпадает с телом POST /chat/{chat_id}.
+// Разметка format и entities проверяется и разбирается в chat-сервисе
+type sendMessageRequest struct {
+	ChatID           int             `json:"chat_id"`
+	Text             string          `json:"text"`
+	Format           string          `json:"format,omitempty"`
+	Entities         json.RawMessage `json:"entities,omitempty"`
+	ReplyToMessageID int             `json:"reply_to_message_id,omitempty"`
+	AttachmentIDs    []int           `json:"attachment_ids,omitempty"`
+	ClientMessageID  string          `json:"client_message_id,omitempty"`
+	Envelope         json.RawMessage `json:"envelope,omitempty"`
+	SendAt           json.RawMessage `json:"send_at,omitempty"`
+}
+
+// sendMessageAck - ответ на send_message. При ошибке заполнены error и status (HTTP-код chat-сервиса),
+// при превышении лимита отправки - retry_after в секундах. Для отложенного сообщения вместо message_id
+// заполнен scheduled_id
+type sendMessageAck struct {
+	MessageID       int    `json:"message_id,omitempty"`
+	ScheduledID     int    `json:"scheduled_id,omitempty"`
+	ClientMessageID string `json:"client_message_id,omitempty"`
+	Error           string `json:"error,omitempty"`
+	Status          int    `json:"status,omitempty"`
+	RetryAfter      int    `json:"retry_after,omitempty"`
+}
+
+// chatClient отправляет сообщения через HTTP API chat-сервиса от имени пользователя,
+// поэтому проверки и идемпотентность по client_message_id те же, что и у HTTP-клиентов
+type chatClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newChatClient() *chatClient {
+	return &chatClient{
+		baseURL: fmt.Sprintf("http://%s:%s", os.Getenv("CHAT_HTTP_HOST"), os.Getenv("CHAT_HTTP_PORT")),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (c *chatClient) sendMessage(ctx context.Context, token string, req sendMessageRequest) sendMessageAck {
+	ack := sendMessageAck{ClientMessageID: req.ClientMessageID}
+	if req.ChatID <= 0 {
+		ack.Error, ack.Status = "chat_id is required", http.StatusBadRequest
+		return ack
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		ack.Error, ack.Status = "invalid message", http.StatusBadRequest
+		return ack
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/chat/%d", c.baseURL, req.ChatID), bytes.NewReader(body))
+	if err != nil {
+		ack.Error, ack.Status = "failed to create request", http.StatusInternalServerError
+		return ack
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.AddCookie(&http.Cookie{Name: jwtCookieName, Value: token})
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		// Клиент может повторить отправку с тем же client_message_id, дубликата не будет
+		ack.Error, ack.Status = "chat service unavailable", http.StatusServiceUnavailable
+		return ack
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		ack.Error, ack.Status = strings.TrimSpace(string(text)), resp.StatusCode
+		ack.RetryAfter, _ = strconv.Atoi(resp.Header.Get("Retry-After"))
+		return ack
+	}
+
+	var created struct {
+		MessageID int `json:"message_id"`
+		ID        int `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		ack.Error, ack.Status = "invalid chat service response", http.StatusBadGateway
+		return ack
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		ack.ScheduledID = created.ID
+	} else {
+		ack.MessageID = created.MessageID
+	}
+
+	return ack
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	socketio "github.com/googollee/go-socket.io"
+)
+
+// event - событие от chat-сервиса, которое нужно доставить перечисленным пользователям.
+// MutedUserIDs отключили уведомления чата: клиент обновляет данные, но не показывает оповещение
+type event struct {
+	Event        string          `json:"event"`
+	ChatID       int             `json:"chat_id"`
+	UserIDs      []int           `json:"user_ids"`
+	MutedUserIDs []int           `json:"muted_user_ids"`
+	Payload      json.RawMessage `json:"payload"`
+}
+
+// eventsHandler - внутренний endpoint для chat-сервиса, наружу через nginx не публикуется
+func eventsHandler(server *socketio.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var e event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if e.Event == "" {
+			http.Error(w, "event is required", http.StatusBadRequest)
+			return
+		}
+
+		payload := map[string]any{
+			"chat_id": e.ChatID,
+			"payload": e.Payload,
+		}
+		mutedPayload := map[string]any{
+			"chat_id": e.ChatID,
+			"payload": e.Payload,
+			"muted":   true,
+		}
+		for _, id := range e.UserIDs {
+			if slices.Contains(e.MutedUserIDs, id) {
+				server.BroadcastToRoom("/", userRoom(id), e.Event, mutedPayload)
+				continue
+			}
+			server.BroadcastToRoom("/", userRoom(id), e.Event, payload)
+		}
+		fmt.Println("event", e.Event, "chat", e.ChatID, "to", e.UserIDs)
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
@@ -2,10 +2,18 @@ module websocket
 
 go 1.24.1
 
-require github.com/googollee/go-socket.io v1.7.0
+require (
+	github.com/googollee/go-socket.io v1.7.0
+	google.golang.org/grpc v1.71.1
+	google.golang.org/protobuf v1.36.5
+)
 
 require (
 	github.com/gofrs/uuid v4.0.0+incompatible // indirect
 	github.com/gomodule/redigo v1.8.4 // indirect
 	github.com/gorilla/websocket v1.4.2 // indirect
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250115164207-1a7da9e5054f // indirect
 )
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,9 +13,32 @@ import (
 func main() {
 	server := socketio.NewServer(&engineio.Options{})
 
+	auth, err := newAuthClient()
+	if err != nil {
+		log.Fatal("failed to create auth client: ", err)
+	}
+	defer auth.Close()
+
+	chat := newChatClient()
+
 	server.OnConnect("/", func(s socketio.Conn) error {
 		s.SetContext("")
 		fmt.Println("connected:", s.ID())
+
+		// Без валидного токена соединение остается анонимным и получает только общие broadcast-события
+		token, err := handshakeToken(s)
+		if err != nil {
+			fmt.Println("anonymous connection:", s.ID(), err)
+			return nil
+		}
+		id, err := auth.userID(token)
+		if err != nil {
+			fmt.Println("anonymous connection:", s.ID(), err)
+			return nil
+		}
+		s.SetContext(session{userID: id, token: token})
+		s.Join(userRoom(id))
+		fmt.Println("user", id, "joined:", s.ID())
 		return nil
 	})
 
@@ -25,11 +49,22 @@ func main() {
 
 	server.OnEvent("/", "msg", func(s socketio.Conn, msg any) {
 		fmt.Println("msg from ", s.ID(), " : ", msg)
-		s.SetContext(msg)
 		server.BroadcastToNamespace("/", "msg", msg)
 
 	})
 
+	// send_message отправляет сообщение в чат и подтверждает его ack-ответом с message_id
+	server.OnEvent("/", "send_message", func(s socketio.Conn, req sendMessageRequest) sendMessageAck {
+		sess, ok := s.Context().(session)
+		if !ok {
+			return sendMessageAck{ClientMessageID: req.ClientMessageID, Error: "unauthorized", Status: http.StatusUnauthorized}
+		}
+
+		ack := chat.sendMessage(context.Background(), sess.token, req)
+		fmt.Println("send_message from user", sess.userID, "chat", req.ChatID, "status", ack.Status)
+		return ack
+	})
+
 	server.OnError("/", func(s socketio.Conn, e error) {
 		// server.Remove(s.ID())
 		fmt.Println("meet error:", e)
@@ -46,6 +81,8 @@ func main() {
 	defer server.Close()
 
 	http.Handle("/socket.io/", server)
+	http.Handle("/events", eventsHandler(server))
+	http.Handle("/presence", presenceHandler(server))
 	http.Handle("/", http.FileServer(http.Dir("./asset")))
 	log.Println("Serving at localhost:3000...")
 	log.Fatal(http.ListenAndServe(":3000", nil))
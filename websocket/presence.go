@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	socketio "github.com/googollee/go-socket.io"
+)
+
+// presenceRequest - пользователи, для которых chat-сервис выбирает, слать ли push-уведомление
+type presenceRequest struct {
+	UserIDs []int `json:"user_ids"`
+}
+
+type presenceResponse struct {
+	OnlineUserIDs []int `json:"online_user_ids"`
+}
+
+// presenceHandler - внутренний endpoint для chat-сервиса: кто из пользователей сейчас подключен.
+// Наружу через nginx не публикуется
+func presenceHandler(server *socketio.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		resp := presenceResponse{OnlineUserIDs: []int{}}
+		for _, id := range req.UserIDs {
+			if server.RoomLen("/", userRoom(id)) > 0 {
+				resp.OnlineUserIDs = append(resp.OnlineUserIDs, id)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}